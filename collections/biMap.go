@@ -0,0 +1,156 @@
+package collections
+
+import (
+	"errors"
+	"fmt"
+)
+
+/*
+ErrBiMapConflict is returned by [BiMap.Put] when the value already belongs to another key.
+
+ErrBiMapConflict 在值已经属于另一个键时由 [BiMap.Put] 返回。
+*/
+var ErrBiMapConflict = errors.New("value already belongs to another key")
+
+/*
+BiMap is a one-to-one map that can be looked up from both sides, such as extensions and category IDs.
+Each key has one value and each value has one key. It is not thread safe.
+
+Example:
+
+	m := NewBiMap[string, int]()
+	m.Put(".jpg", 1)
+	id, _ := m.Get(".jpg")   // 1
+	ext, _ := m.GetKey(1)    // ".jpg"
+	err := m.Put(".jpeg", 1) // wraps ErrBiMapConflict
+
+BiMap 是可以从两端查找的一对一 map，例如扩展名与类别 ID。每个键只有一个值，每个值也只有一个键。非多线程安全。
+*/
+type BiMap[K comparable, V comparable] struct {
+	forward map[K]V
+	inverse map[V]K
+}
+
+/*
+NewBiMap creates an empty BiMap.
+
+NewBiMap 创建空的 BiMap。
+*/
+func NewBiMap[K comparable, V comparable]() *BiMap[K, V] {
+	return &BiMap[K, V]{
+		forward: map[K]V{},
+		inverse: map[V]K{},
+	}
+}
+
+/*
+Put maps the key to the value, replacing the old value of the key.
+
+Returns:
+  - An error wrapping [ErrBiMapConflict] if the value belongs to another key. The map is not changed.
+
+Put 将键映射到值，替换键原来的值。
+
+返回:
+  - 值属于另一个键时，返回包装了 [ErrBiMapConflict] 的错误，map 不变。
+*/
+func (m *BiMap[K, V]) Put(key K, value V) error {
+	if other, ok := m.inverse[value]; ok && other != key {
+		return fmt.Errorf("%w: %v is mapped from %v", ErrBiMapConflict, value, other)
+	}
+
+	m.ForcePut(key, value)
+	return nil
+}
+
+/*
+ForcePut maps the key to the value, removing the old value of the key and the old key of the value.
+
+ForcePut 将键映射到值，删除键原来的值及值原来的键。
+*/
+func (m *BiMap[K, V]) ForcePut(key K, value V) {
+	m.DeleteKey(key)
+	m.DeleteValue(value)
+
+	m.forward[key] = value
+	m.inverse[value] = key
+}
+
+/*
+Get returns the value of the key.
+
+Get 返回键对应的值。
+*/
+func (m *BiMap[K, V]) Get(key K) (V, bool) {
+	value, ok := m.forward[key]
+	return value, ok
+}
+
+/*
+GetKey returns the key of the value.
+
+GetKey 返回值对应的键。
+*/
+func (m *BiMap[K, V]) GetKey(value V) (K, bool) {
+	key, ok := m.inverse[value]
+	return key, ok
+}
+
+/*
+DeleteKey removes the key and its value, and returns whether the key was in the map.
+
+DeleteKey 删除键及其值，并返回键是否在 map 中。
+*/
+func (m *BiMap[K, V]) DeleteKey(key K) bool {
+	value, ok := m.forward[key]
+	if ok {
+		delete(m.forward, key)
+		delete(m.inverse, value)
+	}
+	return ok
+}
+
+/*
+DeleteValue removes the value and its key, and returns whether the value was in the map.
+
+DeleteValue 删除值及其键，并返回值是否在 map 中。
+*/
+func (m *BiMap[K, V]) DeleteValue(value V) bool {
+	key, ok := m.inverse[value]
+	if ok {
+		delete(m.inverse, value)
+		delete(m.forward, key)
+	}
+	return ok
+}
+
+/*
+Len returns the number of pairs.
+
+Len 返回键值对的数量。
+*/
+func (m *BiMap[K, V]) Len() int {
+	return len(m.forward)
+}
+
+/*
+Inverse returns the map from values to keys. It shares the data with m, so changes to either are seen by both.
+
+Inverse 返回从值到键的 map。它与 m 共享数据，所以对任何一个的修改在两者中都可见。
+*/
+func (m *BiMap[K, V]) Inverse() *BiMap[V, K] {
+	return &BiMap[V, K]{forward: m.inverse, inverse: m.forward}
+}
+
+/*
+Range calls fn for each key and value until fn returns false. The order is unspecified.
+
+Range 对每个键及值调用 fn，直到 fn 返回 false。顺序不确定。
+*/
+func (m *BiMap[K, V]) Range(fn func(key K, value V) bool) {
+	for key, value := range m.forward {
+		if !fn(key, value) {
+			return
+		}
+	}
+}
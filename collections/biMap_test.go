@@ -0,0 +1,58 @@
+package collections
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBiMap(t *testing.T) {
+	m := NewBiMap[string, int]()
+	assert.Nil(t, m.Put(".jpg", 1))
+	assert.Nil(t, m.Put(".png", 2))
+	assert.Nil(t, m.Put(".jpg", 1)) // 相同的键值对不冲突。
+
+	id, ok := m.Get(".jpg")
+	assert.True(t, ok)
+	assert.Equal(t, 1, id)
+	ext, ok := m.GetKey(2)
+	assert.True(t, ok)
+	assert.Equal(t, ".png", ext)
+
+	err := m.Put(".jpeg", 1)
+	assert.True(t, errors.Is(err, ErrBiMapConflict))
+	_, ok = m.Get(".jpeg")
+	assert.False(t, ok)
+
+	// 替换键的值时删除原来的值。
+	assert.Nil(t, m.Put(".jpg", 3))
+	_, ok = m.GetKey(1)
+	assert.False(t, ok)
+
+	// ForcePut 删除值原来的键。
+	m.ForcePut(".jpeg", 3)
+	_, ok = m.Get(".jpg")
+	assert.False(t, ok)
+	ext, _ = m.GetKey(3)
+	assert.Equal(t, ".jpeg", ext)
+	assert.Equal(t, 2, m.Len())
+
+	inverse := m.Inverse()
+	ext, _ = inverse.Get(2)
+	assert.Equal(t, ".png", ext)
+	inverse.DeleteKey(2)
+	assert.False(t, m.DeleteKey(".png"))
+	assert.True(t, m.DeleteValue(3))
+	assert.Equal(t, 0, m.Len())
+	assert.Equal(t, 0, inverse.Len())
+
+	m.Put("a", 1)
+	m.Put("b", 2)
+	count := 0
+	m.Range(func(key string, value int) bool {
+		count++
+		return false
+	})
+	assert.Equal(t, 1, count)
+}
@@ -0,0 +1,283 @@
+package collections
+
+import (
+	"encoding/binary"
+	"hash/maphash"
+	"math"
+	"reflect"
+	"sync"
+)
+
+/*
+ConcurrentMap is a thread safe map split into shards, each with its own lock,
+so goroutines working on different keys rarely wait for each other.
+
+Example:
+
+	sizes := NewConcurrentMap[string, int64](0)
+	// In each worker:
+	sizes.Compute(ext, func(total int64, ok bool) int64 { return total + size })
+
+ConcurrentMap 是分片的多线程安全 map，每个分片有自己的锁，所以处理不同键的 goroutine 很少相互等待。
+*/
+type ConcurrentMap[K comparable, V any] struct {
+	seed   maphash.Seed
+	shards []concurrentShard[K, V]
+}
+
+// concurrentShard 是 ConcurrentMap 的一个分片。
+type concurrentShard[K comparable, V any] struct {
+	items map[K]V
+	lock  sync.RWMutex
+}
+
+/*
+NewConcurrentMap creates a ConcurrentMap.
+
+Parameters:
+  - shards: The number of shards, rounded up to a power of 2. 0 or negative means 32.
+
+Returns:
+  - The map.
+
+NewConcurrentMap 创建 ConcurrentMap。
+
+参数:
+  - shards: 分片数量，向上取整为 2 的幂。0 或负数表示 32。
+
+返回:
+  - map。
+*/
+func NewConcurrentMap[K comparable, V any](shards int) *ConcurrentMap[K, V] {
+	if shards <= 0 {
+		shards = 32
+	}
+	count := 1
+	for count < shards {
+		count <<= 1
+	}
+
+	m := &ConcurrentMap[K, V]{
+		seed:   maphash.MakeSeed(),
+		shards: make([]concurrentShard[K, V], count),
+	}
+	for i := range m.shards {
+		m.shards[i].items = map[K]V{}
+	}
+	return m
+}
+
+/*
+Get returns the value of the key.
+
+Get 返回键对应的值。
+*/
+func (m *ConcurrentMap[K, V]) Get(key K) (V, bool) {
+	shard := m.shard(key)
+	shard.lock.RLock()
+	defer shard.lock.RUnlock()
+
+	value, ok := shard.items[key]
+	return value, ok
+}
+
+/*
+Set sets the value of the key.
+
+Set 设置键对应的值。
+*/
+func (m *ConcurrentMap[K, V]) Set(key K, value V) {
+	shard := m.shard(key)
+	shard.lock.Lock()
+	defer shard.lock.Unlock()
+
+	shard.items[key] = value
+}
+
+/*
+Delete removes the key and returns whether it was in the map.
+
+Delete 删除键，并返回它是否在 map 中。
+*/
+func (m *ConcurrentMap[K, V]) Delete(key K) bool {
+	shard := m.shard(key)
+	shard.lock.Lock()
+	defer shard.lock.Unlock()
+
+	_, ok := shard.items[key]
+	delete(shard.items, key)
+	return ok
+}
+
+/*
+GetOrCompute returns the value of the key. If the key is absent, it stores and returns the result of compute.
+compute is called at most once per absent key, holding the lock of the shard,
+so it must not access the map.
+
+Returns:
+  - The value.
+  - Whether the value was already in the map.
+
+GetOrCompute 返回键对应的值。键不存在时，保存并返回 compute 的结果。
+对于不存在的键，compute 最多被调用一次，调用时持有分片的锁，所以 compute 不能访问该 map。
+
+返回:
+  - 值。
+  - 值是否已在 map 中。
+*/
+func (m *ConcurrentMap[K, V]) GetOrCompute(key K, compute func() V) (V, bool) {
+	// 先用读锁查找，已存在时不阻塞其它读取。
+	if value, ok := m.Get(key); ok {
+		return value, true
+	}
+
+	shard := m.shard(key)
+	shard.lock.Lock()
+	defer shard.lock.Unlock()
+
+	if value, ok := shard.items[key]; ok {
+		return value, true
+	}
+	value := compute()
+	shard.items[key] = value
+	return value, false
+}
+
+/*
+Compute replaces the value of the key by the result of update, which gets the current value and whether it exists.
+It is the way to aggregate, such as adding to a total. update holds the lock of the shard,
+so it must not access the map.
+
+Compute 将键对应的值替换为 update 的结果，update 的参数是当前值及其是否存在。
+用于聚合，例如累加。update 持有分片的锁，所以不能访问该 map。
+*/
+func (m *ConcurrentMap[K, V]) Compute(key K, update func(value V, ok bool) V) V {
+	shard := m.shard(key)
+	shard.lock.Lock()
+	defer shard.lock.Unlock()
+
+	value, ok := shard.items[key]
+	value = update(value, ok)
+	shard.items[key] = value
+	return value
+}
+
+/*
+Range calls fn for each key and value until fn returns false. The order is unspecified.
+Each shard is copied before fn is called, so fn may access the map,
+but changes made during Range may or may not be seen.
+
+Range 对每个键及值调用 fn，直到 fn 返回 false。顺序不确定。
+调用 fn 前先复制每个分片，所以 fn 可以访问该 map，但 Range 期间的修改不一定可见。
+*/
+func (m *ConcurrentMap[K, V]) Range(fn func(key K, value V) bool) {
+	for i := range m.shards {
+		shard := &m.shards[i]
+		shard.lock.RLock()
+		items := make(map[K]V, len(shard.items))
+		for key, value := range shard.items {
+			items[key] = value
+		}
+		shard.lock.RUnlock()
+
+		for key, value := range items {
+			if !fn(key, value) {
+				return
+			}
+		}
+	}
+}
+
+/*
+Len returns the number of keys. It is only a snapshot while other goroutines change the map.
+
+Len 返回键的数量。其它 goroutine 修改 map 时只是一个快照。
+*/
+func (m *ConcurrentMap[K, V]) Len() int {
+	n := 0
+	for i := range m.shards {
+		shard := &m.shards[i]
+		shard.lock.RLock()
+		n += len(shard.items)
+		shard.lock.RUnlock()
+	}
+	return n
+}
+
+// shard 返回 key 所在的分片。
+func (m *ConcurrentMap[K, V]) shard(key K) *concurrentShard[K, V] {
+	var h maphash.Hash
+	h.SetSeed(m.seed)
+
+	// 常见的键类型直接写入，其它类型按 == 的语义逐个字段写入。
+	switch k := any(key).(type) {
+	case string:
+		h.WriteString(k)
+	case int:
+		writeUint64(&h, uint64(k))
+	case int64:
+		writeUint64(&h, uint64(k))
+	case uint64:
+		writeUint64(&h, k)
+	default:
+		hashValue(&h, reflect.ValueOf(&key).Elem())
+	}
+
+	return &m.shards[h.Sum64()&uint64(len(m.shards)-1)]
+}
+
+// hashValue 写入 v 的哈希值，== 相等的值得到相同的哈希值：指针按地址而不是指向的内容，-0 与 0 相同。
+// 哈希值只用于选择分片，所以不同的值可以相同。
+func hashValue(h *maphash.Hash, v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			h.WriteByte(1)
+		} else {
+			h.WriteByte(0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		writeUint64(h, uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		writeUint64(h, v.Uint())
+	case reflect.Float32, reflect.Float64:
+		writeFloat(h, v.Float())
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		writeFloat(h, real(c))
+		writeFloat(h, imag(c))
+	case reflect.String:
+		h.WriteString(v.String())
+	case reflect.Pointer, reflect.Chan, reflect.UnsafePointer:
+		writeUint64(h, uint64(v.Pointer()))
+	case reflect.Interface:
+		if !v.IsNil() {
+			hashValue(h, v.Elem())
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			hashValue(h, v.Index(i))
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			// == 忽略空白字段。
+			if t.Field(i).Name != "_" {
+				hashValue(h, v.Field(i))
+			}
+		}
+	}
+}
+
+func writeFloat(h *maphash.Hash, f float64) {
+	if f == 0 { // -0 与 0 相等，必须在同一分片。
+		f = 0
+	}
+	writeUint64(h, math.Float64bits(f))
+}
+
+func writeUint64(h *maphash.Hash, n uint64) {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], n)
+	h.Write(buf[:])
+}
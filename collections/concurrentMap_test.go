@@ -0,0 +1,127 @@
+package collections
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConcurrentMap(t *testing.T) {
+	m := NewConcurrentMap[string, int](3)
+	assert.Equal(t, 4, len(m.shards))
+
+	m.Set("a", 1)
+	value, ok := m.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+
+	value, loaded := m.GetOrCompute("a", func() int { return 100 })
+	assert.True(t, loaded)
+	assert.Equal(t, 1, value)
+	value, loaded = m.GetOrCompute("b", func() int { return 2 })
+	assert.False(t, loaded)
+	assert.Equal(t, 2, value)
+
+	assert.Equal(t, 12, m.Compute("b", func(v int, ok bool) int { return v + 10 }))
+	assert.Equal(t, 2, m.Len())
+
+	keys := []string{}
+	m.Range(func(key string, value int) bool {
+		keys = append(keys, key)
+		m.Set(key, value+1) // Range 中可以访问 map。
+		return true
+	})
+	sort.Strings(keys)
+	assert.Equal(t, []string{"a", "b"}, keys)
+	value, _ = m.Get("b")
+	assert.Equal(t, 13, value)
+
+	assert.True(t, m.Delete("a"))
+	assert.False(t, m.Delete("a"))
+	_, ok = m.Get("a")
+	assert.False(t, ok)
+
+	count := 0
+	m.Range(func(key string, value int) bool {
+		count++
+		return false
+	})
+	assert.Equal(t, 1, count)
+}
+
+func TestConcurrentMapParallel(t *testing.T) {
+	m := NewConcurrentMap[int, int](0)
+	computed := NewConcurrentMap[float64, int](0)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				m.Compute(j%100, func(v int, ok bool) int { return v + 1 })
+				computed.GetOrCompute(float64(j%10), func() int { return j })
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 100, m.Len())
+	m.Range(func(key int, value int) bool {
+		assert.Equal(t, 80, value)
+		return true
+	})
+	assert.Equal(t, 10, computed.Len())
+
+	// -0 与 0 是同一个键。
+	computed.Set(0, 1)
+	negativeZero := -1.0 * 0
+	value, _ := computed.Get(-negativeZero)
+	assert.Equal(t, 1, value)
+}
+
+func TestConcurrentMapKeyHash(t *testing.T) {
+	type file struct{ size int64 }
+	type point struct {
+		x, y float64
+		_    int
+	}
+
+	// 指针按地址比较，修改指向的内容后仍然能找到。
+	pointers := NewConcurrentMap[*file, int](64)
+	files := make([]*file, 100)
+	for i := range files {
+		files[i] = &file{size: int64(i)}
+		pointers.Set(files[i], i)
+	}
+	for i, f := range files {
+		f.size += 1000
+		value, ok := pointers.Get(f)
+		assert.True(t, ok)
+		assert.Equal(t, i, value)
+	}
+
+	negativeZero := math.Copysign(0, -1)
+	points := NewConcurrentMap[point, int](64)
+	arrays := NewConcurrentMap[[2]float64, int](64)
+	anys := NewConcurrentMap[any, int](64)
+	for i := 0; i < 50; i++ {
+		points.Set(point{x: float64(i), y: 0}, i)
+		arrays.Set([2]float64{0, float64(i)}, i)
+		anys.Set(point{x: 0, y: float64(i)}, i)
+	}
+	for i := 0; i < 50; i++ {
+		value, ok := points.Get(point{x: float64(i), y: negativeZero})
+		assert.True(t, ok)
+		assert.Equal(t, i, value)
+		value, ok = arrays.Get([2]float64{negativeZero, float64(i)})
+		assert.True(t, ok)
+		assert.Equal(t, i, value)
+		value, ok = anys.Get(point{x: negativeZero, y: float64(i)})
+		assert.True(t, ok)
+		assert.Equal(t, i, value)
+	}
+}
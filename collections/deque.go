@@ -0,0 +1,188 @@
+package collections
+
+/*
+Deque is a double-ended queue backed by a growable circular slice. The zero value is ready to use.
+It is not thread safe.
+
+Example:
+
+	var d Deque[int]
+	d.PushBack(2)
+	d.PushFront(1)
+	v, _ := d.PopBack() // 2
+
+Deque 是基于可增长的环形切片的双端队列。零值即可使用。非多线程安全。
+*/
+type Deque[T any] struct {
+	items []T
+	head  int // 第一个元素的位置。
+	count int
+}
+
+/*
+NewDeque creates a Deque with room for capacity elements before it grows.
+
+NewDeque 创建在增长前可以容纳 capacity 个元素的 Deque。
+*/
+func NewDeque[T any](capacity int) *Deque[T] {
+	if capacity < 0 {
+		capacity = 0
+	}
+	return &Deque[T]{items: make([]T, capacity)}
+}
+
+/*
+Len returns the number of elements.
+
+Len 返回元素数量。
+*/
+func (d *Deque[T]) Len() int {
+	return d.count
+}
+
+/*
+PushBack adds the value to the back.
+
+PushBack 将值添加到队尾。
+*/
+func (d *Deque[T]) PushBack(value T) {
+	d.grow()
+	d.items[d.index(d.count)] = value
+	d.count++
+}
+
+/*
+PushFront adds the value to the front.
+
+PushFront 将值添加到队首。
+*/
+func (d *Deque[T]) PushFront(value T) {
+	d.grow()
+	d.head = d.index(len(d.items) - 1)
+	d.items[d.head] = value
+	d.count++
+}
+
+/*
+PopFront removes and returns the front value. It returns false if the deque is empty.
+
+PopFront 删除并返回队首的值。队列为空时返回 false。
+*/
+func (d *Deque[T]) PopFront() (value T, ok bool) {
+	if d.count == 0 {
+		return value, false
+	}
+
+	var zero T
+	value, d.items[d.head] = d.items[d.head], zero // 释放引用。
+	d.head = d.index(1)
+	d.count--
+	return value, true
+}
+
+/*
+PopBack removes and returns the back value. It returns false if the deque is empty.
+
+PopBack 删除并返回队尾的值。队列为空时返回 false。
+*/
+func (d *Deque[T]) PopBack() (value T, ok bool) {
+	if d.count == 0 {
+		return value, false
+	}
+
+	var zero T
+	i := d.index(d.count - 1)
+	value, d.items[i] = d.items[i], zero
+	d.count--
+	return value, true
+}
+
+/*
+Front returns the front value without removing it. It returns false if the deque is empty.
+
+Front 返回队首的值但不删除。队列为空时返回 false。
+*/
+func (d *Deque[T]) Front() (value T, ok bool) {
+	if d.count == 0 {
+		return value, false
+	}
+	return d.items[d.head], true
+}
+
+/*
+Back returns the back value without removing it. It returns false if the deque is empty.
+
+Back 返回队尾的值但不删除。队列为空时返回 false。
+*/
+func (d *Deque[T]) Back() (value T, ok bool) {
+	if d.count == 0 {
+		return value, false
+	}
+	return d.items[d.index(d.count-1)], true
+}
+
+/*
+At returns the i-th value from the front. It panics if i is out of range.
+
+At 返回从队首开始的第 i 个值。i 超出范围时 panic。
+*/
+func (d *Deque[T]) At(i int) T {
+	if i < 0 || i >= d.count {
+		panic("collections: deque index out of range")
+	}
+	return d.items[d.index(i)]
+}
+
+/*
+Values returns the values from the front to the back in a new slice.
+
+Values 在新的切片中返回从队首到队尾的值。
+*/
+func (d *Deque[T]) Values() []T {
+	return ringValues(d.items, d.head, d.count)
+}
+
+/*
+Clear removes all values, keeping the allocated room.
+
+Clear 删除所有值，保留已分配的空间。
+*/
+func (d *Deque[T]) Clear() {
+	var zero T
+	for i := range d.items {
+		d.items[i] = zero
+	}
+	d.head, d.count = 0, 0
+}
+
+// index 返回从队首开始第 i 个元素在 items 中的位置。
+func (d *Deque[T]) index(i int) int {
+	return (d.head + i) % len(d.items)
+}
+
+// grow 在 items 已满时将其容量加倍，并使元素从位置 0 开始。
+func (d *Deque[T]) grow() {
+	if d.count < len(d.items) {
+		return
+	}
+
+	size := len(d.items) * 2
+	if size < 8 {
+		size = 8
+	}
+	items := make([]T, size)
+	copy(items, ringValues(d.items, d.head, d.count))
+	d.items, d.head = items, 0
+}
+
+// ringValues 在新的切片中返回环形切片 items 中从 head 开始的 count 个元素。
+func ringValues[T any](items []T, head int, count int) []T {
+	values := make([]T, count)
+	end := head + count
+	if end > len(items) {
+		end = len(items)
+	}
+	n := copy(values, items[head:end])
+	copy(values[n:], items[:count-n])
+	return values
+}
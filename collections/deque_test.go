@@ -0,0 +1,48 @@
+package collections
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeque(t *testing.T) {
+	var d Deque[int]
+	_, ok := d.PopFront()
+	assert.False(t, ok)
+	_, ok = d.Back()
+	assert.False(t, ok)
+
+	// 交替从两端添加，超过初始容量以测试增长。
+	for i := 1; i <= 10; i++ {
+		d.PushBack(i)
+		d.PushFront(-i)
+	}
+	assert.Equal(t, 20, d.Len())
+	assert.Equal(t, []int{-10, -9, -8, -7, -6, -5, -4, -3, -2, -1, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, d.Values())
+	assert.Equal(t, -9, d.At(1))
+
+	front, _ := d.Front()
+	back, _ := d.Back()
+	assert.Equal(t, -10, front)
+	assert.Equal(t, 10, back)
+
+	for i := 10; i >= 1; i-- {
+		v, ok := d.PopBack()
+		assert.True(t, ok)
+		assert.Equal(t, i, v)
+		v, ok = d.PopFront()
+		assert.True(t, ok)
+		assert.Equal(t, -i, v)
+	}
+	assert.Equal(t, 0, d.Len())
+
+	d2 := NewDeque[string](2)
+	d2.PushBack("b")
+	d2.PushFront("a")
+	d2.PushBack("c")
+	assert.Equal(t, []string{"a", "b", "c"}, d2.Values())
+	d2.Clear()
+	assert.Equal(t, []string{}, d2.Values())
+	assert.Panics(t, func() { d2.At(0) })
+}
@@ -0,0 +1,347 @@
+package collections
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+/*
+Clock is the source of the current time for the TTL of [LRU], so the expiry can be tested with simulated time.
+timeutils.Clock, such as timeutils.ManualClock, satisfies it.
+
+Clock 是 [LRU] 的 TTL 使用的当前时间的来源，以便用模拟的时间测试过期。timeutils.Clock，例如 timeutils.ManualClock，满足该接口。
+*/
+type Clock interface {
+	Now() time.Time // Returns the current time.
+}
+
+// systemClock 使用 time.Now()。
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+/*
+EvictReason tells why an entry left an [LRU].
+
+EvictReason 表示条目离开 [LRU] 的原因。
+*/
+type EvictReason int
+
+const (
+	EvictCapacity EvictReason = iota // the least recently used entry was dropped to make room
+	EvictExpired                     // the entry outlived the TTL
+	EvictRemoved                     // the entry was removed by Remove or Purge, or replaced by Set
+)
+
+/*
+LRUOption defines the options for [NewLRU]. See [NewLRUOption] for default settings.
+
+LRUOption 定义了 [NewLRU] 的选项。默认设置参见 [NewLRUOption]。
+*/
+type LRUOption[K comparable, V any] struct {
+	MaxEntries int                                      // the maximum number of entries. 0 or negative means no limit
+	TTL        time.Duration                            // how long an entry lives after it is set. 0 or negative means forever
+	OnEvict    func(key K, value V, reason EvictReason) // called after an entry leaves the cache, outside the lock. nil means no callback
+	Clock      Clock                                    // the source of time for the TTL. nil means the system clock
+}
+
+/*
+NewLRUOption creates a new LRUOption with 1024 entries at most, no TTL and no callback.
+
+NewLRUOption 创建默认的 LRUOption。最多 1024 个条目，没有 TTL 及回调函数。
+*/
+func NewLRUOption[K comparable, V any]() *LRUOption[K, V] {
+	return &LRUOption[K, V]{
+		MaxEntries: 1024,
+		TTL:        0,
+		OnEvict:    nil,
+		Clock:      nil,
+	}
+}
+
+/*
+LRU is a least recently used cache with an optional entry limit and TTL. It is thread safe.
+Expired entries are removed when they are accessed, by Set when room is needed, or by [LRU.RemoveExpired].
+
+Example:
+
+	option := NewLRUOption[string, string]()
+	option.MaxEntries = 2
+	cache := NewLRU(option)
+	cache.Set("a", "1")
+	cache.Set("b", "2")
+	cache.Get("a")
+	cache.Set("c", "3")     // "b" is evicted.
+	_, ok := cache.Get("b") // false
+
+LRU 是最近最少使用缓存，可以限制条目数量及设置 TTL。多线程安全。
+过期的条目在访问时、Set 需要空间时或调用 [LRU.RemoveExpired] 时被删除。
+*/
+type LRU[K comparable, V any] struct {
+	option LRUOption[K, V]
+	clock  Clock
+	items  map[K]*list.Element
+	order  *list.List // 最近使用的在前。
+	lock   sync.Mutex
+}
+
+// lruEntry 是 LRU 中的条目。
+type lruEntry[K comparable, V any] struct {
+	key     K
+	value   V
+	expires time.Time // 零值表示不过期。
+}
+
+// evicted 是离开缓存、等待回调的条目。
+type evicted[K comparable, V any] struct {
+	entry  *lruEntry[K, V]
+	reason EvictReason
+}
+
+/*
+NewLRU creates an LRU cache.
+
+Parameters:
+  - option: The options. nil means [NewLRUOption]. The option is copied, so later changes have no effect.
+
+Returns:
+  - The cache.
+
+NewLRU 创建 LRU 缓存。
+
+参数:
+  - option: 选项。nil 表示 [NewLRUOption]。选项被复制，之后的修改不起作用。
+
+返回:
+  - 缓存。
+*/
+func NewLRU[K comparable, V any](option *LRUOption[K, V]) *LRU[K, V] {
+	if option == nil {
+		option = NewLRUOption[K, V]()
+	}
+
+	clock := option.Clock
+	if clock == nil {
+		clock = systemClock{}
+	}
+
+	return &LRU[K, V]{
+		option: *option,
+		clock:  clock,
+		items:  map[K]*list.Element{},
+		order:  list.New(),
+	}
+}
+
+/*
+Get returns the value of the key and marks it as the most recently used.
+
+Get 返回键对应的值，并将其标记为最近使用的。
+*/
+func (c *LRU[K, V]) Get(key K) (V, bool) {
+	return c.get(key, true)
+}
+
+/*
+Peek returns the value of the key without changing the order.
+
+Peek 返回键对应的值，但不改变顺序。
+*/
+func (c *LRU[K, V]) Peek(key K) (V, bool) {
+	return c.get(key, false)
+}
+
+/*
+Contains reports whether the key is in the cache and not expired, without changing the order.
+
+Contains 检查键是否在缓存中且未过期，不改变顺序。
+*/
+func (c *LRU[K, V]) Contains(key K) bool {
+	_, ok := c.get(key, false)
+	return ok
+}
+
+func (c *LRU[K, V]) get(key K, promote bool) (value V, ok bool) {
+	var removed []evicted[K, V]
+	defer func() { c.notify(removed) }()
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	element, found := c.items[key]
+	if !found {
+		return value, false
+	}
+
+	entry := element.Value.(*lruEntry[K, V])
+	if c.expired(entry, c.clock.Now()) {
+		removed = append(removed, c.remove(element, EvictExpired))
+		return value, false
+	}
+
+	if promote {
+		c.order.MoveToFront(element)
+	}
+	return entry.value, true
+}
+
+/*
+Set adds or replaces the value of the key as the most recently used, with the TTL of the options.
+When the cache is full, expired entries are removed first, then the least recently used ones.
+
+Set 添加或替换键对应的值，并将其作为最近使用的，使用选项中的 TTL。
+缓存已满时，先删除过期的条目，再删除最近最少使用的条目。
+*/
+func (c *LRU[K, V]) Set(key K, value V) {
+	c.SetWithTTL(key, value, c.option.TTL)
+}
+
+/*
+SetWithTTL is like [LRU.Set], with the TTL of this entry. 0 or negative means forever.
+
+SetWithTTL 与 [LRU.Set] 相同，但使用该条目自己的 TTL。0 或负数表示不过期。
+*/
+func (c *LRU[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	var removed []evicted[K, V]
+	defer func() { c.notify(removed) }()
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := c.clock.Now()
+	entry := &lruEntry[K, V]{key: key, value: value}
+	if ttl > 0 {
+		entry.expires = now.Add(ttl)
+	}
+
+	if element, found := c.items[key]; found {
+		removed = append(removed, c.remove(element, EvictRemoved))
+	}
+
+	if c.option.MaxEntries > 0 && c.order.Len() >= c.option.MaxEntries {
+		removed = append(removed, c.removeExpired(now)...)
+	}
+	for c.option.MaxEntries > 0 && c.order.Len() >= c.option.MaxEntries {
+		removed = append(removed, c.remove(c.order.Back(), EvictCapacity))
+	}
+
+	c.items[key] = c.order.PushFront(entry)
+}
+
+/*
+Remove removes the key and returns whether it was in the cache.
+
+Remove 删除键，并返回它是否在缓存中。
+*/
+func (c *LRU[K, V]) Remove(key K) bool {
+	var removed []evicted[K, V]
+	defer func() { c.notify(removed) }()
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	element, found := c.items[key]
+	if found {
+		removed = append(removed, c.remove(element, EvictRemoved))
+	}
+	return found
+}
+
+/*
+RemoveExpired removes all expired entries and returns the number removed.
+
+RemoveExpired 删除所有过期的条目，返回删除的数量。
+*/
+func (c *LRU[K, V]) RemoveExpired() int {
+	var removed []evicted[K, V]
+	defer func() { c.notify(removed) }()
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	removed = c.removeExpired(c.clock.Now())
+	return len(removed)
+}
+
+/*
+Purge removes all entries.
+
+Purge 删除所有条目。
+*/
+func (c *LRU[K, V]) Purge() {
+	var removed []evicted[K, V]
+	defer func() { c.notify(removed) }()
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for c.order.Len() > 0 {
+		removed = append(removed, c.remove(c.order.Back(), EvictRemoved))
+	}
+}
+
+/*
+Len returns the number of entries, including expired ones not yet removed.
+
+Len 返回条目数量，包括尚未删除的过期条目。
+*/
+func (c *LRU[K, V]) Len() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.order.Len()
+}
+
+/*
+Keys returns the keys of the entries not expired, from the most recently used to the least.
+
+Keys 返回未过期条目的键，从最近使用的到最近最少使用的。
+*/
+func (c *LRU[K, V]) Keys() []K {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := c.clock.Now()
+	keys := make([]K, 0, c.order.Len())
+	for element := c.order.Front(); element != nil; element = element.Next() {
+		if entry := element.Value.(*lruEntry[K, V]); !c.expired(entry, now) {
+			keys = append(keys, entry.key)
+		}
+	}
+	return keys
+}
+
+func (c *LRU[K, V]) expired(entry *lruEntry[K, V], now time.Time) bool {
+	return !entry.expires.IsZero() && !now.Before(entry.expires)
+}
+
+// remove 删除条目，调用者必须持有锁。
+func (c *LRU[K, V]) remove(element *list.Element, reason EvictReason) evicted[K, V] {
+	entry := c.order.Remove(element).(*lruEntry[K, V])
+	delete(c.items, entry.key)
+	return evicted[K, V]{entry: entry, reason: reason}
+}
+
+// removeExpired 删除所有过期的条目，调用者必须持有锁。
+func (c *LRU[K, V]) removeExpired(now time.Time) []evicted[K, V] {
+	var removed []evicted[K, V]
+	for element := c.order.Back(); element != nil; {
+		previous := element.Prev()
+		if c.expired(element.Value.(*lruEntry[K, V]), now) {
+			removed = append(removed, c.remove(element, EvictExpired))
+		}
+		element = previous
+	}
+	return removed
+}
+
+// notify 在锁外调用回调函数，使回调函数可以再访问缓存。
+func (c *LRU[K, V]) notify(removed []evicted[K, V]) {
+	if c.option.OnEvict == nil {
+		return
+	}
+	for _, e := range removed {
+		c.option.OnEvict(e.entry.key, e.entry.value, e.reason)
+	}
+}
@@ -0,0 +1,115 @@
+package collections
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRUCapacity(t *testing.T) {
+	evictions := map[string]EvictReason{}
+	option := NewLRUOption[string, int]()
+	option.MaxEntries = 2
+	option.OnEvict = func(key string, value int, reason EvictReason) {
+		evictions[key] = reason
+	}
+	cache := NewLRU(option)
+
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	value, ok := cache.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+
+	cache.Set("c", 3)
+	assert.False(t, cache.Contains("b"))
+	assert.Equal(t, []string{"c", "a"}, cache.Keys())
+	assert.Equal(t, map[string]EvictReason{"b": EvictCapacity}, evictions)
+
+	// Peek 不改变顺序，所以 "a" 仍然最先被淘汰。
+	_, ok = cache.Peek("a")
+	assert.True(t, ok)
+	cache.Set("d", 4)
+	assert.Equal(t, []string{"d", "c"}, cache.Keys())
+	assert.Equal(t, EvictCapacity, evictions["a"])
+
+	cache.Set("c", 30)
+	assert.Equal(t, EvictRemoved, evictions["c"])
+	assert.Equal(t, []string{"c", "d"}, cache.Keys())
+
+	assert.True(t, cache.Remove("d"))
+	assert.False(t, cache.Remove("d"))
+	cache.Purge()
+	assert.Equal(t, 0, cache.Len())
+}
+
+// manualClock 是只在调用 Advance 时前进的 Clock。
+type manualClock struct {
+	now time.Time
+}
+
+func (c *manualClock) Now() time.Time          { return c.now }
+func (c *manualClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func TestLRUTTL(t *testing.T) {
+	clock := &manualClock{now: time.Date(2023, 1, 5, 0, 0, 0, 0, time.UTC)}
+	evictions := map[string]EvictReason{}
+	cache := NewLRU(&LRUOption[string, int]{
+		MaxEntries: 2,
+		TTL:        time.Minute,
+		OnEvict:    func(key string, value int, reason EvictReason) { evictions[key] = reason },
+		Clock:      clock,
+	})
+
+	cache.Set("a", 1)
+	cache.SetWithTTL("b", 2, 0)
+	clock.Advance(time.Minute)
+
+	_, ok := cache.Get("a")
+	assert.False(t, ok)
+	assert.Equal(t, EvictExpired, evictions["a"])
+	_, ok = cache.Get("b")
+	assert.True(t, ok)
+
+	// 缓存已满时先删除过期的条目。
+	cache.Set("c", 3)
+	clock.Advance(time.Minute)
+	assert.Equal(t, []string{"b"}, cache.Keys())
+	assert.Equal(t, 2, cache.Len())
+	cache.Set("d", 4)
+	assert.Equal(t, EvictExpired, evictions["c"])
+	assert.Equal(t, []string{"d", "b"}, cache.Keys())
+
+	clock.Advance(time.Minute)
+	assert.Equal(t, 1, cache.RemoveExpired())
+	assert.Equal(t, 1, cache.Len())
+}
+
+func TestLRUConcurrent(t *testing.T) {
+	option := NewLRUOption[int, int]()
+	option.MaxEntries = 100
+	// 回调函数在锁外调用，可以再访问缓存。
+	var cache *LRU[int, int]
+	option.OnEvict = func(key int, value int, reason EvictReason) { cache.Contains(key) }
+	cache = NewLRU(option)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				cache.Set(n*1000+j, j)
+				cache.Get(n*1000 + j/2)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, 100, cache.Len())
+	assert.Equal(t, 1024, NewLRU[string, string](nil).option.MaxEntries)
+	assert.Equal(t, "[]", fmt.Sprint(NewLRU[string, string](nil).Keys()))
+}
@@ -0,0 +1,137 @@
+package collections
+
+/*
+RingBuffer keeps the latest values up to a fixed capacity. When it is full, a new value overwrites the oldest one.
+It suits sliding windows and "tail" style buffers. It is not thread safe.
+
+Example:
+
+	r := NewRingBuffer[int](3)
+	for i := 1; i <= 5; i++ {
+		r.Push(i)
+	}
+	values := r.Values() // [3 4 5]
+
+RingBuffer 保存最新的值，数量不超过固定的容量。已满时新值覆盖最旧的值。
+适用于滑动窗口及 "tail" 形式的缓冲区。非多线程安全。
+*/
+type RingBuffer[T any] struct {
+	items []T
+	head  int // 最旧元素的位置。
+	count int
+}
+
+/*
+NewRingBuffer creates a RingBuffer. It panics if capacity is not positive.
+
+NewRingBuffer 创建 RingBuffer。capacity 不是正数时 panic。
+*/
+func NewRingBuffer[T any](capacity int) *RingBuffer[T] {
+	if capacity <= 0 {
+		panic("collections: ring buffer capacity must be positive")
+	}
+	return &RingBuffer[T]{items: make([]T, capacity)}
+}
+
+/*
+Len returns the number of values.
+
+Len 返回值的数量。
+*/
+func (r *RingBuffer[T]) Len() int {
+	return r.count
+}
+
+/*
+Cap returns the capacity.
+
+Cap 返回容量。
+*/
+func (r *RingBuffer[T]) Cap() int {
+	return len(r.items)
+}
+
+/*
+Full reports whether the next Push will overwrite the oldest value.
+
+Full 检查下一次 Push 是否会覆盖最旧的值。
+*/
+func (r *RingBuffer[T]) Full() bool {
+	return r.count == len(r.items)
+}
+
+/*
+Push adds the value as the newest.
+
+Returns:
+  - The oldest value overwritten, if the buffer was full.
+  - Whether a value was overwritten.
+
+Push 将值添加为最新的值。
+
+返回:
+  - 缓冲区已满时被覆盖的最旧的值。
+  - 是否覆盖了值。
+*/
+func (r *RingBuffer[T]) Push(value T) (overwritten T, ok bool) {
+	if r.Full() {
+		overwritten, r.items[r.head] = r.items[r.head], value
+		r.head = (r.head + 1) % len(r.items)
+		return overwritten, true
+	}
+
+	r.items[(r.head+r.count)%len(r.items)] = value
+	r.count++
+	return overwritten, false
+}
+
+/*
+Pop removes and returns the oldest value. It returns false if the buffer is empty.
+
+Pop 删除并返回最旧的值。缓冲区为空时返回 false。
+*/
+func (r *RingBuffer[T]) Pop() (value T, ok bool) {
+	if r.count == 0 {
+		return value, false
+	}
+
+	var zero T
+	value, r.items[r.head] = r.items[r.head], zero // 释放引用。
+	r.head = (r.head + 1) % len(r.items)
+	r.count--
+	return value, true
+}
+
+/*
+At returns the i-th value from the oldest. It panics if i is out of range.
+
+At 返回从最旧的值开始的第 i 个值。i 超出范围时 panic。
+*/
+func (r *RingBuffer[T]) At(i int) T {
+	if i < 0 || i >= r.count {
+		panic("collections: ring buffer index out of range")
+	}
+	return r.items[(r.head+i)%len(r.items)]
+}
+
+/*
+Values returns the values from the oldest to the newest in a new slice.
+
+Values 在新的切片中返回从最旧到最新的值。
+*/
+func (r *RingBuffer[T]) Values() []T {
+	return ringValues(r.items, r.head, r.count)
+}
+
+/*
+Clear removes all values.
+
+Clear 删除所有值。
+*/
+func (r *RingBuffer[T]) Clear() {
+	var zero T
+	for i := range r.items {
+		r.items[i] = zero
+	}
+	r.head, r.count = 0, 0
+}
@@ -0,0 +1,38 @@
+package collections
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRingBuffer(t *testing.T) {
+	r := NewRingBuffer[int](3)
+	assert.Equal(t, 3, r.Cap())
+
+	for i := 1; i <= 3; i++ {
+		_, ok := r.Push(i)
+		assert.False(t, ok)
+	}
+	assert.True(t, r.Full())
+
+	overwritten, ok := r.Push(4)
+	assert.True(t, ok)
+	assert.Equal(t, 1, overwritten)
+	r.Push(5)
+	assert.Equal(t, []int{3, 4, 5}, r.Values())
+	assert.Equal(t, 4, r.At(1))
+
+	v, ok := r.Pop()
+	assert.True(t, ok)
+	assert.Equal(t, 3, v)
+	r.Push(6)
+	assert.Equal(t, []int{4, 5, 6}, r.Values())
+
+	r.Clear()
+	assert.Equal(t, 0, r.Len())
+	_, ok = r.Pop()
+	assert.False(t, ok)
+	assert.Panics(t, func() { r.At(0) })
+	assert.Panics(t, func() { NewRingBuffer[int](0) })
+}
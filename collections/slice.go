@@ -0,0 +1,379 @@
+package collections
+
+/*
+Map returns a new slice with fn applied to each element.
+
+Example:
+
+	names := Map(files, func(f os.FileInfo) string { return f.Name() })
+
+Map 返回对每个元素调用 fn 得到的新切片。
+*/
+func Map[T any, U any](slice []T, fn func(T) U) []U {
+	result := make([]U, len(slice))
+	for i, v := range slice {
+		result[i] = fn(v)
+	}
+	return result
+}
+
+/*
+Filter returns a new slice with the elements for which keep returns true, in the original order.
+
+Filter 返回由 keep 返回 true 的元素组成的新切片，保持原来的顺序。
+*/
+func Filter[T any](slice []T, keep func(T) bool) []T {
+	result := []T{}
+	for _, v := range slice {
+		if keep(v) {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+/*
+Reduce combines the elements from the first to the last with fn, starting from initial.
+
+Example:
+
+	total := Reduce(extensions, int64(0), func(sum int64, ext FileExtension) int64 { return sum + ext.Size })
+
+Reduce 从 initial 开始，使用 fn 从第一个到最后一个合并元素。
+*/
+func Reduce[T any, A any](slice []T, initial A, fn func(A, T) A) A {
+	result := initial
+	for _, v := range slice {
+		result = fn(result, v)
+	}
+	return result
+}
+
+/*
+Any reports whether fn returns true for at least one element. It is false for an empty slice.
+
+Any 检查是否至少有一个元素使 fn 返回 true。空切片返回 false。
+*/
+func Any[T any](slice []T, fn func(T) bool) bool {
+	for _, v := range slice {
+		if fn(v) {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+All reports whether fn returns true for every element. It is true for an empty slice.
+
+All 检查是否所有元素都使 fn 返回 true。空切片返回 true。
+*/
+func All[T any](slice []T, fn func(T) bool) bool {
+	for _, v := range slice {
+		if !fn(v) {
+			return false
+		}
+	}
+	return true
+}
+
+/*
+Find returns the first element for which fn returns true.
+
+Returns:
+  - The element, or the zero value if none is found.
+  - Whether an element is found.
+
+Find 返回第一个使 fn 返回 true 的元素。
+
+返回:
+  - 该元素。没有找到时为零值。
+  - 是否找到。
+*/
+func Find[T any](slice []T, fn func(T) bool) (T, bool) {
+	for _, v := range slice {
+		if fn(v) {
+			return v, true
+		}
+	}
+
+	var zero T
+	return zero, false
+}
+
+/*
+Count returns the number of elements for which fn returns true.
+
+Count 返回使 fn 返回 true 的元素数量。
+*/
+func Count[T any](slice []T, fn func(T) bool) int {
+	n := 0
+	for _, v := range slice {
+		if fn(v) {
+			n++
+		}
+	}
+	return n
+}
+
+/*
+Chunk splits the slice into chunks of n elements, the last one may be shorter.
+The chunks share the memory of the slice. It panics if n is not positive.
+
+Example:
+
+	for _, batch := range Chunk(files, 100) {
+		jobs <- batch
+	}
+
+Chunk 将切片分为每块 n 个元素，最后一块可以较短。各块与原切片共享内存。n 不是正数时 panic。
+*/
+func Chunk[T any](slice []T, n int) [][]T {
+	if n <= 0 {
+		panic("collections: chunk size must be positive")
+	}
+
+	chunks := make([][]T, 0, (len(slice)+n-1)/n)
+	for start := 0; start < len(slice); start += n {
+		end := start + n
+		if end > len(slice) {
+			end = len(slice)
+		}
+		// 限制容量，使对块的 append 不会覆盖下一块。
+		chunks = append(chunks, slice[start:end:end])
+	}
+	return chunks
+}
+
+/*
+Flatten joins the slices into a new slice.
+
+Flatten 将多个切片连接为一个新切片。
+*/
+func Flatten[T any](slices [][]T) []T {
+	n := 0
+	for _, s := range slices {
+		n += len(s)
+	}
+
+	result := make([]T, 0, n)
+	for _, s := range slices {
+		result = append(result, s...)
+	}
+	return result
+}
+
+/*
+Pair is two values of possibly different types, as made by [Zip].
+
+Pair 是两个类型可以不同的值，由 [Zip] 生成。
+*/
+type Pair[A any, B any] struct {
+	First  A
+	Second B
+}
+
+/*
+Zip pairs the elements of a and b by index. The result is as long as the shorter one.
+
+Zip 按序号将 a 及 b 的元素配对。结果的长度与较短的一个相同。
+*/
+func Zip[A any, B any](a []A, b []B) []Pair[A, B] {
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+
+	result := make([]Pair[A, B], n)
+	for i := 0; i < n; i++ {
+		result[i] = Pair[A, B]{First: a[i], Second: b[i]}
+	}
+	return result
+}
+
+/*
+Unzip is the inverse of [Zip], splitting the pairs into two slices.
+
+Unzip 是 [Zip] 的逆运算，将配对拆分为两个切片。
+*/
+func Unzip[A any, B any](pairs []Pair[A, B]) ([]A, []B) {
+	a := make([]A, len(pairs))
+	b := make([]B, len(pairs))
+	for i, p := range pairs {
+		a[i], b[i] = p.First, p.Second
+	}
+	return a, b
+}
+
+/*
+Interleave takes one element from each slice in turn, skipping the slices that run out.
+
+Example:
+
+	Interleave([]int{1, 2, 3}, []int{10, 20}) // [1 10 2 20 3]
+
+Interleave 依次从每个切片中各取一个元素，跳过已取完的切片。
+*/
+func Interleave[T any](slices ...[]T) []T {
+	n, longest := 0, 0
+	for _, s := range slices {
+		n += len(s)
+		if len(s) > longest {
+			longest = len(s)
+		}
+	}
+
+	result := make([]T, 0, n)
+	for i := 0; i < longest; i++ {
+		for _, s := range slices {
+			if i < len(s) {
+				result = append(result, s[i])
+			}
+		}
+	}
+	return result
+}
+
+/*
+GroupBy groups the elements by the key returned by keyFn. Each group keeps the original order.
+
+Example:
+
+	bySize := GroupBy(files, func(f os.FileInfo) int64 { return f.Size() })
+
+GroupBy 按 keyFn 返回的键将元素分组。各组保持原来的顺序。
+*/
+func GroupBy[T any, K comparable](slice []T, keyFn func(T) K) map[K][]T {
+	groups := map[K][]T{}
+	for _, v := range slice {
+		key := keyFn(v)
+		groups[key] = append(groups[key], v)
+	}
+	return groups
+}
+
+/*
+Partition splits the elements by pred, keeping the original order.
+
+Returns:
+  - The elements for which pred returns true.
+  - The others.
+
+Partition 按 pred 拆分元素，保持原来的顺序。
+
+返回:
+  - 使 pred 返回 true 的元素。
+  - 其余的元素。
+*/
+func Partition[T any](slice []T, pred func(T) bool) ([]T, []T) {
+	matched, rest := []T{}, []T{}
+	for _, v := range slice {
+		if pred(v) {
+			matched = append(matched, v)
+		} else {
+			rest = append(rest, v)
+		}
+	}
+	return matched, rest
+}
+
+/*
+Distinct returns a new slice without duplicates, keeping the first occurrence of each element in the original order.
+
+Distinct 返回去掉重复元素的新切片，保留每个元素第一次出现的位置及原来的顺序。
+*/
+func Distinct[T comparable](slice []T) []T {
+	return DistinctBy(slice, func(v T) T { return v })
+}
+
+/*
+DistinctBy is like [Distinct], treating elements with the same key returned by keyFn as duplicates.
+
+Example:
+
+	// Files from overlapping roots, compared by absolute path.
+	files = DistinctBy(files, func(f string) string { p, _ := filepath.Abs(f); return p })
+
+DistinctBy 与 [Distinct] 相同，但将 keyFn 返回相同键的元素视为重复。
+*/
+func DistinctBy[T any, K comparable](slice []T, keyFn func(T) K) []T {
+	seen := make(map[K]struct{}, len(slice))
+	result := []T{}
+	for _, v := range slice {
+		key := keyFn(v)
+		if _, ok := seen[key]; !ok {
+			seen[key] = struct{}{}
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+/*
+Diff compares two versions of a list, such as the files of a directory before and after a change.
+
+Returns:
+  - added: The elements of b not in a, in the order of b.
+  - removed: The elements of a not in b, in the order of a.
+
+Duplicates are kept.
+
+Diff 比较列表的两个版本，例如目录在变化前后的文件。
+
+返回:
+  - added: 在 b 中但不在 a 中的元素，按 b 的顺序。
+  - removed: 在 a 中但不在 b 中的元素，按 a 的顺序。
+
+保留重复的元素。
+*/
+func Diff[T comparable](a, b []T) (added []T, removed []T) {
+	return DiffBy(a, b, func(v T) T { return v })
+}
+
+/*
+DiffBy is like [Diff], comparing elements by the key returned by keyFn, such as the relative path of a file.
+
+DiffBy 与 [Diff] 相同，但按 keyFn 返回的键比较元素，例如文件的相对路径。
+*/
+func DiffBy[T any, K comparable](a, b []T, keyFn func(T) K) (added []T, removed []T) {
+	return exceptBy(b, a, keyFn), exceptBy(a, b, keyFn)
+}
+
+/*
+Intersect returns the elements of a that are also in b, in the order of a. Duplicates in a are kept.
+
+Intersect 返回 a 中同时在 b 中的元素，按 a 的顺序。保留 a 中重复的元素。
+*/
+func Intersect[T comparable](a, b []T) []T {
+	return IntersectBy(a, b, func(v T) T { return v })
+}
+
+/*
+IntersectBy is like [Intersect], comparing elements by the key returned by keyFn.
+
+IntersectBy 与 [Intersect] 相同，但按 keyFn 返回的键比较元素。
+*/
+func IntersectBy[T any, K comparable](a, b []T, keyFn func(T) K) []T {
+	keys := keySet(b, keyFn)
+	return Filter(a, func(v T) bool {
+		_, ok := keys[keyFn(v)]
+		return ok
+	})
+}
+
+// exceptBy 返回 a 中键不在 b 中的元素。
+func exceptBy[T any, K comparable](a, b []T, keyFn func(T) K) []T {
+	keys := keySet(b, keyFn)
+	return Filter(a, func(v T) bool {
+		_, ok := keys[keyFn(v)]
+		return !ok
+	})
+}
+
+func keySet[T any, K comparable](slice []T, keyFn func(T) K) map[K]struct{} {
+	keys := make(map[K]struct{}, len(slice))
+	for _, v := range slice {
+		keys[keyFn(v)] = struct{}{}
+	}
+	return keys
+}
@@ -0,0 +1,118 @@
+package collections
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapFilterReduce(t *testing.T) {
+	numbers := []int{1, 2, 3, 4, 5}
+
+	assert.Equal(t, []string{"1", "2", "3", "4", "5"}, Map(numbers, strconv.Itoa))
+	assert.Equal(t, []string{}, Map([]int{}, strconv.Itoa))
+
+	even := func(n int) bool { return n%2 == 0 }
+	assert.Equal(t, []int{2, 4}, Filter(numbers, even))
+	assert.Equal(t, []int{}, Filter(nil, even))
+
+	assert.Equal(t, 15, Reduce(numbers, 0, func(sum int, n int) int { return sum + n }))
+	assert.Equal(t, "12345", Reduce(numbers, "", func(s string, n int) string { return s + strconv.Itoa(n) }))
+}
+
+func TestAnyAllFindCount(t *testing.T) {
+	words := []string{"go", "rust", "zig"}
+	short := func(s string) bool { return len(s) <= 3 }
+
+	assert.True(t, Any(words, short))
+	assert.False(t, All(words, short))
+	assert.False(t, Any([]string{}, short))
+	assert.True(t, All([]string{}, short))
+	assert.Equal(t, 2, Count(words, short))
+
+	word, ok := Find(words, func(s string) bool { return len(s) == 4 })
+	assert.True(t, ok)
+	assert.Equal(t, "rust", word)
+	word, ok = Find(words, func(s string) bool { return s == "c" })
+	assert.False(t, ok)
+	assert.Equal(t, "", word)
+}
+
+func TestChunkFlatten(t *testing.T) {
+	numbers := []int{1, 2, 3, 4, 5}
+
+	chunks := Chunk(numbers, 2)
+	assert.Equal(t, [][]int{{1, 2}, {3, 4}, {5}}, chunks)
+	assert.Equal(t, [][]int{{1, 2, 3, 4, 5}}, Chunk(numbers, 10))
+	assert.Equal(t, [][]int{}, Chunk([]int{}, 3))
+	assert.Panics(t, func() { Chunk(numbers, 0) })
+
+	// 对块的 append 不影响原切片。
+	_ = append(chunks[0], 100)
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, numbers)
+
+	assert.Equal(t, numbers, Flatten(chunks))
+	assert.Equal(t, []int{}, Flatten[int](nil))
+}
+
+func TestZipInterleave(t *testing.T) {
+	pairs := Zip([]string{"a", "b", "c"}, []int{1, 2})
+	assert.Equal(t, []Pair[string, int]{{"a", 1}, {"b", 2}}, pairs)
+
+	letters, numbers := Unzip(pairs)
+	assert.Equal(t, []string{"a", "b"}, letters)
+	assert.Equal(t, []int{1, 2}, numbers)
+
+	assert.Equal(t, []int{1, 10, 100, 2, 20, 3}, Interleave([]int{1, 2, 3}, []int{10, 20}, []int{100}))
+	assert.Equal(t, []int{}, Interleave[int]())
+}
+
+func TestGroupByPartition(t *testing.T) {
+	words := []string{"go", "rust", "zig", "c", "java", "lua"}
+
+	groups := GroupBy(words, func(s string) int { return len(s) })
+	assert.Equal(t, map[int][]string{1: {"c"}, 2: {"go"}, 3: {"zig", "lua"}, 4: {"rust", "java"}}, groups)
+	assert.Equal(t, map[int][]string{}, GroupBy([]string{}, func(s string) int { return len(s) }))
+
+	short, long := Partition(words, func(s string) bool { return len(s) <= 2 })
+	assert.Equal(t, []string{"go", "c"}, short)
+	assert.Equal(t, []string{"rust", "zig", "java", "lua"}, long)
+}
+
+func TestDistinct(t *testing.T) {
+	assert.Equal(t, []int{3, 1, 2}, Distinct([]int{3, 1, 3, 2, 1}))
+	assert.Equal(t, []int{}, Distinct[int](nil))
+
+	files := []string{"a/x.txt", "b/X.TXT", "a/y.txt", "A/Y.txt"}
+	assert.Equal(t, []string{"a/x.txt", "b/X.TXT", "a/y.txt"}, DistinctBy(files, strings.ToLower))
+}
+
+func TestDiffIntersect(t *testing.T) {
+	before := []string{"a.txt", "b.txt", "c.txt"}
+	after := []string{"c.txt", "d.txt", "a.txt", "e.txt"}
+
+	added, removed := Diff(before, after)
+	assert.Equal(t, []string{"d.txt", "e.txt"}, added)
+	assert.Equal(t, []string{"b.txt"}, removed)
+	assert.Equal(t, []string{"a.txt", "c.txt"}, Intersect(before, after))
+
+	added, removed = Diff(before, before)
+	assert.Equal(t, []string{}, added)
+	assert.Equal(t, []string{}, removed)
+	assert.Equal(t, []int{1, 1}, Intersect([]int{1, 2, 1}, []int{1}))
+
+	type entry struct {
+		path string
+		size int64
+	}
+	manifest := []entry{{"a", 1}, {"b", 2}}
+	files := []entry{{"A", 10}, {"c", 3}}
+	byPath := func(e entry) string { return strings.ToLower(e.path) }
+
+	added2, removed2 := DiffBy(manifest, files, byPath)
+	assert.Equal(t, []entry{{"c", 3}}, added2)
+	assert.Equal(t, []entry{{"b", 2}}, removed2)
+	assert.Equal(t, []entry{{"a", 1}}, IntersectBy(manifest, files, byPath))
+}
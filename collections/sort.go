@@ -0,0 +1,164 @@
+package collections
+
+import "sort"
+
+/*
+Ordered is the constraint of types that support <, such as numbers and strings.
+
+Ordered 是支持 < 运算的类型约束，例如数字及字符串。
+*/
+type Ordered interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64 | ~string
+}
+
+/*
+SortKey is one level of ordering for [SortBy] and [SortStableBy], made by [Asc], [Desc] or [By].
+
+SortKey 是 [SortBy] 及 [SortStableBy] 的一级排序条件，由 [Asc]、[Desc] 或 [By] 创建。
+*/
+type SortKey[T any] struct {
+	compare func(a, b T) int
+}
+
+/*
+Asc orders the elements by the key returned by key, ascending.
+
+Asc 按 key 返回的键升序排列元素。
+*/
+func Asc[T any, K Ordered](key func(T) K) SortKey[T] {
+	return SortKey[T]{compare: func(a, b T) int {
+		return compareOrdered(key(a), key(b))
+	}}
+}
+
+/*
+Desc orders the elements by the key returned by key, descending.
+
+Desc 按 key 返回的键降序排列元素。
+*/
+func Desc[T any, K Ordered](key func(T) K) SortKey[T] {
+	return SortKey[T]{compare: func(a, b T) int {
+		return compareOrdered(key(b), key(a))
+	}}
+}
+
+/*
+By orders the elements by compare, which returns a negative number if a comes first,
+a positive number if b comes first, and 0 if they are equal, such as common.CompareVersions.
+
+By 按 compare 排列元素。a 在前时 compare 返回负数，b 在前时返回正数，相等时返回 0，例如 common.CompareVersions。
+*/
+func By[T any](compare func(a, b T) int) SortKey[T] {
+	return SortKey[T]{compare: compare}
+}
+
+/*
+SortBy sorts the slice in place by the keys. Later keys break the ties of earlier ones.
+The order of equal elements is not kept. See [SortStableBy].
+
+Example:
+
+	// By count descending, then by size descending.
+	SortBy(extensions,
+		Desc(func(e FileExtension) int { return e.Count }),
+		Desc(func(e FileExtension) int64 { return e.Size }))
+
+SortBy 按排序条件原地排列切片。后面的条件用于区分前面条件相等的元素。不保持相等元素的顺序，参见 [SortStableBy]。
+*/
+func SortBy[T any](slice []T, keys ...SortKey[T]) {
+	sort.Slice(slice, lessBy(slice, keys))
+}
+
+/*
+SortStableBy is like [SortBy], keeping the original order of equal elements.
+
+SortStableBy 与 [SortBy] 相同，但保持相等元素原来的顺序。
+*/
+func SortStableBy[T any](slice []T, keys ...SortKey[T]) {
+	sort.SliceStable(slice, lessBy(slice, keys))
+}
+
+// lessBy 返回按 keys 依次比较的 less 函数。
+func lessBy[T any](slice []T, keys []SortKey[T]) func(i, j int) bool {
+	return func(i, j int) bool {
+		for _, key := range keys {
+			if result := key.compare(slice[i], slice[j]); result != 0 {
+				return result < 0
+			}
+		}
+		return false
+	}
+}
+
+func compareOrdered[K Ordered](a, b K) int {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+	return 0
+}
+
+/*
+BinarySearchBy searches the target in the slice sorted in ascending order of compare.
+
+Parameters:
+  - slice: The slice, sorted so that compare(slice[i], target) is first negative, then 0, then positive.
+  - target: The value to search, which may be a key of the elements.
+  - compare: Returns a negative number if the element comes before target, 0 if equal, and a positive number if after.
+
+Returns:
+  - The position of the first equal element, or where target would be inserted.
+  - Whether an equal element is found.
+
+Example:
+
+	i, found := BinarySearchBy(extensions, ".go", func(e FileExtension, name string) int {
+		return strings.Compare(e.Name, name)
+	})
+
+BinarySearchBy 在按 compare 升序排列的切片中查找 target。
+
+参数:
+  - slice: 切片。排列顺序使 compare(slice[i], target) 先为负数，然后为 0，最后为正数。
+  - target: 要查找的值，可以是元素的键。
+  - compare: 元素在 target 之前时返回负数，相等时返回 0，在之后时返回正数。
+
+返回:
+  - 第一个相等元素的位置，或者 target 应该插入的位置。
+  - 是否找到相等的元素。
+*/
+func BinarySearchBy[T any, E any](slice []T, target E, compare func(T, E) int) (int, bool) {
+	i := sort.Search(len(slice), func(i int) bool {
+		return compare(slice[i], target) >= 0
+	})
+	return i, i < len(slice) && compare(slice[i], target) == 0
+}
+
+/*
+InsertSorted inserts the value into the slice sorted in ascending order of compare, and returns the new slice.
+The value is placed after the equal elements, so inserting one by one keeps the order of arrival.
+
+Example:
+
+	// Keep the 10 most frequent extensions in descending order of count.
+	top = InsertSorted(top, ext, func(a, b FileExtension) int { return b.Count - a.Count })
+	if len(top) > 10 {
+		top = top[:10]
+	}
+
+InsertSorted 将值插入按 compare 升序排列的切片，返回新的切片。值位于相等的元素之后，所以逐个插入时保持到达的顺序。
+*/
+func InsertSorted[T any](slice []T, value T, compare func(a, b T) int) []T {
+	i := sort.Search(len(slice), func(i int) bool {
+		return compare(slice[i], value) > 0
+	})
+
+	var zero T
+	slice = append(slice, zero)
+	copy(slice[i+1:], slice[i:])
+	slice[i] = value
+	return slice
+}
@@ -0,0 +1,87 @@
+package collections
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type sortItem struct {
+	name  string
+	count int
+	size  int64
+}
+
+func TestSortBy(t *testing.T) {
+	items := []sortItem{
+		{"b", 2, 10}, {"a", 2, 30}, {"c", 1, 50}, {"d", 2, 30},
+	}
+
+	SortBy(items,
+		Desc(func(i sortItem) int { return i.count }),
+		Desc(func(i sortItem) int64 { return i.size }),
+		Asc(func(i sortItem) string { return i.name }))
+	assert.Equal(t, []sortItem{{"a", 2, 30}, {"d", 2, 30}, {"b", 2, 10}, {"c", 1, 50}}, items)
+
+	SortBy(items, Asc(func(i sortItem) int64 { return i.size }))
+	assert.Equal(t, "b", items[0].name)
+	assert.Equal(t, "c", items[3].name)
+}
+
+func TestSortStableBy(t *testing.T) {
+	words := []string{"Go", "c", "rust", "C", "go", "Zig"}
+
+	// 只按小写比较，相等的元素保持原来的顺序。
+	SortStableBy(words, By(func(a, b string) int {
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	}))
+	assert.Equal(t, []string{"c", "C", "Go", "go", "rust", "Zig"}, words)
+
+	SortStableBy(words, Desc(func(s string) int { return len(s) }))
+	assert.Equal(t, []string{"rust", "Zig", "Go", "go", "c", "C"}, words)
+
+	// 没有排序条件时不改变顺序。
+	SortStableBy(words)
+	assert.Equal(t, []string{"rust", "Zig", "Go", "go", "c", "C"}, words)
+}
+
+func TestBinarySearchBy(t *testing.T) {
+	items := []sortItem{{"a", 1, 0}, {"c", 2, 0}, {"c", 3, 0}, {"e", 4, 0}}
+	byName := func(i sortItem, name string) int { return strings.Compare(i.name, name) }
+
+	i, found := BinarySearchBy(items, "c", byName)
+	assert.True(t, found)
+	assert.Equal(t, 1, i)
+
+	i, found = BinarySearchBy(items, "d", byName)
+	assert.False(t, found)
+	assert.Equal(t, 3, i)
+
+	i, found = BinarySearchBy(items, "z", byName)
+	assert.False(t, found)
+	assert.Equal(t, 4, i)
+
+	_, found = BinarySearchBy([]sortItem{}, "a", byName)
+	assert.False(t, found)
+}
+
+func TestInsertSorted(t *testing.T) {
+	byCount := func(a, b sortItem) int { return a.count - b.count }
+
+	items := []sortItem{}
+	for _, item := range []sortItem{{"a", 3, 0}, {"b", 1, 0}, {"c", 3, 0}, {"d", 2, 0}, {"e", 0, 0}} {
+		items = InsertSorted(items, item, byCount)
+	}
+	assert.Equal(t, []string{"e", "b", "d", "a", "c"}, Map(items, func(i sortItem) string { return i.name }))
+
+	// 维护最大的 3 个数。
+	top := []int{}
+	for _, n := range []int{5, 1, 9, 3, 7, 8} {
+		top = InsertSorted(top, n, func(a, b int) int { return b - a })
+		if len(top) > 3 {
+			top = top[:3]
+		}
+	}
+	assert.Equal(t, []int{9, 8, 7}, top)
+}
@@ -0,0 +1,106 @@
+package common
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+/*
+UserCacheDir returns the directory for the cached data of the application, which can be deleted at any time.
+The directory is not created.
+  - Linux and other Unix: $XDG_CACHE_HOME/app, default ~/.cache/app.
+  - macOS: ~/Library/Caches/app.
+  - Windows: %LocalAppData%\app.
+
+Parameters:
+  - app: The name of the application. Empty means the base directory itself.
+
+Returns:
+  - The directory.
+  - An error if the home directory or the base directory can not be determined.
+
+UserCacheDir 返回应用程序缓存数据的目录，其中的数据可以随时删除。不创建该目录。
+  - Linux 及其它 Unix：$XDG_CACHE_HOME/app，默认为 ~/.cache/app。
+  - macOS：~/Library/Caches/app。
+  - Windows：%LocalAppData%\app。
+
+参数:
+  - app: 应用程序名称。为空时返回基础目录本身。
+
+返回:
+  - 目录。
+  - 错误信息。不能确定用户主目录或基础目录时返回错误。
+*/
+func UserCacheDir(app string) (string, error) {
+	return appDir(app, "XDG_CACHE_HOME", ".cache", "Library/Caches", "LocalAppData")
+}
+
+/*
+UserConfigDir returns the directory for the configuration files of the application. The directory is not created.
+  - Linux and other Unix: $XDG_CONFIG_HOME/app, default ~/.config/app.
+  - macOS: ~/Library/Application Support/app.
+  - Windows: %AppData%\app, which roams with the user.
+
+See [UserCacheDir] for the parameters.
+
+UserConfigDir 返回应用程序配置文件的目录。不创建该目录。
+  - Linux 及其它 Unix：$XDG_CONFIG_HOME/app，默认为 ~/.config/app。
+  - macOS：~/Library/Application Support/app。
+  - Windows：%AppData%\app，随用户漫游。
+
+参数参见 [UserCacheDir]。
+*/
+func UserConfigDir(app string) (string, error) {
+	return appDir(app, "XDG_CONFIG_HOME", ".config", "Library/Application Support", "AppData")
+}
+
+/*
+UserDataDir returns the directory for the persistent data of the application, such as databases.
+The directory is not created.
+  - Linux and other Unix: $XDG_DATA_HOME/app, default ~/.local/share/app.
+  - macOS: ~/Library/Application Support/app.
+  - Windows: %LocalAppData%\app.
+
+See [UserCacheDir] for the parameters.
+
+UserDataDir 返回应用程序持久数据的目录，例如数据库。不创建该目录。
+  - Linux 及其它 Unix：$XDG_DATA_HOME/app，默认为 ~/.local/share/app。
+  - macOS：~/Library/Application Support/app。
+  - Windows：%LocalAppData%\app。
+
+参数参见 [UserCacheDir]。
+*/
+func UserDataDir(app string) (string, error) {
+	return appDir(app, "XDG_DATA_HOME", ".local/share", "Library/Application Support", "LocalAppData")
+}
+
+// appDir 按操作系统的约定确定基础目录，再加上 app。
+// Unix 使用 XDG 环境变量，按规范忽略相对路径；macOS 使用 Library 下的目录；Windows 使用环境变量指定的目录。
+func appDir(app string, xdgEnv string, xdgDefault string, darwinDir string, windowsEnv string) (string, error) {
+	var base string
+
+	switch runtime.GOOS {
+	case "windows":
+		if base = os.Getenv(windowsEnv); base == "" {
+			return "", errors.New("%" + windowsEnv + "% is not defined")
+		}
+	case "darwin", "ios":
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		base = filepath.Join(home, filepath.FromSlash(darwinDir))
+	default:
+		if base = os.Getenv(xdgEnv); base == "" || !filepath.IsAbs(base) {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return "", err
+			}
+			base = filepath.Join(home, filepath.FromSlash(xdgDefault))
+		}
+	}
+
+	return filepath.Join(base, app), nil
+}
@@ -0,0 +1,42 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserDirs(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" || runtime.GOOS == "ios" {
+		t.Skip("XDG directories are only used on Unix")
+	}
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CACHE_HOME", "")
+	t.Setenv("XDG_CONFIG_HOME", "relative/is/ignored")
+	t.Setenv("XDG_DATA_HOME", "/data")
+
+	dir, err := UserCacheDir("futool")
+	assert.Nil(t, err)
+	assert.Equal(t, filepath.Join(home, ".cache", "futool"), dir)
+
+	dir, err = UserConfigDir("futool")
+	assert.Nil(t, err)
+	assert.Equal(t, filepath.Join(home, ".config", "futool"), dir)
+
+	dir, err = UserDataDir("futool")
+	assert.Nil(t, err)
+	assert.Equal(t, "/data/futool", dir)
+
+	dir, err = UserDataDir("")
+	assert.Nil(t, err)
+	assert.Equal(t, "/data", dir)
+
+	// 不创建目录。
+	_, err = os.Stat(filepath.Join(home, ".cache"))
+	assert.True(t, os.IsNotExist(err))
+}
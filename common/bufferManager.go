@@ -0,0 +1,132 @@
+package common
+
+import "sync"
+
+/*
+BufferManager hands out byte buffers from sync.Pools, one pool for each size class.
+The size classes are powers of two from the minimum size to the maximum size,
+so a buffer of 40 KB is taken from the 64 KB class. Larger buffers are not pooled.
+Pooled buffers are not cleared, so do not rely on their contents. It is safe for concurrent use.
+
+BufferManager 从 sync.Pool 中分配字节缓冲区，每个大小等级一个池。
+大小等级为从最小值到最大值的 2 的幂，所以 40 KB 的缓冲区从 64 KB 的等级中取得。更大的缓冲区不放入池中。
+池中的缓冲区不会被清零，所以不要依赖其内容。可以并发使用。
+*/
+type BufferManager struct {
+	minSize int
+	pools   []sync.Pool
+}
+
+/*
+NewBufferManager creates a BufferManager.
+
+Parameters:
+  - minSize: The smallest size class, rounded up to a power of two. Values less than 1 are treated as 1.
+  - maxSize: The largest size class, rounded up to a power of two. Values less than minSize are treated as minSize.
+
+Returns:
+  - The created BufferManager.
+
+NewBufferManager 创建 BufferManager。
+
+参数:
+  - minSize: 最小的大小等级，向上取整为 2 的幂。小于 1 时按 1 处理。
+  - maxSize: 最大的大小等级，向上取整为 2 的幂。小于 minSize 时按 minSize 处理。
+
+返回:
+  - 创建的 BufferManager。
+*/
+func NewBufferManager(minSize int, maxSize int) *BufferManager {
+	minSize = ceilPowerOfTwo(minSize)
+	maxSize = ceilPowerOfTwo(maxSize)
+	if maxSize < minSize {
+		maxSize = minSize
+	}
+
+	count := 1
+	for size := minSize; size < maxSize; size <<= 1 {
+		count++
+	}
+
+	m := &BufferManager{minSize: minSize, pools: make([]sync.Pool, count)}
+	for i := range m.pools {
+		size := minSize << i
+		m.pools[i].New = func() any {
+			buf := make([]byte, size)
+			return &buf
+		}
+	}
+	return m
+}
+
+/*
+DefaultBufferManager is used by the other packages of this module, with size classes from 4 KB to 16 MB.
+
+DefaultBufferManager 由本模块的其它包使用，大小等级从 4 KB 到 16 MB。
+*/
+var DefaultBufferManager = NewBufferManager(4*1024, 16*1024*1024)
+
+/*
+Get returns a buffer of the size. Return it with [BufferManager.Put] when it is no longer used.
+
+Parameters:
+  - size: The length of the buffer. The capacity may be larger.
+
+Returns:
+  - The buffer, nil if size is not greater than 0.
+
+Get 返回指定大小的缓冲区。不再使用时用 [BufferManager.Put] 归还。
+
+参数:
+  - size: 缓冲区的长度。容量可能更大。
+
+返回:
+  - 缓冲区。size 不大于 0 时返回 nil。
+*/
+func (m *BufferManager) Get(size int) []byte {
+	if size <= 0 {
+		return nil
+	}
+
+	i := m.classIndex(size)
+	if i < 0 {
+		return make([]byte, size)
+	}
+	return (*m.pools[i].Get().(*[]byte))[:size]
+}
+
+/*
+Put returns the buffer got by [BufferManager.Get]. The buffer must not be used after that.
+Buffers whose capacity is not a size class, such as those not from Get, are dropped.
+
+Put 归还由 [BufferManager.Get] 取得的缓冲区，之后不能再使用该缓冲区。
+容量不是大小等级的缓冲区，例如不是由 Get 取得的，会被丢弃。
+*/
+func (m *BufferManager) Put(buf []byte) {
+	size := cap(buf)
+	if i := m.classIndex(size); i >= 0 && m.minSize<<i == size {
+		buf = buf[:size]
+		m.pools[i].Put(&buf)
+	}
+}
+
+// classIndex 返回能容纳 size 的最小大小等级，超过最大等级时返回 -1。
+func (m *BufferManager) classIndex(size int) int {
+	i := 0
+	for class := m.minSize; class < size; class <<= 1 {
+		i++
+	}
+	if i >= len(m.pools) {
+		return -1
+	}
+	return i
+}
+
+// ceilPowerOfTwo 返回大于等于 n 的最小的 2 的幂，n 小于 1 时返回 1。
+func ceilPowerOfTwo(n int) int {
+	result := 1
+	for result < n {
+		result <<= 1
+	}
+	return result
+}
@@ -0,0 +1,78 @@
+package common
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBufferManager(t *testing.T) {
+	m := NewBufferManager(1000, 5000)
+	assert.Equal(t, 1024, m.minSize)
+	assert.Equal(t, 4, len(m.pools)) // 1K, 2K, 4K, 8K
+
+	assert.Nil(t, m.Get(0))
+
+	buf := m.Get(1500)
+	assert.Equal(t, 1500, len(buf))
+	assert.Equal(t, 2048, cap(buf))
+
+	// 超过最大等级的缓冲区直接分配。
+	large := m.Get(10000)
+	assert.Equal(t, 10000, len(large))
+	assert.Equal(t, 10000, cap(large))
+	m.Put(large)
+	m.Put(make([]byte, 100))
+
+	// 容量正好为 1K 的缓冲区可以放入 1K 的等级。
+	assert.Equal(t, 1024, cap(m.Get(1)))
+	assert.Equal(t, 8192, cap(m.Get(8192)))
+}
+
+func TestBufferManagerConcurrent(t *testing.T) {
+	m := NewBufferManager(16, 64)
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				buf := m.Get(n*8 + 1)
+				for k := range buf {
+					buf[k] = byte(n)
+				}
+				assert.Equal(t, n*8+1, len(buf))
+				m.Put(buf)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestBufferManagerAllocs(t *testing.T) {
+	m := NewBufferManager(1024, 64*1024)
+	m.Put(m.Get(64 * 1024))
+
+	allocs := testing.AllocsPerRun(100, func() {
+		buf := m.Get(40 * 1024)
+		m.Put(buf)
+	})
+	// Put 只为切片头分配，不再分配 64 KB 的缓冲区。
+	assert.LessOrEqual(t, allocs, 1.0)
+}
+
+func TestGetBuffer(t *testing.T) {
+	buf := []byte{1, 2, 3}
+	assert.Equal(t, buf, GetBuffer(buf))
+	assert.Equal(t, 100, len(GetBuffer(100)))
+
+	// 不使用缓冲池，即使池中有用过的缓冲区，返回的缓冲区也全为 0。
+	used := DefaultBufferManager.Get(4 * 1024)
+	for i := range used {
+		used[i] = 0xff
+	}
+	DefaultBufferManager.Put(used)
+	assert.Equal(t, make([]byte, 4*1024), GetBuffer(4*1024))
+}
@@ -1,6 +1,12 @@
 package common
 
-import "fmt"
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
 
 /*
 ByteCount defines type for counting bytes.
@@ -18,8 +24,37 @@ var gb = mb * kb
 var tb = gb * kb
 var pb = tb * kb
 
+/*
+SizeUnits is the unit system of [ToSizeStringIn] and [ParseSizeStringIn].
+
+SizeUnits 是 [ToSizeStringIn] 及 [ParseSizeStringIn] 使用的单位制。
+*/
+type SizeUnits int
+
+const (
+	SizeUnitsBinary SizeUnits = iota // 1024-based with labels KB, MB, GB, as used by Windows. The default of ToSizeString.
+	SizeUnitsIEC                     // 1024-based with labels KiB, MiB, GiB.
+	SizeUnitsSI                      // 1000-based with labels KB, MB, GB, as used by macOS and disk vendors.
+)
+
+// sizeUnitLabels 是各单位制从 KB 到 PB 的单位名称。
+var sizeUnitLabels = map[SizeUnits][]string{
+	SizeUnitsBinary: {"KB", "MB", "GB", "TB", "PB"},
+	SizeUnitsIEC:    {"KiB", "MiB", "GiB", "TiB", "PiB"},
+	SizeUnitsSI:     {"KB", "MB", "GB", "TB", "PB"},
+}
+
+// base 返回单位制的进制。
+func (units SizeUnits) base() float64 {
+	if units == SizeUnitsSI {
+		return 1000
+	}
+	return kb
+}
+
 /*
 ToSizeString converts a byte count to a string with proper units (KB, MB, GB, or TB) and formatted with precision.
+The units are 1024-based. See [ToSizeStringIn] for IEC and SI units.
 
 Parameters:
     - size: Byte count.
@@ -29,6 +64,7 @@ Returns:
     - Formatted string.
 
 ToSizeString 将字节数转换为正确单位(KB, MB, GB, 或 TB)的字符串，并按精度格式化。
+单位按 1024 计算。IEC 及 SI 单位参见 [ToSizeStringIn]。
 
 参数:
 	- size: 字节数。
@@ -38,31 +74,281 @@ ToSizeString 将字节数转换为正确单位(KB, MB, GB, 或 TB)的字符串
 	- 格式化后的字符串。
 */
 func ToSizeString[T ByteCount](size T, precision ...int) string {
+	return ToSizeStringIn(size, SizeUnitsBinary, precision...)
+}
+
+/*
+ToSizeStringIn is like [ToSizeString], using the given unit system.
+Use [SizeUnitsIEC] for labels that say 1024-based, or [SizeUnitsSI] to match the sizes reported by macOS.
+
+Example:
+
+	ToSizeString(1536000)                   // "1.465 MB"
+	ToSizeStringIn(1536000, SizeUnitsIEC)   // "1.465 MiB"
+	ToSizeStringIn(1536000, SizeUnitsSI, 1) // "1.5 MB"
+
+ToSizeStringIn 与 [ToSizeString] 相同，但使用给定的单位制。
+使用 [SizeUnitsIEC] 使单位名称表明按 1024 计算，或使用 [SizeUnitsSI] 以与 macOS 报告的大小一致。
+*/
+func ToSizeStringIn[T ByteCount](size T, units SizeUnits, precision ...int) string {
 	// 未指定 precision 参数时，默认为 3。指定多个参数时也只有第一个有效。
-	p := 3
-	if len(precision) > 0 {
-		p = precision[0]
-		if p < 0 || p > 9 {
-			p = 3
-		}
+	option := NewSizeOption()
+	option.Units = units
+	option.Precision = getPrecision(precision, 3)
+	return FormatSize(size, option)
+}
+
+/*
+SizeOption is the options of [FormatSize]. All size formatting functions are built on it.
+
+SizeOption 是 [FormatSize] 的选项。所有格式化大小的函数都基于它。
+*/
+type SizeOption struct {
+	Units     SizeUnits     // the unit system
+	Precision int           // the number of decimals, 0 to 9. Others mean 3. Byte counts have no decimals
+	FixedUnit string        // always use this unit, such as "MB" of the unit system or "bytes". Empty means the largest fitting unit
+	Separator string        // the separator between the number and the unit
+	Width     int           // the minimum width of the result, padded with spaces on the left. Negative pads on the right. 0 means no padding
+	TrimZeros bool          // whether to remove trailing zeros of the decimals, such as "1.500 KB" to "1.5 KB"
+	UnitCase  SizeUnitCase  // the letter case of the unit
+	Format    *NumberFormat // the number format and the word for bytes. nil means plain digits and "bytes"
+}
+
+/*
+SizeUnitCase is the letter case of the unit in [FormatSize].
+
+SizeUnitCase 是 [FormatSize] 中单位的大小写。
+*/
+type SizeUnitCase int
+
+const (
+	SizeUnitCaseDefault SizeUnitCase = iota // as labeled by the unit system, such as "KB", "KiB" and "bytes"
+	SizeUnitCaseUpper                       // such as "KB", "KIB" and "BYTES"
+	SizeUnitCaseLower                       // such as "kb", "kib" and "bytes"
+)
+
+// apply 按大小写设置转换单位。
+func (c SizeUnitCase) apply(unit string) string {
+	switch c {
+	case SizeUnitCaseUpper:
+		return strings.ToUpper(unit)
+	case SizeUnitCaseLower:
+		return strings.ToLower(unit)
+	default:
+		return unit
+	}
+}
+
+/*
+NewSizeOption creates a new SizeOption with 1024-based units labeled KB, MB, GB, precision 3, and a space as separator,
+the same as [ToSizeString].
+
+NewSizeOption 创建默认的 SizeOption。使用按 1024 计算、名称为 KB、MB、GB 的单位，精度 3，空格作为分隔符，与 [ToSizeString] 相同。
+*/
+func NewSizeOption() *SizeOption {
+	return &SizeOption{
+		Units:     SizeUnitsBinary,
+		Precision: 3,
+		FixedUnit: "",
+		Separator: " ",
+		Width:     0,
+		TrimZeros: false,
+		UnitCase:  SizeUnitCaseDefault,
+		Format:    nil,
+	}
+}
+
+/*
+FormatBytes converts a byte count to a string by the options, the same as [FormatSize].
+It is convenient when the options are kept by a report or table renderer.
+
+Example:
+
+	option := NewSizeOption()
+	option.TrimZeros = true
+	option.Width = -8
+	option.FormatBytes(1536) // "1.5 KB  "
+
+FormatBytes 按选项将字节数转换为字符串，与 [FormatSize] 相同。在报告或表格输出保存选项时便于使用。
+*/
+func (option *SizeOption) FormatBytes(size int64) string {
+	return FormatSize(size, option)
+}
+
+/*
+FormatSize converts a byte count to a string by the options.
+
+Parameters:
+  - size: Byte count.
+  - option: The options. if nil, the default options will be used. An unknown FixedUnit is ignored.
+    See also [SizeOption.FormatBytes].
+
+Returns:
+  - Formatted string.
+
+Example:
+
+	option := NewSizeOption()
+	option.FixedUnit = "MB"
+	option.Precision = 1
+	option.Width = 10
+	s := FormatSize(512*1024, option) // "    0.5 MB"
+
+	option = NewSizeOption()
+	option.TrimZeros = true
+	option.UnitCase = SizeUnitCaseLower
+	s = FormatSize(2048, option) // "2 kb"
+
+FormatSize 按选项将字节数转换为字符串。
+
+参数:
+  - size: 字节数。
+  - option: 选项。如果为 nil 则使用默认选项。忽略未知的 FixedUnit。
+    另参见 [SizeOption.FormatBytes]。
+
+返回:
+  - 格式化后的字符串。
+*/
+func FormatSize[T ByteCount](size T, option *SizeOption) string {
+	if option == nil { // 保证 option 不为 nil。
+		option = NewSizeOption()
 	}
 
-	format := func(unit string) string {
-		return fmt.Sprintf("%%.%df %s", p, unit)
+	labels, ok := sizeUnitLabels[option.Units]
+	if !ok {
+		labels = sizeUnitLabels[SizeUnitsBinary]
 	}
+	precision := getPrecision([]int{option.Precision}, 3)
+
+	// index 为 -1 表示字节。
+	base := option.Units.base()
 	value := float64(size)
+	index := -1
+	if fixed := fixedUnitIndex(labels, option.FixedUnit); fixed >= -1 {
+		index = fixed
+		value /= math.Pow(base, float64(index+1))
+	} else {
+		// 使用不超过 value 的最大单位，最大为 PB。
+		for ; index < len(labels)-1 && value >= base; index++ {
+			value /= base
+		}
+	}
 
-	if value < kb {
-		return fmt.Sprintf("%.0f bytes", value)
-	} else if value < mb {
-		return fmt.Sprintf(format("KB"), value/kb)
-	} else if value < gb {
-		return fmt.Sprintf(format("MB"), value/mb)
-	} else if value < tb {
-		return fmt.Sprintf(format("GB"), value/gb)
-	} else if value < pb {
-		return fmt.Sprintf(format("TB"), value/tb)
+	unit := option.Format.BytesWord()
+	if index < 0 {
+		precision = 0
 	} else {
-		return fmt.Sprintf(format("PB"), value/pb)
+		unit = labels[index]
+	}
+
+	number := strconv.FormatFloat(value, 'f', precision, 64)
+	if option.TrimZeros && strings.Contains(number, ".") {
+		number = strings.TrimRight(strings.TrimRight(number, "0"), ".")
+	}
+
+	s := option.Format.localize(number) + option.Separator + option.UnitCase.apply(unit)
+	return fmt.Sprintf("%*s", option.Width, s)
+}
+
+// fixedUnitIndex 返回固定单位在 labels 中的序号，"bytes" 为 -1，为空或未知时为 -2。不区分大小写。
+func fixedUnitIndex(labels []string, unit string) int {
+	if unit == "" {
+		return -2
+	}
+	if p, ok := sizeUnitPowers[strings.ToLower(unit)]; ok && p.power == 0 {
+		return -1
+	}
+	for i, label := range labels {
+		if strings.EqualFold(label, unit) {
+			return i
+		}
+	}
+	return -2
+}
+
+// regexSizeString 是数字及可选的单位，二者之间可以有空格。
+var regexSizeString = regexp.MustCompile(`^(\d+(?:\.\d*)?|\.\d+)\s*([a-zA-Z]*)$`)
+
+// sizeUnitPowers 是单位的小写形式对应的幂次。IEC 单位总是按 1024 计算，其它的按单位制的进制计算。
+var sizeUnitPowers = map[string]struct {
+	power int
+	iec   bool
+}{
+	"": {0, false}, "b": {0, false}, "byte": {0, false}, "bytes": {0, false},
+	"k": {1, false}, "kb": {1, false}, "kib": {1, true},
+	"m": {2, false}, "mb": {2, false}, "mib": {2, true},
+	"g": {3, false}, "gb": {3, false}, "gib": {3, true},
+	"t": {4, false}, "tb": {4, false}, "tib": {4, true},
+	"p": {5, false}, "pb": {5, false}, "pib": {5, true},
+}
+
+/*
+ParseSizeString converts a human-readable size to a byte count. It is the inverse of [ToSizeString].
+
+Parameters:
+  - s: The size, a number optionally followed by a unit, such as "1.5 GB", "100KiB", "10m" or "4096".
+    Units are case insensitive: B, K, KB, KiB, M, MB, MiB, G, GB, GiB, T, TB, TiB, P, PB and PiB.
+    Like [ToSizeString], both KB and KiB are 1024 bytes.
+
+Returns:
+  - The byte count, rounded to the nearest byte.
+  - An error if s is not a size or is too large for int64.
+
+ParseSizeString 将易读的大小转换为字节数，是 [ToSizeString] 的逆运算。
+
+参数:
+  - s: 大小，数字之后是可选的单位，例如 "1.5 GB"、"100KiB"、"10m" 或 "4096"。
+    单位不区分大小写：B、K、KB、KiB、M、MB、MiB、G、GB、GiB、T、TB、TiB、P、PB 及 PiB。
+    与 [ToSizeString] 相同，KB 及 KiB 都是 1024 字节。
+
+返回:
+  - 字节数，四舍五入到整数。
+  - 错误信息。s 不是大小或超出 int64 范围时返回错误。
+*/
+func ParseSizeString(s string) (int64, error) {
+	return ParseSizeStringIn(s, SizeUnitsBinary)
+}
+
+/*
+ParseSizeStringIn is like [ParseSizeString], using the given unit system.
+With [SizeUnitsSI], KB, MB and the like are 1000-based, while KiB, MiB and the like are still 1024-based.
+
+ParseSizeStringIn 与 [ParseSizeString] 相同，但使用给定的单位制。
+使用 [SizeUnitsSI] 时，KB、MB 等按 1000 计算，而 KiB、MiB 等仍按 1024 计算。
+*/
+func ParseSizeStringIn(s string, units SizeUnits) (int64, error) {
+	subs := regexSizeString.FindStringSubmatch(strings.TrimSpace(s))
+	if subs == nil {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+
+	power, ok := sizeUnitPowers[strings.ToLower(subs[2])]
+	if !ok {
+		return 0, fmt.Errorf("invalid size unit %q", subs[2])
+	}
+
+	base := units.base()
+	if power.iec {
+		base = kb
+	}
+	unit := math.Pow(base, float64(power.power))
+
+	// 整数使用整数运算，避免大于 2^53 的值损失精度。
+	if n, err := strconv.ParseInt(subs[1], 10, 64); err == nil {
+		if n > math.MaxInt64/int64(unit) {
+			return 0, fmt.Errorf("size %q is too large", s)
+		}
+		return n * int64(unit), nil
+	}
+
+	value, err := strconv.ParseFloat(subs[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q: %w", s, err)
+	}
+
+	size := math.Round(value * unit)
+	if size >= math.MaxInt64 {
+		return 0, fmt.Errorf("size %q is too large", s)
 	}
+	return int64(size), nil
 }
@@ -18,3 +18,122 @@ func TestToSizeString(t *testing.T) {
 	assert.Equal(t, "1.309 PB", ToSizeString(1340*1024*1024*1024*1024))
 	assert.Equal(t, "1 PB", ToSizeString(1340*1024*1024*1024*1024, 0))
 }
+
+func TestParseSizeString(t *testing.T) {
+	cases := []struct {
+		s        string
+		expected int64
+	}{
+		{"4096", 4096},
+		{"100 bytes", 100},
+		{"1b", 1},
+		{"1.5 GB", 1536 * 1024 * 1024},
+		{"100KiB", 100 * 1024},
+		{" 10m ", 10 * 1024 * 1024},
+		{"1.309 KB", 1340},
+		{".5k", 512},
+		{"2 TB", 2 << 40},
+		{"1 PiB", 1 << 50},
+	}
+	for _, c := range cases {
+		size, err := ParseSizeString(c.s)
+		assert.Nil(t, err, c.s)
+		assert.Equal(t, c.expected, size, c.s)
+	}
+
+	// 在精度范围内与 ToSizeString 互逆。
+	size, err := ParseSizeString(ToSizeString(int64(1536 * 1024)))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1536*1024), size)
+
+	for _, s := range []string{"", "GB", "-1", "1.5 XB", "1 2", "10000 PB"} {
+		_, err := ParseSizeString(s)
+		assert.NotNil(t, err, s)
+	}
+}
+
+func TestToSizeStringIn(t *testing.T) {
+	assert.Equal(t, "1.465 MB", ToSizeString(1536000))
+	assert.Equal(t, "1.465 MiB", ToSizeStringIn(1536000, SizeUnitsIEC))
+	assert.Equal(t, "1.5 MB", ToSizeStringIn(1536000, SizeUnitsSI, 1))
+	assert.Equal(t, "1.536 MB", ToSizeStringIn(1536000, SizeUnitsSI))
+
+	assert.Equal(t, "999 bytes", ToSizeStringIn(999, SizeUnitsSI))
+	assert.Equal(t, "1.000 KB", ToSizeStringIn(1000, SizeUnitsSI))
+	assert.Equal(t, "1000 bytes", ToSizeStringIn(1000, SizeUnitsIEC))
+	assert.Equal(t, "1.000 KiB", ToSizeStringIn(1024, SizeUnitsIEC))
+	assert.Equal(t, "2000.000 PB", ToSizeStringIn(int64(2e18), SizeUnitsSI))
+	assert.Equal(t, "1.309 PiB", ToSizeStringIn(1340*1024*1024*1024*1024, SizeUnitsIEC))
+}
+
+func TestParseSizeStringIn(t *testing.T) {
+	size, err := ParseSizeStringIn("1.5 MB", SizeUnitsSI)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1500000), size)
+
+	size, err = ParseSizeStringIn("1.5 MiB", SizeUnitsSI)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1536*1024), size)
+
+	size, err = ParseSizeStringIn("2k", SizeUnitsIEC)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2048), size)
+}
+
+func TestFormatSize(t *testing.T) {
+	assert.Equal(t, ToSizeString(1340*1024), FormatSize(1340*1024, nil))
+
+	option := NewSizeOption()
+	option.FixedUnit = "MB"
+	option.Precision = 1
+	option.Width = 10
+	assert.Equal(t, "    0.5 MB", FormatSize(512*1024, option))
+	assert.Equal(t, "  2048.0 MB", FormatSize(2<<30, &SizeOption{FixedUnit: "mb", Precision: 1, Separator: " ", Width: 11}))
+
+	option = &SizeOption{Units: SizeUnitsIEC, Precision: 2, FixedUnit: "bytes", Separator: ""}
+	assert.Equal(t, "1536bytes", FormatSize(1536, option))
+	option.FixedUnit = "KiB"
+	assert.Equal(t, "1.50KiB", FormatSize(1536, option))
+	option.FixedUnit = "KB" // 不是 IEC 单位，忽略。
+	assert.Equal(t, "1.50KiB", FormatSize(1536, option))
+
+	// 无效的精度使用默认值。
+	assert.Equal(t, "1.500 KB", FormatSize(1536, &SizeOption{Precision: 10, Separator: " "}))
+}
+
+func TestFormatSizeWithNumberFormat(t *testing.T) {
+	option := NewSizeOption()
+	option.Format = NewNumberFormat(LocaleChinese)
+	option.FixedUnit = "KB"
+	assert.Equal(t, "1,234.000 KB", FormatSize(1234*1024, option))
+
+	option.FixedUnit = ""
+	assert.Equal(t, "100 字节", FormatSize(100, option))
+}
+
+func TestFormatSizeTrimAndCase(t *testing.T) {
+	option := NewSizeOption()
+	option.TrimZeros = true
+	assert.Equal(t, "1.5 KB", FormatSize(1536, option))
+	assert.Equal(t, "2 KB", FormatSize(2048, option))
+	assert.Equal(t, "100 bytes", FormatSize(100, option))
+	assert.Equal(t, "10 MB", option.FormatBytes(10*1024*1024))
+
+	option.UnitCase = SizeUnitCaseLower
+	assert.Equal(t, "1.5 kb", option.FormatBytes(1536))
+	option.Units = SizeUnitsIEC
+	option.UnitCase = SizeUnitCaseUpper
+	assert.Equal(t, "1.5 KIB", option.FormatBytes(1536))
+	assert.Equal(t, "100 BYTES", option.FormatBytes(100))
+
+	// 负的宽度在右侧填充，用于左对齐的表格列。
+	option = NewSizeOption()
+	option.TrimZeros = true
+	option.Width = -8
+	assert.Equal(t, "1.5 KB  ", option.FormatBytes(1536))
+
+	option.Format = &NumberFormat{DecimalSeparator: ",", GroupSeparator: "."}
+	option.FixedUnit = "KB"
+	option.Width = 0
+	assert.Equal(t, "1.234,5 KB", option.FormatBytes(1234*1024+512))
+}
@@ -0,0 +1,118 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+/*
+ByteSize is a byte count that can be configured with human-readable strings such as "10MB".
+It implements fmt.Stringer, flag.Value, json.Marshaler, json.Unmarshaler,
+encoding.TextMarshaler and encoding.TextUnmarshaler, the last two are also used by YAML and TOML libraries.
+Strings are parsed by [ParseSizeString], so KB and KiB are both 1024 bytes.
+
+Example:
+
+	type Config struct {
+		MinFileSize common.ByteSize `json:"minFileSize"` // "10MB" or 10485760 in JSON.
+	}
+
+	var limit common.ByteSize
+	flag.Var(&limit, "limit", "size limit, such as 1.5GB")
+
+ByteSize 是可以使用“10MB”这样易读的字符串配置的字节数。
+它实现了 fmt.Stringer、flag.Value、json.Marshaler、json.Unmarshaler、encoding.TextMarshaler 及 encoding.TextUnmarshaler，
+后两者也被 YAML 及 TOML 库使用。字符串使用 [ParseSizeString] 解析，所以 KB 及 KiB 都是 1024 字节。
+*/
+type ByteSize int64
+
+/*
+String returns the size formatted by [ToSizeString], such as "1.500 MB". It may lose precision, see [ByteSize.MarshalText].
+
+String 返回使用 [ToSizeString] 格式化的大小，例如 "1.500 MB"。可能损失精度，参见 [ByteSize.MarshalText]。
+*/
+func (b ByteSize) String() string {
+	return ToSizeString(int64(b))
+}
+
+/*
+Set parses the size string for flag.Value. See [ParseSizeString].
+
+Set 为 flag.Value 解析大小字符串。参见 [ParseSizeString]。
+*/
+func (b *ByteSize) Set(s string) error {
+	size, err := ParseSizeString(s)
+	if err != nil {
+		return err
+	}
+	*b = ByteSize(size)
+	return nil
+}
+
+/*
+MarshalText returns the exact size with the largest unit that divides it, such as "10MB", "1536KB" or "1000".
+A negative size is an error, since [ParseSizeString] does not accept it.
+
+MarshalText 使用能整除的最大单位返回准确的大小，例如 "10MB"、"1536KB" 或 "1000"。
+负数返回错误，因为 [ParseSizeString] 不接受负数。
+*/
+func (b ByteSize) MarshalText() ([]byte, error) {
+	size := int64(b)
+	if size < 0 {
+		return nil, fmt.Errorf("negative byte size %d", size)
+	} else if size == 0 {
+		return []byte("0"), nil
+	}
+
+	unit := ""
+	for _, label := range sizeUnitLabels[SizeUnitsBinary] {
+		if size%1024 != 0 {
+			break
+		}
+		size /= 1024
+		unit = label
+	}
+	return []byte(strconv.FormatInt(size, 10) + unit), nil
+}
+
+/*
+UnmarshalText parses the size string. See [ParseSizeString].
+
+UnmarshalText 解析大小字符串。参见 [ParseSizeString]。
+*/
+func (b *ByteSize) UnmarshalText(text []byte) error {
+	return b.Set(string(text))
+}
+
+/*
+MarshalJSON returns the size as a JSON string, the same as [ByteSize.MarshalText].
+
+MarshalJSON 以 JSON 字符串返回大小，与 [ByteSize.MarshalText] 相同。
+*/
+func (b ByteSize) MarshalJSON() ([]byte, error) {
+	text, err := b.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+/*
+UnmarshalJSON accepts both a number of bytes, such as 1024, and a size string, such as "1KB". Negative numbers are rejected.
+
+UnmarshalJSON 接受字节数，例如 1024，也接受大小字符串，例如 "1KB"。不接受负数。
+*/
+func (b *ByteSize) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		return b.Set(s)
+	}
+
+	var size int64
+	if err := json.Unmarshal(data, &size); err != nil || size < 0 {
+		return fmt.Errorf("invalid byte size %s", data)
+	}
+	*b = ByteSize(size)
+	return nil
+}
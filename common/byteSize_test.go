@@ -0,0 +1,74 @@
+package common
+
+import (
+	"encoding/json"
+	"flag"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestByteSizeText(t *testing.T) {
+	cases := []struct {
+		size     ByteSize
+		expected string
+	}{
+		{0, "0"},
+		{1000, "1000"},
+		{1536 * 1024, "1536KB"},
+		{10 << 20, "10MB"},
+		{3 << 50, "3PB"},
+		{2048 << 50, "2048PB"},
+		{1<<60 + 1, "1152921504606846977"},
+		{math.MaxInt64, "9223372036854775807"},
+	}
+	for _, c := range cases {
+		text, err := c.size.MarshalText()
+		assert.Nil(t, err)
+		assert.Equal(t, c.expected, string(text))
+
+		// 准确的往返。
+		var parsed ByteSize
+		assert.Nil(t, parsed.UnmarshalText(text))
+		assert.Equal(t, c.size, parsed)
+	}
+
+	// 负数无法解析，所以不能输出。
+	_, err := ByteSize(-2048).MarshalText()
+	assert.NotNil(t, err)
+	_, err = json.Marshal(ByteSize(-1))
+	assert.NotNil(t, err)
+
+	assert.Equal(t, "1.500 MB", ByteSize(1536*1024).String())
+}
+
+func TestByteSizeJSON(t *testing.T) {
+	type config struct {
+		MinFileSize ByteSize `json:"minFileSize"`
+	}
+
+	data, err := json.Marshal(config{MinFileSize: 10 << 20})
+	assert.Nil(t, err)
+	assert.Equal(t, `{"minFileSize":"10MB"}`, string(data))
+
+	c := config{}
+	assert.Nil(t, json.Unmarshal([]byte(`{"minFileSize":"1.5 GB"}`), &c))
+	assert.Equal(t, ByteSize(1536<<20), c.MinFileSize)
+	assert.Nil(t, json.Unmarshal([]byte(`{"minFileSize":4096}`), &c))
+	assert.Equal(t, ByteSize(4096), c.MinFileSize)
+
+	assert.NotNil(t, json.Unmarshal([]byte(`{"minFileSize":"big"}`), &c))
+	assert.NotNil(t, json.Unmarshal([]byte(`{"minFileSize":true}`), &c))
+	assert.NotNil(t, json.Unmarshal([]byte(`{"minFileSize":-1}`), &c))
+}
+
+func TestByteSizeFlag(t *testing.T) {
+	var limit ByteSize
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.Var(&limit, "limit", "size limit")
+
+	assert.Nil(t, flags.Parse([]string{"-limit", "100KiB"}))
+	assert.Equal(t, ByteSize(100*1024), limit)
+	assert.NotNil(t, limit.Set("100 XB"))
+}
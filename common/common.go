@@ -1,13 +1,18 @@
 package common
 
-import "reflect"
+/*
+GetBuffer returns buf itself if it is a []byte, or a new zeroed buffer of the size if it is an int.
+Use [BufferManager.Get] and [BufferManager.Put] instead when the buffer can be returned after use, so it can be reused.
 
+GetBuffer 在 buf 为 []byte 时返回其本身，为 int 时返回新建的该大小的缓冲区，内容全为 0。
+缓冲区在使用后可以归还时，使用 [BufferManager.Get] 及 [BufferManager.Put]，使缓冲区可以重复使用。
+*/
 func GetBuffer[T int | []byte](buf T) []byte {
-	switch v := reflect.ValueOf(buf); v.Kind() {
-	case reflect.Int:
-		return make([]byte, int(v.Int()))
-	case reflect.Slice:
-		return v.Slice(0, int(v.Len())).Bytes()
+	switch v := any(buf).(type) {
+	case int:
+		return make([]byte, v)
+	case []byte:
+		return v
 	default:
 		// 不会执行到这里。
 		panic("T must be int or []byte")
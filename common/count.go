@@ -0,0 +1,88 @@
+package common
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	LocaleEnglish = "en" // English, such as "1.23M"
+	LocaleChinese = "zh" // Chinese, such as "123.46万"
+)
+
+// countUnit 是数量的单位。
+type countUnit struct {
+	size  float64
+	label string
+}
+
+// countUnits 是各语言的数量单位，从小到大排列。英文按 1000 进位，中文按万进位。
+var countUnits = map[string][]countUnit{
+	LocaleEnglish: {{1, ""}, {1e3, "k"}, {1e6, "M"}, {1e9, "G"}, {1e12, "T"}, {1e15, "P"}},
+	LocaleChinese: {{1, ""}, {1e4, "万"}, {1e8, "亿"}, {1e12, "万亿"}},
+}
+
+/*
+ToCountString converts a count to a short string with 1000-based units k, M, G, T and P, such as "1.23M",
+for reporting file counts alongside byte sizes. Counts below 1000 are shown as they are.
+
+Parameters:
+  - count: The count, such as the number of files.
+  - precision: Precision. Precision must be between 0 and 9. Default is 2.
+
+Returns:
+  - Formatted string.
+
+Example:
+
+	ToCountString(1234567) // "1.23M"
+	ToCountString(999)     // "999"
+	ToCountString(1500, 1) // "1.5k"
+
+ToCountString 使用按 1000 进位的单位 k、M、G、T 及 P 将数量转换为简短的字符串，例如 "1.23M"，用于与字节数一起报告文件数。
+小于 1000 的数量原样显示。
+
+参数:
+  - count: 数量，例如文件数。
+  - precision: 精度。范围 0 到 9，默认为 2。超出范围则使用默认值。
+
+返回:
+  - 格式化后的字符串。
+*/
+func ToCountString[T ByteCount](count T, precision ...int) string {
+	return ToCountStringIn(count, LocaleEnglish, precision...)
+}
+
+/*
+ToCountStringIn is like [ToCountString], using the units of the locale.
+[LocaleChinese] uses 万, 亿 and 万亿, such as "123.46万". Other locales mean [LocaleEnglish].
+
+ToCountStringIn 与 [ToCountString] 相同，但使用指定语言的单位。
+[LocaleChinese] 使用万、亿及万亿，例如 "123.46万"。其它语言表示 [LocaleEnglish]。
+*/
+func ToCountStringIn[T ByteCount](count T, locale string, precision ...int) string {
+	return formatCount(float64(count), locale, getPrecision(precision, 2), true)
+}
+
+// formatCount 使用指定语言的单位格式化数量。使用按精度舍入后不小于 1 的最大单位，避免 "1000.00k"。
+// whole 为 true 时，小于第一个单位的值按整数显示，不进位。
+func formatCount(value float64, locale string, precision int, whole bool) string {
+	units, ok := countUnits[locale]
+	if !ok {
+		units = countUnits[LocaleEnglish]
+	}
+
+	scale := math.Pow(10, float64(precision))
+	unit := units[0]
+	for i := len(units) - 1; i > 0 && !(whole && math.Abs(value) < units[1].size); i-- {
+		if math.Round(math.Abs(value)/units[i].size*scale)/scale >= 1 {
+			unit = units[i]
+			break
+		}
+	}
+
+	if unit.size == 1 && whole {
+		return fmt.Sprintf("%.0f", value)
+	}
+	return fmt.Sprintf("%.*f%s", precision, value/unit.size, unit.label)
+}
@@ -0,0 +1,27 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToCountString(t *testing.T) {
+	assert.Equal(t, "0", ToCountString(0))
+	assert.Equal(t, "999", ToCountString(999))
+	assert.Equal(t, "1.00k", ToCountString(1000))
+	assert.Equal(t, "1.5k", ToCountString(1500, 1))
+	assert.Equal(t, "1.23M", ToCountString(1234567))
+	assert.Equal(t, "1.00M", ToCountString(999999))
+	assert.Equal(t, "-2.50G", ToCountString(int64(-2500000000)))
+	assert.Equal(t, "2000.00P", ToCountString(uint64(2e18)))
+}
+
+func TestToCountStringIn(t *testing.T) {
+	assert.Equal(t, "9999", ToCountStringIn(9999, LocaleChinese))
+	assert.Equal(t, "1.00万", ToCountStringIn(10000, LocaleChinese))
+	assert.Equal(t, "123.46万", ToCountStringIn(1234567, LocaleChinese))
+	assert.Equal(t, "1.2亿", ToCountStringIn(123456789, LocaleChinese, 1))
+	assert.Equal(t, "3.00万亿", ToCountStringIn(int64(3e12), LocaleChinese))
+	assert.Equal(t, "1.23M", ToCountStringIn(1234567, "fr"))
+}
@@ -0,0 +1,128 @@
+package common
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+ExpandEnv replaces $VAR and ${VAR} in the string with the environment variables, like os.ExpandEnv,
+and also supports defaults in the shell style:
+  - ${VAR:-default}: default if VAR is unset or empty.
+  - ${VAR-default}: default if VAR is unset.
+
+Undefined variables without defaults are replaced by empty strings. The default can not contain "}".
+
+Example:
+
+	dir := ExpandEnv("${CACHE_DIR:-/tmp/cache}/checksums") // "/tmp/cache/checksums" if CACHE_DIR is not set.
+
+ExpandEnv 与 os.ExpandEnv 一样将字符串中的 $VAR 及 ${VAR} 替换为环境变量，并支持 shell 形式的默认值：
+  - ${VAR:-default}：VAR 未设置或为空时使用 default。
+  - ${VAR-default}：VAR 未设置时使用 default。
+
+未定义且没有默认值的变量替换为空字符串。默认值不能包含“}”。
+*/
+func ExpandEnv(s string) string {
+	return ExpandEnvWith(s, os.LookupEnv)
+}
+
+/*
+ExpandEnvWith is like [ExpandEnv], looking up the variables by lookup, such as a map in tests.
+
+ExpandEnvWith 与 [ExpandEnv] 相同，但使用 lookup 查找变量，例如测试中的 map。
+*/
+func ExpandEnvWith(s string, lookup func(key string) (string, bool)) string {
+	return os.Expand(s, func(name string) string {
+		// os.Expand 将 ${...} 中的全部内容作为变量名称传入。
+		if key, defaultValue, found := strings.Cut(name, ":-"); found {
+			if value, ok := lookup(key); ok && value != "" {
+				return value
+			}
+			return defaultValue
+		}
+		if key, defaultValue, found := strings.Cut(name, "-"); found {
+			if value, ok := lookup(key); ok {
+				return value
+			}
+			return defaultValue
+		}
+
+		value, _ := lookup(name)
+		return value
+	})
+}
+
+/*
+GetenvInt returns the environment variable as an int.
+
+Parameters:
+  - key: The name of the variable.
+  - defaultValue: The value returned if the variable is unset or empty.
+
+Returns:
+  - The value.
+  - An error if the variable is not a valid int. defaultValue is returned with it.
+
+GetenvInt 返回 int 类型的环境变量。
+
+参数:
+  - key: 变量名称。
+  - defaultValue: 变量未设置或为空时返回的值。
+
+返回:
+  - 变量的值。
+  - 错误信息。变量不是有效的 int 时返回错误，同时返回 defaultValue。
+*/
+func GetenvInt(key string, defaultValue int) (int, error) {
+	return getenv(key, defaultValue, func(s string) (int, error) {
+		return strconv.Atoi(s)
+	})
+}
+
+/*
+GetenvBool returns the environment variable as a bool, such as "true", "false", "1" or "0".
+See [GetenvInt] for the parameters and the error.
+
+GetenvBool 返回 bool 类型的环境变量，例如 "true"、"false"、"1" 或 "0"。参数及错误参见 [GetenvInt]。
+*/
+func GetenvBool(key string, defaultValue bool) (bool, error) {
+	return getenv(key, defaultValue, strconv.ParseBool)
+}
+
+/*
+GetenvSize returns the environment variable as a size in bytes, such as "10MB". See [ParseSizeString].
+See [GetenvInt] for the parameters and the error.
+
+GetenvSize 返回以字节为单位的环境变量，例如 "10MB"。参见 [ParseSizeString]。参数及错误参见 [GetenvInt]。
+*/
+func GetenvSize(key string, defaultValue int64) (int64, error) {
+	return getenv(key, defaultValue, ParseSizeString)
+}
+
+/*
+GetenvDuration returns the environment variable as a duration, such as "90s" or "1h30m". See time.ParseDuration.
+See [GetenvInt] for the parameters and the error.
+
+GetenvDuration 返回时长类型的环境变量，例如 "90s" 或 "1h30m"。参见 time.ParseDuration。参数及错误参见 [GetenvInt]。
+*/
+func GetenvDuration(key string, defaultValue time.Duration) (time.Duration, error) {
+	return getenv(key, defaultValue, time.ParseDuration)
+}
+
+// getenv 读取环境变量并使用 parse 转换，未设置或为空时返回 defaultValue。
+func getenv[T any](key string, defaultValue T, parse func(string) (T, error)) (T, error) {
+	text := strings.TrimSpace(os.Getenv(key))
+	if text == "" {
+		return defaultValue, nil
+	}
+
+	value, err := parse(text)
+	if err != nil {
+		return defaultValue, fmt.Errorf("environment variable %s: %w", key, err)
+	}
+	return value, nil
+}
@@ -0,0 +1,68 @@
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandEnv(t *testing.T) {
+	env := map[string]string{"HOME": "/home/u", "EMPTY": ""}
+	lookup := func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	}
+
+	assert.Equal(t, "/home/u/.cache", ExpandEnvWith("$HOME/.cache", lookup))
+	assert.Equal(t, "/home/u/.cache", ExpandEnvWith("${HOME}/.cache", lookup))
+	assert.Equal(t, "/tmp/x", ExpandEnvWith("${MISSING:-/tmp}/x", lookup))
+	assert.Equal(t, "/tmp/x", ExpandEnvWith("${EMPTY:-/tmp}/x", lookup))
+	assert.Equal(t, "/x", ExpandEnvWith("${EMPTY-/tmp}/x", lookup))
+	assert.Equal(t, "/tmp/x", ExpandEnvWith("${MISSING-/tmp}/x", lookup))
+	assert.Equal(t, "/home/u", ExpandEnvWith("${HOME:-/tmp}", lookup))
+	assert.Equal(t, "a--b", ExpandEnvWith("a-$MISSING-b", lookup))
+
+	t.Setenv("FUTOOL_TEST_DIR", "/data")
+	assert.Equal(t, "/data/a", ExpandEnv("${FUTOOL_TEST_DIR:-/tmp}/a"))
+}
+
+func TestGetenv(t *testing.T) {
+	t.Setenv("FUTOOL_TEST_INT", "42")
+	t.Setenv("FUTOOL_TEST_BOOL", "true")
+	t.Setenv("FUTOOL_TEST_SIZE", "10MB")
+	t.Setenv("FUTOOL_TEST_DURATION", "1m30s")
+	t.Setenv("FUTOOL_TEST_EMPTY", " ")
+	t.Setenv("FUTOOL_TEST_BAD", "x")
+
+	i, err := GetenvInt("FUTOOL_TEST_INT", 1)
+	assert.Nil(t, err)
+	assert.Equal(t, 42, i)
+	b, err := GetenvBool("FUTOOL_TEST_BOOL", false)
+	assert.Nil(t, err)
+	assert.True(t, b)
+	size, err := GetenvSize("FUTOOL_TEST_SIZE", 0)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(10*1024*1024), size)
+	d, err := GetenvDuration("FUTOOL_TEST_DURATION", 0)
+	assert.Nil(t, err)
+	assert.Equal(t, 90*time.Second, d)
+
+	// 未设置或为空时返回默认值。
+	i, err = GetenvInt("FUTOOL_TEST_MISSING", 7)
+	assert.Nil(t, err)
+	assert.Equal(t, 7, i)
+	i, err = GetenvInt("FUTOOL_TEST_EMPTY", 7)
+	assert.Nil(t, err)
+	assert.Equal(t, 7, i)
+
+	i, err = GetenvInt("FUTOOL_TEST_BAD", 7)
+	assert.ErrorContains(t, err, "FUTOOL_TEST_BAD")
+	assert.Equal(t, 7, i)
+	_, err = GetenvBool("FUTOOL_TEST_BAD", false)
+	assert.NotNil(t, err)
+	_, err = GetenvSize("FUTOOL_TEST_BAD", 0)
+	assert.NotNil(t, err)
+	_, err = GetenvDuration("FUTOOL_TEST_BAD", 0)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,125 @@
+package common
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"hash/crc64"
+	"hash/fnv"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	hashLock = sync.RWMutex{}
+	hashes   = map[string]func() hash.Hash{
+		"md5":    md5.New,
+		"sha1":   sha1.New,
+		"sha224": sha256.New224,
+		"sha256": sha256.New,
+		"sha384": sha512.New384,
+		"sha512": sha512.New,
+		"crc32":  func() hash.Hash { return crc32.NewIEEE() },
+		"crc64":  func() hash.Hash { return crc64.New(crc64.MakeTable(crc64.ISO)) },
+		"fnv64a": func() hash.Hash { return fnv.New64a() },
+	}
+)
+
+/*
+RegisterHash registers the hash algorithm of a name, replacing the existing one. Names are case insensitive.
+Built in are md5, sha1, sha224, sha256, sha384, sha512, crc32 (IEEE), crc64 (ISO) and fnv64a.
+Others, such as blake3 or xxhash, can be registered by wrapping third party packages.
+
+RegisterHash 注册一个名称的哈希算法，替换已有的算法。名称不区分大小写。
+内置了 md5、sha1、sha224、sha256、sha384、sha512、crc32 (IEEE)、crc64 (ISO) 及 fnv64a。
+blake3、xxhash 等可通过包装第三方包注册。
+*/
+func RegisterHash(name string, newHash func() hash.Hash) {
+	hashLock.Lock()
+	defer hashLock.Unlock()
+	hashes[strings.ToLower(name)] = newHash
+}
+
+/*
+NewHash creates a hash of the registered algorithm. See [RegisterHash].
+
+NewHash 创建已注册算法的哈希。参见 [RegisterHash]。
+*/
+func NewHash(name string) (hash.Hash, error) {
+	hashLock.RLock()
+	defer hashLock.RUnlock()
+
+	newHash, ok := hashes[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("unknown hash algorithm %q", name)
+	}
+	return newHash(), nil
+}
+
+/*
+HashNames returns the names of the registered algorithms in ascending order.
+
+HashNames 按升序返回已注册算法的名称。
+*/
+func HashNames() []string {
+	hashLock.RLock()
+	defer hashLock.RUnlock()
+
+	names := make([]string, 0, len(hashes))
+	for name := range hashes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+/*
+HashBytes returns the lowercase hex digest of the data by the registered algorithm,
+the same as the file checksums of the algorithm.
+
+Parameters:
+  - name: The algorithm name, such as "sha256". See [RegisterHash].
+  - data: The data to hash.
+
+Returns:
+  - The hex digest.
+  - An error if the algorithm is unknown.
+
+Example:
+
+	key, err := HashBytes("sha256", []byte("hello")) // "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+
+HashBytes 使用已注册的算法返回数据的小写十六进制摘要，与该算法的文件校验值相同。
+
+参数:
+  - name: 算法名称，例如 "sha256"。参见 [RegisterHash]。
+  - data: 待计算的数据。
+
+返回:
+  - 十六进制摘要。
+  - 错误信息。算法未知时返回错误。
+*/
+func HashBytes(name string, data []byte) (string, error) {
+	h, err := NewHash(name)
+	if err != nil {
+		return "", err
+	}
+
+	h.Write(data)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+/*
+HashString is like [HashBytes], hashing the UTF-8 bytes of the string.
+
+HashString 与 [HashBytes] 相同，但计算字符串的 UTF-8 字节。
+*/
+func HashString(name string, s string) (string, error) {
+	return HashBytes(name, []byte(s))
+}
@@ -0,0 +1,46 @@
+package common
+
+import (
+	"crypto/sha256"
+	"hash"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashString(t *testing.T) {
+	for name, expected := range map[string]string{
+		"md5":    "5d41402abc4b2a76b9719d911017c592",
+		"SHA1":   "aaf4c61ddcc5e8a2dabede0f3b482cd9aea9434d",
+		"sha256": "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824",
+		"crc32":  "3610a686",
+	} {
+		digest, err := HashString(name, "hello")
+		assert.Nil(t, err, name)
+		assert.Equal(t, expected, digest, name)
+	}
+
+	digest, err := HashBytes("sha256", []byte{})
+	assert.Nil(t, err)
+	assert.Equal(t, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", digest)
+
+	_, err = HashString("unknown", "hello")
+	assert.ErrorContains(t, err, "unknown hash algorithm")
+}
+
+func TestRegisterHash(t *testing.T) {
+	assert.Contains(t, HashNames(), "sha512")
+	assert.NotContains(t, HashNames(), "test-sha256")
+
+	RegisterHash("Test-SHA256", func() hash.Hash { return sha256.New() })
+	defer func() {
+		hashLock.Lock()
+		delete(hashes, "test-sha256")
+		hashLock.Unlock()
+	}()
+
+	assert.Contains(t, HashNames(), "test-sha256")
+	digest, err := HashString("test-sha256", "hello")
+	assert.Nil(t, err)
+	assert.Equal(t, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", digest)
+}
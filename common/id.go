@@ -0,0 +1,170 @@
+package common
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+/*
+UUID is a universally unique identifier of RFC 9562.
+
+UUID 是 RFC 9562 定义的通用唯一标识符。
+*/
+type UUID [16]byte
+
+/*
+NewUUIDv4 creates a random UUID of version 4 with crypto/rand.
+
+NewUUIDv4 使用 crypto/rand 创建版本 4 的随机 UUID。
+*/
+func NewUUIDv4() (UUID, error) {
+	var u UUID
+	if _, err := rand.Read(u[:]); err != nil {
+		return u, err
+	}
+	u.setVersion(4)
+	return u, nil
+}
+
+/*
+NewUUIDv7 creates a UUID of version 7, which starts with the Unix time in milliseconds followed by random bits,
+so UUIDs created in different milliseconds sort by creation time, as strings or as bytes.
+
+NewUUIDv7 创建版本 7 的 UUID，以毫秒为单位的 Unix 时间开始，之后为随机位，
+所以在不同毫秒创建的 UUID 按创建时间排序，无论作为字符串还是字节。
+*/
+func NewUUIDv7() (UUID, error) {
+	var u UUID
+	if _, err := rand.Read(u[6:]); err != nil {
+		return u, err
+	}
+
+	// 前 48 位为毫秒时间戳，大端序。
+	var ms [8]byte
+	binary.BigEndian.PutUint64(ms[:], uint64(time.Now().UnixMilli()))
+	copy(u[:6], ms[2:])
+
+	u.setVersion(7)
+	return u, nil
+}
+
+/*
+ParseUUID parses a UUID in the standard form "xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx", case insensitive.
+Braces and the "urn:uuid:" prefix are allowed.
+
+ParseUUID 解析 "xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx" 标准形式的 UUID，不区分大小写。允许大括号及 "urn:uuid:" 前缀。
+*/
+func ParseUUID(s string) (UUID, error) {
+	var u UUID
+
+	text := strings.TrimPrefix(strings.ToLower(strings.TrimSpace(s)), "urn:uuid:")
+	if strings.HasPrefix(text, "{") && strings.HasSuffix(text, "}") {
+		text = text[1 : len(text)-1]
+	}
+	if len(text) != 36 || text[8] != '-' || text[13] != '-' || text[18] != '-' || text[23] != '-' {
+		return u, fmt.Errorf("invalid UUID %q", s)
+	}
+
+	if _, err := hex.Decode(u[:], []byte(strings.ReplaceAll(text, "-", ""))); err != nil {
+		return u, fmt.Errorf("invalid UUID %q", s)
+	}
+	return u, nil
+}
+
+/*
+String returns the UUID in the standard lowercase form, such as "0189f7e0-3c4b-7a2d-9e1f-0123456789ab".
+
+String 返回小写标准形式的 UUID，例如 "0189f7e0-3c4b-7a2d-9e1f-0123456789ab"。
+*/
+func (u UUID) String() string {
+	buf := make([]byte, 36)
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+	return string(buf)
+}
+
+/*
+Version returns the version of the UUID, such as 4 or 7.
+
+Version 返回 UUID 的版本，例如 4 或 7。
+*/
+func (u UUID) Version() int {
+	return int(u[6] >> 4)
+}
+
+/*
+Time returns the creation time of a version 7 UUID, in milliseconds. It returns the zero time for other versions.
+
+Time 返回版本 7 的 UUID 的创建时间，精确到毫秒。其它版本返回零值。
+*/
+func (u UUID) Time() time.Time {
+	if u.Version() != 7 {
+		return time.Time{}
+	}
+
+	var ms [8]byte
+	copy(ms[2:], u[:6])
+	return time.UnixMilli(int64(binary.BigEndian.Uint64(ms[:])))
+}
+
+// setVersion 设置版本号及 RFC 9562 的变体位。
+func (u *UUID) setVersion(version byte) {
+	u[6] = u[6]&0x0f | version<<4
+	u[8] = u[8]&0x3f | 0x80
+}
+
+// randomNameAlphabet 是 Crockford base32 字母表的小写形式，没有容易混淆的 i、l、o、u，32 个字符使取模没有偏差。
+const randomNameAlphabet = "0123456789abcdefghjkmnpqrstvwxyz"
+
+/*
+RandomName returns a random string of digits and lowercase letters, generated by crypto/rand.
+It is safe in file names on all platforms, even case insensitive ones,
+and suits temporary files and unique suffixes. Each character carries 5 bits of randomness.
+
+Parameters:
+  - n: The length of the string. 0 or less returns an empty string.
+
+Returns:
+  - The random string.
+  - An error if crypto/rand fails.
+
+Example:
+
+	suffix, err := RandomName(8) // such as "k3x9m2qa"
+	temp := path + "." + suffix + ".tmp"
+
+RandomName 返回由 crypto/rand 生成的随机字符串，包含数字及小写字母。
+在所有平台中，包括不区分大小写的平台，都可以安全地用于文件名，适用于临时文件及唯一的后缀。每个字符包含 5 位随机数。
+
+参数:
+  - n: 字符串长度。小于等于 0 时返回空字符串。
+
+返回:
+  - 随机字符串。
+  - 错误信息。crypto/rand 失败时返回错误。
+*/
+func RandomName(n int) (string, error) {
+	if n <= 0 {
+		return "", nil
+	}
+
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	for i, b := range buf {
+		buf[i] = randomNameAlphabet[b%32]
+	}
+	return string(buf), nil
+}
@@ -0,0 +1,68 @@
+package common
+
+import (
+	"regexp"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var regexUUID = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[47][0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestUUID(t *testing.T) {
+	u4, err := NewUUIDv4()
+	assert.Nil(t, err)
+	assert.Equal(t, 4, u4.Version())
+	assert.Regexp(t, regexUUID, u4.String())
+	assert.True(t, u4.Time().IsZero())
+
+	other, _ := NewUUIDv4()
+	assert.NotEqual(t, u4, other)
+
+	before := time.Now().Truncate(time.Millisecond)
+	u7, err := NewUUIDv7()
+	assert.Nil(t, err)
+	assert.Equal(t, 7, u7.Version())
+	assert.Regexp(t, regexUUID, u7.String())
+	assert.False(t, u7.Time().Before(before))
+	assert.False(t, u7.Time().After(time.Now()))
+
+	// 不同毫秒创建的 v7 按时间排序。
+	ids := []string{}
+	for i := 0; i < 3; i++ {
+		u, _ := NewUUIDv7()
+		ids = append(ids, u.String())
+		time.Sleep(2 * time.Millisecond)
+	}
+	assert.True(t, sort.StringsAreSorted(ids))
+
+	for _, s := range []string{u7.String(), "{" + u7.String() + "}", "urn:uuid:" + u7.String(), " " + u7.String() + " "} {
+		parsed, err := ParseUUID(s)
+		assert.Nil(t, err, s)
+		assert.Equal(t, u7, parsed, s)
+	}
+	parsed, err := ParseUUID("6BA7B810-9DAD-11D1-80B4-00C04FD430C8")
+	assert.Nil(t, err)
+	assert.Equal(t, "6ba7b810-9dad-11d1-80b4-00c04fd430c8", parsed.String())
+	assert.Equal(t, 1, parsed.Version())
+
+	for _, s := range []string{"", "6ba7b810-9dad-11d1-80b4", "6ba7b810x9dad-11d1-80b4-00c04fd430c8", "6ba7b810-9dad-11d1-80b4-00c04fd430cg"} {
+		_, err = ParseUUID(s)
+		assert.NotNil(t, err, s)
+	}
+}
+
+func TestRandomName(t *testing.T) {
+	name, err := RandomName(12)
+	assert.Nil(t, err)
+	assert.Regexp(t, `^[0-9a-hjkmnp-tv-z]{12}$`, name)
+
+	other, _ := RandomName(12)
+	assert.NotEqual(t, name, other)
+
+	name, err = RandomName(0)
+	assert.Nil(t, err)
+	assert.Equal(t, "", name)
+}
@@ -0,0 +1,98 @@
+package common
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+/*
+Must returns the value if err is nil, or panics with err otherwise.
+It is for values that can not fail in practice, such as compiling a constant pattern.
+
+Example:
+
+	pattern := Must(regexp.Compile(`^\d+$`))
+
+Must 在 err 为 nil 时返回值，否则以 err 引发 panic。用于实际上不会失败的情况，例如编译常量的正则表达式。
+*/
+func Must[T any](value T, err error) T {
+	if err != nil {
+		panic(err)
+	}
+	return value
+}
+
+/*
+Must0 panics with err if it is not nil. It is [Must] for functions that return only an error.
+
+Must0 在 err 不为 nil 时以其引发 panic。是只返回错误的函数所用的 [Must]。
+*/
+func Must0(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
+
+/*
+PanicError is the error returned by [Recover] when the function panics.
+
+PanicError 是函数引发 panic 时 [Recover] 返回的错误。
+*/
+type PanicError struct {
+	Value any    // the value passed to panic
+	Stack []byte // the stack of the goroutine when it panicked
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+/*
+Unwrap returns the value passed to panic if it is an error, so errors.Is and errors.As see it.
+
+Unwrap 在传给 panic 的值是错误时返回该值，使 errors.Is 及 errors.As 可以识别它。
+*/
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+/*
+Recover calls fn and turns a panic in it into a [PanicError]. Use it in goroutines,
+where an unrecovered panic would crash the whole program instead of being reported to the caller.
+
+Parameters:
+  - fn: The function to call.
+
+Returns:
+  - The error returned by fn, or a *PanicError if fn panics.
+
+Example:
+
+	go func() {
+		defer wg.Done()
+		if err := Recover(func() error { return work(path) }); err != nil {
+			fail(err)
+		}
+	}()
+
+Recover 调用 fn，并将其中的 panic 转换为 [PanicError]。用于 goroutine 中，
+否则未恢复的 panic 会使整个程序崩溃，而不是报告给调用者。
+
+参数:
+  - fn: 要调用的函数。
+
+返回:
+  - fn 返回的错误。fn 引发 panic 时返回 *PanicError。
+*/
+func Recover(fn func() error) (err error) {
+	defer func() {
+		if value := recover(); value != nil {
+			err = &PanicError{Value: value, Stack: debug.Stack()}
+		}
+	}()
+
+	return fn()
+}
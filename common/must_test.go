@@ -0,0 +1,38 @@
+package common
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMust(t *testing.T) {
+	assert.Equal(t, 12, Must(strconv.Atoi("12")))
+	assert.Panics(t, func() { Must(strconv.Atoi("x")) })
+
+	assert.NotPanics(t, func() { Must0(nil) })
+	assert.Panics(t, func() { Must0(errors.New("failure")) })
+}
+
+func TestRecover(t *testing.T) {
+	failure := errors.New("failure")
+	assert.Nil(t, Recover(func() error { return nil }))
+	assert.Equal(t, failure, Recover(func() error { return failure }))
+
+	err := Recover(func() error { panic("boom") })
+	panicErr := &PanicError{}
+	assert.True(t, errors.As(err, &panicErr))
+	assert.Equal(t, "boom", panicErr.Value)
+	assert.Equal(t, "panic: boom", err.Error())
+	assert.NotEmpty(t, panicErr.Stack)
+	assert.Nil(t, panicErr.Unwrap())
+
+	// panic 的值是错误时可以用 errors.Is 识别，Must 引发的 panic 即是如此。
+	err = Recover(func() error {
+		Must0(failure)
+		return nil
+	})
+	assert.True(t, errors.Is(err, failure))
+}
@@ -0,0 +1,100 @@
+package common
+
+import (
+	"strconv"
+	"strings"
+)
+
+/*
+NumberFormat controls how numbers and unit words are written for people, such as "1,234.5" or "1 234,5".
+It is used by [SizeOption], timeutils.DurationOption and the report writers,
+so one setting applies to all human-readable output. nil means plain digits with "." and English words.
+
+NumberFormat 控制面向用户的数字及单位名称的写法，例如 "1,234.5" 或 "1 234,5"。
+[SizeOption]、timeutils.DurationOption 及报告输出函数都使用它，所以一个设置适用于所有易读的输出。
+nil 表示使用 "." 的普通数字及英文名称。
+*/
+type NumberFormat struct {
+	Locale           string // LocaleEnglish or LocaleChinese, for unit and duration words. Others mean LocaleEnglish
+	DecimalSeparator string // the decimal separator. Empty means "."
+	GroupSeparator   string // the separator between groups of 3 digits in the integer part. Empty means no grouping
+}
+
+/*
+NewNumberFormat creates a NumberFormat with the conventions of the locale.
+Both English and Chinese use "." as the decimal separator and "," between groups.
+
+NewNumberFormat 使用指定语言的习惯创建 NumberFormat。英文及中文都使用 "." 作为小数点，"," 作为分组符。
+*/
+func NewNumberFormat(locale string) *NumberFormat {
+	if locale != LocaleChinese {
+		locale = LocaleEnglish
+	}
+	return &NumberFormat{
+		Locale:           locale,
+		DecimalSeparator: ".",
+		GroupSeparator:   ",",
+	}
+}
+
+/*
+FormatInt formats an integer, such as "1,234,567".
+
+FormatInt 格式化整数，例如 "1,234,567"。
+*/
+func (f *NumberFormat) FormatInt(value int64) string {
+	return f.localize(strconv.FormatInt(value, 10))
+}
+
+/*
+FormatFloat formats a number with the given number of decimals, such as "1,234.57".
+
+FormatFloat 使用指定的小数位数格式化数字，例如 "1,234.57"。
+*/
+func (f *NumberFormat) FormatFloat(value float64, precision int) string {
+	return f.localize(strconv.FormatFloat(value, 'f', precision, 64))
+}
+
+// localize 将 strconv 格式的数字改为使用分组符及小数点。f 为 nil 时原样返回。
+func (f *NumberFormat) localize(s string) string {
+	if f == nil {
+		return s
+	}
+
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+
+	integer, fraction, hasFraction := strings.Cut(s, ".")
+	if f.GroupSeparator != "" {
+		groups := []string{}
+		for len(integer) > 3 {
+			groups = append([]string{integer[len(integer)-3:]}, groups...)
+			integer = integer[:len(integer)-3]
+		}
+		integer = strings.Join(append([]string{integer}, groups...), f.GroupSeparator)
+	}
+
+	if !hasFraction {
+		return sign + integer
+	}
+
+	separator := f.DecimalSeparator
+	if separator == "" {
+		separator = "."
+	}
+	return sign + integer + separator + fraction
+}
+
+/*
+BytesWord returns the word for bytes in the locale, "bytes" or "字节".
+
+BytesWord 返回指定语言中字节的名称，"bytes" 或 "字节"。
+*/
+func (f *NumberFormat) BytesWord() string {
+	if f != nil && f.Locale == LocaleChinese {
+		return "字节"
+	}
+	return "bytes"
+}
@@ -0,0 +1,32 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNumberFormat(t *testing.T) {
+	en := NewNumberFormat(LocaleEnglish)
+	assert.Equal(t, "0", en.FormatInt(0))
+	assert.Equal(t, "999", en.FormatInt(999))
+	assert.Equal(t, "1,000", en.FormatInt(1000))
+	assert.Equal(t, "-1,234,567", en.FormatInt(-1234567))
+	assert.Equal(t, "1,234.57", en.FormatFloat(1234.567, 2))
+	assert.Equal(t, "bytes", en.BytesWord())
+
+	de := &NumberFormat{DecimalSeparator: ",", GroupSeparator: "."}
+	assert.Equal(t, "1.234.567,50", de.FormatFloat(1234567.5, 2))
+
+	plain := &NumberFormat{}
+	assert.Equal(t, "1234.5", plain.FormatFloat(1234.5, 1))
+
+	// nil 表示普通的数字。
+	var none *NumberFormat
+	assert.Equal(t, "1234.50", none.FormatFloat(1234.5, 2))
+	assert.Equal(t, "bytes", none.BytesWord())
+
+	zh := NewNumberFormat(LocaleChinese)
+	assert.Equal(t, "字节", zh.BytesWord())
+	assert.Equal(t, LocaleEnglish, NewNumberFormat("fr").Locale)
+}
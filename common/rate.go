@@ -0,0 +1,86 @@
+package common
+
+import "time"
+
+/*
+ToRateString converts a byte count processed in a duration to a throughput string, such as "12.400 MB/s".
+The units are the same as [ToSizeString].
+
+Parameters:
+  - bytes: Byte count.
+  - d: The duration. 0 or negative means the rate is 0.
+  - precision: Precision. Precision must be between 0 and 9. Default is 3.
+
+Returns:
+  - Formatted string.
+
+Example:
+
+	ToRateString(130*1024*1024, 10*time.Second, 1) // "13.0 MB/s"
+
+ToRateString 将一段时间内处理的字节数转换为吞吐量字符串，例如 "12.400 MB/s"。单位与 [ToSizeString] 相同。
+
+参数:
+  - bytes: 字节数。
+  - d: 时长。为 0 或负数时速度为 0。
+  - precision: 精度。范围 0 到 9，默认为 3。超出范围则使用默认值。
+
+返回:
+  - 格式化后的字符串。
+*/
+func ToRateString[T ByteCount](bytes T, d time.Duration, precision ...int) string {
+	return ToSizeString(int64(rate(float64(bytes), d)+0.5), precision...) + "/s"
+}
+
+/*
+ToCountRateString converts a count processed in a duration to a rate string, such as "3.2k files/s".
+Large rates use 1000-based units k, M, G, T and P.
+
+Parameters:
+  - count: The count, such as the number of files.
+  - d: The duration. 0 or negative means the rate is 0.
+  - unit: The name of the counted things, such as "files". Empty means none, as in "3.2k/s".
+  - precision: Precision. Precision must be between 0 and 9. Default is 1.
+
+Returns:
+  - Formatted string.
+
+Example:
+
+	ToCountRateString(6400, 2*time.Second, "files") // "3.2k files/s"
+	ToCountRateString(3, 2*time.Second, "files", 2) // "1.50 files/s"
+
+ToCountRateString 将一段时间内处理的数量转换为速度字符串，例如 "3.2k files/s"。较大的速度使用按 1000 进位的单位 k、M、G、T 及 P。
+
+参数:
+  - count: 数量，例如文件数。
+  - d: 时长。为 0 或负数时速度为 0。
+  - unit: 计数对象的名称，例如 "files"。为空时不显示，例如 "3.2k/s"。
+  - precision: 精度。范围 0 到 9，默认为 1。超出范围则使用默认值。
+
+返回:
+  - 格式化后的字符串。
+*/
+func ToCountRateString[T ByteCount](count T, d time.Duration, unit string, precision ...int) string {
+	s := formatCount(rate(float64(count), d), LocaleEnglish, getPrecision(precision, 1), false)
+	if unit != "" {
+		s += " " + unit
+	}
+	return s + "/s"
+}
+
+// rate 返回每秒的数量。时长不为正数时返回 0。
+func rate(value float64, d time.Duration) float64 {
+	if d <= 0 {
+		return 0
+	}
+	return value / d.Seconds()
+}
+
+// getPrecision 返回第一个精度参数，未指定或超出 0 到 9 的范围时返回 defaultValue。
+func getPrecision(precision []int, defaultValue int) int {
+	if len(precision) > 0 && precision[0] >= 0 && precision[0] <= 9 {
+		return precision[0]
+	}
+	return defaultValue
+}
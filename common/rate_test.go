@@ -0,0 +1,23 @@
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToRateString(t *testing.T) {
+	assert.Equal(t, "13.0 MB/s", ToRateString(130*1024*1024, 10*time.Second, 1))
+	assert.Equal(t, "1.500 KB/s", ToRateString(3072, 2*time.Second))
+	assert.Equal(t, "512 bytes/s", ToRateString(256, 500*time.Millisecond))
+	assert.Equal(t, "0 bytes/s", ToRateString(100, 0))
+}
+
+func TestToCountRateString(t *testing.T) {
+	assert.Equal(t, "3.2k files/s", ToCountRateString(6400, 2*time.Second, "files"))
+	assert.Equal(t, "1.50 files/s", ToCountRateString(3, 2*time.Second, "files", 2))
+	assert.Equal(t, "2.5M/s", ToCountRateString(5000000, 2*time.Second, ""))
+	assert.Equal(t, "0.0 files/s", ToCountRateString(10, -time.Second, "files"))
+	assert.Equal(t, "1.0k lines/s", ToCountRateString(999999, 1000*time.Second, "lines", 99))
+}
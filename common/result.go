@@ -0,0 +1,196 @@
+package common
+
+/*
+Result holds a value or the error of producing it. It is the payload of channel based streaming APIs,
+so a single channel carries both values and errors.
+
+Result 保存一个值或者生成该值时的错误。用于基于 channel 的流式接口，使一个 channel 同时传递值及错误。
+*/
+type Result[T any] struct {
+	Value T
+	Err   error
+}
+
+/*
+Ok creates a Result with the value.
+
+Ok 使用值创建 Result。
+*/
+func Ok[T any](value T) Result[T] {
+	return Result[T]{Value: value}
+}
+
+/*
+Fail creates a Result with the error.
+
+Fail 使用错误创建 Result。
+*/
+func Fail[T any](err error) Result[T] {
+	return Result[T]{Err: err}
+}
+
+/*
+ResultOf creates a Result from the return values of a function, such as ResultOf(os.Stat(path)).
+
+ResultOf 使用函数的返回值创建 Result，例如 ResultOf(os.Stat(path))。
+*/
+func ResultOf[T any](value T, err error) Result[T] {
+	return Result[T]{Value: value, Err: err}
+}
+
+/*
+IsOk returns true if the Result has no error.
+
+IsOk 在 Result 没有错误时返回 true。
+*/
+func (r Result[T]) IsOk() bool {
+	return r.Err == nil
+}
+
+/*
+Get returns the value and the error, so the Result can be used like a normal function return.
+
+Get 返回值及错误，使 Result 可以像普通的函数返回值一样使用。
+*/
+func (r Result[T]) Get() (T, error) {
+	return r.Value, r.Err
+}
+
+/*
+OrElse returns the value if the Result has no error, or other otherwise.
+
+OrElse 在 Result 没有错误时返回其值，否则返回 other。
+*/
+func (r Result[T]) OrElse(other T) T {
+	if r.Err != nil {
+		return other
+	}
+	return r.Value
+}
+
+/*
+MapResult converts the value of the Result with fn. The error is kept and fn is not called if the Result has an error.
+
+Parameters:
+  - r: The Result to convert.
+  - fn: The function to convert the value. It can return an error.
+
+Returns:
+  - The converted Result.
+
+Example:
+
+	size := MapResult(ResultOf(os.Stat(path)), func(info os.FileInfo) (int64, error) {
+		return info.Size(), nil
+	})
+
+MapResult 使用 fn 转换 Result 的值。Result 有错误时保留该错误，不调用 fn。
+
+参数:
+  - r: 待转换的 Result。
+  - fn: 转换值的函数，可以返回错误。
+
+返回:
+  - 转换后的 Result。
+*/
+func MapResult[T any, U any](r Result[T], fn func(T) (U, error)) Result[U] {
+	if r.Err != nil {
+		return Fail[U](r.Err)
+	}
+	return ResultOf(fn(r.Value))
+}
+
+/*
+Optional holds a value that may be absent. The zero value is absent.
+
+Optional 保存可能不存在的值。零值表示不存在。
+*/
+type Optional[T any] struct {
+	value   T
+	present bool
+}
+
+/*
+Some creates an Optional with the value.
+
+Some 使用值创建 Optional。
+*/
+func Some[T any](value T) Optional[T] {
+	return Optional[T]{value: value, present: true}
+}
+
+/*
+None creates an absent Optional.
+
+None 创建不存在值的 Optional。
+*/
+func None[T any]() Optional[T] {
+	return Optional[T]{}
+}
+
+/*
+OptionalOf creates an Optional from the comma ok idiom, such as OptionalOf(os.LookupEnv("HOME")).
+
+OptionalOf 使用 comma ok 形式的返回值创建 Optional，例如 OptionalOf(os.LookupEnv("HOME"))。
+*/
+func OptionalOf[T any](value T, ok bool) Optional[T] {
+	if !ok {
+		return None[T]()
+	}
+	return Some(value)
+}
+
+/*
+IsPresent returns true if the Optional has a value.
+
+IsPresent 在 Optional 有值时返回 true。
+*/
+func (o Optional[T]) IsPresent() bool {
+	return o.present
+}
+
+/*
+Get returns the value and whether it is present.
+
+Get 返回值及其是否存在。
+*/
+func (o Optional[T]) Get() (T, bool) {
+	return o.value, o.present
+}
+
+/*
+OrElse returns the value if it is present, or other otherwise.
+
+OrElse 在值存在时返回该值，否则返回 other。
+*/
+func (o Optional[T]) OrElse(other T) T {
+	if !o.present {
+		return other
+	}
+	return o.value
+}
+
+/*
+OrElseGet returns the value if it is present, or the result of fn otherwise.
+Use it when the other value is expensive to create.
+
+OrElseGet 在值存在时返回该值，否则返回 fn 的结果。用于另一个值的生成代价较大的情况。
+*/
+func (o Optional[T]) OrElseGet(fn func() T) T {
+	if !o.present {
+		return fn()
+	}
+	return o.value
+}
+
+/*
+MapOptional converts the value of the Optional with fn. An absent Optional stays absent and fn is not called.
+
+MapOptional 使用 fn 转换 Optional 的值。值不存在时仍返回不存在值的 Optional，不调用 fn。
+*/
+func MapOptional[T any, U any](o Optional[T], fn func(T) U) Optional[U] {
+	if !o.present {
+		return None[U]()
+	}
+	return Some(fn(o.value))
+}
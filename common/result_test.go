@@ -0,0 +1,79 @@
+package common
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResult(t *testing.T) {
+	r := Ok(42)
+	assert.True(t, r.IsOk())
+	assert.Equal(t, 42, r.OrElse(0))
+
+	failure := errors.New("failure")
+	f := Fail[int](failure)
+	assert.False(t, f.IsOk())
+	assert.Equal(t, -1, f.OrElse(-1))
+	value, err := f.Get()
+	assert.Equal(t, 0, value)
+	assert.Equal(t, failure, err)
+
+	parsed := ResultOf(strconv.Atoi("12"))
+	assert.Equal(t, 12, parsed.Value)
+	assert.Nil(t, parsed.Err)
+
+	s := MapResult(parsed, func(v int) (string, error) { return strconv.Itoa(v * 2), nil })
+	assert.Equal(t, Ok("24"), s)
+
+	// 有错误时不调用 fn。
+	called := false
+	s = MapResult(f, func(v int) (string, error) { called = true; return "", nil })
+	assert.False(t, called)
+	assert.Equal(t, failure, s.Err)
+
+	s = MapResult(parsed, func(v int) (string, error) { return "", failure })
+	assert.Equal(t, failure, s.Err)
+
+	ch := make(chan Result[int], 2)
+	ch <- Ok(1)
+	ch <- Fail[int](failure)
+	close(ch)
+	sum, errs := 0, 0
+	for r := range ch {
+		if v, err := r.Get(); err != nil {
+			errs++
+		} else {
+			sum += v
+		}
+	}
+	assert.Equal(t, 1, sum)
+	assert.Equal(t, 1, errs)
+}
+
+func TestOptional(t *testing.T) {
+	var zero Optional[string]
+	assert.False(t, zero.IsPresent())
+	assert.Equal(t, None[string](), zero)
+
+	o := Some("a")
+	assert.True(t, o.IsPresent())
+	assert.Equal(t, "a", o.OrElse("b"))
+	assert.Equal(t, "b", zero.OrElse("b"))
+	assert.Equal(t, "c", zero.OrElseGet(func() string { return "c" }))
+	assert.Equal(t, "a", o.OrElseGet(func() string { panic("not called") }))
+
+	lookup := func(key string) (int, bool) {
+		v, ok := map[string]int{"x": 1}[key]
+		return v, ok
+	}
+	v, ok := OptionalOf(lookup("x")).Get()
+	assert.Equal(t, 1, v)
+	assert.True(t, ok)
+	assert.False(t, OptionalOf(lookup("y")).IsPresent())
+
+	assert.Equal(t, Some(2), MapOptional(Some(1), func(v int) int { return v + 1 }))
+	assert.Equal(t, None[int](), MapOptional(None[int](), func(v int) int { return v + 1 }))
+}
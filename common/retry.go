@@ -0,0 +1,163 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+/*
+RetryPolicy defines how [Retry] waits between attempts.
+The delay before the n-th retry is Delay * Multiplier^(n-1), limited to MaxDelay,
+then randomized by Jitter. A Multiplier of 1 gives a fixed backoff.
+
+RetryPolicy 定义 [Retry] 在两次尝试之间如何等待。
+第 n 次重试前的等待时间为 Delay * Multiplier^(n-1)，不超过 MaxDelay，再按 Jitter 随机化。Multiplier 为 1 时为固定间隔。
+*/
+type RetryPolicy struct {
+	MaxAttempts int                                               // the maximum number of attempts, including the first one. 0 or less means no limit
+	Delay       time.Duration                                     // the delay before the first retry
+	MaxDelay    time.Duration                                     // the upper bound of the delay. 0 means no limit
+	Multiplier  float64                                           // the factor applied to the delay after each retry. less than 1 is treated as 1
+	Jitter      float64                                           // the fraction of the delay randomized, from 0 to 1. 0.2 means 80% to 120% of the delay
+	RetryIf     func(err error) bool                              // whether the error should be retried. nil means retry all errors
+	OnRetry     func(attempt int, err error, delay time.Duration) // called before waiting for a retry. nil means no callback
+}
+
+/*
+NewRetryPolicy creates a RetryPolicy with 3 attempts, exponential backoff from 100 milliseconds doubled each time
+up to 10 seconds, 20% jitter, and retrying only transient errors recognized by [IsRetryable].
+
+NewRetryPolicy 创建 RetryPolicy，最多尝试 3 次，指数退避从 100 毫秒开始每次加倍，最多 10 秒，随机化 20%，
+只对 [IsRetryable] 识别的暂时性错误重试。
+*/
+func NewRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 3,
+		Delay:       100 * time.Millisecond,
+		MaxDelay:    10 * time.Second,
+		Multiplier:  2,
+		Jitter:      0.2,
+		RetryIf:     IsRetryable,
+		OnRetry:     nil,
+	}
+}
+
+/*
+Backoff returns the delay before the retry, without jitter.
+
+Parameters:
+  - retry: The number of the retry, starting from 1.
+
+Returns:
+  - The delay.
+
+Backoff 返回重试前的等待时间，不含随机化。
+
+参数:
+  - retry: 重试的次数，从 1 开始。
+
+返回:
+  - 等待时间。
+*/
+func (p *RetryPolicy) Backoff(retry int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	delay := float64(p.Delay)
+	for i := 1; i < retry; i++ {
+		delay *= multiplier
+		// 超过上限后不再计算，同时避免溢出。
+		if p.MaxDelay > 0 && delay >= float64(p.MaxDelay) {
+			return p.MaxDelay
+		}
+	}
+
+	if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+		return p.MaxDelay
+	}
+	return time.Duration(delay)
+}
+
+// jitter 按 Jitter 随机化 delay。
+func (p *RetryPolicy) jitter(delay time.Duration) time.Duration {
+	if p.Jitter <= 0 || delay <= 0 {
+		return delay
+	}
+
+	jitter := p.Jitter
+	if jitter > 1 {
+		jitter = 1
+	}
+	return time.Duration(float64(delay) * (1 + jitter*(2*rand.Float64()-1)))
+}
+
+/*
+Retry calls fn until it succeeds, the error is not to be retried, the attempts are used up, or ctx is done.
+
+Parameters:
+  - ctx: The context to cancel the waiting. nil means context.Background().
+  - policy: The retry policy. nil means [NewRetryPolicy].
+  - fn: The operation to call.
+
+Returns:
+  - nil if fn succeeds.
+  - The last error of fn otherwise. If ctx is done while waiting, both ctx.Err() and the last error are wrapped.
+
+Example:
+
+	// 默认只重试 IsRetryable 的错误，例如 Windows 中被其它进程打开的文件。
+	err := Retry(ctx, nil, func() error {
+		return os.Rename(src, dst)
+	})
+
+Retry 调用 fn，直到成功、错误不需要重试、尝试次数用完或者 ctx 结束。
+
+参数:
+  - ctx: 用于取消等待的 context。nil 表示 context.Background()。
+  - policy: 重试策略。nil 表示 [NewRetryPolicy]。
+  - fn: 要调用的操作。
+
+返回:
+  - fn 成功时返回 nil。
+  - 否则返回 fn 最后一次的错误。在等待时 ctx 结束，则同时包装 ctx.Err() 及最后一次的错误。
+*/
+func Retry(ctx context.Context, policy *RetryPolicy, fn func() error) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if policy == nil {
+		policy = NewRetryPolicy()
+	}
+
+	for attempt := 1; ; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		err := fn()
+		if err == nil {
+			return nil
+		} else if policy.RetryIf != nil && !policy.RetryIf(err) {
+			return err
+		} else if policy.MaxAttempts > 0 && attempt >= policy.MaxAttempts {
+			return err
+		}
+
+		delay := policy.jitter(policy.Backoff(attempt))
+		if policy.OnRetry != nil {
+			policy.OnRetry(attempt, err, delay)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%w: %w", ctx.Err(), err)
+		case <-timer.C:
+		}
+	}
+}
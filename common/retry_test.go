@@ -0,0 +1,118 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := NewRetryPolicy()
+	assert.Equal(t, 100*time.Millisecond, policy.Backoff(1))
+	assert.Equal(t, 200*time.Millisecond, policy.Backoff(2))
+	assert.Equal(t, 400*time.Millisecond, policy.Backoff(3))
+	assert.Equal(t, 10*time.Second, policy.Backoff(100))
+
+	// 固定间隔。
+	policy.Multiplier = 1
+	assert.Equal(t, 100*time.Millisecond, policy.Backoff(5))
+
+	for i := 0; i < 100; i++ {
+		delay := policy.jitter(100 * time.Millisecond)
+		assert.GreaterOrEqual(t, delay, 80*time.Millisecond)
+		assert.LessOrEqual(t, delay, 120*time.Millisecond)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	policy := &RetryPolicy{MaxAttempts: 5, Delay: time.Millisecond, Multiplier: 2}
+	retries := []int{}
+	policy.OnRetry = func(attempt int, err error, delay time.Duration) {
+		retries = append(retries, attempt)
+	}
+
+	calls := 0
+	err := Retry(nil, policy, func() error {
+		calls++
+		if calls < 3 {
+			return errors.New("transient")
+		}
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, []int{1, 2}, retries)
+
+	// 尝试次数用完时返回最后一次的错误。
+	calls = 0
+	last := errors.New("last")
+	err = Retry(context.Background(), policy, func() error {
+		calls++
+		return last
+	})
+	assert.Equal(t, last, err)
+	assert.Equal(t, 5, calls)
+
+	// 不需要重试的错误立即返回。
+	calls = 0
+	permanent := errors.New("permanent")
+	policy.RetryIf = func(err error) bool { return err != permanent }
+	err = Retry(context.Background(), policy, func() error {
+		calls++
+		return permanent
+	})
+	assert.Equal(t, permanent, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestRetryContext(t *testing.T) {
+	policy := &RetryPolicy{Delay: time.Hour}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	failure := errors.New("failure")
+	calls := 0
+	err := Retry(ctx, policy, func() error {
+		calls++
+		return failure
+	})
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.True(t, errors.Is(err, failure))
+	assert.Equal(t, 1, calls)
+
+	// 已结束的 ctx 不再调用 fn。
+	err = Retry(ctx, policy, func() error {
+		calls++
+		return nil
+	})
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Equal(t, 1, calls)
+}
+
+func TestRetryDefaultPolicy(t *testing.T) {
+	// 默认只重试暂时性的错误。
+	calls := 0
+	err := Retry(nil, nil, func() error {
+		calls++
+		return errors.New("permanent")
+	})
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, calls)
+
+	calls = 0
+	err = Retry(nil, nil, func() error {
+		calls++
+		if calls < 2 {
+			return &os.PathError{Op: "open", Path: "a", Err: syscall.EMFILE}
+		}
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, calls)
+}
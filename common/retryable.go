@@ -0,0 +1,61 @@
+package common
+
+import (
+	"errors"
+	"net"
+	"os"
+	"syscall"
+)
+
+/*
+IsTemporary returns true if the error is expected to go away by itself shortly:
+an interrupted system call (EINTR), a resource temporarily unavailable (EAGAIN),
+a timeout of a network or deadline operation, or an error reporting Temporary() true.
+Wrapped errors are unwrapped.
+
+IsTemporary 在错误预期很快会自行消失时返回 true：被中断的系统调用 (EINTR)、资源暂时不可用 (EAGAIN)、
+网络或有截止时间的操作超时，或者 Temporary() 返回 true 的错误。会解开被包装的错误。
+*/
+func IsTemporary(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	// syscall.Errno 的 Temporary() 将 EMFILE 等也视为暂时的，所以单独判断。
+	var errno syscall.Errno
+	if errors.As(err, &errno) {
+		return isTemporaryErrno(errno) || errno.Timeout()
+	}
+
+	if errors.Is(err, os.ErrDeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	var temporary interface{ Temporary() bool }
+	return errors.As(err, &temporary) && temporary.Temporary()
+}
+
+/*
+IsRetryable returns true if retrying the operation may succeed. Besides [IsTemporary] errors,
+it recognizes errors caused by the load of the system or other processes:
+too many open files (EMFILE, ENFILE), a busy device or resource (EBUSY),
+and on Windows, files locked by other processes (sharing and lock violations).
+It is the default RetryIf of [NewRetryPolicy].
+
+IsRetryable 在重试操作可能成功时返回 true。除 [IsTemporary] 的错误外，还识别由系统或其它进程的负载引起的错误：
+打开的文件过多 (EMFILE、ENFILE)、设备或资源忙 (EBUSY)，以及 Windows 中被其它进程锁定的文件（共享冲突及锁冲突）。
+它是 [NewRetryPolicy] 默认的 RetryIf。
+*/
+func IsRetryable(err error) bool {
+	if IsTemporary(err) {
+		return true
+	}
+
+	var errno syscall.Errno
+	return errors.As(err, &errno) && isRetryableErrno(errno)
+}
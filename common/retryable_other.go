@@ -0,0 +1,15 @@
+//go:build !unix && !windows
+
+package common
+
+import "syscall"
+
+// isTemporaryErrno 在其它系统上总是返回 false。plan9 不使用 syscall.Errno，其它系统也没有统一的错误码。
+func isTemporaryErrno(errno syscall.Errno) bool {
+	return false
+}
+
+// isRetryableErrno 在其它系统上总是返回 false。
+func isRetryableErrno(errno syscall.Errno) bool {
+	return false
+}
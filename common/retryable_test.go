@@ -0,0 +1,30 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type temporaryError struct{}
+
+func (temporaryError) Error() string   { return "temporary" }
+func (temporaryError) Temporary() bool { return true }
+
+func TestIsRetryable(t *testing.T) {
+	assert.False(t, IsTemporary(nil))
+	assert.False(t, IsRetryable(nil))
+	assert.False(t, IsRetryable(errors.New("failure")))
+	assert.False(t, IsRetryable(os.ErrNotExist))
+	assert.False(t, IsRetryable(context.Canceled))
+
+	assert.True(t, IsTemporary(fmt.Errorf("wrapped: %w", os.ErrDeadlineExceeded)))
+	assert.True(t, IsTemporary(temporaryError{}))
+	assert.True(t, IsTemporary(&net.DNSError{Err: "timeout", IsTimeout: true}))
+	assert.False(t, IsTemporary(&net.DNSError{Err: "no such host", IsNotFound: true}))
+}
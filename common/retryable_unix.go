@@ -0,0 +1,15 @@
+//go:build unix
+
+package common
+
+import "syscall"
+
+// isTemporaryErrno 判断是否为很快会自行消失的错误。EWOULDBLOCK 在常见平台上与 EAGAIN 相同。
+func isTemporaryErrno(errno syscall.Errno) bool {
+	return errno == syscall.EINTR || errno == syscall.EAGAIN || errno == syscall.EWOULDBLOCK
+}
+
+// isRetryableErrno 判断是否为由系统负载引起、重试可能成功的错误。
+func isRetryableErrno(errno syscall.Errno) bool {
+	return errno == syscall.EMFILE || errno == syscall.ENFILE || errno == syscall.EBUSY
+}
@@ -0,0 +1,24 @@
+//go:build unix
+
+package common
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsRetryableErrno(t *testing.T) {
+	assert.True(t, IsTemporary(&os.PathError{Op: "read", Path: "a", Err: syscall.EINTR}))
+	assert.True(t, IsTemporary(fmt.Errorf("wrapped: %w", syscall.EAGAIN)))
+
+	// 打开的文件过多不会自行消失，但重试可能成功。
+	tooMany := &os.PathError{Op: "open", Path: "a", Err: syscall.EMFILE}
+	assert.False(t, IsTemporary(tooMany))
+	assert.True(t, IsRetryable(tooMany))
+	assert.True(t, IsRetryable(syscall.EINTR))
+	assert.False(t, IsRetryable(syscall.ENOENT))
+}
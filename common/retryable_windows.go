@@ -0,0 +1,23 @@
+//go:build windows
+
+package common
+
+import "syscall"
+
+const (
+	errorTooManyOpenFiles syscall.Errno = 4   // ERROR_TOO_MANY_OPEN_FILES
+	errorSharingViolation syscall.Errno = 32  // ERROR_SHARING_VIOLATION，文件被其它进程打开
+	errorLockViolation    syscall.Errno = 33  // ERROR_LOCK_VIOLATION，文件的一部分被其它进程锁定
+	errorBusy             syscall.Errno = 170 // ERROR_BUSY
+)
+
+// isTemporaryErrno 判断是否为很快会自行消失的错误。
+func isTemporaryErrno(errno syscall.Errno) bool {
+	return errno == syscall.EINTR || errno == syscall.EAGAIN || errno == syscall.EWOULDBLOCK
+}
+
+// isRetryableErrno 判断是否为由系统负载或其它进程引起、重试可能成功的错误。
+func isRetryableErrno(errno syscall.Errno) bool {
+	return errno == errorTooManyOpenFiles || errno == errorSharingViolation ||
+		errno == errorLockViolation || errno == errorBusy
+}
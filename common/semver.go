@@ -0,0 +1,634 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+/*
+Version is a semantic version as defined by SemVer 2.0, such as "1.2.3-rc.1+build.5".
+Versions parsed by [ParseVersion] may also have extra segments, such as 4 in "1.2.3.4".
+
+Version 是 SemVer 2.0 定义的语义化版本号，例如 "1.2.3-rc.1+build.5"。
+[ParseVersion] 解析的版本号还可以有额外的部分，例如 "1.2.3.4" 中的 4。
+*/
+type Version struct {
+	Major      int
+	Minor      int
+	Patch      int
+	Extra      []int  // The segments after the patch, such as [4] in "1.2.3.4". Missing ones are 0 in comparison.
+	PreRelease string // The pre-release identifiers without "-", such as "rc.1". Empty means a release.
+	Build      string // The build metadata without "+", such as "build.5". It is ignored in comparison.
+}
+
+// Version 各部分的序号，用于 [Version.Bump] 及 [Version.Truncate]。额外的部分从 3 开始。
+const (
+	VersionMajor = 0 // The index of the major version.
+	VersionMinor = 1 // The index of the minor version.
+	VersionPatch = 2 // The index of the patch version.
+)
+
+// regexVersion 是宽松的版本号，可以缺少次版本号及补丁版本号，也可以有额外的部分。
+// 预发布标识符可以用“-”分隔，也可以直接以字母开始，例如 "1.0b2"。
+var regexVersion = regexp.MustCompile(`^[vV]?(\d+)((?:\.\d+)*)` +
+	`(?:-([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*)|([a-zA-Z][0-9a-zA-Z-]*(?:\.[0-9a-zA-Z-]+)*))?` +
+	`(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
+
+// regexSemVer 是 semver.org 给出的正则表达式，允许以“v”开始。
+var regexSemVer = regexp.MustCompile(`^[vV]?(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)` +
+	`(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?` +
+	`(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
+
+/*
+ParseSemVer parses a version string strictly per SemVer 2.0. A leading "v", as in git tags, is allowed.
+
+Parameters:
+  - s: The version string, such as "1.2.3", "v1.2.3-rc.1" or "1.2.3+build.5". Spaces around it are ignored.
+
+Returns:
+  - The parsed version.
+  - An error if s is not a valid semantic version.
+
+ParseSemVer 按 SemVer 2.0 严格解析版本号字符串。允许 git 标签中常见的“v”开头。
+
+参数:
+  - s: 版本号字符串，例如 "1.2.3"、"v1.2.3-rc.1" 或 "1.2.3+build.5"。忽略前后的空格。
+
+返回:
+  - 解析后的版本号。
+  - 错误信息。s 不是有效的语义化版本号时返回错误。
+*/
+func ParseSemVer(s string) (Version, error) {
+	subs := regexSemVer.FindStringSubmatch(strings.TrimSpace(s))
+	if subs == nil {
+		return Version{}, fmt.Errorf("invalid semantic version %q", s)
+	}
+
+	v := Version{PreRelease: subs[4], Build: subs[5]}
+	var err error
+	for i, field := range []*int{&v.Major, &v.Minor, &v.Patch} {
+		if *field, err = strconv.Atoi(subs[i+1]); err != nil {
+			return Version{}, fmt.Errorf("invalid semantic version %q: %w", s, err)
+		}
+	}
+	return v, nil
+}
+
+/*
+ParseVersion parses a version string leniently, for versions found in file names and tags that are not strict SemVer.
+Missing minor and patch versions are 0, segments after the patch go to Extra,
+and a pre-release may follow the numbers without "-", as in "1.0b2".
+
+Parameters:
+  - s: The version string, such as "v1", "1.2", "1.2.3.4", "1.0b2" or "1.2.3-rc.1+build.5". Spaces around it are ignored.
+
+Returns:
+  - The parsed version.
+  - An error if s is not a version.
+
+Example:
+
+	v, _ := ParseVersion("v2.5")
+	next := v.Bump(VersionMinor)         // 2.6.0
+	base := v.Truncate(VersionMinor + 1) // 2.5.0
+
+ParseVersion 宽松地解析版本号字符串，用于文件名及标签中不严格符合 SemVer 的版本号。
+缺少的次版本号及补丁版本号为 0，补丁版本号之后的部分保存在 Extra 中，预发布标识符可以不用“-”分隔，例如 "1.0b2"。
+
+参数:
+  - s: 版本号字符串，例如 "v1"、"1.2"、"1.2.3.4"、"1.0b2" 或 "1.2.3-rc.1+build.5"。忽略前后的空格。
+
+返回:
+  - 解析后的版本号。
+  - 错误信息。s 不是版本号时返回错误。
+*/
+func ParseVersion(s string) (Version, error) {
+	subs := regexVersion.FindStringSubmatch(strings.TrimSpace(s))
+	if subs == nil {
+		return Version{}, fmt.Errorf("invalid version %q", s)
+	}
+
+	segments := []int{}
+	for _, text := range strings.Split(subs[1]+subs[2], ".") {
+		n, err := strconv.Atoi(text)
+		if err != nil {
+			return Version{}, fmt.Errorf("invalid version %q: %w", s, err)
+		}
+		segments = append(segments, n)
+	}
+
+	v := Version{PreRelease: subs[3] + subs[4], Build: subs[5]}
+	v.setSegments(segments)
+	return v, nil
+}
+
+/*
+Segments returns the numeric segments, major, minor, patch and then the extra ones.
+
+Segments 返回数字部分，依次为主版本号、次版本号、补丁版本号及额外的部分。
+*/
+func (v Version) Segments() []int {
+	return append([]int{v.Major, v.Minor, v.Patch}, v.Extra...)
+}
+
+// setSegments 设置数字部分，缺少的为 0。
+func (v *Version) setSegments(segments []int) {
+	for len(segments) < 3 {
+		segments = append(segments, 0)
+	}
+
+	v.Major, v.Minor, v.Patch = segments[0], segments[1], segments[2]
+	v.Extra = nil
+	if len(segments) > 3 {
+		v.Extra = append([]int{}, segments[3:]...)
+	}
+}
+
+/*
+Bump returns the version with the segment incremented, the later segments set to 0,
+and the pre-release and build metadata removed.
+
+Parameters:
+  - index: The index of the segment, such as [VersionMajor], [VersionMinor], [VersionPatch], or 3 and above for Extra.
+
+Returns:
+  - The new version. v is not modified.
+
+Bump 返回指定部分加 1、之后的部分为 0，并去除预发布标识符及构建元数据的版本号。
+
+参数:
+  - index: 部分的序号，例如 [VersionMajor]、[VersionMinor]、[VersionPatch]，3 及以上为 Extra。
+
+返回:
+  - 新的版本号。不修改 v。
+*/
+func (v Version) Bump(index int) Version {
+	segments := v.Segments()
+	for len(segments) <= index {
+		segments = append(segments, 0)
+	}
+	if index < 0 {
+		index = 0
+	}
+
+	segments[index]++
+	for i := index + 1; i < len(segments); i++ {
+		segments[i] = 0
+	}
+
+	result := Version{}
+	result.setSegments(segments)
+	return result
+}
+
+/*
+Truncate returns the version keeping only the first count segments, the others set to 0 or removed,
+and the pre-release and build metadata removed. For example, Truncate(2) of "1.2.3.4-rc.1" is "1.2.0".
+
+Truncate 返回只保留前 count 个部分的版本号，其它部分为 0 或被删除，并去除预发布标识符及构建元数据。
+例如 "1.2.3.4-rc.1" 的 Truncate(2) 为 "1.2.0"。
+*/
+func (v Version) Truncate(count int) Version {
+	segments := v.Segments()
+	if count < 0 {
+		count = 0
+	}
+	if count < len(segments) {
+		segments = segments[:count]
+	}
+
+	result := Version{}
+	result.setSegments(segments)
+	return result
+}
+
+/*
+String returns the version in SemVer format, such as "1.2.3-rc.1+build.5". Extra segments follow the patch, as in "1.2.3.4".
+
+String 以 SemVer 格式返回版本号，例如 "1.2.3-rc.1+build.5"。额外的部分在补丁版本号之后，例如 "1.2.3.4"。
+*/
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	for _, n := range v.Extra {
+		s += "." + strconv.Itoa(n)
+	}
+	if v.PreRelease != "" {
+		s += "-" + v.PreRelease
+	}
+	if v.Build != "" {
+		s += "+" + v.Build
+	}
+	return s
+}
+
+/*
+Compare compares two versions by the precedence of SemVer 2.0 §11. The build metadata is ignored.
+Extra segments are compared after the patch, missing ones are 0.
+A pre-release is lower than its release, and pre-release identifiers are compared one by one:
+numeric ones numerically, others in ASCII order, and numeric ones are lower than others.
+So 1.0.0-alpha < 1.0.0-alpha.1 < 1.0.0-beta < 1.0.0-beta.2 < 1.0.0-beta.11 < 1.0.0-rc.1 < 1.0.0.
+
+Returns:
+  - -1: v < other.
+  - 0: v = other.
+  - 1: v > other.
+
+Compare 按 SemVer 2.0 第 11 节的优先级比较两个版本号，忽略构建元数据。额外的部分在补丁版本号之后比较，缺少的为 0。
+预发布版本低于其正式版本，预发布标识符逐个比较：数字按数值比较，其它按 ASCII 顺序比较，数字低于其它。
+所以 1.0.0-alpha < 1.0.0-alpha.1 < 1.0.0-beta < 1.0.0-beta.2 < 1.0.0-beta.11 < 1.0.0-rc.1 < 1.0.0。
+
+返回:
+  - -1: v < other。
+  - 0: v = other。
+  - 1: v > other。
+*/
+func (v Version) Compare(other Version) int {
+	if result := compareInts(v.Segments(), other.Segments()); result != 0 {
+		return result
+	}
+	return comparePreRelease(v.PreRelease, other.PreRelease)
+}
+
+// compareInts 逐个比较两个数组，较短的数组缺少的部分为 0。
+func compareInts(a, b []int) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		x, y := 0, 0
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+
+		if x < y {
+			return -1
+		} else if x > y {
+			return 1
+		}
+	}
+	return 0
+}
+
+// comparePreRelease 按 SemVer 2.0 第 11 节比较预发布标识符。空字符串表示正式版本，高于所有预发布版本。
+func comparePreRelease(a, b string) int {
+	if a == b {
+		return 0
+	} else if a == "" {
+		return 1
+	} else if b == "" {
+		return -1
+	}
+
+	ids1 := strings.Split(a, ".")
+	ids2 := strings.Split(b, ".")
+	for i := 0; i < len(ids1) && i < len(ids2); i++ {
+		n1, err1 := strconv.ParseUint(ids1[i], 10, 64)
+		n2, err2 := strconv.ParseUint(ids2[i], 10, 64)
+
+		switch {
+		case err1 == nil && err2 == nil:
+			if n1 != n2 {
+				if n1 < n2 {
+					return -1
+				}
+				return 1
+			}
+		case err1 == nil: // 数字低于其它标识符。
+			return -1
+		case err2 == nil:
+			return 1
+		default:
+			if result := strings.Compare(ids1[i], ids2[i]); result != 0 {
+				return result
+			}
+		}
+	}
+
+	// 前面的标识符都相同时，标识符少的低。
+	if len(ids1) < len(ids2) {
+		return -1
+	} else if len(ids1) > len(ids2) {
+		return 1
+	}
+	return 0
+}
+
+/*
+Versions implements sort.Interface for []Version in ascending order by [Version.Compare].
+
+Example:
+
+	sort.Sort(Versions(versions))
+	latest := versions[len(versions)-1]
+
+Versions 为 []Version 实现了 sort.Interface，按 [Version.Compare] 升序排列。
+*/
+type Versions []Version
+
+func (vs Versions) Len() int           { return len(vs) }
+func (vs Versions) Less(i, j int) bool { return vs[i].Compare(vs[j]) < 0 }
+func (vs Versions) Swap(i, j int)      { vs[i], vs[j] = vs[j], vs[i] }
+
+// constraintOperator 是约束中比较版本号的运算符。
+type constraintOperator string
+
+const (
+	operatorEqual        constraintOperator = "="
+	operatorNotEqual     constraintOperator = "!="
+	operatorGreater      constraintOperator = ">"
+	operatorGreaterEqual constraintOperator = ">="
+	operatorLess         constraintOperator = "<"
+	operatorLessEqual    constraintOperator = "<="
+)
+
+// comparator 是约束中的一个比较，例如 ">=1.0.0"。
+type comparator struct {
+	operator constraintOperator
+	version  Version
+}
+
+// check 返回 v 是否满足比较条件。
+func (c comparator) check(v Version) bool {
+	result := v.Compare(c.version)
+	switch c.operator {
+	case operatorNotEqual:
+		return result != 0
+	case operatorGreater:
+		return result > 0
+	case operatorGreaterEqual:
+		return result >= 0
+	case operatorLess:
+		return result < 0
+	case operatorLessEqual:
+		return result <= 0
+	default:
+		return result == 0
+	}
+}
+
+/*
+Constraint is a set of version requirements, such as "^1.2", "~1.4.3" or ">=1.0 <2.0". See [ParseConstraint].
+
+Constraint 是一组版本号要求，例如 "^1.2"、"~1.4.3" 或 ">=1.0 <2.0"。参见 [ParseConstraint]。
+*/
+type Constraint struct {
+	text string
+	sets [][]comparator // 各组之间为“或”，组内为“与”。
+}
+
+// regexConstraintVersion 是约束中的版本号，可以只有主版本号或主次版本号，也可以使用“x”或“*”通配。
+var regexConstraintVersion = regexp.MustCompile(`^[vV]?(\d+|[xX*])(?:\.(\d+|[xX*]))?(?:\.(\d+|[xX*]))?` +
+	`(?:-([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?(?:\+[0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*)?$`)
+
+// regexConstraintTerm 将一项约束分为运算符及版本号。
+var regexConstraintTerm = regexp.MustCompile(`^(\^|~|!=|>=|<=|>|<|=)?\s*(\S+)$`)
+
+/*
+ParseConstraint parses a version constraint in the common syntax of package managers.
+
+Parameters:
+  - s: The constraint. Terms separated by spaces or "," must all be satisfied,
+    and groups separated by "||" are alternatives. Each term is one of:
+  - "1.2.3" or "=1.2.3": exactly the version. A partial version like "1.2" or "1.2.x" means any 1.2.z.
+  - "!=1.2.3", ">1.2.3", ">=1.2.3", "<1.2.3", "<=1.2.3": compared with the version. Missing parts are 0.
+  - "^1.2.3": compatible versions, >=1.2.3 <2.0.0. The first non-zero part may not change,
+    so "^0.2.3" is <0.3.0 and "^0.0.3" is <0.0.4.
+  - "~1.4.3": patch updates, >=1.4.3 <1.5.0. "~1" is <2.0.0.
+  - "*" or "x": any version.
+
+Returns:
+  - The parsed constraint.
+  - An error if s is not a valid constraint.
+
+A pre-release version satisfies a group only if a term of the group has a pre-release of the same major,
+minor and patch, so ">=1.0 <2.0" does not accept "2.0.0-rc.1", while ">=2.0.0-rc.1" does.
+
+Example:
+
+	c, _ := ParseConstraint(">=1.0 <2.0 || ^3.1")
+	v, _ := ParseSemVer("3.4.0")
+	ok := c.Check(v) // true
+
+ParseConstraint 解析包管理器常用语法的版本号约束。
+
+参数:
+  - s: 约束。以空格或“,”分隔的各项必须都满足，以“||”分隔的各组满足其一即可。每项为以下之一：
+  - "1.2.3" 或 "=1.2.3": 该版本。"1.2" 或 "1.2.x" 这样不完整的版本号表示任何 1.2.z。
+  - "!=1.2.3"、">1.2.3"、">=1.2.3"、"<1.2.3"、"<=1.2.3": 与该版本比较。缺少的部分为 0。
+  - "^1.2.3": 兼容的版本，>=1.2.3 <2.0.0。第一个不为 0 的部分不能改变，所以 "^0.2.3" 为 <0.3.0，"^0.0.3" 为 <0.0.4。
+  - "~1.4.3": 补丁更新，>=1.4.3 <1.5.0。"~1" 为 <2.0.0。
+  - "*" 或 "x": 任何版本。
+
+返回:
+  - 解析后的约束。
+  - 错误信息。s 不是有效的约束时返回错误。
+
+只有组内某项带有相同主、次及补丁版本号的预发布版本时，预发布版本才能满足该组，
+所以 ">=1.0 <2.0" 不接受 "2.0.0-rc.1"，而 ">=2.0.0-rc.1" 接受。
+*/
+func ParseConstraint(s string) (*Constraint, error) {
+	c := &Constraint{text: strings.TrimSpace(s)}
+
+	for _, group := range strings.Split(s, "||") {
+		// 允许运算符与版本号之间有空格，例如 ">= 1.0"。
+		terms := []string{}
+		for _, field := range strings.Fields(strings.ReplaceAll(group, ",", " ")) {
+			if n := len(terms); n > 0 && strings.Trim(terms[n-1], "^~!=<>") == "" {
+				terms[n-1] += field
+			} else {
+				terms = append(terms, field)
+			}
+		}
+		if len(terms) == 0 {
+			return nil, fmt.Errorf("invalid version constraint %q: empty group", s)
+		}
+
+		set := []comparator{}
+		for _, term := range terms {
+			comparators, err := parseConstraintTerm(term)
+			if err != nil {
+				return nil, fmt.Errorf("invalid version constraint %q: %w", s, err)
+			}
+			set = append(set, comparators...)
+		}
+		c.sets = append(c.sets, set)
+	}
+
+	return c, nil
+}
+
+// parseConstraintTerm 将一项约束转换为比较条件。"*" 返回空数组。
+func parseConstraintTerm(term string) ([]comparator, error) {
+	subs := regexConstraintTerm.FindStringSubmatch(term)
+	if subs == nil {
+		return nil, fmt.Errorf("invalid term %q", term)
+	}
+	operator := subs[1]
+
+	vers := regexConstraintVersion.FindStringSubmatch(subs[2])
+	if vers == nil {
+		return nil, fmt.Errorf("invalid version %q", subs[2])
+	}
+
+	// parts 为给出的数字部分个数，遇到通配符或缺少时结束。
+	v := Version{PreRelease: vers[4]}
+	parts := 0
+	for i, field := range []*int{&v.Major, &v.Minor, &v.Patch} {
+		n, err := strconv.Atoi(vers[i+1])
+		if err != nil {
+			break
+		}
+		*field = n
+		parts++
+	}
+	if parts < 3 && v.PreRelease != "" {
+		return nil, fmt.Errorf("pre-release requires a full version %q", subs[2])
+	}
+
+	// next 返回第 index 部分加 1、之后的部分为 0 的版本号，作为不包含的上限。
+	next := func(index int) Version {
+		upper := []int{v.Major, v.Minor, v.Patch}
+		upper[index]++
+		for i := index + 1; i < 3; i++ {
+			upper[i] = 0
+		}
+		return Version{Major: upper[0], Minor: upper[1], Patch: upper[2]}
+	}
+	between := func(upper Version) []comparator {
+		return []comparator{{operatorGreaterEqual, v}, {operatorLess, upper}}
+	}
+
+	switch {
+	case parts == 0:
+		if operator == "" || operator == "=" || operator == ">=" || operator == "<=" || operator == "^" || operator == "~" {
+			return []comparator{}, nil
+		}
+		return nil, fmt.Errorf("invalid term %q", term)
+	case operator == "^":
+		// 第一个不为 0 的部分不能改变。给出的部分都为 0 时，最后一个给出的部分不能改变。
+		index := parts - 1
+		for i, n := range []int{v.Major, v.Minor, v.Patch}[:parts] {
+			if n != 0 {
+				index = i
+				break
+			}
+		}
+		return between(next(index)), nil
+	case operator == "~":
+		if parts == 1 {
+			return between(next(0)), nil
+		}
+		return between(next(1)), nil
+	case operator == "" || operator == "=":
+		if parts < 3 {
+			return between(next(parts - 1)), nil
+		}
+		return []comparator{{operatorEqual, v}}, nil
+	default:
+		return []comparator{{constraintOperator(operator), v}}, nil
+	}
+}
+
+/*
+Check returns whether the version satisfies the constraint.
+
+Check 返回版本号是否满足约束。
+*/
+func (c *Constraint) Check(v Version) bool {
+	for _, set := range c.sets {
+		if checkSet(set, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkSet 返回 v 是否满足组内所有比较条件，预发布版本还需要组内有相同版本的预发布比较条件。
+func checkSet(set []comparator, v Version) bool {
+	for _, c := range set {
+		if !c.check(v) {
+			return false
+		}
+	}
+
+	if v.PreRelease == "" {
+		return true
+	}
+	for _, c := range set {
+		if c.version.PreRelease != "" &&
+			c.version.Major == v.Major && c.version.Minor == v.Minor && c.version.Patch == v.Patch {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+String returns the constraint as given to [ParseConstraint].
+
+String 返回传给 [ParseConstraint] 的约束字符串。
+*/
+func (c *Constraint) String() string {
+	return c.text
+}
+
+/*
+ErrNoMatchingVersion is returned by [LatestMatching] when no version satisfies the constraint.
+
+ErrNoMatchingVersion 在没有版本号满足约束时由 [LatestMatching] 返回。
+*/
+var ErrNoMatchingVersion = errors.New("no version matches the constraint")
+
+/*
+LatestMatching returns the latest version satisfying the constraint, such as for update checkers
+or finding the newest build directory.
+
+Parameters:
+  - versions: The version strings, parsed by [ParseVersion]. Those can not be parsed are skipped.
+  - constraint: The constraint. See [ParseConstraint].
+
+Returns:
+  - The latest matching version, as given in versions.
+  - An error if the constraint is invalid, or [ErrNoMatchingVersion] if no version matches.
+
+Example:
+
+	latest, err := LatestMatching([]string{"v1.2.0", "v1.10.1", "v2.0.0", "nightly"}, "^1.2")
+	// latest is "v1.10.1".
+
+LatestMatching 返回满足约束的最新版本号，例如用于检查更新或查找最新的构建目录。
+
+参数:
+  - versions: 版本号字符串，使用 [ParseVersion] 解析。跳过无法解析的。
+  - constraint: 约束。参见 [ParseConstraint]。
+
+返回:
+  - 满足约束的最新版本号，与 versions 中的相同。
+  - 错误信息。约束无效时返回错误，没有版本号满足约束时返回 [ErrNoMatchingVersion]。
+*/
+func LatestMatching(versions []string, constraint string) (string, error) {
+	c, err := ParseConstraint(constraint)
+	if err != nil {
+		return "", err
+	}
+
+	latest := ""
+	var latestVersion Version
+	for _, s := range versions {
+		v, err := ParseVersion(s)
+		if err != nil || !c.Check(v) {
+			continue
+		}
+		if latest == "" || v.Compare(latestVersion) > 0 {
+			latest, latestVersion = s, v
+		}
+	}
+
+	if latest == "" {
+		return "", ErrNoMatchingVersion
+	}
+	return latest, nil
+}
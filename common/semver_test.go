@@ -0,0 +1,184 @@
+package common
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSemVer(t *testing.T) {
+	v, err := ParseSemVer(" v1.2.3-rc.1+build.5 ")
+	assert.Nil(t, err)
+	assert.Equal(t, Version{Major: 1, Minor: 2, Patch: 3, PreRelease: "rc.1", Build: "build.5"}, v)
+	assert.Equal(t, "1.2.3-rc.1+build.5", v.String())
+
+	v, err = ParseSemVer("10.20.30")
+	assert.Nil(t, err)
+	assert.Equal(t, "10.20.30", v.String())
+
+	for _, s := range []string{"1.2", "1.2.3.4", "01.2.3", "1.2.3-01", "1.2.3-", "1.2.3+", "a.b.c", ""} {
+		_, err = ParseSemVer(s)
+		assert.NotNil(t, err, s)
+	}
+}
+
+func TestParseVersion(t *testing.T) {
+	cases := []struct {
+		s        string
+		expected string
+	}{
+		{"v1", "1.0.0"},
+		{"1.2", "1.2.0"},
+		{" 01.2.3 ", "1.2.3"},
+		{"1.2.3.4", "1.2.3.4"},
+		{"1.0b2", "1.0.0-b2"},
+		{"1.2.3-rc.1+build.5", "1.2.3-rc.1+build.5"},
+	}
+	for _, c := range cases {
+		v, err := ParseVersion(c.s)
+		assert.Nil(t, err, c.s)
+		assert.Equal(t, c.expected, v.String(), c.s)
+	}
+
+	v, _ := ParseVersion("1.2.3.4.5")
+	assert.Equal(t, []int{4, 5}, v.Extra)
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, v.Segments())
+
+	for _, s := range []string{"", "v", "1.", ".1", "1..2", "a1.2", "1.2-", "1.2+"} {
+		_, err := ParseVersion(s)
+		assert.NotNil(t, err, s)
+	}
+}
+
+func TestVersionBumpTruncate(t *testing.T) {
+	v, _ := ParseVersion("1.2.3.4-rc.1+build.5")
+
+	assert.Equal(t, "2.0.0.0", v.Bump(VersionMajor).String())
+	assert.Equal(t, "1.3.0.0", v.Bump(VersionMinor).String())
+	assert.Equal(t, "1.2.4.0", v.Bump(VersionPatch).String())
+	assert.Equal(t, "1.2.3.5", v.Bump(3).String())
+	assert.Equal(t, "1.2.3.4.0.1", v.Bump(5).String())
+	assert.Equal(t, "1.2.3.4-rc.1+build.5", v.String()) // 不修改 v。
+
+	assert.Equal(t, "1.0.0", v.Truncate(1).String())
+	assert.Equal(t, "1.2.0", v.Truncate(2).String())
+	assert.Equal(t, "1.2.3", v.Truncate(3).String())
+	assert.Equal(t, "1.2.3.4", v.Truncate(9).String())
+	assert.Equal(t, "0.0.0", v.Truncate(0).String())
+
+	// 额外的部分参与比较。
+	a, _ := ParseVersion("1.2.3.1")
+	b, _ := ParseVersion("1.2.3")
+	c, _ := ParseVersion("1.2.3.0")
+	assert.Equal(t, 1, a.Compare(b))
+	assert.Equal(t, 0, b.Compare(c))
+}
+
+func TestVersionCompare(t *testing.T) {
+	ordered := []string{
+		"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta", "1.0.0-beta.2",
+		"1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.0.1", "1.1.0", "2.0.0",
+	}
+	for i := 0; i < len(ordered)-1; i++ {
+		a, _ := ParseSemVer(ordered[i])
+		b, _ := ParseSemVer(ordered[i+1])
+		assert.Equal(t, -1, a.Compare(b), ordered[i])
+		assert.Equal(t, 1, b.Compare(a), ordered[i])
+	}
+
+	// 忽略构建元数据。
+	a, _ := ParseSemVer("1.0.0+a")
+	b, _ := ParseSemVer("1.0.0+b")
+	assert.Equal(t, 0, a.Compare(b))
+}
+
+func TestConstraintCheck(t *testing.T) {
+	cases := []struct {
+		constraint string
+		version    string
+		expected   bool
+	}{
+		{"^1.2", "1.2.0", true},
+		{"^1.2", "1.9.9", true},
+		{"^1.2", "2.0.0", false},
+		{"^1.2", "1.1.9", false},
+		{"^0.2.3", "0.2.9", true},
+		{"^0.2.3", "0.3.0", false},
+		{"^0.0.3", "0.0.4", false},
+		{"^0.0", "0.0.9", true},
+		{"^0.0", "0.1.0", false},
+		{"~1.4.3", "1.4.9", true},
+		{"~1.4.3", "1.5.0", false},
+		{"~1.4.3", "1.4.2", false},
+		{"~1", "1.9.0", true},
+		{">=1.0 <2.0", "1.5.0", true},
+		{">=1.0 <2.0", "2.0.0", false},
+		{">= 1.0, < 2.0", "0.9.0", false},
+		{">=1.0 <2.0", "2.0.0-rc.1", false},
+		{">=2.0.0-rc.1", "2.0.0-rc.2", true},
+		{">=2.0.0-rc.1", "2.1.0-rc.1", false},
+		{"1.2", "1.2.7", true},
+		{"1.2.x", "1.3.0", false},
+		{"=1.2.3", "1.2.3+build", true},
+		{"!=1.2.3", "1.2.3", false},
+		{"<=1.2.3 || >=3", "3.1.0", true},
+		{"<=1.2.3 || >=3", "2.0.0", false},
+		{"*", "0.0.1", true},
+	}
+
+	for _, c := range cases {
+		constraint, err := ParseConstraint(c.constraint)
+		assert.Nil(t, err, c.constraint)
+		v, err := ParseSemVer(c.version)
+		assert.Nil(t, err, c.version)
+		assert.Equal(t, c.expected, constraint.Check(v), c.constraint+" "+c.version)
+	}
+
+	constraint, _ := ParseConstraint(" ^1.2 ")
+	assert.Equal(t, "^1.2", constraint.String())
+
+	for _, s := range []string{"", "1.2 ||", ">", "^a.b", "1.2-rc.1", ">*", "1.2.3 - 2.0"} {
+		_, err := ParseConstraint(s)
+		assert.NotNil(t, err, s)
+	}
+}
+
+func TestSortVersionSlice(t *testing.T) {
+	versions := Versions{}
+	for _, s := range []string{"1.10.0", "1.2.0", "1.2.0-rc.1", "0.9", "1.2.0.1"} {
+		v, err := ParseVersion(s)
+		assert.Nil(t, err)
+		versions = append(versions, v)
+	}
+
+	sort.Sort(versions)
+	result := []string{}
+	for _, v := range versions {
+		result = append(result, v.String())
+	}
+	assert.Equal(t, []string{"0.9.0", "1.2.0-rc.1", "1.2.0", "1.2.0.1", "1.10.0"}, result)
+}
+
+func TestLatestMatching(t *testing.T) {
+	versions := []string{"v1.2.0", "v1.10.1", "nightly", "v2.0.0", "v1.11.0-rc.1", "1.9"}
+
+	latest, err := LatestMatching(versions, "^1.2")
+	assert.Nil(t, err)
+	assert.Equal(t, "v1.10.1", latest)
+
+	latest, err = LatestMatching(versions, ">=1.0 <1.10")
+	assert.Nil(t, err)
+	assert.Equal(t, "1.9", latest)
+
+	latest, err = LatestMatching(versions, "*")
+	assert.Nil(t, err)
+	assert.Equal(t, "v2.0.0", latest)
+
+	_, err = LatestMatching(versions, "^3")
+	assert.Equal(t, ErrNoMatchingVersion, err)
+
+	_, err = LatestMatching(versions, ">>1")
+	assert.NotNil(t, err)
+	assert.NotEqual(t, ErrNoMatchingVersion, err)
+}
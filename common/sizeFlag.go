@@ -0,0 +1,65 @@
+package common
+
+/*
+SizeFlag is a flag.Value that parses a size string like "10MB" into an int64 variable,
+so a command line option can be bound to a field like [fileutils.Filter].MinFileSize.
+Strings are parsed by [ParseSizeString]. Use [ByteSize] when the variable can be of that type.
+
+Example:
+
+	filter := &fileutils.Filter{}
+	flag.Var(common.NewSizeFlag(&filter.MinFileSize), "min-size", "minimum file size, such as 10MB")
+
+SizeFlag 是将“10MB”这样的大小字符串解析到 int64 变量的 flag.Value，
+使命令行选项可以绑定到 [fileutils.Filter].MinFileSize 这样的字段。
+字符串使用 [ParseSizeString] 解析。变量可以是 [ByteSize] 类型时使用它即可。
+*/
+type SizeFlag struct {
+	value *int64
+}
+
+/*
+NewSizeFlag creates a SizeFlag that stores the parsed size in value. The current value is the default.
+
+NewSizeFlag 创建 SizeFlag，解析出的大小保存在 value 中。value 当前的值为默认值。
+*/
+func NewSizeFlag(value *int64) *SizeFlag {
+	return &SizeFlag{value: value}
+}
+
+/*
+String returns the exact size with the largest unit that divides it, such as "10MB". See [ByteSize.MarshalText].
+
+String 使用能整除的最大单位返回准确的大小，例如 "10MB"。参见 [ByteSize.MarshalText]。
+*/
+func (f *SizeFlag) String() string {
+	// flag 包会使用零值调用 String，此时 value 为 nil。
+	if f == nil || f.value == nil {
+		return ""
+	}
+	text, _ := ByteSize(*f.value).MarshalText()
+	return string(text)
+}
+
+/*
+Set parses the size string for flag.Value.
+
+Set 为 flag.Value 解析大小字符串。
+*/
+func (f *SizeFlag) Set(s string) error {
+	size, err := ParseSizeString(s)
+	if err != nil {
+		return err
+	}
+	*f.value = size
+	return nil
+}
+
+/*
+Get returns the size as int64 for flag.Getter.
+
+Get 为 flag.Getter 返回 int64 类型的大小。
+*/
+func (f *SizeFlag) Get() any {
+	return *f.value
+}
@@ -0,0 +1,25 @@
+package common
+
+import (
+	"flag"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSizeFlag(t *testing.T) {
+	minSize := int64(1024)
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Var(NewSizeFlag(&minSize), "min-size", "")
+
+	assert.Equal(t, "1KB", fs.Lookup("min-size").DefValue)
+	assert.Nil(t, fs.Parse([]string{"--min-size", "10MB"}))
+	assert.Equal(t, int64(10*1024*1024), minSize)
+	assert.Equal(t, "10MB", fs.Lookup("min-size").Value.String())
+	assert.Equal(t, int64(10*1024*1024), fs.Lookup("min-size").Value.(flag.Getter).Get())
+
+	assert.NotNil(t, fs.Parse([]string{"--min-size", "ten"}))
+	assert.Equal(t, int64(10*1024*1024), minSize)
+}
@@ -2,6 +2,7 @@ package common
 
 import (
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -69,6 +70,11 @@ CompareVersion 比较两个版本号。版本号必须以 "." 分隔。
   - 1: version1 > version2。
 */
 func CompareVersions(version1, version2 string) int {
+	return compareVersions(version1, version2, strings.Compare)
+}
+
+// compareVersions 与 CompareVersions 相同，但数字部分相同时使用 compareSuffix 比较后缀。
+func compareVersions(version1, version2 string, compareSuffix func(string, string) int) int {
 	// 去年前后的 "."，并以 "." 作为分隔符分离成字符串数组，即子版本号数组。
 	subVerionStrings1 := strings.Split(strings.Trim(version1, "."), ".")
 	subVerionStrings2 := strings.Split(strings.Trim(version2, "."), ".")
@@ -90,13 +96,75 @@ func CompareVersions(version1, version2 string) int {
 		} else if subVersions1[i].number > subVersions2[i].number {
 			return 1
 		} else if subVersions1[i].suffix != subVersions2[i].suffix {
-			return strings.Compare(subVersions1[i].suffix, subVersions2[i].suffix)
+			if result := compareSuffix(subVersions1[i].suffix, subVersions2[i].suffix); result != 0 {
+				return result
+			}
 		}
 	}
 
 	return 0
 }
 
+/*
+CompareSemVersions compares two version numbers in SemVer mode. Unlike [CompareVersions],
+whose suffixes are compared lexicographically and a suffix is newer than none,
+pre-releases are ordered per SemVer 2.0 §11, so 1.0-alpha < 1.0-alpha.2 < 1.0-alpha.10 < 1.0-beta < 1.0-rc.1 < 1.0.
+Both are parsed by [ParseVersion] and compared case insensitively.
+If either can not be parsed, such as "1.1a.0", they are compared by [CompareVersions].
+
+Parameters:
+  - version1: The first version number.
+  - version2: The second version number.
+
+Returns:
+  - -1: version1 < version2.
+  - 0: version1 = version2.
+  - 1: version1 > version2.
+
+Example:
+
+	CompareVersions("1.0-rc.1", "1.0")    // 1, a suffix is newer.
+	CompareSemVersions("1.0-rc.1", "1.0") // -1, a pre-release is older.
+
+CompareSemVersions 以 SemVer 模式比较两个版本号。[CompareVersions] 按字典序比较后缀，且有后缀的较新，
+而此函数按 SemVer 2.0 第 11 节排列预发布版本，所以 1.0-alpha < 1.0-alpha.2 < 1.0-alpha.10 < 1.0-beta < 1.0-rc.1 < 1.0。
+两者都使用 [ParseVersion] 解析，比较时不区分大小写。任一个无法解析时，例如 "1.1a.0"，使用 [CompareVersions] 比较。
+
+参数:
+  - version1: 第一个版本号。
+  - version2: 第二个版本号。
+
+返回:
+  - -1: version1 < version2。
+  - 0: version1 = version2。
+  - 1: version1 > version2。
+*/
+func CompareSemVersions(version1, version2 string) int {
+	v1, err1 := ParseVersion(strings.ToLower(version1))
+	v2, err2 := ParseVersion(strings.ToLower(version2))
+	if err1 != nil || err2 != nil {
+		return CompareVersions(version1, version2)
+	}
+	return v1.Compare(v2)
+}
+
+/*
+SortVersions sorts the version strings in ascending order by [CompareVersions], so the latest is the last.
+Equal versions keep their original order.
+
+Example:
+
+	versions := []string{"1.10", "1.2", "1.9.1"}
+	SortVersions(versions) // ["1.2", "1.9.1", "1.10"]
+
+SortVersions 按 [CompareVersions] 将版本号字符串升序排列，所以最新的在最后。相等的版本号保持原来的顺序。
+*/
+func SortVersions(versions []string) {
+	sort.SliceStable(versions, func(i, j int) bool {
+		return CompareVersions(versions[i], versions[j]) < 0
+	})
+}
+
 /*
 getSubVersions 解析子版本号数组。
 
@@ -0,0 +1,249 @@
+package common
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+/*
+VersionScheme defines how version strings are interpreted by [CompareVersionsWith].
+
+VersionScheme 定义 [CompareVersionsWith] 如何解释版本号字符串。
+*/
+type VersionScheme int
+
+const (
+	VersionSchemeDotted   VersionScheme = iota // sub versions separated by VersionOption.Separators, compared like CompareVersions
+	VersionSchemeSemVer                        // compared like CompareSemVersions
+	VersionSchemeDate                          // dates like "2023.10.05", "2023-10-5" or "20231005", optionally followed by more sub versions
+	VersionSchemeRevision                      // revisions like "r1234", "rev-1234", "build 56" or "1234", optionally followed by more sub versions
+)
+
+/*
+VersionOption defines the options for [CompareVersionsWith] and [SortVersionsWith].
+See [NewVersionOption] for default settings.
+
+VersionOption 定义了 [CompareVersionsWith] 及 [SortVersionsWith] 的选项。默认设置参见 [NewVersionOption]。
+*/
+type VersionOption struct {
+	Scheme      VersionScheme // how to interpret the version strings
+	Separators  string        // the characters separating sub versions in VersionSchemeDotted, such as ".-_". empty means "."
+	SuffixOrder []string      // the suffixes from oldest to newest, used when the numbers are equal. nil means strings.Compare. See [PreReleaseSuffixOrder]
+}
+
+/*
+PreReleaseSuffixOrder ranks common pre-release suffixes before the release, which has no suffix:
+dev < alpha < beta < pre < rc < "". Use it as VersionOption.SuffixOrder, so "1.0-rc1" is older than "1.0".
+
+PreReleaseSuffixOrder 将常见的预发布后缀排在没有后缀的正式版本之前：dev < alpha < beta < pre < rc < ""。
+用作 VersionOption.SuffixOrder，使 "1.0-rc1" 比 "1.0" 旧。
+*/
+var PreReleaseSuffixOrder = []string{"dev", "alpha", "beta", "pre", "rc", ""}
+
+/*
+NewVersionOption creates a new VersionOption with the dotted scheme, "." as the separator
+and suffixes compared by strings.Compare, the same as [CompareVersions].
+
+NewVersionOption 创建默认的 VersionOption。包含以点分隔的方案、分隔符 "." 及使用 strings.Compare 比较后缀，
+与 [CompareVersions] 相同。
+*/
+func NewVersionOption() *VersionOption {
+	return &VersionOption{
+		Scheme:      VersionSchemeDotted,
+		Separators:  ".",
+		SuffixOrder: nil,
+	}
+}
+
+var (
+	// regexDateVersion 是日期版本号，年份为 4 位，月及日可以是 1 位或 2 位，没有分隔符时必须是 2 位。
+	regexDateVersion = regexp.MustCompile(`^\s*(\d{4})(?:[-._/ ](\d{1,2})[-._/ ](\d{1,2})|(\d{2})(\d{2}))(?:$|[-._/ ]+(.*)$)`)
+	// regexRevisionVersion 是修订号，可以有 r、rev、revision、svn、build 或 b 前缀。
+	regexRevisionVersion = regexp.MustCompile(`(?i)^\s*(?:r|rev|revision|svn|build|b)?[-._ ]?(\d+)(?:$|[-._ ]+(.*)$)`)
+)
+
+/*
+CompareVersionsWith compares two version numbers by the scheme of the option.
+A version that does not fit the date or revision scheme is compared with the other by [CompareVersions],
+after the other is converted to dotted form if it fits.
+
+When the numbers of a sub version are equal, the suffixes are ranked by SuffixOrder if it is not nil.
+Leading "-", "_" and "+" of a suffix are ignored, and trailing digits are compared as a number,
+so "rc2" < "rc10". Suffixes not in the order rank after all listed ones and are compared by strings.Compare.
+SuffixOrder does not apply to VersionSchemeSemVer, which has its own pre-release ordering.
+
+Parameters:
+  - version1: The first version number.
+  - version2: The second version number.
+  - option: The options. nil means [NewVersionOption].
+
+Returns:
+  - -1: version1 < version2.
+  - 0: version1 = version2.
+  - 1: version1 > version2.
+
+Example:
+
+	option := NewVersionOption()
+	option.Separators = ".-"
+	CompareVersionsWith("1-2-3", "1.2.4", option) // -1
+
+	option.Scheme = VersionSchemeDate
+	CompareVersionsWith("20231005", "2023-9-30", option) // 1
+
+	option.Scheme = VersionSchemeRevision
+	CompareVersionsWith("r998", "rev-1234", option) // -1
+
+	option.Scheme = VersionSchemeDotted
+	option.SuffixOrder = PreReleaseSuffixOrder
+	CompareVersionsWith("1.0-rc1", "1.0", option) // -1
+
+CompareVersionsWith 按选项中的方案比较两个版本号。
+不符合日期或修订号方案的版本号使用 [CompareVersions] 与另一个比较，另一个符合方案时先转换为点分隔的形式。
+
+子版本号的数字相同时，如果 SuffixOrder 不为 nil，按其排列后缀。忽略后缀开始的 "-"、"_" 及 "+"，
+结尾的数字按数值比较，所以 "rc2" < "rc10"。不在 SuffixOrder 中的后缀排在所有列出的后缀之后，使用 strings.Compare 比较。
+SuffixOrder 不适用于 VersionSchemeSemVer，它有自己的预发布版本顺序。
+
+参数:
+  - version1: 第一个版本号。
+  - version2: 第二个版本号。
+  - option: 选项。nil 表示 [NewVersionOption]。
+
+返回:
+  - -1: version1 < version2。
+  - 0: version1 = version2。
+  - 1: version1 > version2。
+*/
+func CompareVersionsWith(version1, version2 string, option *VersionOption) int {
+	if option == nil {
+		option = NewVersionOption()
+	}
+
+	compareSuffix := strings.Compare
+	if option.SuffixOrder != nil {
+		compareSuffix = newSuffixComparer(option.SuffixOrder)
+	}
+
+	switch option.Scheme {
+	case VersionSchemeSemVer:
+		return CompareSemVersions(version1, version2)
+	case VersionSchemeDate:
+		version1 = normalizeVersion(version1, regexDateVersion, dateVersion)
+		version2 = normalizeVersion(version2, regexDateVersion, dateVersion)
+	case VersionSchemeRevision:
+		version1 = normalizeVersion(version1, regexRevisionVersion, revisionVersion)
+		version2 = normalizeVersion(version2, regexRevisionVersion, revisionVersion)
+	default:
+		version1 = replaceSeparators(version1, option.Separators)
+		version2 = replaceSeparators(version2, option.Separators)
+	}
+	return compareVersions(version1, version2, compareSuffix)
+}
+
+// regexSuffix 将后缀分为名称及结尾的数字，例如 "-rc10" 分为 "rc" 及 "10"。
+var regexSuffix = regexp.MustCompile(`^[-_+]*(.*?)[-_+]*(\d*)$`)
+
+// newSuffixComparer 返回按 order 排列后缀的比较函数。后缀已经是小写的。
+func newSuffixComparer(order []string) func(string, string) int {
+	ranks := make(map[string]int, len(order))
+	for i, suffix := range order {
+		ranks[strings.ToLower(suffix)] = i
+	}
+
+	return func(suffix1, suffix2 string) int {
+		subs1 := regexSuffix.FindStringSubmatch(suffix1)
+		subs2 := regexSuffix.FindStringSubmatch(suffix2)
+
+		rank1, ok1 := ranks[subs1[1]]
+		rank2, ok2 := ranks[subs2[1]]
+		if !ok1 {
+			rank1 = len(order)
+		}
+		if !ok2 {
+			rank2 = len(order)
+		}
+
+		if rank1 != rank2 {
+			return compareInt(rank1, rank2)
+		} else if !ok1 && subs1[1] != subs2[1] {
+			return strings.Compare(subs1[1], subs2[1])
+		}
+
+		// 名称相同，比较结尾的数字，没有数字时为 0。
+		n1, _ := strconv.Atoi(subs1[2])
+		n2, _ := strconv.Atoi(subs2[2])
+		if n1 != n2 {
+			return compareInt(n1, n2)
+		}
+		return strings.Compare(suffix1, suffix2)
+	}
+}
+
+func compareInt(a, b int) int {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+	return 0
+}
+
+/*
+SortVersionsWith sorts the version strings in ascending order by [CompareVersionsWith], so the latest is the last.
+Equal versions keep their original order.
+
+SortVersionsWith 按 [CompareVersionsWith] 将版本号字符串升序排列，所以最新的在最后。相等的版本号保持原来的顺序。
+*/
+func SortVersionsWith(versions []string, option *VersionOption) {
+	sort.SliceStable(versions, func(i, j int) bool {
+		return CompareVersionsWith(versions[i], versions[j], option) < 0
+	})
+}
+
+// replaceSeparators 将 separators 中的字符都替换为 "."。
+func replaceSeparators(version string, separators string) string {
+	if separators == "" || separators == "." {
+		return version
+	}
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(separators, r) {
+			return '.'
+		}
+		return r
+	}, version)
+}
+
+// normalizeVersion 将符合 regex 的版本号由 convert 转换为点分隔的形式，不符合时原样返回。
+func normalizeVersion(version string, regex *regexp.Regexp, convert func(subs []string) string) string {
+	subs := regex.FindStringSubmatch(version)
+	if subs == nil {
+		return version
+	}
+	return convert(subs)
+}
+
+// dateVersion 将日期版本号转换为 "年.月.日.其余部分"，月及日去掉前导 0。
+func dateVersion(subs []string) string {
+	month, day := subs[2], subs[3]
+	if month == "" {
+		month, day = subs[4], subs[5]
+	}
+	m, _ := strconv.Atoi(month)
+	d, _ := strconv.Atoi(day)
+	return joinVersion(subs[1]+"."+strconv.Itoa(m)+"."+strconv.Itoa(d), subs[6])
+}
+
+// revisionVersion 将修订号转换为 "修订号.其余部分"。
+func revisionVersion(subs []string) string {
+	return joinVersion(subs[1], subs[2])
+}
+
+func joinVersion(version string, rest string) string {
+	if rest = strings.TrimSpace(rest); rest == "" {
+		return version
+	}
+	return version + "." + rest
+}
@@ -0,0 +1,75 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareVersionsWithDotted(t *testing.T) {
+	assert.Equal(t, -1, CompareVersionsWith("1.2.3", "1.2.4", nil))
+	// 默认只以 "." 分隔，"1-2-3" 是一个子版本号。
+	assert.Equal(t, 1, CompareVersionsWith("1-2-3", "1.2.4", nil))
+
+	option := NewVersionOption()
+	option.Separators = ".-_"
+	assert.Equal(t, -1, CompareVersionsWith("1-2-3", "1.2.4", option))
+	assert.Equal(t, 0, CompareVersionsWith("1_2_3", "1.2.3", option))
+	assert.Equal(t, 1, CompareVersionsWith("1-10", "1-9", option))
+
+	option.Scheme = VersionSchemeSemVer
+	assert.Equal(t, -1, CompareVersionsWith("1.0.0-rc.1", "1.0.0", option))
+}
+
+func TestCompareVersionsWithDate(t *testing.T) {
+	option := &VersionOption{Scheme: VersionSchemeDate}
+
+	assert.Equal(t, 0, CompareVersionsWith("2023.10.05", "2023-10-5", option))
+	assert.Equal(t, 0, CompareVersionsWith("20231005", "2023_10_05", option))
+	assert.Equal(t, 1, CompareVersionsWith("20231005", "2023-9-30", option))
+	assert.Equal(t, -1, CompareVersionsWith("2023.10.05", "2023.10.05.1", option))
+	assert.Equal(t, 1, CompareVersionsWith("2023-10-05-2", "20231005.1", option))
+	// 不是日期的版本号按 CompareVersions 比较。
+	assert.Equal(t, -1, CompareVersionsWith("latest", "2023.1.1", option))
+
+	versions := []string{"2023-10-05", "20230105", "2022.12.31", "2023.1.5.2", "2023_9_30"}
+	SortVersionsWith(versions, option)
+	assert.Equal(t, []string{"2022.12.31", "20230105", "2023.1.5.2", "2023_9_30", "2023-10-05"}, versions)
+}
+
+func TestCompareVersionsWithRevision(t *testing.T) {
+	option := &VersionOption{Scheme: VersionSchemeRevision}
+
+	assert.Equal(t, -1, CompareVersionsWith("r998", "rev-1234", option))
+	assert.Equal(t, 0, CompareVersionsWith("R1234", "1234", option))
+	assert.Equal(t, 0, CompareVersionsWith("svn 1234", "build_1234", option))
+	assert.Equal(t, -1, CompareVersionsWith("r1234", "r1234.1", option))
+	assert.Equal(t, 1, CompareVersionsWith("b10", "b9", option))
+
+	versions := []string{"r100", "r99", "rev-1000", "build 5"}
+	SortVersionsWith(versions, option)
+	assert.Equal(t, []string{"build 5", "r99", "r100", "rev-1000"}, versions)
+}
+
+func TestCompareVersionsWithSuffixOrder(t *testing.T) {
+	option := NewVersionOption()
+	// 默认按 strings.Compare 比较后缀，有后缀的更新。
+	assert.Equal(t, 1, CompareVersionsWith("1.0-rc1", "1.0", option))
+
+	option.SuffixOrder = PreReleaseSuffixOrder
+	assert.Equal(t, -1, CompareVersionsWith("1.0-rc1", "1.0", option))
+	assert.Equal(t, -1, CompareVersionsWith("1.0-alpha", "1.0-beta", option))
+	assert.Equal(t, -1, CompareVersionsWith("1.0-rc2", "1.0-rc10", option))
+	assert.Equal(t, 0, CompareVersionsWith("1.0-RC1", "1.0-rc1", option))
+	assert.Equal(t, 1, CompareVersionsWith("1.1-dev", "1.0", option))
+	// 未列出的后缀排在最后。
+	assert.Equal(t, 1, CompareVersionsWith("1.0-hotfix", "1.0", option))
+	assert.Equal(t, -1, CompareVersionsWith("1.0-hotfix", "1.0-patch", option))
+
+	versions := []string{"1.0", "1.0-rc1", "1.0-beta2", "1.0-dev", "1.0-beta10", "0.9"}
+	SortVersionsWith(versions, option)
+	assert.Equal(t, []string{"0.9", "1.0-dev", "1.0-beta2", "1.0-beta10", "1.0-rc1", "1.0"}, versions)
+
+	option.Scheme = VersionSchemeDate
+	assert.Equal(t, -1, CompareVersionsWith("2023.10.05-rc1", "20231005", option))
+}
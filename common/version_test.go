@@ -36,3 +36,26 @@ func TestCompareVersions(t *testing.T) {
 	// ' -234' is trimed and treated as string '-234'.
 	assert.Equal(t, 0, CompareVersions("1.1 -234", "1.1-234"))
 }
+
+func TestSortVersions(t *testing.T) {
+	versions := []string{"1.10", "1.2", "1.9.1", "1.2.0", "1.1b.1", "1.1.0"}
+	SortVersions(versions)
+	assert.Equal(t, []string{"1.1.0", "1.1b.1", "1.2", "1.2.0", "1.9.1", "1.10"}, versions)
+
+	SortVersions(nil)
+}
+
+func TestCompareSemVersions(t *testing.T) {
+	ordered := []string{"1.0-alpha", "1.0-Alpha.2", "1.0-alpha.10", "1.0beta", "1.0-rc.1", "1.0", "1.0.1", "v1.1"}
+	for i := 0; i < len(ordered)-1; i++ {
+		assert.Equal(t, -1, CompareSemVersions(ordered[i], ordered[i+1]), ordered[i])
+		assert.Equal(t, 1, CompareSemVersions(ordered[i+1], ordered[i]), ordered[i])
+	}
+
+	assert.Equal(t, 1, CompareVersions("1.0-rc.1", "1.0"))
+	assert.Equal(t, -1, CompareSemVersions("1.0-rc.1", "1.0"))
+	assert.Equal(t, 0, CompareSemVersions("1.0-RC.1+a", "1.0.0-rc.1+b"))
+
+	// 无法解析时使用 CompareVersions。
+	assert.Equal(t, CompareVersions("1.1a.0", "1.1A.1"), CompareSemVersions("1.1a.0", "1.1A.1"))
+}
@@ -0,0 +1,159 @@
+package config
+
+import (
+	"fmt"
+	"hash"
+
+	"github.com/jqk/futool4go/common"
+	"github.com/jqk/futool4go/fileutils"
+)
+
+/*
+Config is the shared configuration of tools built on futool4go. See [Default] for default settings.
+
+Config 是基于 futool4go 的工具共用的配置。默认设置参见 [Default]。
+*/
+type Config struct {
+	Filter  fileutils.Filter `mapstructure:"filter"`  // the file filter
+	Walk    WalkConfig       `mapstructure:"walk"`    // how to walk a directory
+	Archive ArchiveConfig    `mapstructure:"archive"` // how to compress files
+	Sync    SyncConfig       `mapstructure:"sync"`    // how to copy a directory to another
+}
+
+/*
+WalkConfig is the configurable part of [fileutils.WalkOption].
+
+WalkConfig 是 [fileutils.WalkOption] 中可以配置的部分。
+*/
+type WalkConfig struct {
+	Recursive           bool `mapstructure:"recursive"`           // whether to walk sub directories
+	SkipPermissionError bool `mapstructure:"skipPermissionError"` // whether to skip files and directories without permission
+}
+
+/*
+ArchiveConfig is the configurable part of [fileutils.CompressOption].
+
+ArchiveConfig 是 [fileutils.CompressOption] 中可以配置的部分。
+*/
+type ArchiveConfig struct {
+	Format   fileutils.CompressionFormat `mapstructure:"format"`   // the compression format, such as "gzip"
+	Level    int                         `mapstructure:"level"`    // the compression level, format-specific. -1 means the default level
+	Checksum string                      `mapstructure:"checksum"` // the checksum algorithm, such as sha256. See [common.RegisterHash]. empty means no checksum
+}
+
+/*
+SyncConfig is the configurable part of [fileutils.CopyDirOption]. The walk settings are at the same level,
+such as "sync.recursive".
+
+SyncConfig 是 [fileutils.CopyDirOption] 中可以配置的部分。遍历的设置在同一层级，例如 "sync.recursive"。
+*/
+type SyncConfig struct {
+	WalkConfig        `mapstructure:",squash"`
+	PreserveHardLinks bool `mapstructure:"preserveHardLinks"` // whether to keep hard links in the target
+}
+
+/*
+Default creates a Config with the same settings as the defaults of fileutils:
+all files are included, directories are walked recursively skipping permission errors,
+archives are gzip with the default level and no checksum, and hard links are preserved.
+
+Default 创建与 fileutils 默认值相同的 Config：包含所有文件，递归遍历目录并跳过没有权限的文件及目录，
+压缩使用 gzip 及默认级别且不计算校验值，以及保留硬链接。
+*/
+func Default() *Config {
+	compress := fileutils.NewCompressOption()
+	walk := WalkConfig{Recursive: true, SkipPermissionError: true}
+
+	return &Config{
+		Filter: fileutils.Filter{
+			CaseSensitive: false,
+			Include:       []string{"*"},
+			Exclude:       []string{},
+			MinFileSize:   0,
+			MaxFileSize:   0,
+			ExcludeHidden: false,
+		},
+		Walk: walk,
+		Archive: ArchiveConfig{
+			Format:   compress.Format,
+			Level:    compress.Level,
+			Checksum: "",
+		},
+		Sync: SyncConfig{
+			WalkConfig:        walk,
+			PreserveHardLinks: true,
+		},
+	}
+}
+
+/*
+Validate checks the settings. The filter patterns are normalized like [fileutils.Filter.Validate].
+
+Validate 检查设置。过滤条件的模式与 [fileutils.Filter.Validate] 一样被规范化。
+*/
+func (c *Config) Validate() error {
+	if err := c.Filter.Validate(); err != nil {
+		return fmt.Errorf("filter: %w", err)
+	}
+
+	if c.Archive.Format == "" {
+		return fmt.Errorf("archive: format must not be empty")
+	}
+	if _, err := newHash(c.Archive.Checksum); err != nil {
+		return fmt.Errorf("archive: %w", err)
+	}
+
+	return nil
+}
+
+/*
+WalkOption creates a [fileutils.WalkOption] from the settings.
+
+WalkOption 根据设置创建 [fileutils.WalkOption]。
+*/
+func (c *WalkConfig) WalkOption() *fileutils.WalkOption {
+	option := &fileutils.WalkOption{Recursive: c.Recursive}
+	if c.SkipPermissionError {
+		option.PathErrorHandler = fileutils.SkipPermissionError
+	}
+	return option
+}
+
+/*
+CompressOption creates a [fileutils.CompressOption] from the settings.
+It returns an error if the checksum algorithm is unknown.
+
+CompressOption 根据设置创建 [fileutils.CompressOption]。校验算法未知时返回错误。
+*/
+func (c *ArchiveConfig) CompressOption() (*fileutils.CompressOption, error) {
+	checksum, err := newHash(c.Checksum)
+	if err != nil {
+		return nil, err
+	}
+
+	option := fileutils.NewCompressOption()
+	option.Format = c.Format
+	option.Level = c.Level
+	option.Checksum = checksum
+	return option, nil
+}
+
+/*
+CopyDirOption creates a [fileutils.CopyDirOption] from the settings.
+
+CopyDirOption 根据设置创建 [fileutils.CopyDirOption]。
+*/
+func (c *SyncConfig) CopyDirOption() *fileutils.CopyDirOption {
+	return &fileutils.CopyDirOption{
+		WalkOption:        *c.WalkConfig.WalkOption(),
+		PreserveHardLinks: c.PreserveHardLinks,
+	}
+}
+
+// newHash 按名称创建校验算法，名称为空时返回 nil。
+func newHash(name string) (hash.Hash, error) {
+	if name == "" {
+		return nil, nil
+	}
+	return common.NewHash(name)
+}
@@ -0,0 +1,201 @@
+package config
+
+import (
+	"crypto/sha256"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/jqk/futool4go/fileutils"
+	"github.com/stretchr/testify/assert"
+)
+
+const yamlConfig = `
+filter:
+  include: ["*.JPG", "*.png"]
+  excludeHidden: true
+  minFileSize: 1KB
+walk:
+  recursive: false
+archive:
+  level: 9
+  checksum: sha256
+sync:
+  skipPermissionError: false
+  preserveHardLinks: false
+`
+
+const tomlConfig = `
+# 与 yamlConfig 相同。
+[filter]
+include = [
+  "*.JPG",
+  "*.png", # 注释
+]
+excludeHidden = true
+minFileSize = "1KB"
+
+[walk]
+recursive = false
+
+[archive]
+level = 9
+checksum = 'sha256'
+
+[sync]
+skipPermissionError = false
+preserveHardLinks = false
+`
+
+const jsonConfig = `{
+  "filter": {"include": ["*.JPG", "*.png"], "excludeHidden": true, "minFileSize": 1024},
+  "walk": {"recursive": false},
+  "archive": {"level": 9, "checksum": "sha256"},
+  "sync": {"skipPermissionError": false, "preserveHardLinks": false}
+}`
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	noEnv := &LoadOption{}
+
+	for name, content := range map[string]string{"a.yaml": yamlConfig, "a.toml": tomlConfig, "a.json": jsonConfig} {
+		path := filepath.Join(dir, name)
+		assert.Nil(t, os.WriteFile(path, []byte(content), 0644))
+
+		cfg, err := Load(path, noEnv)
+		assert.Nil(t, err, name)
+		assert.Equal(t, []string{"*.jpg", "*.png"}, cfg.Filter.Include, name)
+		assert.True(t, cfg.Filter.ExcludeHidden, name)
+		assert.Equal(t, int64(1024), cfg.Filter.MinFileSize, name)
+		assert.False(t, cfg.Walk.Recursive, name)
+		assert.Equal(t, fileutils.CompressionGzip, cfg.Archive.Format, name) // 保留默认值。
+		assert.Equal(t, 9, cfg.Archive.Level, name)
+		assert.Equal(t, "sha256", cfg.Archive.Checksum, name)
+		assert.True(t, cfg.Sync.Recursive, name)
+		assert.False(t, cfg.Sync.SkipPermissionError, name)
+		assert.False(t, cfg.Sync.PreserveHardLinks, name)
+	}
+
+	// 没有文件时使用默认值。
+	cfg, err := Load("", noEnv)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"*"}, cfg.Filter.Include)
+	assert.True(t, cfg.Walk.Recursive)
+
+	_, err = Load(filepath.Join(dir, "missing.yaml"), noEnv)
+	assert.NotNil(t, err)
+	_, err = Load(filepath.Join(dir, "a.yaml"), &LoadOption{Format: "ini"})
+	assert.NotNil(t, err)
+}
+
+func TestDecodeErrors(t *testing.T) {
+	decode := func(content string) error {
+		_, err := Decode(strings.NewReader(content), &LoadOption{Format: FormatYAML})
+		return err
+	}
+
+	assert.Nil(t, decode(""))
+	assert.ErrorContains(t, decode("filter:\n  unknown: 1"), "unknown setting filter.unknown")
+	assert.ErrorContains(t, decode("walk: true"), "setting walk")
+	assert.ErrorContains(t, decode("archive:\n  level: high"), "setting archive.level")
+	assert.ErrorContains(t, decode("archive:\n  checksum: crc"), "unknown hash algorithm")
+	assert.ErrorContains(t, decode("archive:\n  format: ''"), "format must not be empty")
+	assert.ErrorContains(t, decode("filter:\n  minFileSize: 10\n  maxFileSize: 5"), "filter:")
+	assert.ErrorContains(t, decode("filter:\n  include: [1]"), "setting filter.include")
+}
+
+func TestEnvOverride(t *testing.T) {
+	env := map[string]string{
+		"APP_FILTER_INCLUDE":           "*.txt, *.md",
+		"APP_FILTER_MAX_FILE_SIZE":     "2MB",
+		"APP_WALK_RECURSIVE":           "false",
+		"APP_SYNC_PRESERVE_HARD_LINKS": "false",
+		"APP_ARCHIVE_LEVEL":            "1",
+		"FUTOOL_ARCHIVE_LEVEL":         "2", // 前缀不同，不使用。
+	}
+	option := &LoadOption{
+		Format:    FormatYAML,
+		EnvPrefix: "app",
+		LookupEnv: func(key string) (string, bool) {
+			value, ok := env[key]
+			return value, ok
+		},
+	}
+
+	cfg, err := Decode(strings.NewReader("archive:\n  level: 9"), option)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"*.md", "*.txt"}, cfg.Filter.Include)
+	assert.Equal(t, int64(2*1024*1024), cfg.Filter.MaxFileSize)
+	assert.False(t, cfg.Walk.Recursive)
+	assert.False(t, cfg.Sync.PreserveHardLinks)
+	assert.Equal(t, 1, cfg.Archive.Level)
+
+	// 文件中的字符串引用环境变量。
+	env["APP_EXT"] = "go"
+	cfg, err = Decode(strings.NewReader("filter:\n  include: ['*.${APP_EXT}', '*.${APP_MISSING:-txt}']"), &LoadOption{
+		Format: FormatYAML, LookupEnv: option.LookupEnv,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"*.go", "*.txt"}, cfg.Filter.Include)
+
+	env["APP_WALK_RECURSIVE"] = "maybe"
+	_, err = Decode(strings.NewReader(""), option)
+	assert.ErrorContains(t, err, "APP_WALK_RECURSIVE")
+}
+
+func TestOptions(t *testing.T) {
+	cfg := Default()
+	assert.Nil(t, cfg.Validate())
+
+	walk := cfg.Walk.WalkOption()
+	assert.True(t, walk.Recursive)
+	assert.NotNil(t, walk.PathErrorHandler)
+
+	cfg.Walk.SkipPermissionError = false
+	assert.Nil(t, cfg.Walk.WalkOption().PathErrorHandler)
+
+	compress, err := cfg.Archive.CompressOption()
+	assert.Nil(t, err)
+	assert.Equal(t, fileutils.NewCompressOption(), compress)
+
+	cfg.Archive.Checksum = "SHA256"
+	compress, err = cfg.Archive.CompressOption()
+	assert.Nil(t, err)
+	assert.Equal(t, sha256.Size, compress.Checksum.Size())
+
+	cfg.Archive.Checksum = "crc"
+	_, err = cfg.Archive.CompressOption()
+	assert.NotNil(t, err)
+
+	copyDir := cfg.Sync.CopyDirOption()
+	assert.True(t, copyDir.Recursive)
+	assert.True(t, copyDir.PreserveHardLinks)
+}
+
+func TestLoadDefault(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+		t.Skip("XDG directories are only used on Unix")
+	}
+
+	root := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", root)
+	noEnv := &LoadOption{}
+
+	path, err := FindFile("app")
+	assert.Nil(t, err)
+	assert.Equal(t, "", path)
+	cfg, err := LoadDefault("app", noEnv)
+	assert.Nil(t, err)
+	assert.Equal(t, Default().Walk, cfg.Walk)
+
+	assert.Nil(t, os.MkdirAll(filepath.Join(root, "app"), 0755))
+	assert.Nil(t, os.WriteFile(filepath.Join(root, "app", "config.toml"), []byte("[walk]\nrecursive = false"), 0644))
+	path, err = FindFile("app")
+	assert.Nil(t, err)
+	assert.Equal(t, filepath.Join(root, "app", "config.toml"), path)
+	cfg, err = LoadDefault("app", noEnv)
+	assert.Nil(t, err)
+	assert.False(t, cfg.Walk.Recursive)
+}
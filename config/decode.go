@@ -0,0 +1,213 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/jqk/futool4go/common"
+)
+
+/*
+decodeStruct 将 values 中的设置写入结构体 target，只修改 values 中有的字段，其它字段保留默认值。
+字段名称取自 mapstructure 标签，没有标签时为首字母小写的字段名，键不区分大小写。
+标签为 ",squash" 或者匿名的结构体字段，其设置与外层在同一层级。未知的键返回错误。
+*/
+func decodeStruct(values map[string]any, target reflect.Value, path string) error {
+	fields := map[string]reflect.Value{}
+	collectFields(target, fields)
+
+	for key, value := range values {
+		field, ok := fields[strings.ToLower(key)]
+		if !ok {
+			return fmt.Errorf("unknown setting %s", joinPath(path, key))
+		}
+		if err := assign(value, field, joinPath(path, key)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// collectFields 收集结构体的字段，键为小写的字段名称。
+func collectFields(target reflect.Value, fields map[string]reflect.Value) {
+	targetType := target.Type()
+	for i := 0; i < targetType.NumField(); i++ {
+		structField := targetType.Field(i)
+		if !structField.IsExported() {
+			continue
+		}
+
+		name, squash := fieldName(structField)
+		if squash {
+			collectFields(target.Field(i), fields)
+		} else {
+			fields[strings.ToLower(name)] = target.Field(i)
+		}
+	}
+}
+
+// fieldName 返回字段的设置名称，以及是否与外层在同一层级。
+func fieldName(field reflect.StructField) (string, bool) {
+	name, options, _ := strings.Cut(field.Tag.Get("mapstructure"), ",")
+	if options == "squash" || (field.Anonymous && name == "") {
+		return "", true
+	}
+	if name == "" {
+		runes := []rune(field.Name)
+		runes[0] = unicode.ToLower(runes[0])
+		name = string(runes)
+	}
+	return name, false
+}
+
+// assign 将设置的值写入字段。字符串可以转换为任何标量类型，因此也用于环境变量。
+func assign(value any, field reflect.Value, path string) error {
+	invalid := func() error {
+		return fmt.Errorf("invalid value %v for setting %s of type %s", value, path, field.Type())
+	}
+	text, isText := value.(string)
+
+	switch field.Kind() {
+	case reflect.Struct:
+		values, ok := value.(map[string]any)
+		if !ok {
+			return invalid()
+		}
+		return decodeStruct(values, field, path)
+
+	case reflect.Bool:
+		if b, ok := value.(bool); ok {
+			field.SetBool(b)
+		} else if b, err := strconv.ParseBool(strings.TrimSpace(text)); isText && err == nil {
+			field.SetBool(b)
+		} else {
+			return invalid()
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, ok := toInt64(value)
+		if !ok || field.OverflowInt(n) {
+			return invalid()
+		}
+		field.SetInt(n)
+
+	case reflect.String:
+		if !isText {
+			return invalid()
+		}
+		field.SetString(text)
+
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String {
+			return invalid()
+		}
+
+		var items []string
+		switch v := value.(type) {
+		case string:
+			// 环境变量中的列表以逗号分隔。
+			for _, item := range strings.Split(v, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					items = append(items, item)
+				}
+			}
+		case []any:
+			for _, item := range v {
+				s, ok := item.(string)
+				if !ok {
+					return invalid()
+				}
+				items = append(items, s)
+			}
+		default:
+			return invalid()
+		}
+
+		list := reflect.MakeSlice(field.Type(), len(items), len(items))
+		for i, item := range items {
+			list.Index(i).SetString(item)
+		}
+		field.Set(list)
+
+	default:
+		return fmt.Errorf("setting %s of type %s is not supported", path, field.Type())
+	}
+
+	return nil
+}
+
+// toInt64 将各种格式解析出的数字转换为 int64。字符串可以是带单位的大小，例如 "10MB"。
+func toInt64(value any) (int64, bool) {
+	switch v := value.(type) {
+	case int:
+		return int64(v), true
+	case int64:
+		return v, true
+	case uint64:
+		return int64(v), v <= math.MaxInt64
+	case float64:
+		// JSON 的数字都是 float64。
+		return int64(v), v == math.Trunc(v) && v >= math.MinInt64 && v < math.MaxInt64
+	case string:
+		text := strings.TrimSpace(v)
+		if n, err := strconv.ParseInt(text, 10, 64); err == nil {
+			return n, true
+		}
+		n, err := common.ParseSizeString(text)
+		return n, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// applyEnv 使用环境变量覆盖结构体的字段，变量名称为前缀加上大写下划线形式的设置路径。
+func applyEnv(target reflect.Value, prefix string, lookup func(string) (string, bool)) error {
+	targetType := target.Type()
+	for i := 0; i < targetType.NumField(); i++ {
+		structField := targetType.Field(i)
+		if !structField.IsExported() {
+			continue
+		}
+
+		name, squash := fieldName(structField)
+		field := target.Field(i)
+		key := prefix
+		if !squash {
+			key += "_" + upperSnake(name)
+		}
+
+		if field.Kind() == reflect.Struct {
+			if err := applyEnv(field, key, lookup); err != nil {
+				return err
+			}
+		} else if value, ok := lookup(key); ok {
+			if err := assign(value, field, key); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// upperSnake 将 "minFileSize" 转换为 "MIN_FILE_SIZE"。
+func upperSnake(name string) string {
+	builder := strings.Builder{}
+	for i, r := range name {
+		if i > 0 && unicode.IsUpper(r) {
+			builder.WriteByte('_')
+		}
+		builder.WriteRune(unicode.ToUpper(r))
+	}
+	return builder.String()
+}
+
+func joinPath(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
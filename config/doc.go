@@ -0,0 +1,28 @@
+/*
+config loads the settings of [fileutils.Filter], [fileutils.WalkOption], [fileutils.CompressOption]
+and [fileutils.CopyDirOption] from YAML, TOML or JSON files, with environment variable overrides,
+defaults and validation, so tools built on futool4go share one way of configuration.
+
+A configuration file in YAML looks like this:
+
+	filter:
+	  include: ["*.jpg", "*.png"]
+	  excludeHidden: true
+	  minFileSize: 1KB
+	walk:
+	  recursive: true
+	archive:
+	  format: gzip
+	  level: 9
+	  checksum: sha256
+	sync:
+	  preserveHardLinks: false
+
+TOML support covers what such files need: tables, keys, strings, numbers, booleans and arrays.
+
+config 从 YAML、TOML 或 JSON 文件加载 [fileutils.Filter]、[fileutils.WalkOption]、[fileutils.CompressOption]
+及 [fileutils.CopyDirOption] 的设置，支持环境变量覆盖、默认值及校验，使基于 futool4go 的工具使用相同的配置方式。
+
+TOML 支持此类文件所需的部分：表、键、字符串、数字、布尔值及数组。
+*/
+package config
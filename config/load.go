@@ -0,0 +1,278 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/jqk/futool4go/common"
+	"gopkg.in/yaml.v3"
+)
+
+/*
+Format is the format of a configuration file.
+
+Format 是配置文件的格式。
+*/
+type Format string
+
+const (
+	FormatYAML Format = "yaml"
+	FormatTOML Format = "toml"
+	FormatJSON Format = "json"
+)
+
+/*
+FormatOf returns the format by the extension of the file name: .yaml, .yml, .toml or .json, case insensitive.
+It returns an empty string for other extensions.
+
+FormatOf 根据文件扩展名返回格式：.yaml、.yml、.toml 或 .json，不区分大小写。其它扩展名返回空字符串。
+*/
+func FormatOf(path string) Format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return FormatYAML
+	case ".toml":
+		return FormatTOML
+	case ".json":
+		return FormatJSON
+	default:
+		return ""
+	}
+}
+
+/*
+LoadOption defines the options for [Load] and [Decode].
+See [NewLoadOption] for default settings.
+
+LoadOption 定义了 [Load] 及 [Decode] 的选项。默认设置参见 [NewLoadOption]。
+*/
+type LoadOption struct {
+	Format    Format                          // the format of the content. empty means by the file extension in Load
+	EnvPrefix string                          // the prefix of environment variables overriding the settings. empty means no override
+	LookupEnv func(key string) (string, bool) // looks up an environment variable. nil means os.LookupEnv
+}
+
+/*
+NewLoadOption creates a new LoadOption with the format by file extension,
+environment variables prefixed by "FUTOOL" and os.LookupEnv.
+
+NewLoadOption 创建默认的 LoadOption。包含根据文件扩展名确定格式、前缀为 "FUTOOL" 的环境变量及 os.LookupEnv。
+*/
+func NewLoadOption() *LoadOption {
+	return &LoadOption{
+		Format:    "",
+		EnvPrefix: "FUTOOL",
+		LookupEnv: nil,
+	}
+}
+
+/*
+Load reads the configuration file, applies it over [Default],
+overrides the settings with environment variables, and validates the result.
+
+An environment variable is named by the prefix and the path of the setting in upper snake case,
+such as FUTOOL_FILTER_MIN_FILE_SIZE for "filter.minFileSize". Lists are separated by commas.
+Sizes can have units, such as "10MB", see [common.ParseSizeString].
+Strings in the file can reference environment variables, such as "${DATA_DIR:-/tmp}/*.log", see [common.ExpandEnv].
+
+Parameters:
+  - path: The configuration file. Empty means no file, only the defaults and the environment variables are used.
+  - option: The options. nil means [NewLoadOption].
+
+Returns:
+  - The configuration.
+  - An error if the file can not be read or parsed, or the result is invalid.
+
+Example:
+
+	cfg, err := config.Load("futool.yaml", nil)
+	if err != nil {
+		return err
+	}
+	files, err := cfg.Filter.GetFiles(root, cfg.Walk.WalkOption())
+
+Load 读取配置文件，在 [Default] 的基础上应用其设置，使用环境变量覆盖设置，并校验结果。
+
+环境变量的名称为前缀加上设置路径的大写下划线形式，例如 "filter.minFileSize" 为 FUTOOL_FILTER_MIN_FILE_SIZE。
+列表以逗号分隔。大小可以带单位，例如 "10MB"，参见 [common.ParseSizeString]。
+文件中的字符串可以引用环境变量，例如 "${DATA_DIR:-/tmp}/*.log"，参见 [common.ExpandEnv]。
+
+参数:
+  - path: 配置文件。为空表示没有文件，只使用默认值及环境变量。
+  - option: 选项。nil 表示 [NewLoadOption]。
+
+返回:
+  - 配置。
+  - 错误信息。文件不能读取或解析，或者结果无效时返回错误。
+*/
+func Load(path string, option *LoadOption) (*Config, error) {
+	if option == nil {
+		option = NewLoadOption()
+	}
+
+	var data []byte
+	format := option.Format
+	if path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, err
+		}
+		if format == "" {
+			if format = FormatOf(path); format == "" {
+				return nil, fmt.Errorf("unknown config format of %s", path)
+			}
+		}
+	}
+
+	optionCopy := *option
+	optionCopy.Format = format
+	return Decode(bytes.NewReader(data), &optionCopy)
+}
+
+/*
+FindFile returns the configuration file of the application in [common.UserConfigDir],
+the first existing one of config.yaml, config.yml, config.toml and config.json,
+such as ~/.config/app/config.yaml on Linux.
+
+Parameters:
+  - app: The name of the application.
+
+Returns:
+  - The path of the file. Empty if none exists.
+  - An error if the configuration directory can not be determined.
+
+FindFile 返回应用程序在 [common.UserConfigDir] 中的配置文件，即 config.yaml、config.yml、config.toml 及 config.json 中第一个存在的，
+例如 Linux 中的 ~/.config/app/config.yaml。
+
+参数:
+  - app: 应用程序名称。
+
+返回:
+  - 文件路径。都不存在时为空。
+  - 错误信息。不能确定配置目录时返回错误。
+*/
+func FindFile(app string) (string, error) {
+	dir, err := common.UserConfigDir(app)
+	if err != nil {
+		return "", err
+	}
+
+	for _, name := range []string{"config.yaml", "config.yml", "config.toml", "config.json"} {
+		path := filepath.Join(dir, name)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, nil
+		}
+	}
+	return "", nil
+}
+
+/*
+LoadDefault loads the configuration file of the application found by [FindFile] like [Load].
+If there is no such file, only the defaults and the environment variables are used.
+
+LoadDefault 与 [Load] 一样加载 [FindFile] 找到的应用程序配置文件。没有该文件时只使用默认值及环境变量。
+*/
+func LoadDefault(app string, option *LoadOption) (*Config, error) {
+	path, err := FindFile(app)
+	if err != nil {
+		return nil, err
+	}
+	return Load(path, option)
+}
+
+/*
+Decode is like [Load], reading the content from the reader. option.Format must not be empty
+unless the content is empty. Empty content is allowed and gives the defaults.
+
+Decode 与 [Load] 相同，但从 reader 读取内容。除非内容为空，否则 option.Format 不能为空。允许内容为空，此时得到默认值。
+*/
+func Decode(reader io.Reader, option *LoadOption) (*Config, error) {
+	if option == nil {
+		option = NewLoadOption()
+	}
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := parse(data, option.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	lookup := option.LookupEnv
+	if lookup == nil {
+		lookup = os.LookupEnv
+	}
+	expandValues(values, lookup)
+
+	cfg := Default()
+	target := reflect.ValueOf(cfg).Elem()
+	if err = decodeStruct(values, target, ""); err != nil {
+		return nil, err
+	}
+
+	if option.EnvPrefix != "" {
+		if err = applyEnv(target, strings.ToUpper(option.EnvPrefix), lookup); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// parse 将内容解析为 map，键为设置名称，值为标量、列表或嵌套的 map。
+func parse(data []byte, format Format) (map[string]any, error) {
+	values := map[string]any{}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return values, nil
+	} else if format != FormatYAML && format != FormatTOML && format != FormatJSON {
+		return nil, fmt.Errorf("unknown config format %q", format)
+	}
+
+	var err error
+	switch format {
+	case FormatYAML:
+		err = yaml.Unmarshal(data, &values)
+	case FormatTOML:
+		values, err = parseTOML(data)
+	default:
+		err = json.Unmarshal(data, &values)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", format, err)
+	}
+	return values, nil
+}
+
+// expandValues 展开所有字符串中引用的环境变量，包括列表及嵌套的 map 中的字符串。
+func expandValues(values map[string]any, lookup func(string) (string, bool)) {
+	var expand func(value any) any
+	expand = func(value any) any {
+		switch v := value.(type) {
+		case string:
+			return common.ExpandEnvWith(v, lookup)
+		case []any:
+			for i := range v {
+				v[i] = expand(v[i])
+			}
+		case map[string]any:
+			for key := range v {
+				v[key] = expand(v[key])
+			}
+		}
+		return value
+	}
+	expand(values)
+}
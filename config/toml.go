@@ -0,0 +1,330 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+/*
+parseTOML 解析配置文件所需的 TOML 子集：注释、[table] 及 [a.b] 表头、key = value 及 a.b = value 形式的键，
+键可以是裸键、基本字符串或字面字符串，值为基本字符串、字面字符串、整数、浮点数、布尔值，以及由这些值组成、可以跨行的数组。
+不支持内联表、表数组、多行字符串及日期时间，遇到时返回错误。不符合 TOML 的值也返回错误，而不是猜测其含义。
+*/
+func parseTOML(data []byte) (map[string]any, error) {
+	root := map[string]any{}
+	table := root
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(stripTOMLComment(scanner.Text()))
+		if line == "" {
+			continue
+		}
+
+		fail := func(format string, args ...any) error {
+			return fmt.Errorf("line %d: %s", lineNumber, fmt.Sprintf(format, args...))
+		}
+
+		if strings.HasPrefix(line, "[") {
+			if strings.HasPrefix(line, "[[") {
+				return nil, fail("unsupported table header %s", line)
+			}
+
+			keys, rest, err := parseTOMLKey(line[1:], ']')
+			if err != nil {
+				return nil, fail("invalid table header %s: %v", line, err)
+			}
+			if strings.TrimSpace(rest) != "" {
+				return nil, fail("unexpected %s after table header", strings.TrimSpace(rest))
+			}
+			if table, err = tomlTable(root, keys); err != nil {
+				return nil, fail("%v", err)
+			}
+			continue
+		}
+
+		keys, text, err := parseTOMLKey(line, '=')
+		if err != nil {
+			return nil, fail("%v", err)
+		}
+		text = strings.TrimSpace(text)
+
+		// 数组可以跨行，读到方括号配对为止。
+		for strings.HasPrefix(text, "[") && !tomlArrayClosed(text) && scanner.Scan() {
+			lineNumber++
+			text += " " + strings.TrimSpace(stripTOMLComment(scanner.Text()))
+		}
+
+		value, err := parseTOMLValue(text)
+		if err != nil {
+			return nil, fail("%v", err)
+		}
+
+		parent, err := tomlTable(table, keys[:len(keys)-1])
+		if err != nil {
+			return nil, fail("%v", err)
+		}
+		name := keys[len(keys)-1]
+		if _, exists := parent[name]; exists {
+			return nil, fail("duplicate key %s", strings.Join(keys, "."))
+		}
+		parent[name] = value
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// tomlTable 返回 keys 指定的表，不存在时创建。
+func tomlTable(table map[string]any, keys []string) (map[string]any, error) {
+	for _, key := range keys {
+		switch child := table[key].(type) {
+		case nil:
+			next := map[string]any{}
+			table[key] = next
+			table = next
+		case map[string]any:
+			table = child
+		default:
+			return nil, fmt.Errorf("%s is not a table", key)
+		}
+	}
+	return table, nil
+}
+
+// parseTOMLKey 解析以 end 结束的键，返回以点分隔的各部分及 end 之后的内容。
+// 各部分可以是裸键、基本字符串或字面字符串，引号中的点及 end 不是分隔符。
+func parseTOMLKey(text string, end byte) ([]string, string, error) {
+	keys := []string{}
+	i := skipTOMLSpace(text, 0)
+	for {
+		if i >= len(text) {
+			return nil, "", fmt.Errorf("missing key")
+		}
+
+		var key string
+		switch text[i] {
+		case '"':
+			j := i + 1
+			for ; j < len(text) && text[j] != '"'; j++ {
+				if text[j] == '\\' {
+					j++
+				}
+			}
+			if j >= len(text) {
+				return nil, "", fmt.Errorf("unterminated key %s", text[i:])
+			}
+			var err error
+			if key, err = strconv.Unquote(text[i : j+1]); err != nil {
+				return nil, "", fmt.Errorf("invalid key %s", text[i:j+1])
+			}
+			i = j + 1
+		case '\'':
+			j := strings.IndexByte(text[i+1:], '\'')
+			if j < 0 {
+				return nil, "", fmt.Errorf("unterminated key %s", text[i:])
+			}
+			key = text[i+1 : i+1+j]
+			i += j + 2
+		default:
+			j := i
+			for j < len(text) && isTOMLBareKeyChar(text[j]) {
+				j++
+			}
+			if j == i {
+				return nil, "", fmt.Errorf("invalid key %s", text[i:])
+			}
+			key = text[i:j]
+			i = j
+		}
+		keys = append(keys, key)
+
+		i = skipTOMLSpace(text, i)
+		switch {
+		case i < len(text) && text[i] == '.':
+			i = skipTOMLSpace(text, i+1)
+		case i < len(text) && text[i] == end:
+			return keys, text[i+1:], nil
+		default:
+			return nil, "", fmt.Errorf("missing %c after key %s", end, strings.Join(keys, "."))
+		}
+	}
+}
+
+// isTOMLBareKeyChar 检查 c 是否可以用于裸键：字母、数字、"_" 及 "-"。
+func isTOMLBareKeyChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
+
+func skipTOMLSpace(text string, i int) int {
+	for i < len(text) && (text[i] == ' ' || text[i] == '\t') {
+		i++
+	}
+	return i
+}
+
+// parseTOMLValue 解析单个值。
+func parseTOMLValue(text string) (any, error) {
+	switch {
+	case text == "":
+		return nil, fmt.Errorf("missing value")
+	case text == "true":
+		return true, nil
+	case text == "false":
+		return false, nil
+	case strings.HasPrefix(text, `"""`) || strings.HasPrefix(text, "'''"):
+		return nil, fmt.Errorf("multi-line strings are not supported")
+	case text[0] == '"':
+		return strconv.Unquote(text)
+	case text[0] == '\'':
+		if len(text) < 2 || text[len(text)-1] != '\'' {
+			return nil, fmt.Errorf("invalid string %s", text)
+		}
+		return text[1 : len(text)-1], nil
+	case text[0] == '[':
+		return parseTOMLArray(text)
+	case text[0] == '{':
+		return nil, fmt.Errorf("inline tables are not supported")
+	}
+
+	return parseTOMLNumber(text)
+}
+
+var (
+	// regexTOMLInteger 是十进制整数，不能有前导 0，"_" 只能在数字之间。
+	regexTOMLInteger = regexp.MustCompile(`^[+-]?(?:0|[1-9](?:_?[0-9])*)$`)
+	// regexTOMLPrefixedInteger 是十六进制、八进制及二进制整数，不能有符号。
+	regexTOMLPrefixedInteger = regexp.MustCompile(`^0(?:x[0-9A-Fa-f](?:_?[0-9A-Fa-f])*|o[0-7](?:_?[0-7])*|b[01](?:_?[01])*)$`)
+	// regexTOMLFloat 是有小数部分或指数部分的浮点数，小数点两侧都必须有数字。
+	regexTOMLFloat = regexp.MustCompile(`^[+-]?(?:0|[1-9](?:_?[0-9])*)(?:\.[0-9](?:_?[0-9])*)?(?:[eE][+-]?[0-9](?:_?[0-9])*)?$`)
+	// regexTOMLSpecialFloat 是无穷大及非数，只能是小写的 inf 及 nan。
+	regexTOMLSpecialFloat = regexp.MustCompile(`^([+-]?)(inf|nan)$`)
+)
+
+// parseTOMLNumber 按 TOML 的规则解析整数及浮点数。
+func parseTOMLNumber(text string) (any, error) {
+	number := strings.ReplaceAll(text, "_", "")
+
+	switch {
+	case regexTOMLInteger.MatchString(text):
+		return strconv.ParseInt(number, 10, 64)
+	case regexTOMLPrefixedInteger.MatchString(text):
+		bases := map[byte]int{'x': 16, 'o': 8, 'b': 2}
+		return strconv.ParseInt(number[2:], bases[number[1]], 64)
+	case regexTOMLFloat.MatchString(text):
+		return strconv.ParseFloat(number, 64)
+	}
+
+	if subs := regexTOMLSpecialFloat.FindStringSubmatch(text); subs != nil {
+		if subs[2] == "nan" {
+			return math.NaN(), nil
+		}
+		if subs[1] == "-" {
+			return math.Inf(-1), nil
+		}
+		return math.Inf(1), nil
+	}
+	return nil, fmt.Errorf("invalid value %s", text)
+}
+
+// parseTOMLArray 解析数组，元素以逗号分隔，允许最后有逗号。
+func parseTOMLArray(text string) ([]any, error) {
+	if !tomlArrayClosed(text) || text[len(text)-1] != ']' {
+		return nil, fmt.Errorf("invalid array %s", text)
+	}
+
+	result := []any{}
+	for _, item := range splitTOMLArray(text[1 : len(text)-1]) {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		value, err := parseTOMLValue(item)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, value)
+	}
+	return result, nil
+}
+
+// splitTOMLArray 以不在字符串及嵌套数组中的逗号分隔数组内容。
+func splitTOMLArray(text string) []string {
+	items := []string{}
+	depth, start := 0, 0
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+		case c == ',' && depth == 0:
+			items = append(items, text[start:i])
+			start = i + 1
+		}
+	}
+	return append(items, text[start:])
+}
+
+// tomlArrayClosed 检查不在字符串中的方括号是否已经配对。
+func tomlArrayClosed(text string) bool {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+		}
+	}
+	return depth == 0
+}
+
+// stripTOMLComment 去掉不在字符串中的“#”及之后的内容。
+func stripTOMLComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
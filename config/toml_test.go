@@ -0,0 +1,107 @@
+package config
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTOML(t *testing.T) {
+	values, err := parseTOML([]byte(`
+title = "a # b" # 注释
+count = 1_000
+ratio = 0.5
+hex = 0x10
+enabled = true
+site.name = 'x\y'
+list = [1, "two", [3]]
+
+[a.b]
+c = "中"
+`))
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]any{
+		"title":   "a # b",
+		"count":   int64(1000),
+		"ratio":   0.5,
+		"hex":     int64(16),
+		"enabled": true,
+		"site":    map[string]any{"name": `x\y`},
+		"list":    []any{int64(1), "two", []any{int64(3)}},
+		"a":       map[string]any{"b": map[string]any{"c": "中"}},
+	}, values)
+
+	for _, text := range []string{
+		"a",
+		"a = ",
+		"a = {b = 1}",
+		"[[a]]",
+		"a = 1\na = 2",
+		"a = 1\n[a]",
+		`a = """x"""`,
+		"a = [1, 2",
+		"a = bad",
+		"a b = 1",
+		`"a = 1`,
+		"a. = 1",
+		"[a] b",
+		"[a",
+		// 不符合 TOML 的数字。
+		"a = 010",
+		"a = 1__0",
+		"a = _1",
+		"a = 1_",
+		"a = 1.",
+		"a = .5",
+		"a = 0x",
+		"a = -0x10",
+		"a = infinity",
+		"a = Inf",
+		"a = NaN",
+		"a = 9223372036854775808",
+	} {
+		_, err = parseTOML([]byte(text))
+		assert.NotNil(t, err, text)
+	}
+}
+
+func TestParseTOMLKeys(t *testing.T) {
+	values, err := parseTOML([]byte(`
+"a=b" = 1
+"x.y".z = 2
+'lit.k' = 3
+bare-key_1 . "sub key" = 4
+
+[ "t.1" . 'u=v' ]
+k = "#"
+`))
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]any{
+		"a=b":        int64(1),
+		"x.y":        map[string]any{"z": int64(2)},
+		"lit.k":      int64(3),
+		"bare-key_1": map[string]any{"sub key": int64(4)},
+		"t.1":        map[string]any{"u=v": map[string]any{"k": "#"}},
+	}, values)
+}
+
+func TestParseTOMLNumbers(t *testing.T) {
+	values, err := parseTOML([]byte(`
+zero = 0
+signed = [+5, -5, -0]
+prefixed = [0x1F, 0o17, 0b101, 0xdead_beef]
+floats = [1e3, 1.5E-2, -0.25, 6.626_1e-34, 1e0_1]
+special = [inf, +inf, -inf]
+`))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), values["zero"])
+	assert.Equal(t, []any{int64(5), int64(-5), int64(0)}, values["signed"])
+	assert.Equal(t, []any{int64(31), int64(15), int64(5), int64(0xdeadbeef)}, values["prefixed"])
+	assert.Equal(t, []any{1e3, 1.5e-2, -0.25, 6.6261e-34, 1e1}, values["floats"])
+	assert.Equal(t, []any{math.Inf(1), math.Inf(1), math.Inf(-1)}, values["special"])
+
+	value, err := parseTOMLValue("-nan")
+	assert.Nil(t, err)
+	assert.True(t, math.IsNaN(value.(float64)))
+}
@@ -0,0 +1,277 @@
+package fileutils
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+/*
+ArchiveReport describes the differences between an archive and its source. All paths are slash separated and sorted.
+
+ArchiveReport 描述了归档文件与其来源的差异。所有路径都以斜杠分隔，已排序。
+*/
+type ArchiveReport struct {
+	Missing   []string // Files in the source but not in the archive.
+	Extra     []string // Files in the archive but not in the source.
+	Corrupted []string // Files whose checksum in the archive differs from the source.
+	Verified  []string // Files with the same checksum.
+}
+
+/*
+IsClean returns true if no file is missing, extra or corrupted.
+
+IsClean 在没有缺失、多余或损坏的文件时返回 true。
+*/
+func (r *ArchiveReport) IsClean() bool {
+	return len(r.Missing) == 0 && len(r.Extra) == 0 && len(r.Corrupted) == 0
+}
+
+/*
+VerifyArchive compares the checksum of each file in the archive with the source.
+Zip, tar and gzip compressed tar archives are supported, detected by content.
+Directories, links and other non-regular entries in the archive are ignored.
+
+Parameters:
+  - archive: the archive file.
+  - source: a directory the archive was created from, or a manifest file saved by [Manifest.Save].
+    Paths in the archive are compared with paths relative to the directory or recorded in the manifest.
+  - filter: only files matching the filter, both in the archive and the source directory, are compared. nil means all files.
+    It is not applied to the manifest.
+  - buffer: buffer for reading files.
+  - provider: the object that performs the checksum calculation, cannot be nil.
+    Its method must be the same as the manifest.
+
+Returns:
+  - the report.
+  - an error if any occurred.
+
+VerifyArchive 将归档文件中每个文件的校验值与来源比较。支持 zip、tar 及 gzip 压缩的 tar，根据内容识别格式。
+忽略归档中的目录、链接及其它非普通文件条目。
+
+参数:
+  - archive: 归档文件。
+  - source: 创建归档时的目录，或者由 [Manifest.Save] 保存的清单文件。归档中的路径与相对于该目录或清单中记录的路径比较。
+  - filter: 只比较满足过滤条件的文件，同时作用于归档及来源目录。nil 表示所有文件。不作用于清单。
+  - buffer: 读取文件的缓冲区。
+  - provider: 执行校验和计算的对象，不能为 nil。其算法必须与清单相同。
+
+返回:
+  - 比较报告。
+  - 错误信息。
+*/
+func VerifyArchive(
+	archive string,
+	source string,
+	filter *Filter,
+	buffer []byte,
+	provider FileChecksumCalculationProvider,
+) (*ArchiveReport, error) {
+	if provider == nil {
+		return nil, errors.New("provider must not be nil")
+	} else if len(buffer) == 0 {
+		return nil, errors.New("buffer must not be empty")
+	}
+
+	if filter != nil {
+		if err := filter.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	manifest, err := loadArchiveSource(source, filter, buffer, provider)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ArchiveReport{Missing: []string{}, Extra: []string{}, Corrupted: []string{}, Verified: []string{}}
+	found := make(map[string]bool)
+
+	err = eachArchiveFile(archive, func(name string, info os.FileInfo, reader io.Reader) error {
+		if filter != nil && filter.IsMatched(info) != nil {
+			return nil
+		}
+
+		if err := getReaderChecksumWithProvider(reader, info, buffer, provider); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+
+		found[name] = true
+		entry, ok := manifest.Entries[name]
+		if !ok {
+			report.Extra = append(report.Extra, name)
+		} else if entry.Checksum != hex.EncodeToString(provider.FullChecksum()) {
+			report.Corrupted = append(report.Corrupted, name)
+		} else {
+			report.Verified = append(report.Verified, name)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	for name := range manifest.Entries {
+		if !found[name] {
+			report.Missing = append(report.Missing, name)
+		}
+	}
+
+	for _, paths := range [][]string{report.Missing, report.Extra, report.Corrupted, report.Verified} {
+		sort.Strings(paths)
+	}
+
+	return report, nil
+}
+
+// loadArchiveSource 根据来源是目录还是文件，创建或读取清单。
+func loadArchiveSource(
+	source string,
+	filter *Filter,
+	buffer []byte,
+	provider FileChecksumCalculationProvider,
+) (*Manifest, error) {
+	info, err := os.Stat(source)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.IsDir() {
+		return CreateManifest(source, filter, nil, buffer, provider)
+	}
+
+	manifest, err := LoadManifest(source)
+	if err != nil {
+		return nil, err
+	} else if manifest.Method != provider.Method() {
+		return nil, fmt.Errorf("method mismatch: manifest is %s, provider is %s", manifest.Method, provider.Method())
+	}
+	return manifest, nil
+}
+
+// archiveFileFunc 是遍历归档中普通文件时的回调函数类型。name 为以斜杠分隔的相对路径。
+type archiveFileFunc func(name string, info os.FileInfo, reader io.Reader) error
+
+// eachArchiveFile 遍历归档中的普通文件。
+func eachArchiveFile(archive string, handler archiveFileFunc) error {
+	header, err := readFileHeader(archive, 512)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case bytes.HasPrefix(header, []byte("PK\x03\x04")) || bytes.HasPrefix(header, []byte("PK\x05\x06")):
+		return eachZipFile(archive, handler)
+	case bytes.HasPrefix(header, []byte("\x1f\x8b")):
+		return eachTarFile(archive, true, handler)
+	case len(header) > 262 && string(header[257:262]) == "ustar":
+		return eachTarFile(archive, false, handler)
+	}
+
+	return fmt.Errorf("unsupported archive format: %s", archive)
+}
+
+func eachZipFile(archive string, handler archiveFileFunc) error {
+	reader, err := zip.OpenReader(archive)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	for _, file := range reader.File {
+		if !file.Mode().IsRegular() {
+			continue
+		}
+
+		content, err := file.Open()
+		if err != nil {
+			return err
+		}
+
+		err = handler(normalizeArchiveName(file.Name), file.FileInfo(), content)
+		content.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func eachTarFile(archive string, compressed bool, handler archiveFileFunc) error {
+	file, err := os.Open(archive)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var source io.Reader = file
+	if compressed {
+		gzipReader, err := gzip.NewReader(file)
+		if err != nil {
+			return err
+		}
+		defer gzipReader.Close()
+		source = gzipReader
+	}
+
+	reader := tar.NewReader(source)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		// 旧的归档中普通文件的类型可能是 TypeRegA（'\x00'），较新的 Go 版本才会将其转换为 TypeReg。
+		if header.Typeflag != tar.TypeReg && header.Typeflag != tar.TypeRegA {
+			continue
+		}
+
+		if err = handler(normalizeArchiveName(header.Name), header.FileInfo(), reader); err != nil {
+			return err
+		}
+	}
+}
+
+// normalizeArchiveName 将归档中的路径统一为以斜杠分隔、不以 ./ 或 / 开始的形式。
+func normalizeArchiveName(name string) string {
+	name = path.Clean(strings.ReplaceAll(name, `\`, "/"))
+	return strings.TrimPrefix(name, "/")
+}
+
+// getReaderChecksumWithProvider 与 GetFileChecksumWithProvider 类似，但从 reader 读取内容，只计算完整校验值。
+func getReaderChecksumWithProvider(
+	reader io.Reader,
+	info os.FileInfo,
+	buffer []byte,
+	provider FileChecksumCalculationProvider,
+) error {
+	provider.Reset()
+
+	for {
+		readCount, err := reader.Read(buffer)
+		if readCount > 0 {
+			if _, err := provider.ChecksumCalculator(buffer[:readCount]); err != nil {
+				return err
+			}
+		}
+
+		if err == io.EOF {
+			return provider.FullReadyHandler(info)
+		} else if err != nil {
+			return err
+		}
+	}
+}
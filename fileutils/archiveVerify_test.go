@@ -0,0 +1,145 @@
+package fileutils
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"crypto/md5"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyArchive(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{"a.txt": "aaa", "sub/b.txt": "bbb", "sub/c.md": "ccc"}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		assert.Nil(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.Nil(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	// 归档中 a.txt 一致，sub/b.txt 损坏，缺少 sub/c.md，多出 extra.txt。
+	entries := []struct{ name, content string }{
+		{"./a.txt", "aaa"}, {"sub/", ""}, {"sub/b.txt", "bad"}, {"extra.txt", "extra"},
+	}
+
+	dir := t.TempDir()
+	zipFile := filepath.Join(dir, "test.zip")
+	tgzFile := filepath.Join(dir, "test.tar.gz")
+	writeTestZip(t, zipFile, entries)
+	writeTestTarGz(t, tgzFile, entries)
+
+	buffer := make([]byte, 1024)
+	p := NewCommonFileChecksumProvider("MD5", md5.New())
+
+	for _, archive := range []string{zipFile, tgzFile} {
+		report, err := VerifyArchive(archive, root, nil, buffer, p)
+		assert.Nil(t, err)
+		assert.False(t, report.IsClean())
+		assert.Equal(t, []string{"a.txt"}, report.Verified)
+		assert.Equal(t, []string{"sub/b.txt"}, report.Corrupted)
+		assert.Equal(t, []string{"sub/c.md"}, report.Missing)
+		assert.Equal(t, []string{"extra.txt"}, report.Extra)
+	}
+
+	// 旧的归档中普通文件的类型为 '\x00'。
+	oldTar := filepath.Join(dir, "old.tar")
+	writeTestTar(t, oldTar, tar.TypeRegA, []struct{ name, content string }{{"a.txt", "aaa"}, {"sub/b.txt", "bbb"}})
+	report, err := VerifyArchive(oldTar, root, nil, buffer, p)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a.txt", "sub/b.txt"}, report.Verified)
+	assert.Equal(t, []string{"sub/c.md"}, report.Missing)
+
+	// 与清单比较，并使用过滤条件。
+	manifest, err := CreateManifest(root, nil, nil, buffer, p)
+	assert.Nil(t, err)
+	manifestFile := filepath.Join(dir, "manifest.json")
+	assert.Nil(t, manifest.Save(manifestFile))
+
+	report, err = VerifyArchive(zipFile, manifestFile, &Filter{Include: []string{"a.txt"}}, buffer, p)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a.txt"}, report.Verified)
+	assert.Equal(t, 2, len(report.Missing))
+	assert.Equal(t, 0, len(report.Extra))
+
+	// 算法不一致。
+	_, err = VerifyArchive(zipFile, manifestFile, nil, buffer, NewCommonFileChecksumProvider("crc32", crc32.NewIEEE()))
+	assert.NotNil(t, err)
+
+	// 不支持的格式。
+	_, err = VerifyArchive(manifestFile, root, nil, buffer, p)
+	assert.NotNil(t, err)
+}
+
+func writeTestZip(t *testing.T, filename string, entries []struct{ name, content string }) {
+	file, err := os.Create(filename)
+	assert.Nil(t, err)
+	defer file.Close()
+
+	writer := zip.NewWriter(file)
+	for _, entry := range entries {
+		w, err := writer.Create(entry.name)
+		assert.Nil(t, err)
+		_, err = io.WriteString(w, entry.content)
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, writer.Close())
+}
+
+func writeTestTarGz(t *testing.T, filename string, entries []struct{ name, content string }) {
+	file, err := os.Create(filename)
+	assert.Nil(t, err)
+	defer file.Close()
+
+	gzipWriter := gzip.NewWriter(file)
+	writer := tar.NewWriter(gzipWriter)
+	for _, entry := range entries {
+		header := &tar.Header{Name: entry.name, Mode: 0644, Size: int64(len(entry.content)), Typeflag: tar.TypeReg}
+		if entry.name[len(entry.name)-1] == '/' {
+			header.Typeflag, header.Mode = tar.TypeDir, 0755
+		}
+		assert.Nil(t, writer.WriteHeader(header))
+		_, err = io.WriteString(writer, entry.content)
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, writer.Close())
+	assert.Nil(t, gzipWriter.Close())
+}
+
+// writeTestTar 创建 tar 文件，所有条目的类型为 typeflag。tar.Writer 会将 TypeRegA 转换为 TypeReg，所以直接修改头部。
+func writeTestTar(t *testing.T, filename string, typeflag byte, entries []struct{ name, content string }) {
+	buf := &bytes.Buffer{}
+	writer := tar.NewWriter(buf)
+	for _, entry := range entries {
+		header := &tar.Header{Name: entry.name, Mode: 0644, Size: int64(len(entry.content)), Typeflag: tar.TypeReg}
+		assert.Nil(t, writer.WriteHeader(header))
+		_, err := io.WriteString(writer, entry.content)
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, writer.Close())
+
+	data := buf.Bytes()
+	pos := 0
+	for _, entry := range entries {
+		header := data[pos : pos+512]
+		header[156] = typeflag
+
+		// 校验和是头部所有字节之和，计算时校验和字段按空格计算。
+		copy(header[148:156], "        ")
+		sum := 0
+		for _, b := range header {
+			sum += int(b)
+		}
+		copy(header[148:156], fmt.Sprintf("%06o\x00 ", sum))
+
+		pos += 512 + (len(entry.content)+511)/512*512
+	}
+	assert.Nil(t, os.WriteFile(filename, data, 0644))
+}
@@ -0,0 +1,371 @@
+package fileutils
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jqk/futool4go/common"
+)
+
+/*
+RenameTemplate is a parsed file name template used by [BatchRename].
+
+Supported tokens:
+  - {name}: file name without extension.
+  - {ext}: extension including the dot.
+  - {parent}: name of the directory containing the file.
+  - {n} or {n:03}: counter, optionally zero padded to the given width.
+  - {date} or {date:yyyyMMdd_HHmmss}: time of the file, from its name, metadata or modification time. See [ResolveFileTime].
+    Pattern letters are yyyy, yy, MM, dd, HH, mm, ss and SSS. Default is yyyyMMdd.
+  - {mtime} or {mtime:pattern}: modification time of the file, same pattern as {date}.
+  - {checksum} or {checksum:8}: hex full checksum, optionally truncated to the given length.
+  - {1}, {2} ...: capture groups of BatchRenameOption.Pattern. {0} is the whole match.
+  - {{ and }}: literal { and }.
+
+RenameTemplate 是 [BatchRename] 使用的已解析的文件名模板。支持的标记见上文英文说明。
+*/
+type RenameTemplate struct {
+	segments []templateSegment
+}
+
+// templateSegment 是模板的一部分。token 为空时是普通文本 text，否则为标记及其参数 arg。
+type templateSegment struct {
+	text  string
+	token string
+	arg   string
+}
+
+// regexGroupToken 匹配 {1}、{2} 等正则表达式分组标记。
+var regexGroupToken = regexp.MustCompile(`^\d+$`)
+
+/*
+ParseRenameTemplate parses the file name template. See [RenameTemplate] for supported tokens.
+
+ParseRenameTemplate 解析文件名模板。支持的标记参见 [RenameTemplate]。
+*/
+func ParseRenameTemplate(template string) (*RenameTemplate, error) {
+	result := &RenameTemplate{}
+	text := strings.Builder{}
+
+	for i := 0; i < len(template); i++ {
+		c := template[i]
+
+		if c == '}' {
+			if i+1 < len(template) && template[i+1] == '}' {
+				text.WriteByte('}')
+				i++
+				continue
+			}
+			return nil, fmt.Errorf("unexpected '}' at %d", i)
+		} else if c != '{' {
+			text.WriteByte(c)
+			continue
+		} else if i+1 < len(template) && template[i+1] == '{' {
+			text.WriteByte('{')
+			i++
+			continue
+		}
+
+		end := strings.IndexByte(template[i:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("unclosed '{' at %d", i)
+		}
+
+		token, arg, _ := strings.Cut(template[i+1:i+end], ":")
+		if err := validateTemplateToken(token, arg); err != nil {
+			return nil, err
+		}
+
+		if text.Len() > 0 {
+			result.segments = append(result.segments, templateSegment{text: text.String()})
+			text.Reset()
+		}
+
+		result.segments = append(result.segments, templateSegment{token: token, arg: arg})
+		i += end
+	}
+
+	if text.Len() > 0 {
+		result.segments = append(result.segments, templateSegment{text: text.String()})
+	}
+
+	return result, nil
+}
+
+func validateTemplateToken(token string, arg string) error {
+	switch token {
+	case "name", "ext", "parent", "date", "mtime":
+		return nil
+	case "n", "checksum":
+		if arg == "" {
+			return nil
+		} else if width, err := strconv.Atoi(arg); err != nil || width < 0 {
+			return fmt.Errorf("invalid argument of {%s}: %s", token, arg)
+		}
+		return nil
+	}
+
+	if regexGroupToken.MatchString(token) {
+		return nil
+	}
+	return fmt.Errorf("unknown token: {%s}", token)
+}
+
+// uses 检查模板是否包含给定的标记。
+func (t *RenameTemplate) uses(token string) bool {
+	for _, segment := range t.segments {
+		if segment.token == token {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+RenameTemplateValues holds the values used to execute a [RenameTemplate] for a file.
+
+RenameTemplateValues 保存了针对一个文件执行 [RenameTemplate] 时使用的值。
+*/
+type RenameTemplateValues struct {
+	Path        string    // The file path.
+	Counter     int       // The value of {n}.
+	CaptureTime time.Time // The value of {date}.
+	ModTime     time.Time // The value of {mtime}.
+	Checksum    []byte    // The value of {checksum}.
+	Groups      []string  // The values of {0}, {1} ...
+}
+
+/*
+Execute returns the new file name generated by the template.
+
+Execute 返回由模板生成的新文件名。
+*/
+func (t *RenameTemplate) Execute(values *RenameTemplateValues) (string, error) {
+	result := strings.Builder{}
+	base := filepath.Base(values.Path)
+	ext := filepath.Ext(base)
+
+	for _, segment := range t.segments {
+		switch segment.token {
+		case "":
+			result.WriteString(segment.text)
+		case "name":
+			result.WriteString(strings.TrimSuffix(base, ext))
+		case "ext":
+			result.WriteString(ext)
+		case "parent":
+			result.WriteString(filepath.Base(filepath.Dir(values.Path)))
+		case "n":
+			width, _ := strconv.Atoi(segment.arg)
+			result.WriteString(fmt.Sprintf("%0*d", width, values.Counter))
+		case "date":
+			result.WriteString(formatDatePattern(values.CaptureTime, segment.arg))
+		case "mtime":
+			result.WriteString(formatDatePattern(values.ModTime, segment.arg))
+		case "checksum":
+			checksum := hex.EncodeToString(values.Checksum)
+			if length, _ := strconv.Atoi(segment.arg); length > 0 && length < len(checksum) {
+				checksum = checksum[:length]
+			}
+			result.WriteString(checksum)
+		default:
+			// 校验过了，只能是正则表达式分组。
+			index, _ := strconv.Atoi(segment.token)
+			if index >= len(values.Groups) {
+				return "", fmt.Errorf("no capture group {%d} for %s", index, values.Path)
+			}
+			result.WriteString(values.Groups[index])
+		}
+	}
+
+	name := result.String()
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return "", fmt.Errorf("invalid file name generated for %s: %q", values.Path, name)
+	}
+	return name, nil
+}
+
+// formatDatePattern 按 yyyy、yy、MM、dd、HH、mm、ss 及 SSS 格式化时间，其它字符原样输出。pattern 为空时使用 yyyyMMdd。
+func formatDatePattern(tm time.Time, pattern string) string {
+	if pattern == "" {
+		pattern = "yyyyMMdd"
+	}
+
+	fields := []struct {
+		letters string
+		value   func() string
+	}{
+		{"yyyy", func() string { return fmt.Sprintf("%04d", tm.Year()) }},
+		{"yy", func() string { return fmt.Sprintf("%02d", tm.Year()%100) }},
+		{"MM", func() string { return fmt.Sprintf("%02d", tm.Month()) }},
+		{"dd", func() string { return fmt.Sprintf("%02d", tm.Day()) }},
+		{"HH", func() string { return fmt.Sprintf("%02d", tm.Hour()) }},
+		{"mm", func() string { return fmt.Sprintf("%02d", tm.Minute()) }},
+		{"ss", func() string { return fmt.Sprintf("%02d", tm.Second()) }},
+		{"SSS", func() string { return fmt.Sprintf("%03d", tm.Nanosecond()/1000_000) }},
+	}
+
+	result := strings.Builder{}
+	for i := 0; i < len(pattern); {
+		matched := false
+		for _, field := range fields {
+			if strings.HasPrefix(pattern[i:], field.letters) {
+				result.WriteString(field.value())
+				i += len(field.letters)
+				matched = true
+				break
+			}
+		}
+
+		if !matched {
+			result.WriteByte(pattern[i])
+			i++
+		}
+	}
+
+	return result.String()
+}
+
+/*
+BatchRenameOption defines the options for [BatchRename].
+See [NewBatchRenameOption] for default settings.
+
+BatchRenameOption 定义了 [BatchRename] 的选项。默认设置参见 [NewBatchRenameOption]。
+*/
+type BatchRenameOption struct {
+	WalkOption
+	Filter            *Filter                         // only files matching the filter are renamed. nil means all files
+	Pattern           *regexp.Regexp                  // only file names matching the pattern are renamed. its groups are {0}, {1}... nil means all
+	CounterStart      int                             // the first value of {n}
+	CounterStep       int                             // the increment of {n}
+	ChecksumProvider  FileChecksumCalculationProvider // the provider for {checksum}. required if {checksum} is used
+	DryRun            bool                            // if true, only plan the renames without changing any file
+	RenameOnCollision bool                            // if true, a unique name is used when the new path exists. Otherwise the file is skipped
+}
+
+/*
+NewBatchRenameOption creates a new BatchRenameOption with scan directory recursively,
+bypass permission denied error, no filter, no pattern, counter starts from 1 with step 1,
+no checksum provider, no dry run and skip on collision.
+
+NewBatchRenameOption 创建默认的 BatchRenameOption。包含递归扫描目录、跳过没有权限的文件及目录、不过滤文件、无正则表达式、
+计数器从 1 开始且步长为 1、无校验值计算对象、实际执行改名，以及冲突时跳过。
+*/
+func NewBatchRenameOption() *BatchRenameOption {
+	return &BatchRenameOption{
+		WalkOption: WalkOption{
+			Recursive:        true,
+			PathErrorHandler: SkipPermissionError,
+		},
+		Filter:            nil,
+		Pattern:           nil,
+		CounterStart:      1,
+		CounterStep:       1,
+		ChecksumProvider:  nil,
+		DryRun:            false,
+		RenameOnCollision: false,
+	}
+}
+
+/*
+BatchRename renames files under the given directory by the template. Files are renamed in their own directories.
+
+Parameters:
+  - root: the directory to scan.
+  - template: the file name template. See [RenameTemplate] for supported tokens.
+  - option: the options. if nil, the default options will be used.
+
+Returns:
+  - the rename result. Use it as preview in dry run, or save it by [SaveRenameJournal] for rollback.
+    Partial result is returned along with the error.
+  - an error if any occurred.
+
+Example:
+
+	option := NewBatchRenameOption()
+	option.DryRun = true
+	result, err := BatchRename("photos", "{date:yyyyMMdd}_{n:03}_{name}{ext}", option)
+
+BatchRename 按模板重命名给定目录下的文件。文件在其所在的目录内改名。
+
+参数:
+  - root: 要扫描的目录。
+  - template: 文件名模板。支持的标记参见 [RenameTemplate]。
+  - option: 选项。如果为 nil 则使用默认选项。
+
+返回:
+  - 改名结果。空运行时可用于预览，或者使用 [SaveRenameJournal] 保存以便撤销。出错时返回已完成的部分结果。
+  - 错误信息。
+*/
+func BatchRename(root string, template string, option *BatchRenameOption) (*RenameResult, error) {
+	emptyResult := &RenameResult{Renamed: []RenameRecord{}, Collisions: []RenameRecord{}}
+
+	if option == nil { // 保证 option 不为 nil。
+		option = NewBatchRenameOption()
+	}
+
+	tmpl, err := ParseRenameTemplate(template)
+	if err != nil {
+		return emptyResult, err
+	} else if tmpl.uses("checksum") && option.ChecksumProvider == nil {
+		return emptyResult, errors.New("ChecksumProvider must not be nil when {checksum} is used")
+	}
+
+	var buffer []byte
+	if option.ChecksumProvider != nil {
+		buffer = common.DefaultBufferManager.Get(64 * 1024)
+		defer common.DefaultBufferManager.Put(buffer)
+	}
+
+	// 先生成所有新名称，再统一改名，避免在遍历的同时修改目录内容。
+	plans := make([]RenameRecord, 0)
+	counter := option.CounterStart
+
+	err = walkFilteredFiles(root, option.Filter, &option.WalkOption, func(relPath string, path string, info os.FileInfo) error {
+		var err error
+		values := &RenameTemplateValues{Path: path, ModTime: info.ModTime()}
+
+		if option.Pattern != nil {
+			if values.Groups = option.Pattern.FindStringSubmatch(info.Name()); values.Groups == nil {
+				return nil // 文件名不匹配，不改名，也不计数。
+			}
+		}
+
+		values.Counter = counter
+		counter += option.CounterStep
+
+		if tmpl.uses("date") {
+			if values.CaptureTime, _, err = ResolveFileTime(path, info); err != nil {
+				return err
+			}
+		}
+
+		if tmpl.uses("checksum") {
+			if err = GetFileChecksumWithProvider(path, 0, buffer, false, true, option.ChecksumProvider); err != nil {
+				return err
+			}
+			values.Checksum = option.ChecksumProvider.FullChecksum()
+		}
+
+		name, err := tmpl.Execute(values)
+		if err != nil {
+			return err
+		} else if name != info.Name() {
+			plans = append(plans, RenameRecord{From: path, To: filepath.Join(filepath.Dir(path), name)})
+		}
+		return nil
+	})
+
+	if err != nil {
+		return emptyResult, err
+	}
+
+	return executeRenames(plans, option.DryRun, option.RenameOnCollision)
+}
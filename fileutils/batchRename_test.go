@@ -0,0 +1,88 @@
+package fileutils
+
+import (
+	"crypto/md5"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRenameTemplate(t *testing.T) {
+	tmpl, err := ParseRenameTemplate("{{{name}}}_{n:03}{ext}")
+	assert.Nil(t, err)
+
+	name, err := tmpl.Execute(&RenameTemplateValues{Path: "/tmp/photo.jpg", Counter: 7})
+	assert.Nil(t, err)
+	assert.Equal(t, "{photo}_007.jpg", name)
+
+	tmpl, err = ParseRenameTemplate("{mtime:yyyy-MM-dd_HHmmss.SSS}_{parent}")
+	assert.Nil(t, err)
+
+	mtime := time.Date(2023, 5, 1, 12, 3, 4, 5000_000, time.Local)
+	name, err = tmpl.Execute(&RenameTemplateValues{Path: "/tmp/photo.jpg", ModTime: mtime})
+	assert.Nil(t, err)
+	assert.Equal(t, "2023-05-01_120304.005_tmp", name)
+
+	// 模板错误。
+	for _, template := range []string{"{unknown}", "{n:abc}", "{name", "name}"} {
+		_, err = ParseRenameTemplate(template)
+		assert.NotNil(t, err, template)
+	}
+
+	// 生成的文件名无效。
+	tmpl, _ = ParseRenameTemplate("{1}")
+	_, err = tmpl.Execute(&RenameTemplateValues{Path: "/tmp/photo.jpg", Groups: []string{"photo.jpg"}})
+	assert.NotNil(t, err)
+}
+
+func TestBatchRename(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"IMG_20230501_120000.jpg", "IMG_20230502_130000.jpg", "notes.txt"} {
+		assert.Nil(t, os.WriteFile(filepath.Join(root, name), []byte(name), 0644))
+	}
+
+	option := NewBatchRenameOption()
+	option.Pattern = regexp.MustCompile(`^IMG_(\d+)_\d+`)
+	option.DryRun = true
+
+	result, err := BatchRename(root, "{date:yyyyMMdd}_{n:03}_{1}{ext}", option)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(result.Renamed))
+	assert.Equal(t, filepath.Join(root, "20230501_001_20230501.jpg"), result.Renamed[0].To)
+	assert.Equal(t, filepath.Join(root, "20230502_002_20230502.jpg"), result.Renamed[1].To)
+
+	// 使用校验值改名，所有文件都参与。
+	option = NewBatchRenameOption()
+	_, err = BatchRename(root, "{checksum:8}{ext}", option)
+	assert.NotNil(t, err)
+
+	option.ChecksumProvider = NewCommonFileChecksumProvider("MD5", md5.New())
+	result, err = BatchRename(root, "{checksum:8}{ext}", option)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(result.Renamed))
+
+	for _, record := range result.Renamed {
+		assert.Equal(t, 8+len(filepath.Ext(record.From)), len(filepath.Base(record.To)))
+		exists, _, _ := FileExists(record.To)
+		assert.True(t, exists)
+	}
+
+	undone, err := RollbackRenames(result.Renamed)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(undone))
+
+	exists, _, _ := FileExists(filepath.Join(root, "notes.txt"))
+	assert.True(t, exists)
+
+	// 所有文件改为同一个名称，冲突时跳过。
+	option = NewBatchRenameOption()
+	option.DryRun = true
+	result, err = BatchRename(root, "same", option)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(result.Renamed))
+	assert.Equal(t, 2, len(result.Collisions))
+}
@@ -0,0 +1,235 @@
+package fileutils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+/*
+ScanCheckpoint records the progress of a scan by [Filter.GetEachFileResumable].
+
+ScanCheckpoint 记录 [Filter.GetEachFileResumable] 扫描的进度。
+*/
+type ScanCheckpoint struct {
+	Root      string          `json:"root"`            // The absolute path of the scanned directory.
+	LastPath  string          `json:"lastPath"`        // The last file processed, relative to Root. Empty if none.
+	FileCount int             `json:"fileCount"`       // The number of files processed.
+	TotalSize int64           `json:"totalSize"`       // The total size of files processed.
+	State     json.RawMessage `json:"state,omitempty"` // The caller's partial result. See ResumableScanOption.State.
+	UpdatedAt time.Time       `json:"updatedAt"`       // The time the checkpoint was saved.
+}
+
+/*
+Save writes the checkpoint to the given file atomically in JSON format.
+
+Save 以原子方式将进度以 JSON 格式写入给定的文件。
+*/
+func (c *ScanCheckpoint) Save(filename string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(filename, data)
+}
+
+/*
+LoadScanCheckpoint reads a checkpoint written by [ScanCheckpoint.Save].
+
+LoadScanCheckpoint 读取由 [ScanCheckpoint.Save] 写入的进度。
+*/
+func LoadScanCheckpoint(filename string) (*ScanCheckpoint, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoint := &ScanCheckpoint{}
+	if err = json.Unmarshal(data, checkpoint); err != nil {
+		return nil, err
+	}
+
+	return checkpoint, nil
+}
+
+/*
+ResumableScanOption defines the options for [Filter.GetEachFileResumable].
+See [NewResumableScanOption] for default settings.
+
+ResumableScanOption 定义了 [Filter.GetEachFileResumable] 的选项。默认设置参见 [NewResumableScanOption]。
+*/
+type ResumableScanOption struct {
+	WalkOption
+	CheckpointFile string // the file to save the checkpoint. Must not be empty
+	Interval       int    // save the checkpoint after every Interval files processed. 0 means only save when stopped by an error
+	/*
+		A pointer to the caller's partial result, such as a map of checksums for finding duplicates. nil means none.
+		It is marshalled to JSON in each checkpoint, and unmarshalled from the checkpoint when resuming.
+	*/
+	State any
+}
+
+/*
+NewResumableScanOption creates a new ResumableScanOption with scan directory recursively,
+bypass permission denied error, save the checkpoint every 1000 files and no state.
+
+NewResumableScanOption 创建默认的 ResumableScanOption。包含递归扫描目录、跳过没有权限的文件及目录、每处理 1000 个文件保存一次进度，以及没有调用者状态。
+*/
+func NewResumableScanOption(checkpointFile string) *ResumableScanOption {
+	return &ResumableScanOption{
+		WalkOption: WalkOption{
+			Recursive:        true,
+			PathErrorHandler: SkipPermissionError,
+		},
+		CheckpointFile: checkpointFile,
+		Interval:       1000,
+		State:          nil,
+	}
+}
+
+/*
+GetEachFileResumable is like [Filter.GetEachFile], but periodically saves a checkpoint, and resumes from it if it exists.
+Files up to and including the last processed one in the checkpoint are skipped, relying on the lexical order of filepath.Walk.
+A file is counted as processed after the handler returns nil.
+
+The checkpoint is saved when the scan stops by an error or the handler returns filepath.SkipAll, so that it can be resumed.
+The checkpoint file is removed when the scan completes.
+
+Parameters:
+  - root: The directory to scan.
+  - option: the scan options. Cannot be nil and the checkpoint file must be given.
+  - handler: Callback function to handle files that meet the filter condition. Cannot be nil.
+
+Returns:
+  - the final checkpoint, including the statistics of all processed files, even of the previous runs.
+  - an error if any occurred. It is an error if the checkpoint was made for another directory.
+
+GetEachFileResumable 与 [Filter.GetEachFile] 相同，但定期保存进度，并在进度存在时从中恢复。
+依据 filepath.Walk 的字典顺序，跳过进度中最后处理的文件及其之前的文件。处理函数返回 nil 后，文件计为已处理。
+
+扫描因错误停止或者处理函数返回 filepath.SkipAll 时保存进度，以便恢复。扫描完成后删除进度文件。
+
+参数:
+  - root: 要扫描的目录。
+  - option: 扫描选项。不能为 nil，且必须给出进度文件。
+  - handler: 处理满足过滤条件的文件回调函数。不能为 nil。
+
+返回:
+  - 最终的进度，包含所有已处理文件的统计信息，包括之前运行的部分。
+  - 错误信息。进度是针对其它目录的，也返回错误。
+*/
+func (f *Filter) GetEachFileResumable(root string, option *ResumableScanOption, handler FileMatchedFunc) (*ScanCheckpoint, error) {
+	if option == nil || option.CheckpointFile == "" {
+		return nil, errors.New("checkpoint file must be given")
+	} else if handler == nil {
+		return nil, errors.New("handler cannot be nil")
+	}
+
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoint, err := loadOrCreateCheckpoint(absRoot, option)
+	if err != nil {
+		return nil, err
+	}
+
+	save := func() error {
+		if option.State != nil {
+			state, err := json.Marshal(option.State)
+			if err != nil {
+				return err
+			}
+			checkpoint.State = state
+		}
+		checkpoint.UpdatedAt = time.Now()
+		return checkpoint.Save(option.CheckpointFile)
+	}
+
+	stopped := false
+	sinceSave := 0
+	resumeFrom := checkpoint.LastPath
+
+	err = f.GetEachFile(root, &option.WalkOption, func(path string, info os.FileInfo) error {
+		relPath, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		if resumeFrom != "" && compareWalkOrder(relPath, resumeFrom) <= 0 {
+			return nil // 已在之前的运行中处理。
+		}
+
+		if err = handler(path, info); err != nil {
+			stopped = err == filepath.SkipAll
+			return err
+		}
+
+		checkpoint.LastPath = relPath
+		checkpoint.FileCount++
+		checkpoint.TotalSize += info.Size()
+
+		sinceSave++
+		if option.Interval > 0 && sinceSave >= option.Interval {
+			sinceSave = 0
+			return save()
+		}
+		return nil
+	})
+
+	if err != nil || stopped {
+		if saveErr := save(); err == nil {
+			err = saveErr
+		}
+		return checkpoint, err
+	}
+
+	if err = os.Remove(option.CheckpointFile); err != nil && !os.IsNotExist(err) {
+		return checkpoint, err
+	}
+	return checkpoint, nil
+}
+
+// loadOrCreateCheckpoint 读取已有的进度，并恢复调用者的状态。进度文件不存在时创建新的进度。
+func loadOrCreateCheckpoint(absRoot string, option *ResumableScanOption) (*ScanCheckpoint, error) {
+	checkpoint, err := LoadScanCheckpoint(option.CheckpointFile)
+	if os.IsNotExist(err) {
+		return &ScanCheckpoint{Root: absRoot}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if checkpoint.Root != absRoot {
+		return nil, fmt.Errorf("checkpoint is for %s, not %s", checkpoint.Root, absRoot)
+	}
+	if option.State != nil && len(checkpoint.State) > 0 {
+		if err = json.Unmarshal(checkpoint.State, option.State); err != nil {
+			return nil, err
+		}
+	}
+
+	return checkpoint, nil
+}
+
+// compareWalkOrder 按 filepath.Walk 的访问顺序比较两个相对路径。
+// Walk 在每个目录内按名称排序，所以要逐级比较，而不能直接比较字符串。例如 "a/b" 在 "a.txt" 之前访问。
+func compareWalkOrder(path1, path2 string) int {
+	parts1 := strings.Split(path1, string(filepath.Separator))
+	parts2 := strings.Split(path2, string(filepath.Separator))
+
+	for i := 0; i < len(parts1) && i < len(parts2); i++ {
+		if parts1[i] != parts2[i] {
+			if parts1[i] < parts2[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	return len(parts1) - len(parts2)
+}
@@ -0,0 +1,103 @@
+package fileutils
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func createCheckpointTestDir(t *testing.T) string {
+	root := t.TempDir()
+	assert.Nil(t, os.MkdirAll(filepath.Join(root, "a"), 0755))
+	for _, name := range []string{"a/1.txt", "a/2.txt", "a.txt", "b.txt", "c.txt"} {
+		assert.Nil(t, os.WriteFile(filepath.Join(root, name), []byte(name), 0644))
+	}
+	return root
+}
+
+func TestGetEachFileResumable(t *testing.T) {
+	root := createCheckpointTestDir(t)
+	checkpointFile := filepath.Join(t.TempDir(), "scan.json")
+	filter := &Filter{Include: []string{"*"}}
+
+	// 第一次运行在处理第三个文件时出错。
+	state := map[string]int{}
+	option := NewResumableScanOption(checkpointFile)
+	option.Interval = 1
+	option.State = &state
+	failure := errors.New("interrupted")
+
+	visited := []string{}
+	checkpoint, err := filter.GetEachFileResumable(root, option, func(path string, info os.FileInfo) error {
+		if len(visited) == 2 {
+			return failure
+		}
+		visited = append(visited, filepath.Base(path))
+		state[filepath.Base(path)] = int(info.Size())
+		return nil
+	})
+	assert.ErrorIs(t, err, failure)
+	assert.Equal(t, []string{"1.txt", "2.txt"}, visited)
+	assert.Equal(t, 2, checkpoint.FileCount)
+	assert.Equal(t, filepath.Join("a", "2.txt"), checkpoint.LastPath)
+
+	saved, err := LoadScanCheckpoint(checkpointFile)
+	assert.Nil(t, err)
+	assert.Equal(t, checkpoint.LastPath, saved.LastPath)
+	assert.Equal(t, int64(14), saved.TotalSize)
+
+	// 恢复后从下一个文件继续，并恢复状态。
+	resumedState := map[string]int{}
+	option = NewResumableScanOption(checkpointFile)
+	option.State = &resumedState
+	visited = visited[:0]
+	checkpoint, err = filter.GetEachFileResumable(root, option, func(path string, info os.FileInfo) error {
+		visited = append(visited, filepath.Base(path))
+		resumedState[filepath.Base(path)] = int(info.Size())
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a.txt", "b.txt", "c.txt"}, visited)
+	assert.Equal(t, 5, checkpoint.FileCount)
+	assert.Equal(t, 5, len(resumedState))
+
+	// 完成后删除进度文件。
+	_, err = os.Stat(checkpointFile)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestGetEachFileResumableSkipAll(t *testing.T) {
+	root := createCheckpointTestDir(t)
+	checkpointFile := filepath.Join(t.TempDir(), "scan.json")
+	filter := &Filter{Include: []string{"*"}}
+
+	option := NewResumableScanOption(checkpointFile)
+	option.Interval = 0
+	checkpoint, err := filter.GetEachFileResumable(root, option, func(path string, info os.FileInfo) error {
+		return filepath.SkipAll
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, checkpoint.FileCount)
+
+	// 停止时保存进度，目录不同时拒绝恢复。
+	_, err = os.Stat(checkpointFile)
+	assert.Nil(t, err)
+	_, err = filter.GetEachFileResumable(t.TempDir(), option, func(path string, info os.FileInfo) error {
+		return nil
+	})
+	assert.NotNil(t, err)
+
+	_, err = filter.GetEachFileResumable(root, nil, func(path string, info os.FileInfo) error { return nil })
+	assert.NotNil(t, err)
+}
+
+func TestCompareWalkOrder(t *testing.T) {
+	sep := string(filepath.Separator)
+	assert.Less(t, compareWalkOrder("a"+sep+"b", "a.txt"), 0)
+	assert.Less(t, compareWalkOrder("a", "a"+sep+"b"), 0)
+	assert.Greater(t, compareWalkOrder("b", "a"+sep+"z"), 0)
+	assert.Equal(t, 0, compareWalkOrder("a"+sep+"b", "a"+sep+"b"))
+}
@@ -0,0 +1,229 @@
+package fileutils
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strconv"
+	"time"
+)
+
+// 保存校验值信息所使用的扩展属性名称。
+const (
+	XattrChecksumName = "user.futool.checksum" // 校验值，原始字节。
+	XattrMethodName   = "user.futool.method"   // 算法名称，即 FileChecksumCalculationProvider.Method()。
+	XattrModTimeName  = "user.futool.mtime"    // 计算校验值时文件的修改时间，Unix 纳秒数的十进制字符串。
+)
+
+// 一组扩展属性相关的错误类型。
+var (
+	ErrXattrNotSupported    = errors.New("extended attributes are not supported")
+	ErrChecksumAttrNotFound = errors.New("checksum attributes not found")
+)
+
+/*
+ChecksumAttr defines the checksum information stored in the extended attributes of a file.
+
+ChecksumAttr 定义了保存在文件扩展属性中的校验值信息。
+*/
+type ChecksumAttr struct {
+	Method   string    // The digest algorithm name.
+	Checksum []byte    // The checksum of the whole file.
+	ModTime  time.Time // The modification time of the file when the checksum was calculated.
+}
+
+/*
+ChecksumAttrStatus defines the result of [VerifyChecksumAttr].
+
+ChecksumAttrStatus 定义了 [VerifyChecksumAttr] 的处理结果。
+*/
+type ChecksumAttrStatus int
+
+const (
+	ChecksumAttrCreated   ChecksumAttrStatus = iota // No checksum stored before. It is calculated and stored.
+	ChecksumAttrUpdated                             // The file or method has changed. The checksum is recalculated and stored.
+	ChecksumAttrSkipped                             // The file has not changed and scrub is false. Nothing is calculated.
+	ChecksumAttrVerified                            // The file has not changed and the recalculated checksum matches.
+	ChecksumAttrCorrupted                           // The file has not changed but the recalculated checksum does not match.
+)
+
+/*
+SetChecksumAttr stores the checksum information into the extended attributes of the file.
+
+Parameters:
+  - path: the file path.
+  - attr: the checksum information. Cannot be nil.
+
+Returns:
+  - an error if any occurred. [ErrXattrNotSupported] if the platform or file system does not support it.
+
+SetChecksumAttr 将校验值信息保存到文件的扩展属性中。
+
+参数:
+  - path: 文件路径。
+  - attr: 校验值信息。不能为 nil。
+
+返回:
+  - 错误信息。平台或文件系统不支持时返回 [ErrXattrNotSupported]。
+*/
+func SetChecksumAttr(path string, attr *ChecksumAttr) error {
+	if attr == nil {
+		return errors.New("attr must not be nil")
+	} else if attr.Method == "" {
+		return errors.New("attr.Method must not be empty")
+	}
+
+	// 最后写入修改时间。读取时以它为准，即使中途失败，也不会得到不一致的信息。
+	if err := setXattr(path, XattrChecksumName, attr.Checksum); err != nil {
+		return err
+	}
+	if err := setXattr(path, XattrMethodName, []byte(attr.Method)); err != nil {
+		return err
+	}
+
+	mtime := strconv.FormatInt(attr.ModTime.UnixNano(), 10)
+	return setXattr(path, XattrModTimeName, []byte(mtime))
+}
+
+/*
+GetChecksumAttr reads the checksum information from the extended attributes of the file.
+
+Parameters:
+  - path: the file path.
+
+Returns:
+  - the checksum information.
+  - an error if any occurred. [ErrChecksumAttrNotFound] if no checksum is stored.
+
+GetChecksumAttr 从文件的扩展属性中读取校验值信息。
+
+参数:
+  - path: 文件路径。
+
+返回:
+  - 校验值信息。
+  - 错误信息。没有保存校验值时返回 [ErrChecksumAttrNotFound]。
+*/
+func GetChecksumAttr(path string) (*ChecksumAttr, error) {
+	mtime, err := getXattr(path, XattrModTimeName)
+	if err != nil {
+		return nil, err
+	}
+
+	nanoseconds, err := strconv.ParseInt(string(mtime), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	method, err := getXattr(path, XattrMethodName)
+	if err != nil {
+		return nil, err
+	}
+
+	checksum, err := getXattr(path, XattrChecksumName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ChecksumAttr{
+		Method:   string(method),
+		Checksum: checksum,
+		ModTime:  time.Unix(0, nanoseconds),
+	}, nil
+}
+
+/*
+RemoveChecksumAttr removes the checksum information from the extended attributes of the file.
+Attributes that do not exist are ignored.
+
+RemoveChecksumAttr 删除文件扩展属性中的校验值信息。忽略不存在的属性。
+*/
+func RemoveChecksumAttr(path string) error {
+	for _, name := range []string{XattrModTimeName, XattrMethodName, XattrChecksumName} {
+		if err := removeXattr(path, name); err != nil && err != ErrChecksumAttrNotFound {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/*
+VerifyChecksumAttr checks the file against the checksum stored in its extended attributes.
+
+The checksum is only recalculated when the modification time of the file has changed,
+so repeated calls are cheap. Set scrub to true to recalculate unchanged files as well,
+which detects silent corruption (bit rot): the content changed but the modification time did not.
+
+Parameters:
+  - path: the file path.
+  - buffer: buffer for reading the file.
+  - provider: the object that performs the checksum calculation, cannot be nil.
+  - scrub: whether to recalculate the checksum even if the file has not changed.
+
+Returns:
+  - the verification status.
+  - an error if any occurred.
+
+VerifyChecksumAttr 使用文件扩展属性中保存的校验值检查文件。
+
+仅当文件的修改时间发生变化时才重新计算校验值，因此重复调用的代价很小。
+scrub 为 true 时，未变化的文件也会重新计算，用于发现修改时间未变但内容已损坏的情况（位衰减）。
+
+参数:
+  - path: 文件路径。
+  - buffer: 读取文件的缓冲区。
+  - provider: 执行校验和计算的对象，不能为 nil。
+  - scrub: 文件未变化时是否仍重新计算校验值。
+
+返回:
+  - 检查结果。
+  - 错误信息。
+*/
+func VerifyChecksumAttr(
+	path string,
+	buffer []byte,
+	provider FileChecksumCalculationProvider,
+	scrub bool,
+) (ChecksumAttrStatus, error) {
+	if provider == nil {
+		return ChecksumAttrSkipped, errors.New("provider must not be nil")
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return ChecksumAttrSkipped, err
+	}
+
+	status := ChecksumAttrCreated
+	attr, err := GetChecksumAttr(path)
+	if err == nil {
+		if attr.Method != provider.Method() || !attr.ModTime.Equal(info.ModTime()) {
+			status = ChecksumAttrUpdated
+		} else if !scrub {
+			return ChecksumAttrSkipped, nil
+		} else {
+			status = ChecksumAttrVerified
+		}
+	} else if err != ErrChecksumAttrNotFound {
+		return ChecksumAttrSkipped, err
+	}
+
+	if err = GetFileChecksumWithProvider(path, 0, buffer, false, true, provider); err != nil {
+		return ChecksumAttrSkipped, err
+	}
+
+	if status == ChecksumAttrVerified {
+		if !bytes.Equal(attr.Checksum, provider.FullChecksum()) {
+			// 修改时间没变，内容却变了，不能覆盖原有的校验值。
+			return ChecksumAttrCorrupted, nil
+		}
+		return ChecksumAttrVerified, nil
+	}
+
+	return status, SetChecksumAttr(path, &ChecksumAttr{
+		Method:   provider.Method(),
+		Checksum: provider.FullChecksum(),
+		ModTime:  info.ModTime(),
+	})
+}
@@ -0,0 +1,45 @@
+//go:build linux
+
+package fileutils
+
+import "syscall"
+
+func setXattr(path string, name string, value []byte) error {
+	return convertXattrError(syscall.Setxattr(path, name, value, 0))
+}
+
+func getXattr(path string, name string) ([]byte, error) {
+	// 先取得属性值的长度，再读取。两次调用之间属性值可能被修改，所以长度不符时重试。
+	for {
+		size, err := syscall.Getxattr(path, name, nil)
+		if err != nil {
+			return nil, convertXattrError(err)
+		} else if size == 0 {
+			return []byte{}, nil
+		}
+
+		value := make([]byte, size)
+		size, err = syscall.Getxattr(path, name, value)
+		if err == syscall.ERANGE {
+			continue
+		} else if err != nil {
+			return nil, convertXattrError(err)
+		}
+
+		return value[:size], nil
+	}
+}
+
+func removeXattr(path string, name string) error {
+	return convertXattrError(syscall.Removexattr(path, name))
+}
+
+func convertXattrError(err error) error {
+	switch err {
+	case syscall.ENODATA:
+		return ErrChecksumAttrNotFound
+	case syscall.ENOTSUP:
+		return ErrXattrNotSupported
+	}
+	return err
+}
@@ -0,0 +1,15 @@
+//go:build !linux
+
+package fileutils
+
+func setXattr(path string, name string, value []byte) error {
+	return ErrXattrNotSupported
+}
+
+func getXattr(path string, name string) ([]byte, error) {
+	return nil, ErrXattrNotSupported
+}
+
+func removeXattr(path string, name string) error {
+	return ErrXattrNotSupported
+}
@@ -0,0 +1,68 @@
+package fileutils
+
+import (
+	"crypto/md5"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyChecksumAttr(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "data.txt")
+	assert.Nil(t, os.WriteFile(filename, []byte("hello world"), 0644))
+
+	_, err := GetChecksumAttr(filename)
+	if err == ErrXattrNotSupported {
+		t.Skip("extended attributes are not supported")
+	}
+	assert.Equal(t, ErrChecksumAttrNotFound, err)
+
+	buffer := make([]byte, 1024)
+	p := NewCommonFileChecksumProvider("MD5", md5.New())
+
+	// 第一次检查，保存校验值。
+	status, err := VerifyChecksumAttr(filename, buffer, p, false)
+	assert.Nil(t, err)
+	assert.Equal(t, ChecksumAttrCreated, status)
+
+	attr, err := GetChecksumAttr(filename)
+	assert.Nil(t, err)
+	assert.Equal(t, "MD5", attr.Method)
+	assert.Equal(t, p.FullChecksum(), attr.Checksum)
+
+	// 文件未变化，不重新计算。
+	status, err = VerifyChecksumAttr(filename, buffer, p, false)
+	assert.Nil(t, err)
+	assert.Equal(t, ChecksumAttrSkipped, status)
+
+	status, err = VerifyChecksumAttr(filename, buffer, p, true)
+	assert.Nil(t, err)
+	assert.Equal(t, ChecksumAttrVerified, status)
+
+	// 修改内容，但恢复原有的修改时间，模拟位衰减。
+	assert.Nil(t, os.WriteFile(filename, []byte("hello World"), 0644))
+	assert.Nil(t, os.Chtimes(filename, attr.ModTime, attr.ModTime))
+
+	status, err = VerifyChecksumAttr(filename, buffer, p, false)
+	assert.Nil(t, err)
+	assert.Equal(t, ChecksumAttrSkipped, status)
+
+	status, err = VerifyChecksumAttr(filename, buffer, p, true)
+	assert.Nil(t, err)
+	assert.Equal(t, ChecksumAttrCorrupted, status)
+
+	// 修改时间变化，重新计算并保存。
+	mtime := attr.ModTime.Add(time.Second)
+	assert.Nil(t, os.Chtimes(filename, mtime, mtime))
+
+	status, err = VerifyChecksumAttr(filename, buffer, p, false)
+	assert.Nil(t, err)
+	assert.Equal(t, ChecksumAttrUpdated, status)
+
+	assert.Nil(t, RemoveChecksumAttr(filename))
+	_, err = GetChecksumAttr(filename)
+	assert.Equal(t, ErrChecksumAttrNotFound, err)
+}
@@ -0,0 +1,64 @@
+package fileutils
+
+import (
+	"hash"
+	"io"
+)
+
+/*
+ChecksumReader hashes the data read through it, so that data can be copied and checksummed in a single pass.
+
+ChecksumReader 对通过它读取的数据计算哈希值，使复制数据与计算校验值可以一次完成。
+*/
+type ChecksumReader struct {
+	reader io.Reader
+	hashes []hash.Hash
+	writer io.Writer
+}
+
+/*
+TeeWithChecksum returns a reader that writes everything read from reader to all the hashes.
+
+Example:
+
+	md5Hash, sha256Hash := md5.New(), sha256.New()
+	reader := TeeWithChecksum(file, md5Hash, sha256Hash)
+	io.Copy(target, reader)
+	sums := reader.Sums() // md5 and sha256 of the file.
+
+TeeWithChecksum 返回一个读取器，将从 reader 读取的所有数据写入所有的哈希对象。
+*/
+func TeeWithChecksum(reader io.Reader, hashes ...hash.Hash) *ChecksumReader {
+	writers := make([]io.Writer, len(hashes))
+	for i, h := range hashes {
+		writers[i] = h
+	}
+
+	return &ChecksumReader{
+		reader: reader,
+		hashes: hashes,
+		writer: io.MultiWriter(writers...),
+	}
+}
+
+func (r *ChecksumReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if n > 0 {
+		// hash.Hash 的 Write 不会返回错误。
+		r.writer.Write(p[:n])
+	}
+	return n, err
+}
+
+/*
+Sums returns the checksums of the data read so far, in the order of the hashes.
+
+Sums 返回目前已读取数据的校验值，顺序与哈希对象相同。
+*/
+func (r *ChecksumReader) Sums() [][]byte {
+	result := make([][]byte, len(r.hashes))
+	for i, h := range r.hashes {
+		result[i] = h.Sum(nil)
+	}
+	return result
+}
@@ -0,0 +1,54 @@
+package fileutils
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTeeWithChecksum(t *testing.T) {
+	data := strings.Repeat("checksum", 1000)
+	reader := TeeWithChecksum(strings.NewReader(data), md5.New(), sha256.New())
+
+	copied, err := io.ReadAll(reader)
+	assert.Nil(t, err)
+	assert.Equal(t, data, string(copied))
+
+	md5Sum := md5.Sum([]byte(data))
+	sha256Sum := sha256.Sum256([]byte(data))
+	assert.Equal(t, [][]byte{md5Sum[:], sha256Sum[:]}, reader.Sums())
+
+	// 没有哈希对象时只是普通的读取器。
+	reader = TeeWithChecksum(strings.NewReader(data))
+	copied, err = io.ReadAll(reader)
+	assert.Nil(t, err)
+	assert.Equal(t, data, string(copied))
+	assert.Equal(t, 0, len(reader.Sums()))
+}
+
+func TestCopyFileVerify(t *testing.T) {
+	root := t.TempDir()
+	src := filepath.Join(root, "src.txt")
+	dst := filepath.Join(root, "dst.txt")
+	data := strings.Repeat("verify", 10000)
+	assert.Nil(t, os.WriteFile(src, []byte(data), 0644))
+
+	option := NewCopyOption()
+	option.Verify = sha256.New()
+	option.Buffer = make([]byte, 1024)
+
+	copied, err := CopyFile(src, dst, option)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(len(data)), copied)
+
+	expected := sha256.Sum256([]byte(data))
+	assert.Equal(t, expected[:], option.Verify.Sum(nil))
+	content, _ := os.ReadFile(dst)
+	assert.Equal(t, data, string(content))
+}
@@ -0,0 +1,130 @@
+package fileutils
+
+import (
+	"errors"
+	"os"
+	"sort"
+	"time"
+)
+
+/*
+CleanupOption defines the options for [CleanupByAge].
+See [NewCleanupOption] for default settings.
+
+CleanupOption 定义了 [CleanupByAge] 的选项。默认设置参见 [NewCleanupOption]。
+*/
+type CleanupOption struct {
+	WalkOption
+	DryRun bool // if true, only report the files to be removed without removing any file
+}
+
+/*
+NewCleanupOption creates a new CleanupOption with scan directory recursively,
+bypass permission denied error and no dry run.
+
+NewCleanupOption 创建默认的 CleanupOption。包含递归扫描目录、跳过没有权限的文件及目录，以及实际执行删除。
+*/
+func NewCleanupOption() *CleanupOption {
+	return &CleanupOption{
+		WalkOption: WalkOption{
+			Recursive:        true,
+			PathErrorHandler: SkipPermissionError,
+		},
+		DryRun: false,
+	}
+}
+
+/*
+CleanupReport is the result of [CleanupByAge].
+
+CleanupReport 是 [CleanupByAge] 的结果。
+*/
+type CleanupReport struct {
+	Removed        []string // The removed files, oldest first.
+	Kept           int      // The number of matched files kept.
+	ReclaimedBytes int64    // The total size of the removed files.
+}
+
+/*
+CleanupByAge removes files whose modification time is older than the given duration,
+but always keeps the newest keepAtLeastN matched files even if they are old. Directories are kept.
+
+Parameters:
+  - root: the directory to scan.
+  - filter: only files matching the filter are considered. nil means all files.
+  - olderThan: files modified before now minus olderThan are removed.
+  - keepAtLeastN: the number of the newest files to keep regardless of age.
+  - option: the options. if nil, the default options will be used.
+
+Returns:
+  - the report. Partial report is returned along with the error.
+  - an error if any occurred.
+
+CleanupByAge 删除修改时间早于给定时长的文件，但总是保留满足条件的最新 keepAtLeastN 个文件，即使它们已经过期。保留目录。
+
+参数:
+  - root: 要扫描的目录。
+  - filter: 只考虑满足过滤条件的文件。nil 表示所有文件。
+  - olderThan: 删除修改时间早于当前时间减去 olderThan 的文件。
+  - keepAtLeastN: 无论新旧都保留的最新文件数量。
+  - option: 选项。如果为 nil 则使用默认选项。
+
+返回:
+  - 清理报告。出错时返回已完成的部分报告。
+  - 错误信息。
+*/
+func CleanupByAge(root string, filter *Filter, olderThan time.Duration, keepAtLeastN int, option *CleanupOption) (*CleanupReport, error) {
+	report := &CleanupReport{Removed: []string{}}
+
+	if olderThan < 0 || keepAtLeastN < 0 {
+		return report, errors.New("olderThan and keepAtLeastN must not be negative")
+	}
+	if option == nil { // 保证 option 不为 nil。
+		option = NewCleanupOption()
+	}
+
+	type candidate struct {
+		path string
+		info os.FileInfo
+	}
+
+	// 先收集文件，再删除，避免在遍历的同时修改目录内容。
+	candidates := []candidate{}
+	err := walkFilteredFiles(root, filter, &option.WalkOption, func(relPath string, path string, info os.FileInfo) error {
+		candidates = append(candidates, candidate{path, info})
+		return nil
+	})
+	if err != nil {
+		return report, err
+	}
+
+	// 按修改时间从新到旧排序，前 keepAtLeastN 个总是保留。
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].info.ModTime().After(candidates[j].info.ModTime())
+	})
+
+	deadline := time.Now().Add(-olderThan)
+	expired := []candidate{}
+	for i, c := range candidates {
+		if i >= keepAtLeastN && c.info.ModTime().Before(deadline) {
+			expired = append(expired, c)
+		} else {
+			report.Kept++
+		}
+	}
+
+	for i := len(expired) - 1; i >= 0; i-- {
+		c := expired[i]
+		if !option.DryRun {
+			if err = os.Remove(c.path); err != nil {
+				report.Kept += i + 1
+				return report, err
+			}
+		}
+
+		report.Removed = append(report.Removed, c.path)
+		report.ReclaimedBytes += c.info.Size()
+	}
+
+	return report, nil
+}
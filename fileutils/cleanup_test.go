@@ -0,0 +1,59 @@
+package fileutils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCleanupByAge(t *testing.T) {
+	root := t.TempDir()
+	assert.Nil(t, os.MkdirAll(filepath.Join(root, "sub"), 0755))
+
+	now := time.Now()
+	files := []struct {
+		name string
+		age  time.Duration
+	}{
+		{"a.tmp", 10 * time.Hour}, {"sub/b.tmp", 5 * time.Hour}, {"c.tmp", 3 * time.Hour},
+		{"d.tmp", time.Minute}, {"keep.txt", 10 * time.Hour},
+	}
+	for _, f := range files {
+		path := filepath.Join(root, filepath.FromSlash(f.name))
+		assert.Nil(t, os.WriteFile(path, []byte("12345"), 0644))
+		assert.Nil(t, os.Chtimes(path, now.Add(-f.age), now.Add(-f.age)))
+	}
+
+	filter := &Filter{Include: []string{"*.tmp"}}
+	option := NewCleanupOption()
+	option.DryRun = true
+
+	// 超过 1 小时的有 3 个，但要保留最新的 2 个，所以 c.tmp 保留。
+	report, err := CleanupByAge(root, filter, time.Hour, 2, option)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{filepath.Join(root, "a.tmp"), filepath.Join(root, "sub", "b.tmp")}, report.Removed)
+	assert.Equal(t, 2, report.Kept)
+	assert.Equal(t, int64(10), report.ReclaimedBytes)
+
+	exists, _, _ := FileExists(filepath.Join(root, "a.tmp"))
+	assert.True(t, exists)
+
+	option.DryRun = false
+	report, err = CleanupByAge(root, filter, time.Hour, 0, option)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(report.Removed))
+	assert.Equal(t, 1, report.Kept)
+
+	for _, name := range []string{"d.tmp", "keep.txt"} {
+		exists, _, _ = FileExists(filepath.Join(root, name))
+		assert.True(t, exists, name)
+	}
+	exists, _, _ = FileExists(filepath.Join(root, "sub"))
+	assert.True(t, exists)
+
+	_, err = CleanupByAge(root, filter, -time.Hour, 0, nil)
+	assert.NotNil(t, err)
+}
@@ -1,9 +1,10 @@
 package fileutils
 
 import (
-	"io"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 /*
@@ -136,6 +137,66 @@ func FileExists(path string) (bool, bool, error) {
 	return false, false, err
 }
 
+/*
+GetUniqueFilePath returns a path that does not exist yet, based on the given path.
+If the given path does not exist, it is returned as is. Otherwise a number is appended to the file name,
+such as "photo_1.jpg", "photo_2.jpg" and so on.
+
+Parameters:
+  - path: the desired path.
+
+Returns:
+  - a path that does not exist.
+  - an error if any occurred.
+
+GetUniqueFilePath 根据给定的路径返回一个尚不存在的路径。如果给定的路径不存在，则直接返回；
+否则在文件名后追加数字，例如 "photo_1.jpg"、"photo_2.jpg" 等。
+
+参数:
+  - path: 期望的路径。
+
+返回:
+  - 不存在的路径。
+  - 错误信息。
+*/
+func GetUniqueFilePath(path string) (string, error) {
+	return getUniqueFilePath(path, func(path string) (bool, error) {
+		exists, _, err := FileExists(path)
+		return exists, err
+	})
+}
+
+// getUniqueFilePath 返回 isTaken 为 false 的路径。isTaken 用于判断路径是否已被占用，例如已存在或已被计划使用。
+func getUniqueFilePath(path string, isTaken func(string) (bool, error)) (string, error) {
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext)
+	result := path
+
+	for i := 1; ; i++ {
+		taken, err := isTaken(result)
+		if err != nil {
+			return "", err
+		} else if !taken {
+			return result, nil
+		}
+
+		result = fmt.Sprintf("%s_%d%s", base, i, ext)
+	}
+}
+
+// isSameFile 检查两个路径是否指向同一个文件。任一路径无法访问时返回 false。
+func isSameFile(path1, path2 string) bool {
+	info1, err := os.Stat(path1)
+	if err != nil {
+		return false
+	}
+	info2, err := os.Stat(path2)
+	if err != nil {
+		return false
+	}
+	return os.SameFile(info1, info2)
+}
+
 /*
 CopyDir copies the directory and its contents from the source path to the target path.
 
@@ -158,10 +219,85 @@ CopyDir 复制目录。包含其下的文件和子目录。
   - 错误信息。
 */
 func CopyDir(source, target string, option *WalkOption) error {
+	return copyDir(source, target, option, false, nil)
+}
+
+/*
+CopyDirOption defines the options for [CopyDirWithOption].
+See [NewCopyDirOption] for default settings.
+
+CopyDirOption 定义了 [CopyDirWithOption] 的选项。默认设置参见 [NewCopyDirOption]。
+*/
+type CopyDirOption struct {
+	WalkOption
+	/*
+		If true, files hard linked to each other in the source are hard linked in the target as well,
+		instead of being copied more than once. Not supported on Windows, where they are always copied.
+	*/
+	PreserveHardLinks bool
+}
+
+/*
+NewCopyDirOption creates a new CopyDirOption with scan directory recursively,
+bypass permission denied error and preserve hard links.
+
+NewCopyDirOption 创建默认的 CopyDirOption。包含递归扫描目录、跳过没有权限的文件及目录，以及保留硬链接。
+*/
+func NewCopyDirOption() *CopyDirOption {
+	return &CopyDirOption{
+		WalkOption: WalkOption{
+			Recursive:        true,
+			PathErrorHandler: SkipPermissionError,
+		},
+		PreserveHardLinks: true,
+	}
+}
+
+/*
+CopyDirWithOption copies the directory like [CopyDir], with more options.
+
+Parameters:
+  - source: the source path of the directory to be copied.
+  - target: the target path where the directory and its contents will be copied to.
+  - option: the options. if nil, the default options will be used.
+
+Returns:
+  - an error if any occurred during the copy process.
+
+CopyDirWithOption 与 [CopyDir] 一样复制目录，但支持更多选项。
+
+参数:
+  - source: 要复制的源路径。
+  - target: 要复制的目标路径。
+  - option: 选项。如果为 nil 则使用默认选项。
+
+返回:
+  - 错误信息。
+*/
+func CopyDirWithOption(source, target string, option *CopyDirOption) error {
+	if option == nil { // 保证 option 不为 nil。
+		option = NewCopyDirOption()
+	}
+	return copyDir(source, target, &option.WalkOption, option.PreserveHardLinks, nil)
+}
+
+// copyDir 复制目录。preserveHardLinks 为 true 时，在目标中重建源目录中的硬链接。
+// beforeCopy 不为 nil 时，在复制每个文件前调用，返回错误则停止复制。重建硬链接的文件不调用。
+func copyDir(
+	source, target string,
+	option *WalkOption,
+	preserveHardLinks bool,
+	copyFile func(path string, target string, info os.FileInfo) error,
+) error {
 	if option == nil { // 保证 option 不为 nil。
 		option = NewWalkOption()
 	}
 
+	var tracker *hardLinkTracker
+	if preserveHardLinks {
+		tracker = newHardLinkTracker()
+	}
+
 	walkErr := filepath.Walk(source, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			if option.PathErrorHandler != nil {
@@ -183,28 +319,21 @@ func CopyDir(source, target string, option *WalkOption) error {
 			}
 
 			os.MkdirAll(abspath, os.ModePerm)
-		} else {
-			// 复制文件
-			from, err := os.Open(path)
-			if err != nil {
-				return err
-			}
-
-			to, err := os.Create(abspath)
-			if err != nil {
-				from.Close()
-				return err
-			}
+			return nil
+		}
 
-			_, err = io.Copy(to, from)
-			from.Close()
-			to.Close()
-			if err != nil {
-				return err
+		if tracker != nil {
+			if first, ok := tracker.track(abspath, info); ok && linkFile(first, abspath) == nil {
+				return nil
 			}
 		}
 
-		return nil
+		// 复制文件
+		if copyFile != nil {
+			return copyFile(path, abspath, info)
+		}
+		_, err = CopyFile(path, abspath, nil)
+		return err
 	})
 
 	if walkErr == filepath.SkipAll || walkErr == filepath.SkipDir {
@@ -214,13 +343,24 @@ func CopyDir(source, target string, option *WalkOption) error {
 	return walkErr
 }
 
+// linkFile 创建指向 existing 的硬链接 path，替换已存在的 path。失败时由调用者改为复制。
+func linkFile(existing, path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return os.Link(existing, path)
+}
+
 /*
 DirStatistics defines the statistics of a directory.
 */
 type DirStatistics struct {
-	DirCount  int
-	FileCount int
-	TotalSize int64
+	DirCount     int
+	FileCount    int
+	TotalSize    int64 // apparent size of all files, hard links to the same file are counted each time
+	PhysicalSize int64 // disk space allocated for all files, less than TotalSize for sparse files
+	UniqueSize   int64 // size of all files, hard links to the same file are counted once
+	LinkCount    int   // number of files which are hard links to a file already counted
 }
 
 /*
@@ -250,6 +390,7 @@ func GetDirStatistics(dir string, option *WalkOption) (stat *DirStatistics, err
 	}
 
 	stat = &DirStatistics{}
+	tracker := newHardLinkTracker()
 
 	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -268,6 +409,13 @@ func GetDirStatistics(dir string, option *WalkOption) (stat *DirStatistics, err
 		} else {
 			stat.FileCount++
 			stat.TotalSize += info.Size()
+			stat.PhysicalSize += physicalSize(info)
+
+			if _, ok := tracker.track(path, info); ok {
+				stat.LinkCount++
+			} else {
+				stat.UniqueSize += info.Size()
+			}
 		}
 
 		return nil
@@ -287,3 +435,85 @@ func FilterFilePathSkipErrors(err error) error {
 	}
 	return err
 }
+
+// filteredFileFunc 是遍历满足过滤条件的文件时的回调函数类型。relPath 为相对于根目录的路径。
+type filteredFileFunc func(relPath string, path string, info os.FileInfo) error
+
+// walkFilteredFiles 遍历满足过滤条件的文件，并计算其相对路径。
+func walkFilteredFiles(root string, filter *Filter, option *WalkOption, handler filteredFileFunc) error {
+	if filter == nil {
+		filter = &Filter{Include: []string{"*"}}
+	}
+
+	return filter.GetEachFile(root, option, func(path string, info os.FileInfo) error {
+		relPath, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		return handler(filepath.ToSlash(relPath), path, info)
+	})
+}
+
+// atomicFile 是写入同一目录下临时文件的文件。调用 Commit 后才改名覆盖目标文件，保证目标文件不会只写入一部分。
+type atomicFile struct {
+	*os.File
+	path string
+	mode os.FileMode
+}
+
+// createAtomicFile 为目标文件创建临时文件。目标文件已存在时，提交后保留其权限。
+func createAtomicFile(path string) (*atomicFile, error) {
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp*")
+	if err != nil {
+		return nil, err
+	}
+
+	return &atomicFile{File: temp, path: path, mode: mode}, nil
+}
+
+// Commit 将临时文件改名为目标文件。失败时删除临时文件。
+func (f *atomicFile) Commit() error {
+	err := f.Sync()
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(f.Name(), f.mode)
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), f.path)
+	}
+
+	if err != nil {
+		os.Remove(f.Name())
+	}
+	return err
+}
+
+// Abort 放弃写入，删除临时文件。
+func (f *atomicFile) Abort() {
+	f.Close()
+	os.Remove(f.Name())
+}
+
+// writeFileAtomic 以原子方式将数据写入文件。参见 atomicFile。
+func writeFileAtomic(path string, data []byte) error {
+	file, err := createAtomicFile(path)
+	if err != nil {
+		return err
+	}
+
+	if _, err = file.Write(data); err != nil {
+		file.Abort()
+		return err
+	}
+	return file.Commit()
+}
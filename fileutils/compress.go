@@ -0,0 +1,389 @@
+package fileutils
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/jqk/futool4go/common"
+)
+
+/*
+CompressionFormat is the name of a compression format.
+
+CompressionFormat 是压缩格式的名称。
+*/
+type CompressionFormat string
+
+const (
+	CompressionGzip CompressionFormat = "gzip" // built in
+	CompressionZstd CompressionFormat = "zstd" // must be registered by RegisterCompressor
+)
+
+/*
+ChecksumFileSuffix is appended to the compressed file name to get the checksum file name.
+The checksum file contains the algorithm, the source file name and the hex checksum of the uncompressed content,
+in the BSD format written by "sha256sum --tag", such as "SHA256 (data.txt) = 2cf2...".
+The format of sha256sum without the algorithm, such as "2cf2...  data.txt", can also be read.
+
+ChecksumFileSuffix 添加到压缩文件名之后，作为校验值文件名。校验值文件包含算法、源文件名及未压缩内容的十六进制校验值，
+格式与“sha256sum --tag”输出的 BSD 格式相同，例如 "SHA256 (data.txt) = 2cf2..."。
+也可以读取没有算法的 sha256sum 格式，例如 "2cf2...  data.txt"。
+*/
+const ChecksumFileSuffix = ".sum"
+
+// regexBSDChecksum 是 BSD 格式的校验值行。分组依次为：算法；文件名；十六进制校验值。
+var regexBSDChecksum = regexp.MustCompile(`^(\S+) \((.*)\) = ([0-9a-fA-F]+)$`)
+
+/*
+ErrChecksumMismatch is returned when the checksum of the decompressed content does not match the recorded one.
+
+ErrChecksumMismatch 在解压后内容的校验值与记录的不一致时返回。
+*/
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+/*
+Compressor creates compressing writers and decompressing readers of a format.
+
+Compressor 创建某种格式的压缩写入器及解压读取器。
+*/
+type Compressor interface {
+	// NewWriter returns a writer compressing data to w. level is format-specific, -1 means the default level.
+	NewWriter(w io.Writer, level int) (io.WriteCloser, error)
+	// NewReader returns a reader decompressing data from r.
+	NewReader(r io.Reader) (io.ReadCloser, error)
+}
+
+var (
+	compressorLock = sync.RWMutex{}
+	compressors    = map[CompressionFormat]Compressor{
+		CompressionGzip: gzipCompressor{},
+	}
+)
+
+/*
+RegisterCompressor registers the compressor of a format, replacing the existing one.
+Only gzip is built in. zstd can be registered by wrapping github.com/klauspost/compress/zstd, for example.
+
+RegisterCompressor 注册一种格式的压缩器，替换已有的压缩器。只内置了 gzip。
+zstd 可通过包装 github.com/klauspost/compress/zstd 等实现注册。
+*/
+func RegisterCompressor(format CompressionFormat, compressor Compressor) {
+	compressorLock.Lock()
+	defer compressorLock.Unlock()
+	compressors[format] = compressor
+}
+
+func getCompressor(format CompressionFormat) (Compressor, error) {
+	compressorLock.RLock()
+	defer compressorLock.RUnlock()
+
+	compressor, ok := compressors[format]
+	if !ok {
+		return nil, fmt.Errorf("no compressor registered for format %q", format)
+	}
+	return compressor, nil
+}
+
+/*
+ProgressFunc reports the progress of a long-running operation.
+done is the number of bytes processed, total is the number of bytes to process, or -1 if unknown.
+
+ProgressFunc 报告耗时操作的进度。done 为已处理的字节数，total 为需要处理的字节数，未知时为 -1。
+*/
+type ProgressFunc func(done int64, total int64)
+
+/*
+CompressOption defines the options for [CompressFile] and [DecompressFile].
+See [NewCompressOption] for default settings.
+
+CompressOption 定义了 [CompressFile] 及 [DecompressFile] 的选项。默认设置参见 [NewCompressOption]。
+*/
+type CompressOption struct {
+	Format   CompressionFormat // the compression format
+	Level    int               // the compression level, format-specific. -1 means the default level
+	Buffer   []byte            // the buffer for copying, can be reused between calls. nil means allocate one
+	Progress ProgressFunc      // called after each buffer is processed, with the bytes of the source file. nil means no report
+	/*
+		If not nil, the checksum of the uncompressed content is calculated.
+		CompressFile writes it to the checksum file. See [ChecksumFileSuffix].
+		DecompressFile verifies it against the checksum file if it exists.
+	*/
+	Checksum hash.Hash
+	/*
+		The algorithm name of Checksum, such as "sha256", written to the checksum file and checked against it.
+		Required by CompressFile if Checksum is not nil. If Checksum is nil, the algorithm registered in
+		common.RegisterHash with this name is used. Empty means no checksum.
+	*/
+	ChecksumName string
+}
+
+/*
+NewCompressOption creates a new CompressOption with gzip format, default level,
+allocated buffer, no progress report and no checksum.
+
+NewCompressOption 创建默认的 CompressOption。包含 gzip 格式、默认压缩级别、自动分配缓冲区、不报告进度，以及不计算校验值。
+*/
+func NewCompressOption() *CompressOption {
+	return &CompressOption{
+		Format:       CompressionGzip,
+		Level:        -1,
+		Buffer:       nil,
+		Progress:     nil,
+		Checksum:     nil,
+		ChecksumName: "",
+	}
+}
+
+/*
+CompressResult is the result of [CompressFile] and [DecompressFile].
+
+CompressResult 是 [CompressFile] 及 [DecompressFile] 的结果。
+*/
+type CompressResult struct {
+	CompressedSize   int64  // The size of the compressed content.
+	UncompressedSize int64  // The size of the uncompressed content.
+	Checksum         []byte // The checksum of the uncompressed content. nil if no checksum is calculated.
+}
+
+/*
+CompressFile compresses the file src to dst. dst is written atomically.
+
+Parameters:
+  - src: the file to compress.
+  - dst: the compressed file.
+  - option: the options. if nil, the default options will be used.
+
+Returns:
+  - the result.
+  - an error if any occurred.
+
+CompressFile 将文件 src 压缩为 dst。dst 以原子方式写入。
+
+参数:
+  - src: 要压缩的文件。
+  - dst: 压缩后的文件。
+  - option: 选项。如果为 nil 则使用默认选项。
+
+返回:
+  - 压缩结果。
+  - 错误信息。
+*/
+func CompressFile(src string, dst string, option *CompressOption) (*CompressResult, error) {
+	return transformFile(src, dst, option, true)
+}
+
+/*
+DecompressFile decompresses the file src to dst. dst is written atomically.
+If a checksum is calculated and the checksum file of src exists, the decompressed content is verified,
+and [ErrChecksumMismatch] is returned without writing dst if they do not match.
+It is an error if the checksum file records an algorithm other than option.ChecksumName.
+
+Parameters:
+  - src: the compressed file.
+  - dst: the decompressed file.
+  - option: the options. if nil, the default options will be used.
+
+Returns:
+  - the result.
+  - an error if any occurred.
+
+DecompressFile 将文件 src 解压为 dst。dst 以原子方式写入。
+如果计算校验值且 src 的校验值文件存在，则校验解压后的内容，不一致时返回 [ErrChecksumMismatch] 且不写入 dst。
+校验值文件记录的算法与 option.ChecksumName 不同时返回错误。
+
+参数:
+  - src: 压缩文件。
+  - dst: 解压后的文件。
+  - option: 选项。如果为 nil 则使用默认选项。
+
+返回:
+  - 解压结果。
+  - 错误信息。
+*/
+func DecompressFile(src string, dst string, option *CompressOption) (*CompressResult, error) {
+	return transformFile(src, dst, option, false)
+}
+
+// transformFile 压缩或解压文件。
+func transformFile(src string, dst string, option *CompressOption, compress bool) (*CompressResult, error) {
+	if option == nil { // 保证 option 不为 nil。
+		option = NewCompressOption()
+	}
+
+	compressor, err := getCompressor(option.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	if option.Checksum == nil && option.ChecksumName != "" {
+		checksum, err := common.NewHash(option.ChecksumName)
+		if err != nil {
+			return nil, err
+		}
+		// 复制选项，不修改调用者的选项。
+		copied := *option
+		copied.Checksum = checksum
+		option = &copied
+	} else if compress && option.Checksum != nil && option.ChecksumName == "" {
+		return nil, errors.New("ChecksumName is required to record the checksum algorithm")
+	}
+
+	buffer := option.Buffer
+	if len(buffer) == 0 {
+		buffer = common.DefaultBufferManager.Get(64 * 1024)
+		defer common.DefaultBufferManager.Put(buffer)
+	}
+
+	srcFile, err := os.Open(src)
+	if err != nil {
+		return nil, err
+	}
+	defer srcFile.Close()
+
+	srcInfo, err := srcFile.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	var expected []byte
+	if !compress && option.Checksum != nil {
+		var algorithm string
+		if algorithm, expected, err = readChecksumFile(src + ChecksumFileSuffix); err != nil {
+			return nil, err
+		} else if algorithm != "" && !strings.EqualFold(algorithm, option.ChecksumName) {
+			return nil, fmt.Errorf("%s: checksum file uses %s, not %q", src, algorithm, option.ChecksumName)
+		}
+	}
+
+	dstFile, err := createAtomicFile(dst)
+	if err != nil {
+		return nil, err
+	}
+
+	// 统计读取的源文件字节数，用于报告进度。
+	srcCounter := NewCountingReader(srcFile, false)
+	result := &CompressResult{}
+
+	if compress {
+		dstCounter := NewCountingWriter(dstFile, false)
+		var writer io.WriteCloser
+		if writer, err = compressor.NewWriter(dstCounter, option.Level); err == nil {
+			result.UncompressedSize, err = copyUncompressed(writer, srcCounter, buffer, option, srcCounter, srcInfo.Size())
+			if closeErr := writer.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		result.CompressedSize = dstCounter.Count()
+	} else {
+		var reader io.ReadCloser
+		if reader, err = compressor.NewReader(srcCounter); err == nil {
+			result.UncompressedSize, err = copyUncompressed(dstFile, reader, buffer, option, srcCounter, srcInfo.Size())
+			reader.Close()
+		}
+		result.CompressedSize = srcCounter.Count()
+	}
+
+	if err == nil && option.Checksum != nil {
+		result.Checksum = option.Checksum.Sum(nil)
+		if expected != nil && !bytes.Equal(expected, result.Checksum) {
+			err = fmt.Errorf("%s: %w", src, ErrChecksumMismatch)
+		}
+	}
+
+	if err != nil {
+		dstFile.Abort()
+		return nil, err
+	}
+	if err = dstFile.Commit(); err != nil {
+		return nil, err
+	}
+
+	if compress && option.Checksum != nil {
+		line := fmt.Sprintf("%s (%s) = %s\n", strings.ToUpper(option.ChecksumName), filepath.Base(src), hex.EncodeToString(result.Checksum))
+		if err = writeFileAtomic(dst+ChecksumFileSuffix, []byte(line)); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// copyUncompressed 使用给定的缓冲区复制未压缩的内容，同时计算校验值并报告进度。返回复制的字节数。
+// 进度为 srcCounter 已读取的源文件字节数，而不是复制的字节数。
+func copyUncompressed(writer io.Writer, reader io.Reader, buffer []byte, option *CompressOption, srcCounter *CountingReader, total int64) (int64, error) {
+	if option.Checksum != nil {
+		option.Checksum.Reset()
+		writer = io.MultiWriter(writer, option.Checksum)
+	}
+
+	var written int64
+	for {
+		n, err := reader.Read(buffer)
+		if n > 0 {
+			if _, writeErr := writer.Write(buffer[:n]); writeErr != nil {
+				return written, writeErr
+			}
+			written += int64(n)
+
+			if option.Progress != nil {
+				option.Progress(srcCounter.Count(), total)
+			}
+		}
+
+		if err == io.EOF {
+			return written, nil
+		} else if err != nil {
+			return written, err
+		}
+	}
+}
+
+// readChecksumFile 读取校验值文件中的算法及十六进制校验值。没有算法的 sha256sum 格式返回空的算法，文件不存在时返回 nil。
+func readChecksumFile(path string) (string, []byte, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return "", nil, nil
+	} else if err != nil {
+		return "", nil, err
+	}
+	defer file.Close()
+
+	line, err := bufio.NewReader(file).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", nil, err
+	}
+
+	line = strings.TrimRight(line, "\r\n")
+	if subs := regexBSDChecksum.FindStringSubmatch(line); subs != nil {
+		sum, err := hex.DecodeString(subs[3])
+		return subs[1], sum, err
+	}
+
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return "", nil, fmt.Errorf("invalid checksum file: %s", path)
+	}
+	sum, err := hex.DecodeString(fields[0])
+	return "", sum, err
+}
+
+type gzipCompressor struct{}
+
+func (gzipCompressor) NewWriter(w io.Writer, level int) (io.WriteCloser, error) {
+	return gzip.NewWriterLevel(w, level)
+}
+
+func (gzipCompressor) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return gzip.NewReader(r)
+}
@@ -0,0 +1,140 @@
+package fileutils
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompressFile(t *testing.T) {
+	root := t.TempDir()
+	src := filepath.Join(root, "data.txt")
+	gz := filepath.Join(root, "data.txt.gz")
+	out := filepath.Join(root, "out.txt")
+	content := []byte(strings.Repeat("futool4go compress test\n", 10000))
+	assert.Nil(t, os.WriteFile(src, content, 0644))
+
+	option := NewCompressOption()
+	option.Buffer = make([]byte, 4096)
+	option.Checksum = sha256.New()
+	option.ChecksumName = "sha256"
+	var lastDone, lastTotal int64
+	option.Progress = func(done int64, total int64) {
+		assert.True(t, done >= lastDone)
+		lastDone, lastTotal = done, total
+	}
+
+	result, err := CompressFile(src, gz, option)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(len(content)), result.UncompressedSize)
+	assert.True(t, result.CompressedSize < result.UncompressedSize)
+	assert.Equal(t, int64(len(content)), lastDone)
+	assert.Equal(t, int64(len(content)), lastTotal)
+
+	expected := sha256.Sum256(content)
+	assert.Equal(t, expected[:], result.Checksum)
+	sum, _ := os.ReadFile(gz + ChecksumFileSuffix)
+	assert.Equal(t, "SHA256 (data.txt) = "+hex.EncodeToString(expected[:])+"\n", string(sum))
+
+	info, _ := os.Stat(gz)
+	assert.Equal(t, result.CompressedSize, info.Size())
+
+	// 解压并校验。
+	lastDone = 0
+	result, err = DecompressFile(gz, out, option)
+	assert.Nil(t, err)
+	assert.Equal(t, info.Size(), lastDone)
+	assert.Equal(t, expected[:], result.Checksum)
+	data, _ := os.ReadFile(out)
+	assert.Equal(t, content, data)
+
+	// 只给出算法名称时使用注册的算法。
+	assert.Nil(t, os.Remove(out))
+	result, err = DecompressFile(gz, out, &CompressOption{Format: CompressionGzip, ChecksumName: "SHA256"})
+	assert.Nil(t, err)
+	assert.Equal(t, expected[:], result.Checksum)
+
+	// 校验值文件记录的算法不同。
+	assert.Nil(t, os.WriteFile(gz+ChecksumFileSuffix, []byte("MD5 (data.txt) = "+strings.Repeat("00", 16)+"\n"), 0644))
+	assert.Nil(t, os.Remove(out))
+	_, err = DecompressFile(gz, out, option)
+	assert.NotNil(t, err)
+	assert.False(t, errors.Is(err, ErrChecksumMismatch))
+	assert.Contains(t, err.Error(), "MD5")
+
+	// 校验值不一致时不写入目标文件，也可以读取没有算法的格式。
+	assert.Nil(t, os.WriteFile(gz+ChecksumFileSuffix, []byte(strings.Repeat("00", 32)+"  data.txt\n"), 0644))
+	_, err = DecompressFile(gz, out, option)
+	assert.ErrorIs(t, err, ErrChecksumMismatch)
+	exists, _, _ := FileExists(out)
+	assert.False(t, exists)
+
+	// 不计算校验值时忽略校验值文件。
+	_, err = DecompressFile(gz, out, nil)
+	assert.Nil(t, err)
+
+	// 不是压缩文件。
+	_, err = DecompressFile(src, filepath.Join(root, "bad"), nil)
+	assert.NotNil(t, err)
+	entries, _ := os.ReadDir(root)
+	assert.Equal(t, 4, len(entries))
+
+	// 没有算法名称时无法记录校验值。
+	option = NewCompressOption()
+	option.Checksum = sha256.New()
+	_, err = CompressFile(src, filepath.Join(root, "noname.gz"), option)
+	assert.NotNil(t, err)
+
+	// 未注册的格式。
+	option = NewCompressOption()
+	option.Format = CompressionZstd
+	_, err = CompressFile(src, gz, option)
+	assert.NotNil(t, err)
+}
+
+// xorCompressor 是用于测试的压缩器，将每个字节与 1 异或。
+type xorCompressor struct{}
+
+type xorWriter struct{ w io.Writer }
+
+func (x xorWriter) Write(p []byte) (int, error) {
+	return x.w.Write(bytes.Map(func(r rune) rune { return r ^ 1 }, p))
+}
+func (x xorWriter) Close() error { return nil }
+
+func (xorCompressor) NewWriter(w io.Writer, level int) (io.WriteCloser, error) {
+	return xorWriter{w}, nil
+}
+
+func (xorCompressor) NewReader(r io.Reader) (io.ReadCloser, error) {
+	data, err := io.ReadAll(r)
+	return io.NopCloser(bytes.NewReader(bytes.Map(func(r rune) rune { return r ^ 1 }, data))), err
+}
+
+func TestRegisterCompressor(t *testing.T) {
+	root := t.TempDir()
+	src := filepath.Join(root, "data.txt")
+	assert.Nil(t, os.WriteFile(src, []byte("abc"), 0644))
+
+	RegisterCompressor("xor", xorCompressor{})
+	option := NewCompressOption()
+	option.Format = "xor"
+
+	_, err := CompressFile(src, src+".xor", option)
+	assert.Nil(t, err)
+	data, _ := os.ReadFile(src + ".xor")
+	assert.Equal(t, "`cb", string(data))
+
+	_, err = DecompressFile(src+".xor", src+".out", option)
+	assert.Nil(t, err)
+	data, _ = os.ReadFile(src + ".out")
+	assert.Equal(t, "abc", string(data))
+}
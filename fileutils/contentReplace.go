@@ -0,0 +1,323 @@
+package fileutils
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+/*
+ReplaceOption defines the options for [ReplaceContents].
+See [NewReplaceOption] for default settings.
+
+ReplaceOption 定义了 [ReplaceContents] 的选项。默认设置参见 [NewReplaceOption]。
+*/
+type ReplaceOption struct {
+	WalkOption
+	IsRegexp   bool // if true, the pattern is a regular expression and the replacement may contain $1, ${name} etc.
+	IgnoreCase bool // if true, the pattern is matched case-insensitively
+	DryRun     bool // if true, only report the changes without modifying any file
+}
+
+/*
+NewReplaceOption creates a new ReplaceOption with scan directory recursively,
+bypass permission denied error, literal case-sensitive pattern and no dry run.
+
+NewReplaceOption 创建默认的 ReplaceOption。包含递归扫描目录、跳过没有权限的文件及目录、区分大小写的字面量匹配，以及实际执行替换。
+*/
+func NewReplaceOption() *ReplaceOption {
+	return &ReplaceOption{
+		WalkOption: WalkOption{
+			Recursive:        true,
+			PathErrorHandler: SkipPermissionError,
+		},
+		IsRegexp:   false,
+		IgnoreCase: false,
+		DryRun:     false,
+	}
+}
+
+/*
+ReplaceLineChange is a line changed by [ReplaceContents].
+
+ReplaceLineChange 是 [ReplaceContents] 修改的一行。
+*/
+type ReplaceLineChange struct {
+	LineNumber int    // The line number, starts from 1.
+	Before     string // The line before replacement, without line ending.
+	After      string // The line after replacement, without line ending.
+}
+
+/*
+ReplaceFileResult is the changes made to a file by [ReplaceContents].
+
+ReplaceFileResult 是 [ReplaceContents] 对一个文件所做的修改。
+*/
+type ReplaceFileResult struct {
+	Path         string              // The file path.
+	Encoding     TextEncoding        // The detected encoding of the file, kept when written. EncodingUnknown for other text processed as bytes.
+	Replacements int                 // The number of replacements.
+	Changes      []ReplaceLineChange // The changed lines in line order, in UTF-8.
+}
+
+/*
+Diff returns the changes of the file in a unified-diff-like format without context lines.
+
+Diff 以类似 unified diff 的格式返回文件的修改，不包含上下文行。
+*/
+func (r *ReplaceFileResult) Diff() string {
+	result := strings.Builder{}
+	result.WriteString(fmt.Sprintf("--- %s\n+++ %s\n", r.Path, r.Path))
+
+	for _, change := range r.Changes {
+		result.WriteString(fmt.Sprintf("@@ -%d +%d @@\n", change.LineNumber, change.LineNumber))
+		result.WriteString("-" + change.Before + "\n")
+		result.WriteString("+" + change.After + "\n")
+	}
+
+	return result.String()
+}
+
+/*
+ReplaceReport is the result of [ReplaceContents].
+
+ReplaceReport 是 [ReplaceContents] 的结果。
+*/
+type ReplaceReport struct {
+	Files        []*ReplaceFileResult // The changed files in walk order.
+	Replacements int                  // The total number of replacements.
+	SkippedFiles []string             // The binary files and the files that cannot be decoded, which are skipped.
+}
+
+/*
+ReplaceContents replaces text in files under the given directory line by line.
+
+The encoding of each file is detected by [DetectEncodingBytes]. Files in UTF-16, GBK, Big5 or other encodings with
+a registered [TextCodec] are decoded to UTF-8, replaced and encoded back. UTF-8 files and other text not recognized are
+processed as bytes, so only ASCII-compatible patterns match in the latter. Either way, the encoding, byte order mark and
+line endings of the files are preserved. Binary files and files that cannot be decoded are skipped and listed in the report.
+Each changed file is written to a temporary file first and then renamed to the original one, so a file is never partially written.
+
+Parameters:
+  - root: the directory to scan.
+  - filter: only files matching the filter are processed. nil means all files.
+  - pattern: the literal string or regular expression to search for.
+  - replacement: the replacement text.
+  - option: the options. if nil, the default options will be used.
+
+Returns:
+  - the report of changes. Use ReplaceFileResult.Diff to preview in dry run. Partial report is returned along with the error.
+  - an error if any occurred, including the replaced text of a file that cannot be encoded back to its encoding.
+
+ReplaceContents 逐行替换给定目录下文件中的文本。
+
+使用 [DetectEncodingBytes] 检测每个文件的编码。UTF-16、GBK、Big5 或其它注册了 [TextCodec] 的编码的文件先解码为 UTF-8，
+替换后再编码为原来的编码。UTF-8 文件及其它无法识别编码的文本按字节处理，后者只有兼容 ASCII 的模式才能匹配。
+这两种方式都保留文件的编码、字节顺序标记及换行符。跳过二进制文件及无法解码的文件，并在报告中列出。
+修改后的文件先写入临时文件，再改名覆盖原文件，所以不会出现只写入一部分的文件。
+
+参数:
+  - root: 要扫描的目录。
+  - filter: 只处理满足过滤条件的文件。nil 表示所有文件。
+  - pattern: 要搜索的字面量字符串或正则表达式。
+  - replacement: 替换文本。
+  - option: 选项。如果为 nil 则使用默认选项。
+
+返回:
+  - 修改报告。空运行时可使用 ReplaceFileResult.Diff 预览。出错时返回已完成的部分报告。
+  - 错误信息，包括替换后的文本无法编码为文件原来的编码。
+*/
+func ReplaceContents(root string, filter *Filter, pattern string, replacement string, option *ReplaceOption) (*ReplaceReport, error) {
+	report := &ReplaceReport{Files: []*ReplaceFileResult{}, SkippedFiles: []string{}}
+
+	if option == nil { // 保证 option 不为 nil。
+		option = NewReplaceOption()
+	}
+
+	replace, err := newLineReplacer(pattern, replacement, option.IsRegexp, option.IgnoreCase)
+	if err != nil {
+		return report, err
+	}
+
+	err = walkFilteredFiles(root, filter, &option.WalkOption, func(relPath string, path string, info os.FileInfo) error {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		// UTF-16 也被判断为二进制内容，所以先检测编码。
+		encoding, hasBOM := DetectEncodingBytes(data)
+		if encoding == EncodingUnknown {
+			header := data
+			if len(header) > DefaultSniffSize {
+				header = header[:DefaultSniffSize]
+			}
+			if IsBinaryContent(header) {
+				report.SkippedFiles = append(report.SkippedFiles, path)
+				return nil
+			}
+		}
+
+		result, newData, err := replaceEncoded(path, data, encoding, hasBOM, replace)
+		if err == errCannotDecode {
+			report.SkippedFiles = append(report.SkippedFiles, path)
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		} else if result.Replacements == 0 {
+			return nil
+		}
+
+		if !option.DryRun {
+			if err = writeFileAtomic(path, newData); err != nil {
+				return err
+			}
+		}
+
+		report.Files = append(report.Files, result)
+		report.Replacements += result.Replacements
+		return nil
+	})
+
+	return report, err
+}
+
+// errCannotDecode 表示文件无法按检测到的编码解码。
+var errCannotDecode = errors.New("cannot decode")
+
+// replaceEncoded 替换文件数据中的文本。UTF-8 及无法识别编码的文本按字节处理，其它编码先解码为 UTF-8，替换后再按原编码及字节顺序标记编码。
+func replaceEncoded(path string, data []byte, encoding TextEncoding, hasBOM bool, replace func(string) (string, int)) (*ReplaceFileResult, []byte, error) {
+	if encoding == EncodingUnknown || encoding == EncodingUTF8 {
+		result, newData := replaceLines(path, data, replace)
+		result.Encoding = encoding
+		return result, newData, nil
+	}
+
+	codec, err := getTextCodec(encoding)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var bom []byte
+	if hasBOM {
+		switch encoding {
+		case EncodingUTF16LE:
+			bom = utf16LEBOM
+		case EncodingUTF16BE:
+			bom = utf16BEBOM
+		}
+	}
+
+	text, err := codec.Decode(data[len(bom):])
+	if err != nil {
+		return nil, nil, errCannotDecode
+	}
+	if (encoding == EncodingUTF16LE || encoding == EncodingUTF16BE) && !hasBOM && !isPlainASCIIText(text) {
+		// 没有 BOM 的 UTF-16 只是按 0 字节的分布推测的，不是以 ASCII 为主的可打印文本时按二进制内容跳过。
+		return nil, nil, errCannotDecode
+	}
+
+	result, newText := replaceLines(path, []byte(text), replace)
+	result.Encoding = encoding
+	if result.Replacements == 0 {
+		return result, data, nil
+	}
+
+	encoded, err := codec.Encode(string(newText))
+	if err != nil {
+		return nil, nil, err
+	}
+	return result, append(append([]byte{}, bom...), encoded...), nil
+}
+
+// isPlainASCIIText 检查文本是否只包含可打印字符及空白字符，且一半以上是 ASCII 字符。
+func isPlainASCIIText(text string) bool {
+	total, ascii := 0, 0
+	for _, c := range text {
+		if !unicode.IsPrint(c) && c != '\t' && c != '\n' && c != '\r' {
+			return false
+		}
+		total++
+		if c < utf8.RuneSelf {
+			ascii++
+		}
+	}
+	return ascii*2 > total
+}
+
+// replaceLines 逐行替换，保留每行原有的换行符。
+func replaceLines(path string, data []byte, replace func(string) (string, int)) (*ReplaceFileResult, []byte) {
+	result := &ReplaceFileResult{Path: path, Changes: []ReplaceLineChange{}}
+	output := bytes.Buffer{}
+	output.Grow(len(data))
+
+	for lineNumber := 1; len(data) > 0; lineNumber++ {
+		end := bytes.IndexByte(data, '\n') + 1
+		if end == 0 {
+			end = len(data)
+		}
+
+		line := string(data[:end])
+		data = data[end:]
+
+		// 分离行内容与换行符。
+		content := strings.TrimSuffix(line, "\n")
+		content = strings.TrimSuffix(content, "\r")
+		ending := line[len(content):]
+
+		newContent, count := replace(content)
+		if count > 0 {
+			result.Replacements += count
+			result.Changes = append(result.Changes, ReplaceLineChange{LineNumber: lineNumber, Before: content, After: newContent})
+		}
+
+		output.WriteString(newContent)
+		output.WriteString(ending)
+	}
+
+	return result, output.Bytes()
+}
+
+// newLineReplacer 根据选项创建替换一行文本的函数，返回替换后的文本及替换次数。
+func newLineReplacer(pattern string, replacement string, isRegexp bool, ignoreCase bool) (func(string) (string, int), error) {
+	if pattern == "" {
+		return nil, errors.New("pattern must not be empty")
+	}
+
+	if !isRegexp && !ignoreCase {
+		return func(line string) (string, int) {
+			count := strings.Count(line, pattern)
+			if count == 0 {
+				return line, 0
+			}
+			return strings.ReplaceAll(line, pattern, replacement), count
+		}, nil
+	}
+
+	if !isRegexp {
+		pattern = regexp.QuoteMeta(pattern)
+		// 字面量替换时，$ 不是分组引用。
+		replacement = strings.ReplaceAll(replacement, "$", "$$")
+	}
+	if ignoreCase {
+		pattern = "(?i)" + pattern
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(line string) (string, int) {
+		count := len(re.FindAllStringIndex(line, -1))
+		if count == 0 {
+			return line, 0
+		}
+		return re.ReplaceAllString(line, replacement), count
+	}, nil
+}
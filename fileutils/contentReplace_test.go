@@ -0,0 +1,120 @@
+package fileutils
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplaceContents(t *testing.T) {
+	root := t.TempDir()
+	crlf := filepath.Join(root, "a.txt")
+	bom := filepath.Join(root, "b.txt")
+	binary := filepath.Join(root, "c.bin")
+	assert.Nil(t, os.WriteFile(crlf, []byte("foo bar\r\nbar\r\nFOO foo"), 0600))
+	assert.Nil(t, os.WriteFile(bom, []byte("\xef\xbb\xbf中文 foo\n"), 0644))
+	assert.Nil(t, os.WriteFile(binary, []byte("foo\x00"), 0644))
+
+	// 空运行不修改文件。
+	option := NewReplaceOption()
+	option.DryRun = true
+	report, err := ReplaceContents(root, nil, "foo", "baz", option)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, report.Replacements)
+	assert.Equal(t, 2, len(report.Files))
+	assert.Equal(t, []string{binary}, report.SkippedFiles)
+	assert.Equal(t, []ReplaceLineChange{
+		{LineNumber: 1, Before: "foo bar", After: "baz bar"},
+		{LineNumber: 3, Before: "FOO foo", After: "FOO baz"},
+	}, report.Files[0].Changes)
+	assert.True(t, strings.Contains(report.Files[0].Diff(), "@@ -3 +3 @@\n-FOO foo\n+FOO baz\n"))
+
+	data, _ := os.ReadFile(crlf)
+	assert.Equal(t, "foo bar\r\nbar\r\nFOO foo", string(data))
+
+	// 忽略大小写，保留换行符、BOM 及权限。字面量替换中的 $ 不是分组引用。
+	option.DryRun = false
+	option.IgnoreCase = true
+	report, err = ReplaceContents(root, nil, "foo", "$1", option)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, report.Replacements)
+
+	data, _ = os.ReadFile(crlf)
+	assert.Equal(t, "$1 bar\r\nbar\r\n$1 $1", string(data))
+	data, _ = os.ReadFile(bom)
+	assert.Equal(t, "\xef\xbb\xbf中文 $1\n", string(data))
+	info, _ := os.Stat(crlf)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	// 正则表达式，使用分组引用，只处理 txt 文件。
+	option = NewReplaceOption()
+	option.IsRegexp = true
+	report, err = ReplaceContents(root, &Filter{Include: []string{"a.txt"}}, `(\$1) (\w+)`, "$2 ${1}", option)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, report.Replacements)
+	assert.Equal(t, 0, len(report.SkippedFiles))
+
+	data, _ = os.ReadFile(crlf)
+	assert.Equal(t, "bar $1\r\nbar\r\n$1 $1", string(data))
+
+	// 没有临时文件残留。
+	entries, _ := os.ReadDir(root)
+	assert.Equal(t, 3, len(entries))
+
+	_, err = ReplaceContents(root, nil, "(", "", option)
+	assert.NotNil(t, err)
+	_, err = ReplaceContents(root, nil, "", "", nil)
+	assert.NotNil(t, err)
+}
+
+func TestReplaceContentsEncodings(t *testing.T) {
+	gbkCodec, _ := getTextCodec(EncodingGBK)
+	utf16Codec, _ := getTextCodec(EncodingUTF16LE)
+	encode := func(codec TextCodec, text string) []byte {
+		data, err := codec.Encode(text)
+		assert.Nil(t, err)
+		return data
+	}
+
+	root := t.TempDir()
+	gbk := filepath.Join(root, "gbk.txt")
+	utf16 := filepath.Join(root, "utf16.txt")
+	assert.Nil(t, os.WriteFile(gbk, encode(gbkCodec, "中文文本 foo\r\n第二行\r\n"), 0644))
+	assert.Nil(t, os.WriteFile(utf16, append([]byte{0xff, 0xfe}, encode(utf16Codec, "中文 foo\n")...), 0644))
+
+	// 先解码为 UTF-8 再替换，写入时保留原来的编码、BOM 及换行符。
+	report, err := ReplaceContents(root, nil, "中文", "汉字", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, report.Replacements)
+	assert.Equal(t, 0, len(report.SkippedFiles))
+	assert.Equal(t, EncodingGBK, report.Files[0].Encoding)
+	assert.Equal(t, []ReplaceLineChange{{LineNumber: 1, Before: "中文文本 foo", After: "汉字文本 foo"}}, report.Files[0].Changes)
+	assert.Equal(t, EncodingUTF16LE, report.Files[1].Encoding)
+
+	data, _ := os.ReadFile(gbk)
+	assert.Equal(t, encode(gbkCodec, "汉字文本 foo\r\n第二行\r\n"), data)
+	data, _ = os.ReadFile(utf16)
+	assert.Equal(t, append([]byte{0xff, 0xfe}, encode(utf16Codec, "汉字 foo\n")...), data)
+
+	// 没有 BOM 时，只有以 ASCII 为主的可打印文本才按 UTF-16 处理。
+	noBOM := filepath.Join(root, "utf16.bin")
+	assert.Nil(t, os.WriteFile(noBOM, encode(utf16Codec, "foo bar\n"), 0644))
+	assert.Nil(t, os.WriteFile(filepath.Join(root, "data.bin"), []byte("foo\x00\x01\x00\x02\x00"), 0644))
+	option := NewReplaceOption()
+	option.DryRun = true
+	report, err = ReplaceContents(root, &Filter{Include: []string{"*.bin"}}, "foo", "baz", option)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(report.Files))
+	assert.Equal(t, noBOM, report.Files[0].Path)
+	assert.Equal(t, []string{filepath.Join(root, "data.bin")}, report.SkippedFiles)
+
+	// 替换后的文本无法编码为 GBK 时返回错误，文件不变。
+	_, err = ReplaceContents(root, &Filter{Include: []string{"gbk.txt"}}, "foo", "😀", nil)
+	assert.NotNil(t, err)
+	assert.True(t, strings.Contains(err.Error(), gbk))
+	data, _ = os.ReadFile(gbk)
+	assert.Equal(t, encode(gbkCodec, "汉字文本 foo\r\n第二行\r\n"), data)
+}
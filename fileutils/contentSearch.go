@@ -0,0 +1,316 @@
+package fileutils
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/jqk/futool4go/common"
+)
+
+/*
+SearchMatch is a line matched by [SearchContents].
+
+SearchMatch 是 [SearchContents] 匹配到的行。
+*/
+type SearchMatch struct {
+	Path       string   // The file path.
+	LineNumber int      // The line number, starts from 1.
+	Line       string   // The matched line without line ending.
+	Before     []string // Context lines before the matched line.
+	After      []string // Context lines after the matched line.
+}
+
+/*
+SearchMatchHandler is called for each match found by [SearchContents].
+Matches of a file are delivered in line order, and the handler is never called concurrently.
+Return filepath.SkipAll to stop the search without error.
+
+SearchMatchHandler 针对 [SearchContents] 找到的每个匹配调用。同一文件的匹配按行号顺序给出，且不会并发调用。
+返回 filepath.SkipAll 可停止搜索且不返回错误。
+*/
+type SearchMatchHandler func(match *SearchMatch) error
+
+/*
+SearchOption defines the options for [SearchContents].
+See [NewSearchOption] for default settings.
+
+SearchOption 定义了 [SearchContents] 的选项。默认设置参见 [NewSearchOption]。
+*/
+type SearchOption struct {
+	WalkOption
+	IsRegexp      bool // if true, the pattern is a regular expression. Otherwise it is a literal string
+	IgnoreCase    bool // if true, the pattern is matched case-insensitively
+	ContextLines  int  // the number of lines before and after each match to be included
+	IncludeBinary bool // if true, binary files are searched too. See [IsBinaryContent]
+	Parallelism   int  // the number of files searched at the same time. 1 or less means sequentially
+}
+
+/*
+NewSearchOption creates a new SearchOption with scan directory recursively,
+bypass permission denied error, literal case-sensitive pattern, no context lines,
+skip binary files and search sequentially.
+
+NewSearchOption 创建默认的 SearchOption。包含递归扫描目录、跳过没有权限的文件及目录、区分大小写的字面量匹配、
+无上下文行、跳过二进制文件，以及顺序搜索。
+*/
+func NewSearchOption() *SearchOption {
+	return &SearchOption{
+		WalkOption: WalkOption{
+			Recursive:        true,
+			PathErrorHandler: SkipPermissionError,
+		},
+		IsRegexp:      false,
+		IgnoreCase:    false,
+		ContextLines:  0,
+		IncludeBinary: false,
+		Parallelism:   1,
+	}
+}
+
+/*
+SearchContents searches the contents of files under the given directory line by line, like grep.
+
+Parameters:
+  - root: the directory to scan.
+  - filter: only files matching the filter are searched. nil means all files.
+  - pattern: the literal string or regular expression to search for.
+  - option: the options. if nil, the default options will be used.
+  - handler: called for each match. See [SearchMatchHandler].
+
+Returns:
+  - an error if any occurred.
+
+Example:
+
+	option := NewSearchOption()
+	option.IsRegexp = true
+	option.ContextLines = 2
+	err := SearchContents("src", &Filter{Include: []string{"*.go"}}, `func \w+`, option, func(match *SearchMatch) error {
+		fmt.Printf("%s:%d: %s\n", match.Path, match.LineNumber, match.Line)
+		return nil
+	})
+
+SearchContents 类似 grep，逐行搜索给定目录下文件的内容。
+
+参数:
+  - root: 要扫描的目录。
+  - filter: 只搜索满足过滤条件的文件。nil 表示所有文件。
+  - pattern: 要搜索的字面量字符串或正则表达式。
+  - option: 选项。如果为 nil 则使用默认选项。
+  - handler: 针对每个匹配调用。参见 [SearchMatchHandler]。
+
+返回:
+  - 错误信息。
+*/
+func SearchContents(root string, filter *Filter, pattern string, option *SearchOption, handler SearchMatchHandler) error {
+	if option == nil { // 保证 option 不为 nil。
+		option = NewSearchOption()
+	}
+	if handler == nil {
+		return errors.New("handler must not be nil")
+	}
+
+	match, err := newLineMatcher(pattern, option.IsRegexp, option.IgnoreCase)
+	if err != nil {
+		return err
+	}
+
+	var walkErr error
+	if option.Parallelism <= 1 {
+		walkErr = walkFilteredFiles(root, filter, &option.WalkOption, func(relPath string, path string, info os.FileInfo) error {
+			matches, err := searchFile(path, match, option)
+			if err != nil {
+				return err
+			}
+			return deliverMatches(matches, handler)
+		})
+	} else {
+		walkErr = searchParallel(root, filter, match, option, handler)
+	}
+
+	if walkErr == filepath.SkipAll {
+		return nil
+	}
+	return walkErr
+}
+
+// searchRetryPolicy 用于并行搜索时重试 common.IsRetryable 的错误，例如打开的文件过多。
+var searchRetryPolicy = common.NewRetryPolicy()
+
+// searchParallel 由多个 goroutine 同时搜索文件，并串行调用 handler。
+func searchParallel(root string, filter *Filter, match func(string) bool, option *SearchOption, handler SearchMatchHandler) error {
+	paths := make(chan string)
+	done := make(chan struct{})
+	var once sync.Once
+	var firstErr error
+	var handlerLock sync.Mutex
+	var wg sync.WaitGroup
+
+	// 记录第一个错误，并通知遍历停止。
+	fail := func(err error) {
+		once.Do(func() {
+			firstErr = err
+			close(done)
+		})
+	}
+
+	for i := 0; i < option.Parallelism; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range paths {
+				// handler 引发的 panic 转换为错误，避免整个程序崩溃。
+				err := common.Recover(func() error {
+					// 并行打开大量文件时可能暂时超过打开文件数的限制，稍后重试。
+					var matches []*SearchMatch
+					err := common.Retry(nil, searchRetryPolicy, func() (err error) {
+						matches, err = searchFile(path, match, option)
+						return err
+					})
+					if err != nil {
+						return err
+					}
+
+					handlerLock.Lock()
+					defer handlerLock.Unlock()
+					select {
+					case <-done: // 已停止，不再调用 handler。
+						return nil
+					default:
+						return deliverMatches(matches, handler)
+					}
+				})
+				if err != nil {
+					fail(err)
+				}
+			}
+		}()
+	}
+
+	err := walkFilteredFiles(root, filter, &option.WalkOption, func(relPath string, path string, info os.FileInfo) error {
+		select {
+		case paths <- path:
+			return nil
+		case <-done:
+			return filepath.SkipAll
+		}
+	})
+
+	close(paths)
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return err
+}
+
+// deliverMatches 按顺序将匹配交给 handler，遇到错误时停止。
+func deliverMatches(matches []*SearchMatch, handler SearchMatchHandler) error {
+	for _, m := range matches {
+		if err := handler(m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newLineMatcher 根据选项创建判断一行是否匹配的函数。
+func newLineMatcher(pattern string, isRegexp bool, ignoreCase bool) (func(string) bool, error) {
+	if pattern == "" {
+		return nil, errors.New("pattern must not be empty")
+	}
+
+	if !isRegexp && !ignoreCase {
+		return func(line string) bool { return strings.Contains(line, pattern) }, nil
+	}
+
+	if !isRegexp {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	if ignoreCase {
+		pattern = "(?i)" + pattern
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return re.MatchString, nil
+}
+
+// searchFile 搜索一个文件，返回按行号排序的全部匹配。需要跳过的二进制文件返回 nil。
+func searchFile(path string, match func(string) bool, option *SearchOption) ([]*SearchMatch, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	// 与 LineReader 的缓冲区大小相同，使其直接使用该 bufio.Reader。
+	reader := bufio.NewReaderSize(file, 64*1024)
+	if !option.IncludeBinary {
+		// Peek 在文件长度不足时返回 io.EOF，此时返回的内容即为整个文件。
+		header, err := reader.Peek(DefaultSniffSize)
+		if err != nil && err != io.EOF {
+			return nil, err
+		} else if IsBinaryContent(header) {
+			return nil, nil
+		}
+	}
+
+	contextLines := option.ContextLines
+	if contextLines < 0 {
+		contextLines = 0
+	}
+
+	var matches []*SearchMatch
+	var pending []*SearchMatch // 还需要后续上下文行的匹配。
+	before := make([]string, 0, contextLines)
+
+	lines := NewLineReader(reader)
+	for lines.Next() {
+		line := lines.Line().Text
+
+		// 先为之前的匹配补充后续上下文行。
+		for _, m := range pending {
+			m.After = append(m.After, line)
+		}
+		for len(pending) > 0 && len(pending[0].After) >= contextLines {
+			pending = pending[1:]
+		}
+
+		if match(line) {
+			m := &SearchMatch{
+				Path:       path,
+				LineNumber: lines.Line().Number,
+				Line:       line,
+				Before:     append([]string{}, before...),
+				After:      []string{},
+			}
+			matches = append(matches, m)
+			if contextLines > 0 {
+				pending = append(pending, m)
+			}
+		}
+
+		if contextLines > 0 {
+			if len(before) == contextLines {
+				before = before[1:]
+			}
+			before = append(before, line)
+		}
+	}
+
+	if err = lines.Err(); err != nil {
+		return nil, err
+	}
+
+	return matches, nil
+}
@@ -0,0 +1,131 @@
+package fileutils
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/jqk/futool4go/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchContents(t *testing.T) {
+	root := t.TempDir()
+	assert.Nil(t, os.WriteFile(filepath.Join(root, "a.txt"), []byte("one\r\ntwo\r\nthree\r\nfour\r\nTWO"), 0644))
+	assert.Nil(t, os.WriteFile(filepath.Join(root, "b.md"), []byte("nothing\ntwo words\n"), 0644))
+	assert.Nil(t, os.WriteFile(filepath.Join(root, "c.bin"), []byte("two\x00\x01\x02"), 0644))
+
+	collect := func(filter *Filter, pattern string, option *SearchOption) ([]*SearchMatch, error) {
+		lock := sync.Mutex{}
+		matches := []*SearchMatch{}
+		err := SearchContents(root, filter, pattern, option, func(match *SearchMatch) error {
+			lock.Lock()
+			defer lock.Unlock()
+			matches = append(matches, match)
+			return nil
+		})
+		sort.SliceStable(matches, func(i, j int) bool { return matches[i].Path < matches[j].Path })
+		return matches, err
+	}
+
+	// 默认区分大小写，跳过二进制文件。
+	matches, err := collect(nil, "two", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(matches))
+	assert.Equal(t, filepath.Join(root, "a.txt"), matches[0].Path)
+	assert.Equal(t, 2, matches[0].LineNumber)
+	assert.Equal(t, "two", matches[0].Line)
+	assert.Equal(t, "two words", matches[1].Line)
+
+	// 忽略大小写，带上下文行，包含二进制文件。
+	option := NewSearchOption()
+	option.IgnoreCase = true
+	option.ContextLines = 1
+	option.IncludeBinary = true
+	matches, err = collect(&Filter{Include: []string{"*.txt", "*.bin"}}, "two", option)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(matches))
+	assert.Equal(t, []string{"one"}, matches[0].Before)
+	assert.Equal(t, []string{"three"}, matches[0].After)
+	assert.Equal(t, 5, matches[1].LineNumber)
+	assert.Equal(t, []string{"four"}, matches[1].Before)
+	assert.Equal(t, 0, len(matches[1].After))
+	assert.Equal(t, filepath.Join(root, "c.bin"), matches[2].Path)
+
+	// 正则表达式，并行搜索。
+	option = NewSearchOption()
+	option.IsRegexp = true
+	option.Parallelism = 4
+	matches, err = collect(nil, `^t\w+$`, option)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(matches))
+	assert.Equal(t, "two", matches[0].Line)
+	assert.Equal(t, "three", matches[1].Line)
+
+	_, err = collect(nil, `(`, option)
+	assert.NotNil(t, err)
+	_, err = collect(nil, "", nil)
+	assert.NotNil(t, err)
+
+	// handler 返回 SkipAll 时停止且不报错，返回其它错误时停止并返回该错误。
+	for _, parallelism := range []int{1, 4} {
+		option = NewSearchOption()
+		option.Parallelism = parallelism
+		count := 0
+		err = SearchContents(root, nil, "o", option, func(match *SearchMatch) error {
+			count++
+			return filepath.SkipAll
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, 1, count)
+
+		stop := errors.New("stop")
+		err = SearchContents(root, nil, "o", option, func(match *SearchMatch) error { return stop })
+		assert.Equal(t, stop, err)
+	}
+
+	// 并行搜索时 handler 引发的 panic 作为错误返回。
+	option = NewSearchOption()
+	option.Parallelism = 4
+	err = SearchContents(root, nil, "o", option, func(match *SearchMatch) error { panic("boom") })
+	panicErr := &common.PanicError{}
+	assert.True(t, errors.As(err, &panicErr))
+	assert.Equal(t, "boom", panicErr.Value)
+}
+
+func TestIsBinaryContent(t *testing.T) {
+	assert.False(t, IsBinaryContent([]byte("plain text\n")))
+	assert.False(t, IsBinaryContent([]byte(`{\rtf1\ansi hello}`)))
+	assert.True(t, IsBinaryContent([]byte("text\x00")))
+	assert.True(t, IsBinaryContent([]byte("%PDF-1.7\n")))
+
+	// 以二进制格式特征字母开始的文本。
+	for _, text := range []string{
+		"BMW service records\n",
+		"MZ-700 emulator notes\n",
+		"BZh is the bzip2 magic\n",
+		"ID3 tags explained\n",
+		"\xff\xfb\xff\xff latin-1 text\n",
+	} {
+		assert.False(t, IsBinaryContent([]byte(text)), text)
+	}
+
+	// 这样的文本文件也被搜索。
+	root := t.TempDir()
+	assert.Nil(t, os.WriteFile(filepath.Join(root, "bmw.txt"), []byte("BMW service records\n"), 0644))
+	assert.Nil(t, os.WriteFile(filepath.Join(root, "mz.txt"), []byte("MZ-700 service notes\n"), 0644))
+	count := 0
+	err := SearchContents(root, nil, "service", nil, func(match *SearchMatch) error {
+		count++
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, count)
+
+	binary, err := IsBinaryFile("../test-data/fileutils/filter/001.MD")
+	assert.Nil(t, err)
+	assert.False(t, binary)
+}
@@ -0,0 +1,379 @@
+package fileutils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultSniffSize is the default number of bytes read from the file header to detect the content type.
+//
+// DefaultSniffSize 是检测内容类型时默认读取的文件头字节数。
+const DefaultSniffSize = 4096
+
+/*
+ContentType describes the real type of a file identified by its magic bytes.
+
+ContentType 描述了通过文件头特征字节识别出的文件真实类型。
+*/
+type ContentType struct {
+	Name       string   // Short name, such as "JPEG", "ZIP" or "DOCX".
+	MIME       string   // MIME type, such as "image/jpeg".
+	Category   string   // One of "image", "archive", "document", "audio", "video", "executable" and "database".
+	Extensions []string // Usual extensions in lower case, including the dot. The first one is the preferred one.
+}
+
+/*
+HasExtension checks whether the given extension is one of the usual extensions of the content type. Case insensitive.
+
+HasExtension 检查给定的扩展名是否为该内容类型的常用扩展名之一。不区分大小写。
+*/
+func (c *ContentType) HasExtension(ext string) bool {
+	ext = strings.ToLower(ext)
+	for _, e := range c.Extensions {
+		if e == ext {
+			return true
+		}
+	}
+	return false
+}
+
+// contentTypeMatcher 定义了内容类型及其匹配函数。
+type contentTypeMatcher struct {
+	contentType ContentType
+	match       func(header []byte) bool
+}
+
+// prefixAt 返回检查 header 在 offset 处是否以 magic 开始的匹配函数。
+func prefixAt(offset int, magic string) func([]byte) bool {
+	return func(header []byte) bool {
+		return len(header) >= offset+len(magic) && string(header[offset:offset+len(magic)]) == magic
+	}
+}
+
+// zipContains 返回检查 header 是否为 zip 文件且包含给定内容的匹配函数。用于识别以 zip 为容器的 Office 文档等。
+func zipContains(content string) func([]byte) bool {
+	return func(header []byte) bool {
+		return prefixAt(0, "PK\x03\x04")(header) && bytes.Contains(header, []byte(content))
+	}
+}
+
+// ftypBrand 返回检查 ISO 媒体文件（MP4、MOV、HEIC 等）主品牌的匹配函数。
+func ftypBrand(brands ...string) func([]byte) bool {
+	return func(header []byte) bool {
+		if !prefixAt(4, "ftyp")(header) || len(header) < 12 {
+			return false
+		}
+		for _, brand := range brands {
+			if string(header[8:12]) == brand {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// 以下匹配函数检查完整的文件头，而不只是 2 至 3 字节的特征，避免将以“BM”、“MZ”等字母开始的文本当作二进制内容。
+
+// isBMP 检查 BMP 文件头及其后 DIB 头的大小。
+func isBMP(header []byte) bool {
+	if !prefixAt(0, "BM")(header) || len(header) < 18 {
+		return false
+	}
+
+	switch binary.LittleEndian.Uint32(header[14:18]) {
+	case 12, 40, 52, 56, 64, 108, 124:
+		return true
+	}
+	return false
+}
+
+// isPE 检查“MZ”头，及其 0x3c 处的偏移量指向的“PE\0\0”。
+func isPE(header []byte) bool {
+	if !prefixAt(0, "MZ")(header) || len(header) < 0x40 {
+		return false
+	}
+
+	offset := binary.LittleEndian.Uint32(header[0x3c:0x40])
+	return offset >= 0x40 && offset <= uint32(len(header)-4) && prefixAt(int(offset), "PE\x00\x00")(header)
+}
+
+// isBZIP2 检查“BZh”、1 至 9 的块大小，及其后第一个块或流结束的特征。
+func isBZIP2(header []byte) bool {
+	if !prefixAt(0, "BZh")(header) || len(header) < 10 || header[3] < '1' || header[3] > '9' {
+		return false
+	}
+	return prefixAt(4, "\x31\x41\x59\x26\x53\x59")(header) || prefixAt(4, "\x17\x72\x45\x38\x50\x90")(header)
+}
+
+// isMP3 检查 ID3v2 标签头，或者 MPEG 音频帧头。
+func isMP3(header []byte) bool {
+	if prefixAt(0, "ID3")(header) {
+		// 主版本为 2 至 4，修订版本不为 0xff，标签大小的 4 个字节都小于 0x80。
+		if len(header) < 10 || header[3] < 2 || header[3] > 4 || header[4] == 0xff {
+			return false
+		}
+		for _, b := range header[6:10] {
+			if b >= 0x80 {
+				return false
+			}
+		}
+		return true
+	}
+
+	if !prefixAt(0, "\xff\xfb")(header) && !prefixAt(0, "\xff\xf3")(header) && !prefixAt(0, "\xff\xf2")(header) {
+		return false
+	}
+	// 比特率序号不能为 15，采样率序号不能为 3。
+	return len(header) >= 4 && header[2]>>4 != 0x0f && (header[2]>>2)&0x03 != 0x03
+}
+
+// contentTypeMatchers 是内置的内容类型列表。按顺序匹配，所以更具体的类型要排在前面，例如 DOCX 在 ZIP 之前。
+var contentTypeMatchers = []contentTypeMatcher{
+	// 图片。
+	{ContentType{"JPEG", "image/jpeg", "image", []string{".jpg", ".jpeg", ".jpe", ".jfif"}}, prefixAt(0, "\xff\xd8\xff")},
+	{ContentType{"PNG", "image/png", "image", []string{".png"}}, prefixAt(0, "\x89PNG\r\n\x1a\n")},
+	{ContentType{"GIF", "image/gif", "image", []string{".gif"}}, func(h []byte) bool {
+		return prefixAt(0, "GIF87a")(h) || prefixAt(0, "GIF89a")(h)
+	}},
+	{ContentType{"WEBP", "image/webp", "image", []string{".webp"}}, func(h []byte) bool {
+		return prefixAt(0, "RIFF")(h) && prefixAt(8, "WEBP")(h)
+	}},
+	{ContentType{"TIFF", "image/tiff", "image", []string{".tif", ".tiff"}}, func(h []byte) bool {
+		return prefixAt(0, "II*\x00")(h) || prefixAt(0, "MM\x00*")(h)
+	}},
+	{ContentType{"HEIC", "image/heic", "image", []string{".heic", ".heif"}}, ftypBrand("heic", "heix", "mif1", "msf1")},
+	{ContentType{"BMP", "image/bmp", "image", []string{".bmp"}}, isBMP},
+	{ContentType{"ICO", "image/x-icon", "image", []string{".ico"}}, prefixAt(0, "\x00\x00\x01\x00")},
+
+	// 以 zip 为容器的文档，必须在 ZIP 之前。
+	{ContentType{"DOCX", "application/vnd.openxmlformats-officedocument.wordprocessingml.document", "document", []string{".docx", ".docm"}}, zipContains("word/")},
+	{ContentType{"XLSX", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", "document", []string{".xlsx", ".xlsm"}}, zipContains("xl/")},
+	{ContentType{"PPTX", "application/vnd.openxmlformats-officedocument.presentationml.presentation", "document", []string{".pptx", ".pptm"}}, zipContains("ppt/")},
+	{ContentType{"ODT", "application/vnd.oasis.opendocument.text", "document", []string{".odt"}}, zipContains("application/vnd.oasis.opendocument.text")},
+	{ContentType{"ODS", "application/vnd.oasis.opendocument.spreadsheet", "document", []string{".ods"}}, zipContains("application/vnd.oasis.opendocument.spreadsheet")},
+	{ContentType{"EPUB", "application/epub+zip", "document", []string{".epub"}}, zipContains("application/epub+zip")},
+	{ContentType{"JAR", "application/java-archive", "archive", []string{".jar", ".war", ".ear"}}, zipContains("META-INF/")},
+
+	// 文档。
+	{ContentType{"PDF", "application/pdf", "document", []string{".pdf"}}, prefixAt(0, "%PDF-")},
+	{ContentType{"OLE2", "application/x-ole-storage", "document", []string{".doc", ".xls", ".ppt", ".msi", ".msg"}}, prefixAt(0, "\xd0\xcf\x11\xe0\xa1\xb1\x1a\xe1")},
+	{ContentType{"RTF", "application/rtf", "document", []string{".rtf"}}, prefixAt(0, "{\\rtf")},
+
+	// 压缩包。
+	{ContentType{"ZIP", "application/zip", "archive", []string{".zip"}}, func(h []byte) bool {
+		return prefixAt(0, "PK\x03\x04")(h) || prefixAt(0, "PK\x05\x06")(h)
+	}},
+	{ContentType{"GZIP", "application/gzip", "archive", []string{".gz", ".tgz"}}, prefixAt(0, "\x1f\x8b")},
+	{ContentType{"BZIP2", "application/x-bzip2", "archive", []string{".bz2", ".tbz2"}}, isBZIP2},
+	{ContentType{"XZ", "application/x-xz", "archive", []string{".xz", ".txz"}}, prefixAt(0, "\xfd7zXZ\x00")},
+	{ContentType{"ZSTD", "application/zstd", "archive", []string{".zst"}}, prefixAt(0, "\x28\xb5\x2f\xfd")},
+	{ContentType{"7Z", "application/x-7z-compressed", "archive", []string{".7z"}}, prefixAt(0, "7z\xbc\xaf\x27\x1c")},
+	{ContentType{"RAR", "application/vnd.rar", "archive", []string{".rar"}}, prefixAt(0, "Rar!\x1a\x07")},
+	{ContentType{"TAR", "application/x-tar", "archive", []string{".tar"}}, prefixAt(257, "ustar")},
+
+	// 音频。
+	{ContentType{"MP3", "audio/mpeg", "audio", []string{".mp3"}}, isMP3},
+	{ContentType{"FLAC", "audio/flac", "audio", []string{".flac"}}, prefixAt(0, "fLaC")},
+	{ContentType{"OGG", "audio/ogg", "audio", []string{".ogg", ".oga", ".opus"}}, prefixAt(0, "OggS")},
+	{ContentType{"WAV", "audio/wav", "audio", []string{".wav"}}, func(h []byte) bool {
+		return prefixAt(0, "RIFF")(h) && prefixAt(8, "WAVE")(h)
+	}},
+	{ContentType{"M4A", "audio/mp4", "audio", []string{".m4a"}}, ftypBrand("M4A ", "M4B ")},
+
+	// 视频。
+	{ContentType{"MOV", "video/quicktime", "video", []string{".mov"}}, ftypBrand("qt  ")},
+	{ContentType{"3GP", "video/3gpp", "video", []string{".3gp"}}, ftypBrand("3gp4", "3gp5", "3gp6")},
+	{ContentType{"MP4", "video/mp4", "video", []string{".mp4", ".m4v"}}, prefixAt(4, "ftyp")},
+	{ContentType{"AVI", "video/x-msvideo", "video", []string{".avi"}}, func(h []byte) bool {
+		return prefixAt(0, "RIFF")(h) && prefixAt(8, "AVI ")(h)
+	}},
+	{ContentType{"MKV", "video/x-matroska", "video", []string{".mkv", ".webm"}}, prefixAt(0, "\x1a\x45\xdf\xa3")},
+	{ContentType{"FLV", "video/x-flv", "video", []string{".flv"}}, prefixAt(0, "FLV\x01")},
+
+	// 可执行文件及数据库。
+	{ContentType{"ELF", "application/x-elf", "executable", []string{"", ".so", ".o"}}, prefixAt(0, "\x7fELF")},
+	{ContentType{"PE", "application/vnd.microsoft.portable-executable", "executable", []string{".exe", ".dll", ".sys"}}, isPE},
+	{ContentType{"SQLITE", "application/vnd.sqlite3", "database", []string{".db", ".sqlite", ".sqlite3"}}, prefixAt(0, "SQLite format 3\x00")},
+}
+
+/*
+DetectContentType identifies the content type by the magic bytes at the beginning of the data.
+
+Parameters:
+  - header: the beginning of the file content. [DefaultSniffSize] bytes are suggested.
+
+Returns:
+  - the content type. nil if it is unknown.
+
+DetectContentType 通过数据开始部分的特征字节识别内容类型。
+
+参数:
+  - header: 文件开始部分的内容。建议使用 [DefaultSniffSize] 字节。
+
+返回:
+  - 内容类型。无法识别时为 nil。
+*/
+func DetectContentType(header []byte) *ContentType {
+	for i := range contentTypeMatchers {
+		if contentTypeMatchers[i].match(header) {
+			// 返回副本，防止调用者修改内置列表。
+			result := contentTypeMatchers[i].contentType
+			return &result
+		}
+	}
+	return nil
+}
+
+/*
+DetectFileContentType reads the first sniffSize bytes of the file and identifies its content type.
+
+Parameters:
+  - path: the file path.
+  - sniffSize: the number of bytes to read. [DefaultSniffSize] is used if it is less than or equal to 0.
+
+Returns:
+  - the content type. nil if it is unknown.
+  - an error if any occurred.
+
+DetectFileContentType 读取文件开始的 sniffSize 字节，并识别其内容类型。
+
+参数:
+  - path: 文件路径。
+  - sniffSize: 读取的字节数。小于等于 0 时使用 [DefaultSniffSize]。
+
+返回:
+  - 内容类型。无法识别时为 nil。
+  - 错误信息。
+*/
+func DetectFileContentType(path string, sniffSize int) (*ContentType, error) {
+	if sniffSize <= 0 {
+		sniffSize = DefaultSniffSize
+	}
+
+	header, err := readFileHeader(path, sniffSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return DetectContentType(header), nil
+}
+
+// readFileHeader 读取文件开始的 size 字节。文件长度不足时返回全部内容。
+func readFileHeader(path string, size int) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	header := make([]byte, size)
+	count, err := io.ReadFull(file, header)
+	// 文件长度小于 size 时返回 io.EOF 或 io.ErrUnexpectedEOF，不是错误。
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+
+	return header[:count], nil
+}
+
+/*
+IsBinaryContent checks whether the data looks like binary content rather than text.
+It is binary if it contains a NUL byte, or its content type is identified by [DetectContentType] (except RTF).
+Formats with short magic bytes, such as BMP, PE, BZIP2 and MP3, need their full headers,
+so text starting with letters like "BM" or "MZ" is still text.
+Note that UTF-16 text is treated as binary because it contains NUL bytes.
+
+IsBinaryContent 检查数据是否像是二进制内容而不是文本。
+包含 NUL 字节，或者可由 [DetectContentType] 识别出内容类型（RTF 除外）时，为二进制内容。
+BMP、PE、BZIP2 及 MP3 这些特征字节较短的格式需要完整的文件头，所以以“BM”或“MZ”等字母开始的文本仍然是文本。
+注意 UTF-16 文本由于包含 NUL 字节，也被视为二进制内容。
+*/
+func IsBinaryContent(header []byte) bool {
+	if bytes.IndexByte(header, 0) >= 0 {
+		return true
+	}
+
+	// RTF 是唯一一个可识别的文本格式。
+	contentType := DetectContentType(header)
+	return contentType != nil && contentType.Name != "RTF"
+}
+
+/*
+IsBinaryFile reads the first [DefaultSniffSize] bytes of the file and checks whether it is binary. See [IsBinaryContent].
+
+IsBinaryFile 读取文件开始的 [DefaultSniffSize] 字节，检查其是否为二进制文件。参见 [IsBinaryContent]。
+*/
+func IsBinaryFile(path string) (bool, error) {
+	header, err := readFileHeader(path, DefaultSniffSize)
+	if err != nil {
+		return false, err
+	}
+	return IsBinaryContent(header), nil
+}
+
+/*
+ContentMismatch describes a file whose extension disagrees with its content.
+
+ContentMismatch 描述了扩展名与内容不符的文件。
+*/
+type ContentMismatch struct {
+	Path        string       // The file path.
+	Extension   string       // The extension of the file, including the dot.
+	ContentType *ContentType // The real content type.
+}
+
+/*
+FindContentMismatches scans the directory and reports files whose extension disagrees with their content.
+Files of unknown content type are ignored.
+
+Parameters:
+  - root: the directory to scan.
+  - filter: the filter condition. nil means all files.
+  - option: the scan options. if nil, the default options will be used.
+
+Returns:
+  - the mismatched files in walking order.
+  - an error if any occurred.
+
+FindContentMismatches 扫描目录，报告扩展名与内容不符的文件。忽略无法识别内容类型的文件。
+
+参数:
+  - root: 要扫描的目录。
+  - filter: 过滤条件。nil 表示所有文件。
+  - option: 扫描选项。如果为 nil 则使用默认选项。
+
+返回:
+  - 按遍历顺序排列的不符的文件。
+  - 错误信息。
+*/
+func FindContentMismatches(root string, filter *Filter, option *WalkOption) ([]ContentMismatch, error) {
+	result := make([]ContentMismatch, 0)
+
+	err := walkFilteredFiles(root, filter, option, func(relPath string, path string, info os.FileInfo) error {
+		contentType, err := DetectFileContentType(path, DefaultSniffSize)
+		if err != nil {
+			return err
+		}
+
+		ext := filepath.Ext(path)
+		if contentType != nil && !contentType.HasExtension(ext) {
+			result = append(result, ContentMismatch{
+				Path:        path,
+				Extension:   ext,
+				ContentType: contentType,
+			})
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,79 @@
+package fileutils
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectContentType(t *testing.T) {
+	assert.Equal(t, "PNG", DetectContentType([]byte("\x89PNG\r\n\x1a\n0000")).Name)
+	assert.Equal(t, "JPEG", DetectContentType([]byte("\xff\xd8\xff\xe0")).Name)
+	assert.Equal(t, "PDF", DetectContentType([]byte("%PDF-1.7")).Name)
+	assert.Equal(t, "WAV", DetectContentType([]byte("RIFF0000WAVEfmt ")).Name)
+	assert.Equal(t, "MOV", DetectContentType([]byte("\x00\x00\x00\x14ftypqt  ")).Name)
+	assert.Equal(t, "MP4", DetectContentType([]byte("\x00\x00\x00\x18ftypisom")).Name)
+
+	// 数据不足或无法识别。
+	assert.Nil(t, DetectContentType([]byte{}))
+	assert.Nil(t, DetectContentType([]byte("\x89PN")))
+	assert.Nil(t, DetectContentType([]byte("hello world")))
+
+	// Office 文档是 zip 文件，按内容区分。
+	assert.Equal(t, "DOCX", DetectContentType(zipBytes(t, "word/document.xml")).Name)
+	assert.Equal(t, "ZIP", DetectContentType(zipBytes(t, "readme.txt")).Name)
+
+	// 只有 2 至 3 字节特征的类型需要完整的文件头。
+	bmp := []byte("BM\x36\x00\x00\x00\x00\x00\x00\x00\x36\x00\x00\x00\x28\x00\x00\x00")
+	assert.Equal(t, "BMP", DetectContentType(bmp).Name)
+	pe := make([]byte, 0x84)
+	copy(pe, "MZ")
+	pe[0x3c] = 0x80
+	copy(pe[0x80:], "PE\x00\x00")
+	assert.Equal(t, "PE", DetectContentType(pe).Name)
+	assert.Equal(t, "BZIP2", DetectContentType([]byte("BZh91AY&SY\x00\x00")).Name)
+	assert.Equal(t, "MP3", DetectContentType([]byte("ID3\x04\x00\x00\x00\x00\x01\x00")).Name)
+	assert.Equal(t, "MP3", DetectContentType([]byte("\xff\xfb\x90\x64")).Name)
+	pe[0x3c] = 0xf0 // 偏移量超出数据。
+	assert.Nil(t, DetectContentType(pe))
+
+	contentType := DetectContentType([]byte("GIF89a"))
+	assert.True(t, contentType.HasExtension(".GIF"))
+	assert.False(t, contentType.HasExtension(".png"))
+}
+
+func TestFindContentMismatches(t *testing.T) {
+	root := t.TempDir()
+	assert.Nil(t, os.WriteFile(filepath.Join(root, "ok.png"), []byte("\x89PNG\r\n\x1a\n"), 0644))
+	assert.Nil(t, os.WriteFile(filepath.Join(root, "wrong.jpg"), []byte("\x89PNG\r\n\x1a\n"), 0644))
+	assert.Nil(t, os.WriteFile(filepath.Join(root, "report.zip"), zipBytes(t, "xl/workbook.xml"), 0644))
+	assert.Nil(t, os.WriteFile(filepath.Join(root, "unknown.txt"), []byte("plain text"), 0644))
+
+	contentType, err := DetectFileContentType(filepath.Join(root, "ok.png"), 0)
+	assert.Nil(t, err)
+	assert.Equal(t, "image/png", contentType.MIME)
+
+	_, err = DetectFileContentType(filepath.Join(root, "not-exist"), 0)
+	assert.NotNil(t, err)
+
+	mismatches, err := FindContentMismatches(root, nil, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(mismatches))
+	assert.Equal(t, filepath.Join(root, "report.zip"), mismatches[0].Path)
+	assert.Equal(t, "XLSX", mismatches[0].ContentType.Name)
+	assert.Equal(t, ".jpg", mismatches[1].Extension)
+	assert.Equal(t, "PNG", mismatches[1].ContentType.Name)
+}
+
+func zipBytes(t *testing.T, name string) []byte {
+	buf := &bytes.Buffer{}
+	writer := zip.NewWriter(buf)
+	_, err := writer.Create(name)
+	assert.Nil(t, err)
+	assert.Nil(t, writer.Close())
+	return buf.Bytes()
+}
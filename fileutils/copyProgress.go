@@ -0,0 +1,277 @@
+package fileutils
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"time"
+
+	"github.com/jqk/futool4go/common"
+)
+
+/*
+CopyProgress is the progress reported by [CopyWithProgress].
+
+CopyProgress 是 [CopyWithProgress] 报告的进度。
+*/
+type CopyProgress struct {
+	Copied        int64         // The total number of bytes copied so far.
+	Total         int64         // The total number of bytes to copy, or -1 if unknown.
+	IntervalBytes int64         // The number of bytes copied since the last report.
+	Interval      time.Duration // The time elapsed since the last report.
+	Done          bool          // True for the final report when the copy completes successfully.
+}
+
+/*
+Rate returns the copy speed of the last interval in bytes per second.
+
+Rate 返回最近一个间隔的复制速度，单位为字节每秒。
+*/
+func (p *CopyProgress) Rate() float64 {
+	if p.Interval <= 0 {
+		return 0
+	}
+	return float64(p.IntervalBytes) / p.Interval.Seconds()
+}
+
+/*
+CopyProgressFunc is called by [CopyWithProgress] to report progress.
+
+CopyProgressFunc 由 [CopyWithProgress] 调用，报告进度。
+*/
+type CopyProgressFunc func(progress *CopyProgress)
+
+/*
+CopyWithProgress copies from src to dst like io.Copy, reporting progress and respecting the context.
+
+Parameters:
+  - ctx: the context. The copy stops with ctx.Err() when it is done. nil means context.Background().
+  - dst: the writer.
+  - src: the reader.
+  - total: the number of bytes to copy, used in the progress only. -1 if unknown.
+  - buffer: the buffer for copying. nil means allocate one.
+  - interval: the minimum time between two progress reports. 0 means report after each buffer.
+    The final report is always made.
+  - progress: the progress callback. nil means no report.
+
+Returns:
+  - the number of bytes copied.
+  - an error if any occurred.
+
+CopyWithProgress 与 io.Copy 类似，从 src 复制到 dst，同时报告进度并响应 context。
+
+参数:
+  - ctx: 上下文。其结束时复制停止并返回 ctx.Err()。nil 表示 context.Background()。
+  - dst: 写入器。
+  - src: 读取器。
+  - total: 要复制的字节数，只用于进度报告。未知时为 -1。
+  - buffer: 复制使用的缓冲区。nil 表示自动分配。
+  - interval: 两次进度报告之间的最短时间。0 表示每个缓冲区都报告。总会进行最后一次报告。
+  - progress: 进度回调函数。nil 表示不报告。
+
+返回:
+  - 复制的字节数。
+  - 错误信息。
+*/
+func CopyWithProgress(
+	ctx context.Context,
+	dst io.Writer,
+	src io.Reader,
+	total int64,
+	buffer []byte,
+	interval time.Duration,
+	progress CopyProgressFunc,
+) (int64, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if len(buffer) == 0 {
+		buffer = common.DefaultBufferManager.Get(64 * 1024)
+		defer common.DefaultBufferManager.Put(buffer)
+	}
+
+	state := &CopyProgress{Total: total}
+	lastReport := time.Now()
+
+	report := func(done bool) {
+		now := time.Now()
+		state.Interval = now.Sub(lastReport)
+		state.Done = done
+		progress(state)
+
+		state.IntervalBytes = 0
+		lastReport = now
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return state.Copied, err
+		}
+
+		readCount, err := src.Read(buffer)
+		if readCount > 0 {
+			written, writeErr := dst.Write(buffer[:readCount])
+			state.Copied += int64(written)
+			state.IntervalBytes += int64(written)
+
+			if writeErr == nil && written < readCount {
+				writeErr = io.ErrShortWrite
+			}
+			if writeErr != nil {
+				return state.Copied, writeErr
+			}
+
+			if progress != nil && time.Since(lastReport) >= interval {
+				report(false)
+			}
+		}
+
+		if err == io.EOF {
+			if progress != nil {
+				report(true)
+			}
+			return state.Copied, nil
+		} else if err != nil {
+			return state.Copied, err
+		}
+	}
+}
+
+/*
+CopyOption defines the options for [CopyFile].
+See [NewCopyOption] for default settings.
+
+CopyOption 定义了 [CopyFile] 的选项。默认设置参见 [NewCopyOption]。
+*/
+type CopyOption struct {
+	Context  context.Context  // the context to cancel the copy. nil means context.Background()
+	Buffer   []byte           // the buffer for copying, can be reused between calls. nil means allocate one
+	Interval time.Duration    // the minimum time between two progress reports. See [CopyWithProgress]
+	Progress CopyProgressFunc // the progress callback. nil means no report
+	/*
+		If not nil, the source is hashed while copying, then the written file is read back and hashed again.
+		[ErrChecksumMismatch] is returned and the destination is not written if they differ.
+	*/
+	Verify hash.Hash
+	/*
+		If true, holes of the source are detected by SEEK_DATA/SEEK_HOLE where available and not read,
+		and blocks of zeros are not written, so the destination is sparse as well.
+	*/
+	Sparse bool
+}
+
+/*
+NewCopyOption creates a new CopyOption with no context, allocated buffer,
+1 second progress interval, no progress report, no verification and sparse copy.
+
+NewCopyOption 创建默认的 CopyOption。包含无上下文、自动分配缓冲区、1 秒的进度报告间隔、不报告进度、不校验，以及保留稀疏文件的空洞。
+*/
+func NewCopyOption() *CopyOption {
+	return &CopyOption{
+		Context:  nil,
+		Buffer:   nil,
+		Interval: time.Second,
+		Progress: nil,
+		Verify:   nil,
+		Sparse:   true,
+	}
+}
+
+/*
+CopyFile copies the file src to dst. dst is written atomically and gets the permission of src.
+
+Parameters:
+  - src: the source file.
+  - dst: the destination file. It is replaced if exists.
+  - option: the options. if nil, the default options will be used.
+
+Returns:
+  - the number of bytes copied.
+  - an error if any occurred.
+
+CopyFile 将文件 src 复制为 dst。dst 以原子方式写入，并使用 src 的权限。
+
+参数:
+  - src: 源文件。
+  - dst: 目标文件。已存在时被替换。
+  - option: 选项。如果为 nil 则使用默认选项。
+
+返回:
+  - 复制的字节数。
+  - 错误信息。
+*/
+func CopyFile(src string, dst string, option *CopyOption) (int64, error) {
+	if option == nil { // 保证 option 不为 nil。
+		option = NewCopyOption()
+	}
+
+	from, err := os.Open(src)
+	if err != nil {
+		return 0, err
+	}
+	defer from.Close()
+
+	info, err := from.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	to, err := createAtomicFile(dst)
+	if err != nil {
+		return 0, err
+	}
+	to.mode = info.Mode().Perm()
+
+	var reader io.Reader = from
+	var writer io.Writer = to
+	var sparse *sparseWriter
+	if option.Sparse {
+		reader = newHoleReader(from, info.Size())
+		sparse = newSparseWriter(to.File)
+		writer = sparse
+	}
+	if option.Verify != nil {
+		option.Verify.Reset()
+		reader = TeeWithChecksum(reader, option.Verify)
+	}
+
+	copied, err := CopyWithProgress(option.Context, writer, reader, info.Size(), option.Buffer, option.Interval, option.Progress)
+	if err == nil && sparse != nil {
+		err = sparse.finish()
+	}
+	if err == nil && option.Verify != nil {
+		err = verifyCopiedFile(to.File, option.Verify, option.Buffer)
+	}
+
+	if err != nil {
+		to.Abort()
+		return copied, err
+	}
+
+	return copied, to.Commit()
+}
+
+// verifyCopiedFile 从头读取已写入的文件，与 checksum 中源文件的校验值比较。
+func verifyCopiedFile(file *os.File, checksum hash.Hash, buffer []byte) error {
+	expected := checksum.Sum(nil)
+	checksum.Reset()
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if len(buffer) == 0 {
+		buffer = common.DefaultBufferManager.Get(64 * 1024)
+		defer common.DefaultBufferManager.Put(buffer)
+	}
+	if _, err := io.CopyBuffer(checksum, file, buffer); err != nil {
+		return err
+	}
+
+	if !bytes.Equal(expected, checksum.Sum(nil)) {
+		return fmt.Errorf("%s: %w", file.Name(), ErrChecksumMismatch)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package fileutils
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCopyWithProgress(t *testing.T) {
+	data := strings.Repeat("0123456789", 1000)
+	dst := &bytes.Buffer{}
+	reports := []CopyProgress{}
+
+	copied, err := CopyWithProgress(nil, dst, strings.NewReader(data), int64(len(data)), make([]byte, 1024), 0, func(p *CopyProgress) {
+		reports = append(reports, *p)
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(len(data)), copied)
+	assert.Equal(t, data, dst.String())
+
+	// 每个缓冲区报告一次，另有最后一次报告。
+	assert.Equal(t, 11, len(reports))
+	assert.Equal(t, int64(1024), reports[0].IntervalBytes)
+	assert.Equal(t, int64(1024), reports[0].Copied)
+	assert.Equal(t, int64(len(data)), reports[0].Total)
+	assert.False(t, reports[9].Done)
+	assert.True(t, reports[10].Done)
+	assert.Equal(t, int64(len(data)), reports[10].Copied)
+	assert.True(t, reports[0].Rate() >= 0)
+
+	// 间隔很长时只有最后一次报告。
+	reports = reports[:0]
+	_, err = CopyWithProgress(nil, &bytes.Buffer{}, strings.NewReader(data), -1, make([]byte, 1024), 1<<62, func(p *CopyProgress) {
+		reports = append(reports, *p)
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(reports))
+	assert.Equal(t, int64(len(data)), reports[0].IntervalBytes)
+
+	// 取消。
+	ctx, cancel := context.WithCancel(context.Background())
+	copied, err = CopyWithProgress(ctx, &bytes.Buffer{}, strings.NewReader(data), -1, make([]byte, 1024), 0, func(p *CopyProgress) {
+		cancel()
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, int64(1024), copied)
+}
+
+func TestCopyFile(t *testing.T) {
+	root := t.TempDir()
+	src := filepath.Join(root, "src.txt")
+	dst := filepath.Join(root, "dst.txt")
+	assert.Nil(t, os.WriteFile(src, []byte("hello"), 0600))
+	assert.Nil(t, os.WriteFile(dst, []byte("old content"), 0644))
+
+	done := false
+	option := NewCopyOption()
+	option.Progress = func(p *CopyProgress) { done = p.Done }
+
+	copied, err := CopyFile(src, dst, option)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(5), copied)
+	assert.True(t, done)
+
+	data, _ := os.ReadFile(dst)
+	assert.Equal(t, "hello", string(data))
+	info, _ := os.Stat(dst)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	// 取消时不修改目标文件，也不留下临时文件。
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	option.Context = ctx
+	assert.Nil(t, os.WriteFile(src, []byte("new"), 0600))
+	_, err = CopyFile(src, dst, option)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	data, _ = os.ReadFile(dst)
+	assert.Equal(t, "hello", string(data))
+	entries, _ := os.ReadDir(root)
+	assert.Equal(t, 2, len(entries))
+
+	_, err = CopyFile(filepath.Join(root, "not-exist"), dst, nil)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,151 @@
+package fileutils
+
+import (
+	"io"
+	"sync/atomic"
+	"time"
+
+	"github.com/jqk/futool4go/timeutils"
+)
+
+/*
+byteCounter 统计字节数，并可选地记录首字节时间及吞吐量。计数可在其它 goroutine 中读取。
+*/
+type byteCounter struct {
+	count     atomic.Int64
+	firstByte atomic.Int64 // 首字节时间，单位为纳秒。0 表示尚未收到数据。
+	stopwatch *timeutils.Stopwatch
+}
+
+func (c *byteCounter) init(timed bool) {
+	if timed {
+		c.stopwatch = &timeutils.Stopwatch{}
+		c.stopwatch.Start()
+	}
+}
+
+func (c *byteCounter) add(n int) {
+	if n <= 0 {
+		return
+	}
+
+	if c.count.Add(int64(n)) == int64(n) && c.stopwatch != nil {
+		// 至少为 1 纳秒，以便与尚未收到数据区分。
+		c.firstByte.Store(int64(c.stopwatch.ElapsedTime()) | 1)
+	}
+}
+
+/*
+Count returns the number of bytes counted.
+
+Count 返回统计的字节数。
+*/
+func (c *byteCounter) Count() int64 {
+	return c.count.Load()
+}
+
+/*
+TimeToFirstByte returns the time from creation to the first byte. 0 if timing is disabled or no byte yet.
+
+TimeToFirstByte 返回从创建到收到第一个字节的时间。未启用计时或尚未收到数据时返回 0。
+*/
+func (c *byteCounter) TimeToFirstByte() time.Duration {
+	return time.Duration(c.firstByte.Load())
+}
+
+/*
+Elapsed returns the time since creation, until Stop is called. 0 if timing is disabled.
+
+Elapsed 返回从创建开始，到调用 Stop 为止的时间。未启用计时时返回 0。
+*/
+func (c *byteCounter) Elapsed() time.Duration {
+	if c.stopwatch == nil {
+		return 0
+	}
+	return c.stopwatch.ElapsedTime()
+}
+
+/*
+Throughput returns the average bytes per second over Elapsed. 0 if timing is disabled.
+
+Throughput 返回 Elapsed 期间平均每秒的字节数。未启用计时时返回 0。
+*/
+func (c *byteCounter) Throughput() float64 {
+	elapsed := c.Elapsed()
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(c.Count()) / elapsed.Seconds()
+}
+
+/*
+Stop stops timing, so that Elapsed and Throughput no longer change.
+
+Stop 停止计时，此后 Elapsed 及 Throughput 不再变化。
+*/
+func (c *byteCounter) Stop() {
+	if c.stopwatch != nil {
+		c.stopwatch.Stop()
+	}
+}
+
+/*
+CountingReader counts the bytes read through it.
+If timed, it also measures time-to-first-byte and throughput by a [timeutils.Stopwatch],
+which stops automatically when the underlying reader returns an error or io.EOF.
+
+CountingReader 统计通过它读取的字节数。启用计时时，还使用 [timeutils.Stopwatch] 测量首字节时间及吞吐量，
+下层读取器返回错误或 io.EOF 时自动停止计时。
+*/
+type CountingReader struct {
+	byteCounter
+	reader io.Reader
+}
+
+/*
+NewCountingReader creates a CountingReader. If timed is true, timing starts now.
+
+NewCountingReader 创建 CountingReader。timed 为 true 时，从现在开始计时。
+*/
+func NewCountingReader(reader io.Reader, timed bool) *CountingReader {
+	r := &CountingReader{reader: reader}
+	r.init(timed)
+	return r
+}
+
+func (r *CountingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.add(n)
+	if err != nil {
+		r.Stop()
+	}
+	return n, err
+}
+
+/*
+CountingWriter counts the bytes written through it.
+If timed, it also measures time-to-first-byte and throughput by a [timeutils.Stopwatch]. Call Stop when done.
+
+CountingWriter 统计通过它写入的字节数。启用计时时，还使用 [timeutils.Stopwatch] 测量首字节时间及吞吐量。完成后调用 Stop。
+*/
+type CountingWriter struct {
+	byteCounter
+	writer io.Writer
+}
+
+/*
+NewCountingWriter creates a CountingWriter. If timed is true, timing starts now.
+
+NewCountingWriter 创建 CountingWriter。timed 为 true 时，从现在开始计时。
+*/
+func NewCountingWriter(writer io.Writer, timed bool) *CountingWriter {
+	w := &CountingWriter{writer: writer}
+	w.init(timed)
+	return w
+}
+
+func (w *CountingWriter) Write(p []byte) (int, error) {
+	n, err := w.writer.Write(p)
+	w.add(n)
+	return n, err
+}
@@ -0,0 +1,52 @@
+package fileutils
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCountingReader(t *testing.T) {
+	r := NewCountingReader(strings.NewReader("hello world"), false)
+	data, err := io.ReadAll(r)
+	assert.Nil(t, err)
+	assert.Equal(t, "hello world", string(data))
+	assert.Equal(t, int64(11), r.Count())
+	assert.Equal(t, time.Duration(0), r.TimeToFirstByte())
+	assert.Equal(t, time.Duration(0), r.Elapsed())
+	assert.Equal(t, float64(0), r.Throughput())
+
+	// 计时，读到结尾后自动停止。
+	r = NewCountingReader(strings.NewReader("hello world"), true)
+	assert.Equal(t, time.Duration(0), r.TimeToFirstByte())
+	_, err = io.ReadAll(r)
+	assert.Nil(t, err)
+	assert.True(t, r.TimeToFirstByte() > 0)
+	assert.True(t, r.Elapsed() >= r.TimeToFirstByte())
+	assert.True(t, r.Throughput() > 0)
+
+	elapsed := r.Elapsed()
+	time.Sleep(time.Millisecond)
+	assert.Equal(t, elapsed, r.Elapsed())
+}
+
+func TestCountingWriter(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	w := NewCountingWriter(buffer, true)
+	time.Sleep(time.Millisecond)
+
+	_, err := w.Write([]byte("hello "))
+	assert.Nil(t, err)
+	_, err = io.WriteString(w, "world")
+	assert.Nil(t, err)
+	w.Stop()
+
+	assert.Equal(t, "hello world", buffer.String())
+	assert.Equal(t, int64(11), w.Count())
+	assert.True(t, w.TimeToFirstByte() >= time.Millisecond)
+	assert.True(t, w.Throughput() > 0)
+}
@@ -0,0 +1,102 @@
+package fileutils
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+/*
+ExtensionRenameOption defines the options for [NormalizeExtensions].
+See [NewExtensionRenameOption] for default settings.
+
+ExtensionRenameOption 定义了 [NormalizeExtensions] 的选项。默认设置参见 [NewExtensionRenameOption]。
+*/
+type ExtensionRenameOption struct {
+	WalkOption
+	Filter *Filter // only files matching the filter are renamed. nil means all files
+	/*
+		Mapping from lower-case extension to the new extension, both including the dot.
+		Extensions not in the mapping are converted to lower case.
+
+		For example:
+			map[string]string{".jpeg": ".jpg", ".htm": ".html"}
+	*/
+	Mapping           map[string]string
+	DryRun            bool // if true, only plan the renames without changing any file
+	RenameOnCollision bool // if true, a unique name is used when the new path exists. Otherwise the file is skipped
+}
+
+/*
+NewExtensionRenameOption creates a new ExtensionRenameOption with scan directory recursively,
+bypass permission denied error, no filter, no mapping, no dry run and skip on collision.
+
+NewExtensionRenameOption 创建默认的 ExtensionRenameOption。
+包含递归扫描目录、跳过没有权限的文件及目录、不过滤文件、无映射、实际执行改名，以及冲突时跳过。
+*/
+func NewExtensionRenameOption() *ExtensionRenameOption {
+	return &ExtensionRenameOption{
+		WalkOption: WalkOption{
+			Recursive:        true,
+			PathErrorHandler: SkipPermissionError,
+		},
+		Filter:            nil,
+		Mapping:           nil,
+		DryRun:            false,
+		RenameOnCollision: false,
+	}
+}
+
+/*
+NormalizeExtensions renames files under the given directory to lower-case or mapped extensions.
+
+Parameters:
+  - root: the directory to scan.
+  - option: the options. if nil, the default options will be used.
+
+Returns:
+  - the rename result. Partial result is returned along with the error.
+  - an error if any occurred.
+
+NormalizeExtensions 将给定目录下文件的扩展名改为小写或映射后的扩展名。
+
+参数:
+  - root: 要扫描的目录。
+  - option: 选项。如果为 nil 则使用默认选项。
+
+返回:
+  - 改名结果。出错时返回已完成的部分结果。
+  - 错误信息。
+*/
+func NormalizeExtensions(root string, option *ExtensionRenameOption) (*RenameResult, error) {
+	if option == nil { // 保证 option 不为 nil。
+		option = NewExtensionRenameOption()
+	}
+
+	// 先收集需要改名的文件，再统一改名，避免在遍历的同时修改目录内容。
+	plans := make([]RenameRecord, 0)
+	err := walkFilteredFiles(root, option.Filter, &option.WalkOption, func(relPath string, path string, info os.FileInfo) error {
+		ext := filepath.Ext(path)
+		newExt := normalizeExtension(ext, option.Mapping)
+
+		if newExt != ext {
+			plans = append(plans, RenameRecord{From: path, To: strings.TrimSuffix(path, ext) + newExt})
+		}
+		return nil
+	})
+
+	if err != nil {
+		return &RenameResult{Renamed: []RenameRecord{}, Collisions: []RenameRecord{}}, err
+	}
+
+	return executeRenames(plans, option.DryRun, option.RenameOnCollision)
+}
+
+// normalizeExtension 返回映射后的扩展名。没有映射时返回小写的扩展名。
+func normalizeExtension(ext string, mapping map[string]string) string {
+	lower := strings.ToLower(ext)
+	if newExt, ok := mapping[lower]; ok {
+		return newExt
+	}
+	return lower
+}
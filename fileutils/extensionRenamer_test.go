@@ -0,0 +1,79 @@
+package fileutils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeExtensions(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"a.JPEG", "b.Txt", "c.txt", "c.TXT", "d.md", "e"} {
+		assert.Nil(t, os.WriteFile(filepath.Join(root, name), []byte(name), 0644))
+	}
+
+	option := NewExtensionRenameOption()
+	option.Mapping = map[string]string{".jpeg": ".jpg"}
+	option.DryRun = true
+
+	// 只计划，不改名。c.TXT 与已存在的 c.txt 冲突。
+	result, err := NormalizeExtensions(root, option)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(result.Renamed))
+	assert.Equal(t, filepath.Join(root, "a.jpg"), result.Renamed[0].To)
+	assert.Equal(t, filepath.Join(root, "b.txt"), result.Renamed[1].To)
+	assert.Equal(t, 1, len(result.Collisions))
+	assert.Equal(t, filepath.Join(root, "c.TXT"), result.Collisions[0].From)
+
+	exists, _, _ := FileExists(filepath.Join(root, "a.JPEG"))
+	assert.True(t, exists)
+
+	// 冲突时使用唯一的文件名。
+	option.DryRun = false
+	option.RenameOnCollision = true
+	result, err = NormalizeExtensions(root, option)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(result.Renamed))
+	assert.Equal(t, filepath.Join(root, "c_1.txt"), result.Renamed[2].To)
+	assert.Equal(t, 0, len(result.Collisions))
+
+	for _, name := range []string{"a.jpg", "b.txt", "c.txt", "c_1.txt", "d.md", "e"} {
+		exists, _, _ := FileExists(filepath.Join(root, name))
+		assert.True(t, exists, name)
+	}
+
+	// 通过日志撤销。
+	journal := filepath.Join(t.TempDir(), "journal.json")
+	assert.Nil(t, SaveRenameJournal(journal, result.Renamed))
+
+	undone, err := RollbackRenameJournal(journal)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(undone))
+
+	for _, name := range []string{"a.JPEG", "b.Txt", "c.txt", "c.TXT"} {
+		exists, _, _ := FileExists(filepath.Join(root, name))
+		assert.True(t, exists, name)
+	}
+
+	// 已撤销，原文件已存在，不能再次撤销。
+	_, err = RollbackRenameJournal(journal)
+	assert.NotNil(t, err)
+}
+
+func TestGetUniqueFilePath(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "photo.jpg")
+
+	unique, err := GetUniqueFilePath(path)
+	assert.Nil(t, err)
+	assert.Equal(t, path, unique)
+
+	assert.Nil(t, os.WriteFile(path, nil, 0644))
+	assert.Nil(t, os.WriteFile(filepath.Join(root, "photo_1.jpg"), nil, 0644))
+
+	unique, err = GetUniqueFilePath(path)
+	assert.Nil(t, err)
+	assert.Equal(t, filepath.Join(root, "photo_2.jpg"), unique)
+}
@@ -1,7 +1,6 @@
 package fileutils
 
 import (
-	"bufio"
 	"errors"
 	"io"
 	"os"
@@ -124,7 +123,8 @@ func GetFileChecksum(
 
 	// 文件已打开，此处不会再有错误。
 	info, _ := file.Stat()
-	reader := bufio.NewReader(file)
+	// buffer 已足够大，直接读取文件，不再为每个文件分配 bufio.Reader。
+	reader := file
 	readCount := 0
 
 	// 计算文件头的校验和。
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"hash"
 	"os"
+
+	"github.com/jqk/futool4go/common"
 )
 
 // FileChecksumCalculationProvider defines the interface for calculating the checksum.
@@ -179,6 +181,21 @@ func NewCommonFileChecksumProvider(method string, hashInstance hash.Hash) *Commo
 	return result
 }
 
+/*
+NewFileChecksumProvider creates a CommonFileChecksumProvider of the algorithm registered in [common.RegisterHash],
+such as "md5" or "sha256", so file checksums match [common.HashBytes] of the same algorithm.
+
+NewFileChecksumProvider 使用 [common.RegisterHash] 中注册的算法创建 CommonFileChecksumProvider，例如 "md5" 或 "sha256"，
+使文件校验值与同一算法的 [common.HashBytes] 一致。
+*/
+func NewFileChecksumProvider(method string) (*CommonFileChecksumProvider, error) {
+	hashInstance, err := common.NewHash(method)
+	if err != nil {
+		return nil, err
+	}
+	return NewCommonFileChecksumProvider(method, hashInstance), nil
+}
+
 // Method returns the digest algorithm name.
 //
 // Method 返回哈希算法名称。
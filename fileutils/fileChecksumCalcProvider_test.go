@@ -4,9 +4,13 @@ import (
 	"bytes"
 	"crypto/md5"
 	"encoding/binary"
+	"encoding/hex"
 	"hash/crc64"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/jqk/futool4go/common"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -192,3 +196,20 @@ func TestGetLargeFileChecksumDrivedProviderMD5(t *testing.T) {
 	assert.False(t, p.IsFullChecksumReady())
 	assert.True(t, bytes.Equal(header, p.HeaderChecksum()))
 }
+
+func TestNewFileChecksumProvider(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.txt")
+	assert.Nil(t, os.WriteFile(path, []byte("hello"), 0644))
+
+	p, err := NewFileChecksumProvider("sha256")
+	assert.Nil(t, err)
+	assert.Equal(t, "sha256", p.Method())
+	assert.Nil(t, GetFileChecksumWithProvider(path, 0, make([]byte, 1024), false, true, p))
+
+	// 与 common.HashString 的结果相同。
+	expected, _ := common.HashString("sha256", "hello")
+	assert.Equal(t, expected, hex.EncodeToString(p.FullChecksum()))
+
+	_, err = NewFileChecksumProvider("unknown")
+	assert.NotNil(t, err)
+}
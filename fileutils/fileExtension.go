@@ -1,11 +1,13 @@
 package fileutils
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
-	"sort"
 	"strings"
+
+	"github.com/jqk/futool4go/collections"
 )
 
 // FileExtension describes file extension information.
@@ -20,10 +22,11 @@ type FileExtension struct {
 			".html"
 			"" means no extension.
 	*/
-	Name  string
-	Count int    // occurrence count
-	Size  int64  // total file size in byte
-	key   string // key is an internal key used for sorting
+	Name      string
+	Count     int            // occurrence count
+	Size      int64          // total file size in byte
+	Histogram *SizeHistogram // distribution of file sizes. nil if WalkExtensionOption.SizeBuckets is nil
+	key       string         // key is an internal key used for sorting
 }
 
 /*
@@ -78,15 +81,18 @@ WalkExtensionOption defines the options for walk through a path when scanning fi
 */
 type WalkExtensionOption struct {
 	WalkOption
-	CaseSensitive bool // whether to distinguish case for extensions
+	CaseSensitive bool            // whether to distinguish case for extensions
+	Filter        *Filter         // only files matching the filter are counted. nil means all files
+	Context       context.Context // the scan stops with Context.Err() when it is done. nil means never cancelled
+	SizeBuckets   []int64         // bucket bounds of FileExtension.Histogram. nil means no histogram. see DefaultSizeBuckets
 }
 
 /*
 NewWalkExtensionOption creates a new WalkExtensionOption with scan directory recursively,
-bypass permission denied error and case insensitive for extensions.
+bypass permission denied error, case insensitive for extensions, no filter, no context and no histogram.
 
 NewWalkExtensionOption 创建默认的 WalkExtensionOption。
-包含递归扫描目录、跳过没有权限的文件及目录，以及扩展名大小写不敏感。
+包含递归扫描目录、跳过没有权限的文件及目录、扩展名大小写不敏感、不过滤文件、不可取消，以及不统计大小分布。
 */
 func NewWalkExtensionOption() *WalkExtensionOption {
 	return &WalkExtensionOption{
@@ -95,6 +101,9 @@ func NewWalkExtensionOption() *WalkExtensionOption {
 			PathErrorHandler: SkipPermissionError,
 		},
 		CaseSensitive: false,
+		Filter:        nil,
+		Context:       nil,
+		SizeBuckets:   nil,
 	}
 }
 
@@ -133,12 +142,25 @@ func GetFileExtensions(path string, option *WalkExtensionOption, consumer FileEx
 
 	if option == nil { // 保证 option 不为 nil。
 		option = NewWalkExtensionOption()
+	} else if option.Filter != nil {
+		if outerErr = option.Filter.Validate(); outerErr != nil {
+			return nil, outerErr
+		}
+	}
+	if outerErr = validateSizeBuckets(option.SizeBuckets); outerErr != nil {
+		return nil, outerErr
 	}
 
 	// 使用 map 主要是为了合并同名扩展名，统计各个扩展名出现的次数。
 	extMap := make(map[string]*FileExtension)
 
 	outerErr = filepath.Walk(path, func(path string, info os.FileInfo, err error) error {
+		if option.Context != nil {
+			if ctxErr := option.Context.Err(); ctxErr != nil {
+				return ctxErr // 已被取消或超时，中断遍历。
+			}
+		}
+
 		if err != nil {
 			if option.PathErrorHandler != nil {
 				return option.PathErrorHandler(path, info, err)
@@ -152,6 +174,8 @@ func GetFileExtensions(path string, option *WalkExtensionOption, consumer FileEx
 				return consumer(path, info, nil) // 将开始处理新目录通知外部调用者。
 			}
 			return nil
+		} else if option.Filter != nil && option.Filter.IsMatched(info) != nil {
+			return nil // 不满足过滤条件的文件不统计，也不通知调用者。
 		}
 
 		ext := filepath.Ext(path)
@@ -161,10 +185,17 @@ func GetFileExtensions(path string, option *WalkExtensionOption, consumer FileEx
 
 		if _, ok := extMap[ext]; !ok {
 			extMap[ext] = NewFileExtension(ext) // 该扩展名第一次出现，创建对象。
+			if option.SizeBuckets != nil {
+				// 前面已校验过 SizeBuckets，不会出错。
+				extMap[ext].Histogram, _ = NewSizeHistogram(option.SizeBuckets)
+			}
 		}
 
 		extMap[ext].Count++
 		extMap[ext].Size += info.Size()
+		if extMap[ext].Histogram != nil {
+			extMap[ext].Histogram.Add(info.Size())
+		}
 
 		if consumer != nil {
 			return consumer(path, info, extMap[ext]) // 将处理新文件通知外部调用者。
@@ -187,6 +218,92 @@ func GetFileExtensions(path string, option *WalkExtensionOption, consumer FileEx
 	return extensions, nil
 }
 
+/*
+GetFileExtensionsByDir scans the given path like [GetFileExtensions], but the statistics are broken down by directory.
+Each directory only counts the files directly in it, not those in its sub directories.
+
+Parameters:
+  - path: Path to be scanned.
+  - option: the scan options. if nil, the default options will be used.
+  - consumer: This function will be invoked whenever a new file or directory is processed to notify the caller. Can be nil.
+    The extension passed to it is the one of the directory containing the file.
+
+Returns:
+  - A map from directory path to the unsorted array of [FileExtension] in it. Directories without files are not included.
+  - nil if processed successfully, otherwise the error message.
+
+GetFileExtensionsByDir 与 [GetFileExtensions] 一样扫描给定路径，但按目录分别统计。每个目录只统计直接位于其中的文件，不包括子目录中的文件。
+
+参数:
+  - path: 待扫描的路径。
+  - option: 扫描选项。如果为 nil 则使用默认选项。
+  - consumer: 每处理一个新的文件或目录都将尝试调用该函数，从而通知调用者。可为 nil。传给它的扩展名信息是文件所在目录的。
+
+返回:
+  - 目录路径到其中未经排序的文件扩展名信息数组的 map。不包括没有文件的目录。
+  - 处理正常时为 nil，否则为错误信息。
+*/
+func GetFileExtensionsByDir(
+	path string,
+	option *WalkExtensionOption,
+	consumer FileExtensionConsumer,
+) (map[string][]FileExtension, error) {
+	// 目录路径 -> 扩展名 -> 扩展名信息。
+	dirMap := make(map[string]map[string]*FileExtension)
+
+	_, err := GetFileExtensions(path, option, func(path string, info os.FileInfo, extension *FileExtension) error {
+		if extension == nil {
+			if consumer != nil {
+				return consumer(path, info, nil)
+			}
+			return nil
+		}
+
+		dir := filepath.Dir(path)
+		extMap, ok := dirMap[dir]
+		if !ok {
+			extMap = make(map[string]*FileExtension)
+			dirMap[dir] = extMap
+		}
+
+		// extension.Name 已按 option.CaseSensitive 处理过了，可直接作为 key。
+		ext, ok := extMap[extension.Name]
+		if !ok {
+			ext = NewFileExtension(extension.Name)
+			if extension.Histogram != nil {
+				ext.Histogram, _ = NewSizeHistogram(extension.Histogram.Bounds)
+			}
+			extMap[extension.Name] = ext
+		}
+
+		ext.Count++
+		ext.Size += info.Size()
+		if ext.Histogram != nil {
+			ext.Histogram.Add(info.Size())
+		}
+
+		if consumer != nil {
+			return consumer(path, info, ext)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string][]FileExtension, len(dirMap))
+	for dir, extMap := range dirMap {
+		extensions := make([]FileExtension, 0, len(extMap))
+		for _, ext := range extMap {
+			extensions = append(extensions, *ext)
+		}
+		result[dir] = extensions
+	}
+
+	return result, nil
+}
+
 /*
 SortFileExtensionsByName sorts the given list of [FileExtension] objects by name, asec. The function modifies the given slice in-place.
 
@@ -199,18 +316,10 @@ SortFileExtensionsByName 按名称升序排列。将直接修改给定的切片
   - extensions: 待排序的 [FileExtension] 数组。
 */
 func SortFileExtensionsByName(extensions []FileExtension) {
-	sort.Slice(extensions, func(i, j int) bool {
-		key_i := extensions[i].key
-		key_j := extensions[j].key
-
-		if key_i == key_j {
-			// 这样做可以在区分大小写的情况下将 key 相同但大小写不同的扩展名排在一起。
-			return extensions[i].Name > extensions[j].Name
-		}
-
-		// 升序。
-		return key_i < key_j
-	})
+	// key 相同时按名称降序，这样可以在区分大小写的情况下将 key 相同但大小写不同的扩展名排在一起。
+	collections.SortBy(extensions,
+		collections.Asc(func(e FileExtension) string { return e.key }),
+		collections.Desc(func(e FileExtension) string { return e.Name }))
 }
 
 /*
@@ -225,17 +334,7 @@ SortFileExtensionsByCount 按数量降序排列。将直接修改给定的切片
   - extensions: 待排序的 [FileExtension] 数组。
 */
 func SortFileExtensionsByCount(extensions []FileExtension) {
-	sort.Slice(extensions, func(i, j int) bool {
-		count_i := extensions[i].Count
-		count_j := extensions[j].Count
-
-		if count_i == count_j {
-			return extensions[i].Size > extensions[j].Size
-		}
-
-		// 降序。
-		return count_i > count_j
-	})
+	collections.SortBy(extensions, extensionsByCount, extensionsBySize)
 }
 
 /*
@@ -250,15 +349,11 @@ SortFileExtensionsBySize 按文件大小降序排列。将直接修改给定的
   - extensions: 待排序的 [FileExtension] 数组。
 */
 func SortFileExtensionsBySize(extensions []FileExtension) {
-	sort.Slice(extensions, func(i, j int) bool {
-		size_i := extensions[i].Size
-		size_j := extensions[j].Size
-
-		if size_i == size_j {
-			return extensions[i].Count > extensions[j].Count
-		}
-
-		// 降序。
-		return size_i > size_j
-	})
+	collections.SortBy(extensions, extensionsBySize, extensionsByCount)
 }
+
+// extensionsByCount 及 extensionsBySize 是按数量及按大小降序的排序条件。
+var (
+	extensionsByCount = collections.Desc(func(e FileExtension) int { return e.Count })
+	extensionsBySize  = collections.Desc(func(e FileExtension) int64 { return e.Size })
+)
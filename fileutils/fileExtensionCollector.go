@@ -0,0 +1,113 @@
+package fileutils
+
+import (
+	"strings"
+	"sync"
+)
+
+/*
+FileExtensionCollector accumulates [FileExtension] statistics from multiple scans, thread safe.
+It is used for incremental or sharded scans of large volumes.
+
+FileExtensionCollector 累计多次扫描得到的 [FileExtension] 统计信息，多线程安全。用于对大容量存储的增量或分片扫描。
+*/
+type FileExtensionCollector struct {
+	caseSensitive bool
+	extMap        map[string]*FileExtension
+	lock          sync.Mutex
+}
+
+/*
+NewFileExtensionCollector creates a new [FileExtensionCollector].
+
+Parameters:
+  - caseSensitive: whether to distinguish case for extensions.
+    If false, extensions that differ only in case are merged, and the name is in lower case.
+
+NewFileExtensionCollector 创建 [FileExtensionCollector]。
+
+参数:
+  - caseSensitive: 扩展名是否区分大小写。为 false 时，仅大小写不同的扩展名将被合并，且名称为小写。
+*/
+func NewFileExtensionCollector(caseSensitive bool) *FileExtensionCollector {
+	return &FileExtensionCollector{
+		caseSensitive: caseSensitive,
+		extMap:        make(map[string]*FileExtension),
+	}
+}
+
+/*
+Add merges the given extensions into the collector.
+Histograms are merged too. If their bounds differ, the histogram of that extension is dropped.
+
+Add 将给定的扩展名信息合并到收集器中。直方图也会合并。如果分组边界不同，则放弃该扩展名的直方图。
+*/
+func (c *FileExtensionCollector) Add(extensions ...FileExtension) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, ext := range extensions {
+		name := ext.Name
+		if !c.caseSensitive {
+			name = strings.ToLower(name)
+		}
+
+		target, ok := c.extMap[name]
+		if !ok {
+			target = NewFileExtension(name)
+			if ext.Histogram != nil {
+				target.Histogram = ext.Histogram.clone()
+			}
+			c.extMap[name] = target
+		} else if target.Histogram != nil {
+			if ext.Histogram == nil || target.Histogram.Merge(ext.Histogram) != nil {
+				target.Histogram = nil
+			}
+		}
+
+		target.Count += ext.Count
+		target.Size += ext.Size
+	}
+}
+
+/*
+Extensions returns the unsorted merged extensions.
+
+Extensions 返回合并后未经排序的扩展名信息。
+*/
+func (c *FileExtensionCollector) Extensions() []FileExtension {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	extensions := make([]FileExtension, 0, len(c.extMap))
+	for _, ext := range c.extMap {
+		result := *ext
+		if result.Histogram != nil {
+			// 返回副本，避免后续的 Add() 修改已返回的结果。
+			result.Histogram = result.Histogram.clone()
+		}
+		extensions = append(extensions, result)
+	}
+
+	return extensions
+}
+
+/*
+MergeFileExtensions merges two arrays of [FileExtension] case insensitively,
+which is the default behavior of [GetFileExtensions]. Use [FileExtensionCollector] for case sensitive merging.
+
+Returns:
+  - An unsorted array of merged [FileExtension]. The names are in lower case.
+
+MergeFileExtensions 不区分大小写地合并两个 [FileExtension] 数组，这也是 [GetFileExtensions] 的默认行为。
+需区分大小写时请使用 [FileExtensionCollector]。
+
+返回:
+  - 未经排序的合并后的文件扩展名信息数组。名称为小写。
+*/
+func MergeFileExtensions(a, b []FileExtension) []FileExtension {
+	collector := NewFileExtensionCollector(false)
+	collector.Add(a...)
+	collector.Add(b...)
+	return collector.Extensions()
+}
@@ -0,0 +1,69 @@
+package fileutils
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeFileExtensions(t *testing.T) {
+	a := []FileExtension{
+		*NewFileExtension(".txt"),
+		*NewFileExtension(".md"),
+	}
+	a[0].Count, a[0].Size = 2, 100
+	a[1].Count, a[1].Size = 1, 10
+
+	b := []FileExtension{
+		*NewFileExtension(".TXT"),
+		*NewFileExtension(""),
+	}
+	b[0].Count, b[0].Size = 3, 300
+	b[1].Count, b[1].Size = 1, 1
+
+	merged := MergeFileExtensions(a, b)
+	SortFileExtensionsByName(merged)
+
+	assert.Equal(t, 3, len(merged))
+	assert.Equal(t, "", merged[0].Name)
+	assert.Equal(t, ".md", merged[1].Name)
+	assert.Equal(t, ".txt", merged[2].Name)
+	assert.Equal(t, 5, merged[2].Count)
+	assert.Equal(t, int64(400), merged[2].Size)
+
+	collector := NewFileExtensionCollector(true)
+	collector.Add(a...)
+	collector.Add(b...)
+	assert.Equal(t, 4, len(collector.Extensions()))
+}
+
+func TestFileExtensionCollectorWithScans(t *testing.T) {
+	// 分别扫描两个子目录，并发合并结果。
+	collector := NewFileExtensionCollector(false)
+	wg := sync.WaitGroup{}
+
+	for _, dir := range []string{"sub1", "sub2"} {
+		wg.Add(1)
+		go func(dir string) {
+			defer wg.Done()
+
+			// WalkOption 记录了遍历状态，不能在多个扫描之间共享。
+			option := NewWalkExtensionOption()
+			option.Recursive = false
+
+			extensions, err := GetFileExtensions("../test-data/fileutils/extension/"+dir, option, nil)
+			assert.Nil(t, err)
+			collector.Add(extensions...)
+		}(dir)
+	}
+
+	wg.Wait()
+
+	extensions := collector.Extensions()
+	SortFileExtensionsByName(extensions)
+	assert.Equal(t, 2, len(extensions))
+	assert.Equal(t, ".md", extensions[0].Name)
+	assert.Equal(t, ".txt", extensions[1].Name)
+	assert.Equal(t, 3, extensions[1].Count)
+}
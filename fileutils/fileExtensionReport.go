@@ -0,0 +1,201 @@
+package fileutils
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/jqk/futool4go/common"
+)
+
+// noExtensionName 是没有扩展名的文件在 CSV 和 Markdown 中显示的名称。
+const noExtensionName = "(none)"
+
+/*
+FileExtensionReportRow is a row of the file extension report.
+
+FileExtensionReportRow 是文件扩展名报告中的一行。
+*/
+type FileExtensionReportRow struct {
+	Name         string  `json:"name"`         // Name of the file extension. "" means no extension.
+	Count        int     `json:"count"`        // Occurrence count.
+	CountPercent float64 `json:"countPercent"` // Percentage of the count in total, 0 to 100.
+	Size         int64   `json:"size"`         // Total file size in byte.
+	SizeString   string  `json:"sizeString"`   // Human-readable size. See [common.ToSizeString].
+	SizePercent  float64 `json:"sizePercent"`  // Percentage of the size in total, 0 to 100.
+}
+
+/*
+FileExtensionReport is the file extension statistics with percentages and totals.
+
+FileExtensionReport 是包含百分比及合计的文件扩展名统计信息。
+*/
+type FileExtensionReport struct {
+	Extensions []FileExtensionReportRow `json:"extensions"` // Rows in the same order as the given extensions.
+	Total      FileExtensionReportRow   `json:"total"`      // The total row. Its name is "total".
+}
+
+/*
+NewFileExtensionReport calculates the percentages and totals of the given extensions.
+The order of the rows is the same as the given extensions, so sort them first if necessary.
+
+NewFileExtensionReport 计算给定扩展名的百分比及合计。行的顺序与给定的扩展名相同，如有需要请先排序。
+*/
+func NewFileExtensionReport(extensions []FileExtension) *FileExtensionReport {
+	report := &FileExtensionReport{
+		Extensions: make([]FileExtensionReportRow, 0, len(extensions)),
+		Total:      FileExtensionReportRow{Name: "total"},
+	}
+
+	for _, ext := range extensions {
+		report.Total.Count += ext.Count
+		report.Total.Size += ext.Size
+	}
+
+	percent := func(value, total int64) float64 {
+		if total == 0 {
+			return 0
+		}
+		return float64(value) * 100 / float64(total)
+	}
+
+	for _, ext := range extensions {
+		report.Extensions = append(report.Extensions, FileExtensionReportRow{
+			Name:         ext.Name,
+			Count:        ext.Count,
+			CountPercent: percent(int64(ext.Count), int64(report.Total.Count)),
+			Size:         ext.Size,
+			SizeString:   common.ToSizeString(ext.Size),
+			SizePercent:  percent(ext.Size, report.Total.Size),
+		})
+	}
+
+	report.Total.SizeString = common.ToSizeString(report.Total.Size)
+	if report.Total.Count > 0 {
+		report.Total.CountPercent = 100
+	}
+	if report.Total.Size > 0 {
+		report.Total.SizePercent = 100
+	}
+
+	return report
+}
+
+/*
+WriteFileExtensionsCSV writes the file extension report in CSV format, including a header line and a total line.
+
+WriteFileExtensionsCSV 以 CSV 格式输出文件扩展名报告，包括标题行和合计行。
+*/
+func WriteFileExtensionsCSV(w io.Writer, extensions []FileExtension) error {
+	report := NewFileExtensionReport(extensions)
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write([]string{"extension", "count", "count%", "size", "size string", "size%"}); err != nil {
+		return err
+	}
+
+	for _, row := range append(report.Extensions, report.Total) {
+		record := []string{
+			displayExtensionName(row.Name),
+			strconv.Itoa(row.Count),
+			strconv.FormatFloat(row.CountPercent, 'f', 2, 64),
+			strconv.FormatInt(row.Size, 10),
+			row.SizeString,
+			strconv.FormatFloat(row.SizePercent, 'f', 2, 64),
+		}
+
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+/*
+WriteFileExtensionsJSON writes the file extension report in JSON format. See [FileExtensionReport] for the structure.
+
+WriteFileExtensionsJSON 以 JSON 格式输出文件扩展名报告。结构参见 [FileExtensionReport]。
+*/
+func WriteFileExtensionsJSON(w io.Writer, extensions []FileExtension) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(NewFileExtensionReport(extensions))
+}
+
+/*
+WriteFileExtensionsMarkdown writes the file extension report as a Markdown table, with the total line in bold.
+"|" and "\" in the cells are escaped, and line breaks become "<br>", so each row stays on one line.
+
+WriteFileExtensionsMarkdown 以 Markdown 表格输出文件扩展名报告，合计行为粗体。
+单元格中的“|”及“\”被转义，换行符替换为“<br>”，所以每行数据都在一行中。
+*/
+func WriteFileExtensionsMarkdown(w io.Writer, extensions []FileExtension) error {
+	return WriteFileExtensionsMarkdownIn(w, extensions, nil)
+}
+
+/*
+WriteFileExtensionsMarkdownIn is like [WriteFileExtensionsMarkdown], writing numbers and sizes by the format,
+such as "1,234" and "1.5 MB". CSV and JSON are for programs, so they always use plain numbers.
+
+WriteFileExtensionsMarkdownIn 与 [WriteFileExtensionsMarkdown] 相同，但按 format 输出数字及大小，例如 "1,234" 及 "1.5 MB"。
+CSV 及 JSON 用于程序读取，所以总是使用普通的数字。
+*/
+func WriteFileExtensionsMarkdownIn(w io.Writer, extensions []FileExtension, format *common.NumberFormat) error {
+	report := NewFileExtensionReport(extensions)
+
+	sizeOption := common.NewSizeOption()
+	sizeOption.Format = format
+	sizeString := func(row FileExtensionReportRow) string {
+		if format == nil {
+			return row.SizeString
+		}
+		return common.FormatSize(row.Size, sizeOption)
+	}
+
+	if _, err := fmt.Fprint(w,
+		"| Extension | Count | Count % | Size | Size % |\n",
+		"|:----------|------:|--------:|-----:|-------:|\n",
+	); err != nil {
+		return err
+	}
+
+	for _, row := range report.Extensions {
+		if _, err := fmt.Fprintf(w, "| %s | %s | %s | %s | %s |\n", markdownCells(
+			displayExtensionName(row.Name), format.FormatInt(int64(row.Count)), format.FormatFloat(row.CountPercent, 2),
+			sizeString(row), format.FormatFloat(row.SizePercent, 2),
+		)...); err != nil {
+			return err
+		}
+	}
+
+	total := report.Total
+	_, err := fmt.Fprintf(w, "| **%s** | **%s** | **%s** | **%s** | **%s** |\n", markdownCells(
+		total.Name, format.FormatInt(int64(total.Count)), format.FormatFloat(total.CountPercent, 2),
+		sizeString(total), format.FormatFloat(total.SizePercent, 2),
+	)...)
+	return err
+}
+
+// markdownCellReplacer 转义 Markdown 表格单元格中的“\”及“|”，并将换行符替换为“<br>”。
+var markdownCellReplacer = strings.NewReplacer(`\`, `\\`, "|", `\|`, "\r\n", "<br>", "\n", "<br>", "\r", "<br>")
+
+// markdownCells 转义 Markdown 表格的单元格，返回可以直接传给 fmt.Fprintf 的参数。
+func markdownCells(cells ...string) []any {
+	result := make([]any, len(cells))
+	for i, cell := range cells {
+		result[i] = markdownCellReplacer.Replace(cell)
+	}
+	return result
+}
+
+func displayExtensionName(name string) string {
+	if name == "" {
+		return noExtensionName
+	}
+	return name
+}
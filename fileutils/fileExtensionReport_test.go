@@ -0,0 +1,83 @@
+package fileutils
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/jqk/futool4go/common"
+	"github.com/stretchr/testify/assert"
+)
+
+var reportExtensions = []FileExtension{
+	{Name: ".txt", Count: 3, Size: 3072, key: ".txt"},
+	{Name: "", Count: 1, Size: 1024, key: ""},
+}
+
+func TestWriteFileExtensionsCSV(t *testing.T) {
+	buf := &bytes.Buffer{}
+	assert.Nil(t, WriteFileExtensionsCSV(buf, reportExtensions))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Equal(t, 4, len(lines))
+	assert.Equal(t, "extension,count,count%,size,size string,size%", lines[0])
+	assert.Equal(t, ".txt,3,75.00,3072,3.000 KB,75.00", lines[1])
+	assert.Equal(t, "(none),1,25.00,1024,1.000 KB,25.00", lines[2])
+	assert.Equal(t, "total,4,100.00,4096,4.000 KB,100.00", lines[3])
+}
+
+func TestWriteFileExtensionsJSON(t *testing.T) {
+	buf := &bytes.Buffer{}
+	assert.Nil(t, WriteFileExtensionsJSON(buf, reportExtensions))
+
+	report := &FileExtensionReport{}
+	assert.Nil(t, json.Unmarshal(buf.Bytes(), report))
+	assert.Equal(t, 2, len(report.Extensions))
+	assert.Equal(t, "", report.Extensions[1].Name)
+	assert.Equal(t, 75.0, report.Extensions[0].SizePercent)
+	assert.Equal(t, int64(4096), report.Total.Size)
+	assert.Equal(t, "4.000 KB", report.Total.SizeString)
+}
+
+func TestWriteFileExtensionsMarkdown(t *testing.T) {
+	buf := &bytes.Buffer{}
+	assert.Nil(t, WriteFileExtensionsMarkdown(buf, reportExtensions))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Equal(t, 5, len(lines))
+	assert.Equal(t, "| .txt | 3 | 75.00 | 3.000 KB | 75.00 |", lines[2])
+	assert.Equal(t, "| **total** | **4** | **100.00** | **4.000 KB** | **100.00** |", lines[4])
+
+	// 空数组也能输出表头及合计。
+	buf.Reset()
+	assert.Nil(t, WriteFileExtensionsMarkdown(buf, nil))
+	assert.True(t, strings.HasSuffix(buf.String(), "| **total** | **0** | **0.00** | **0 bytes** | **0.00** |\n"))
+}
+
+func TestWriteFileExtensionsMarkdownEscape(t *testing.T) {
+	extensions := []FileExtension{{Name: ".a|b", Count: 1, Size: 1, key: ".a|b"}, {Name: ".c\nd\\", Count: 1, Size: 1, key: ".c\nd\\"}}
+
+	buf := &bytes.Buffer{}
+	assert.Nil(t, WriteFileExtensionsMarkdown(buf, extensions))
+
+	// 每行数据仍然在一行中，且只有 5 列。
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Equal(t, 5, len(lines))
+	assert.Equal(t, `| .a\|b | 1 | 50.00 | 1 bytes | 50.00 |`, lines[2])
+	assert.Equal(t, `| .c<br>d\\ | 1 | 50.00 | 1 bytes | 50.00 |`, lines[3])
+}
+
+func TestWriteFileExtensionsMarkdownIn(t *testing.T) {
+	extensions := []FileExtension{{Name: ".jpg", Count: 12345, Size: 1536, key: ".jpg"}, {Name: "", Count: 1, Size: 5, key: ""}}
+
+	buf := &bytes.Buffer{}
+	assert.Nil(t, WriteFileExtensionsMarkdownIn(buf, extensions, &common.NumberFormat{
+		Locale: common.LocaleChinese, DecimalSeparator: ",", GroupSeparator: ".",
+	}))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Equal(t, "| .jpg | 12.345 | 99,99 | 1,500 KB | 99,68 |", lines[2])
+	assert.Equal(t, "| (none) | 1 | 0,01 | 5 字节 | 0,32 |", lines[3])
+	assert.Equal(t, "| **total** | **12.346** | **100,00** | **1,505 KB** | **100,00** |", lines[4])
+}
@@ -1,6 +1,7 @@
 package fileutils
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"strings"
@@ -70,6 +71,55 @@ func TestGetExtensionsWithConsumer(t *testing.T) {
 	assert.Equal(t, 6, len(extensions))
 }
 
+func TestGetExtensionsWithFilterAndContext(t *testing.T) {
+	option := NewWalkExtensionOption()
+	option.Filter = &Filter{Include: []string{"*.txt"}}
+
+	extensions, err := GetFileExtensions("../test-data/fileutils/extension", option, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(extensions))
+	assert.Equal(t, ".txt", extensions[0].Name)
+	assert.Equal(t, 4, extensions[0].Count)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	option = NewWalkExtensionOption()
+	option.Context = ctx
+
+	extensions, err = GetFileExtensions("../test-data/fileutils/extension", option, nil)
+	assert.Equal(t, context.Canceled, err)
+	assert.Nil(t, extensions)
+}
+
+func TestGetExtensionsByDir(t *testing.T) {
+	root := "../test-data/fileutils/extension"
+	option := NewWalkExtensionOption()
+
+	dirs, err := GetFileExtensionsByDir(root, option, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(dirs))
+	assert.Equal(t, 4, len(dirs[root]))
+
+	sub1 := dirs[filepath.Join(root, "sub1")]
+	assert.Equal(t, 1, len(sub1))
+	assert.Equal(t, ".txt", sub1[0].Name)
+	assert.Equal(t, 2, sub1[0].Count)
+
+	sub2 := dirs[filepath.Join(root, "sub2")]
+	SortFileExtensionsByName(sub2)
+	assert.Equal(t, 2, len(sub2))
+	assert.Equal(t, ".md", sub2[0].Name)
+	assert.Equal(t, ".txt", sub2[1].Name)
+
+	// 不递归时只有根目录。
+	option = NewWalkExtensionOption()
+	option.Recursive = false
+	dirs, err = GetFileExtensionsByDir(root, option, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(dirs))
+}
+
 func TestSortExtensions(t *testing.T) {
 	fs := []FileExtension{
 		{
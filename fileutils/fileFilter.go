@@ -16,6 +16,7 @@ var (
 	ErrReasonMaxSize      = errors.New("file size is larger than max size")
 	ErrReasonInExclude    = errors.New("file name matches exclude")
 	ErrReasonNotInInclude = errors.New("file name does not match include")
+	ErrReasonHidden       = errors.New("file is hidden")
 )
 
 /*
@@ -29,6 +30,7 @@ type Filter struct {
 	Exclude       []string `mapstructure:"exclude"`       // Files matching at least one pattern will be excluded. Supports glob patterns.
 	MinFileSize   int64    `mapstructure:"minFileSize"`   // Minimum file size in bytes. Files smaller than this will be excluded. 0 means no limit.
 	MaxFileSize   int64    `mapstructure:"maxFileSize"`   // Maximum file size in bytes. Files larger than this will be excluded. 0 means no limit.
+	ExcludeHidden bool     `mapstructure:"excludeHidden"` // If true, hidden files and files under hidden directories are excluded. See [IsHidden].
 }
 
 /*
@@ -71,7 +73,7 @@ IsRefusedReason 检查给定的错误是否为预定义的拒绝原因。
 */
 func IsRefusedReason(err error) bool {
 	return err == ErrReasonInExclude || err == ErrReasonNotInInclude ||
-		err == ErrReasonIsDir || err == ErrReasonMinSize || err == ErrReasonMaxSize
+		err == ErrReasonIsDir || err == ErrReasonMinSize || err == ErrReasonMaxSize || err == ErrReasonHidden
 }
 
 /*
@@ -114,6 +116,9 @@ func (f *Filter) GetEachFile(root string, option *WalkOption, handler FileMatche
 			if option.ShouldQuitForNonRecursive() {
 				return filepath.SkipAll
 			}
+			if f.ExcludeHidden && path != root && IsHiddenInfo(info) {
+				return filepath.SkipDir // 隐藏目录下的文件都不处理。
+			}
 			return nil
 		}
 
@@ -189,6 +194,8 @@ func (f *Filter) IsMatched(fileInfo os.FileInfo) error {
 		return ErrReasonMinSize
 	} else if fileInfo.Size() > f.MaxFileSize && f.MaxFileSize > 0 {
 		return ErrReasonMaxSize
+	} else if f.ExcludeHidden && IsHiddenInfo(fileInfo) {
+		return ErrReasonHidden
 	}
 
 	filename := fileInfo.Name()
@@ -235,6 +242,9 @@ func (f *Filter) Diff(other *Filter) string {
 	if f.MinFileSize != other.MinFileSize {
 		return "Filter.MinFileSize"
 	}
+	if f.ExcludeHidden != other.ExcludeHidden {
+		return "Filter.ExcludeHidden"
+	}
 	if !reflect.DeepEqual(f.Include, other.Include) {
 		return "Filter.Include"
 	}
@@ -0,0 +1,101 @@
+package fileutils
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+/*
+FileSystemInfo describes the type and capabilities of the filesystem containing a path.
+
+FileSystemInfo 描述了路径所在文件系统的类型及能力。
+*/
+type FileSystemInfo struct {
+	Type            string // The filesystem type in lower case, such as "ntfs", "ext4", "apfs", "nfs". "unknown" if not recognized.
+	IsNetwork       bool   // True for network filesystems, such as NFS and SMB shares.
+	CaseSensitive   bool   // True if file names differing only in case are different files.
+	SupportsSymlink bool   // True if symbolic links can be created by the current user.
+	MaxNameLength   int    // The maximum length of a file name in bytes, or UTF-16 units on Windows.
+	MaxPathLength   int    // The maximum length of a path. 0 means no practical limit.
+	Probed          bool   // True if CaseSensitive and SupportsSymlink were probed by creating files, false if guessed by platform.
+}
+
+// 常见网络文件系统的类型名称。
+var networkFileSystems = map[string]bool{
+	"nfs": true, "nfs4": true, "cifs": true, "smb": true, "smb2": true, "smbfs": true,
+	"afpfs": true, "webdav": true, "fuse.sshfs": true, "9p": true, "ceph": true,
+}
+
+/*
+GetFileSystemInfo returns the type and capabilities of the filesystem containing the path.
+Case sensitivity and symbolic link support are probed by creating a temporary directory under the path,
+which is removed afterwards. If it cannot be created, they are guessed by the platform and Probed is false.
+
+Parameters:
+  - path: an existing file or directory.
+
+Returns:
+  - the filesystem information.
+  - an error if any occurred.
+
+GetFileSystemInfo 返回路径所在文件系统的类型及能力。
+通过在路径下创建临时目录探测大小写敏感及符号链接支持，之后删除该目录。无法创建时，按平台推测，且 Probed 为 false。
+
+参数:
+  - path: 已存在的文件或目录。
+
+返回:
+  - 文件系统信息。
+  - 错误信息。
+*/
+func GetFileSystemInfo(path string) (*FileSystemInfo, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := path
+	if !info.IsDir() {
+		dir = filepath.Dir(path)
+	}
+
+	result, err := getFileSystemType(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Type = strings.ToLower(result.Type)
+	if result.Type == "" {
+		result.Type = "unknown"
+	}
+	if networkFileSystems[result.Type] {
+		result.IsNetwork = true
+	}
+
+	probeFileSystem(dir, result)
+	return result, nil
+}
+
+// probeFileSystem 在 dir 下创建临时目录，探测大小写敏感及符号链接支持。失败时按平台推测。
+func probeFileSystem(dir string, result *FileSystemInfo) {
+	result.CaseSensitive = runtime.GOOS != "windows" && runtime.GOOS != "darwin"
+	result.SupportsSymlink = runtime.GOOS != "windows"
+
+	probeDir, err := os.MkdirTemp(dir, ".fsprobe-")
+	if err != nil {
+		return
+	}
+	defer os.RemoveAll(probeDir)
+
+	target := filepath.Join(probeDir, "probe")
+	if err = os.WriteFile(target, nil, 0600); err != nil {
+		return
+	}
+
+	_, err = os.Stat(filepath.Join(probeDir, "PROBE"))
+	result.CaseSensitive = os.IsNotExist(err)
+	result.SupportsSymlink = os.Symlink(target, filepath.Join(probeDir, "link")) == nil
+	result.Probed = true
+}
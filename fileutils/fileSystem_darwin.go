@@ -0,0 +1,26 @@
+//go:build darwin
+
+package fileutils
+
+import "syscall"
+
+func getFileSystemType(dir string) (*FileSystemInfo, error) {
+	stat := syscall.Statfs_t{}
+	if err := syscall.Statfs(dir, &stat); err != nil {
+		return nil, err
+	}
+
+	name := make([]byte, 0, len(stat.Fstypename))
+	for _, c := range stat.Fstypename {
+		if c == 0 {
+			break
+		}
+		name = append(name, byte(c))
+	}
+
+	return &FileSystemInfo{
+		Type:          string(name),
+		MaxNameLength: 255,
+		MaxPathLength: 1024, // PATH_MAX
+	}, nil
+}
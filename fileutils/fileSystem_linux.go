@@ -0,0 +1,43 @@
+//go:build linux
+
+package fileutils
+
+import "syscall"
+
+// statfs 返回的 f_type 与文件系统类型的对应关系。参见 statfs(2)。
+var linuxFileSystemTypes = map[uint32]string{
+	0xEF53:     "ext4", // ext2/ext3/ext4 相同。
+	0x58465342: "xfs",
+	0x9123683E: "btrfs",
+	0x01021994: "tmpfs",
+	0x794C7630: "overlay",
+	0x6969:     "nfs",
+	0xFF534D42: "cifs",
+	0xFE534D42: "smb2",
+	0x517B:     "smb",
+	0x4D44:     "vfat",
+	0x5346544E: "ntfs",
+	0x7366746E: "ntfs3",
+	0x2011BAB0: "exfat",
+	0x65735546: "fuse",
+	0x2FC12FC1: "zfs",
+	0xF2F52010: "f2fs",
+	0x9660:     "iso9660",
+	0x01021997: "9p",
+	0x00C36400: "ceph",
+	0x858458F6: "ramfs",
+	0x73717368: "squashfs",
+}
+
+func getFileSystemType(dir string) (*FileSystemInfo, error) {
+	stat := syscall.Statfs_t{}
+	if err := syscall.Statfs(dir, &stat); err != nil {
+		return nil, err
+	}
+
+	return &FileSystemInfo{
+		Type:          linuxFileSystemTypes[uint32(stat.Type)],
+		MaxNameLength: int(stat.Namelen),
+		MaxPathLength: 4096, // PATH_MAX
+	}, nil
+}
@@ -0,0 +1,11 @@
+//go:build !linux && !darwin && !windows
+
+package fileutils
+
+// getFileSystemType 在其它系统上无法识别文件系统类型，只返回 POSIX 的常见限制。
+func getFileSystemType(dir string) (*FileSystemInfo, error) {
+	return &FileSystemInfo{
+		MaxNameLength: 255,
+		MaxPathLength: 1024,
+	}, nil
+}
@@ -0,0 +1,39 @@
+package fileutils
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetFileSystemInfo(t *testing.T) {
+	root := t.TempDir()
+	file := filepath.Join(root, "file.txt")
+	assert.Nil(t, os.WriteFile(file, []byte("x"), 0644))
+
+	info, err := GetFileSystemInfo(root)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, info.Type)
+	assert.True(t, info.Probed)
+	assert.Greater(t, info.MaxNameLength, 0)
+	assert.Greater(t, info.MaxPathLength, 0)
+	if runtime.GOOS == "linux" {
+		assert.True(t, info.SupportsSymlink)
+	}
+
+	// 文件使用其所在目录的文件系统，探测用的临时目录已删除。
+	fileInfo, err := GetFileSystemInfo(file)
+	assert.Nil(t, err)
+	assert.Equal(t, info.Type, fileInfo.Type)
+	assert.Equal(t, info.CaseSensitive, fileInfo.CaseSensitive)
+
+	entries, err := os.ReadDir(root)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(entries))
+
+	_, err = GetFileSystemInfo(filepath.Join(root, "missing"))
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,56 @@
+//go:build windows
+
+package fileutils
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	kernel32                  = syscall.NewLazyDLL("kernel32.dll")
+	procGetVolumePathNameW    = kernel32.NewProc("GetVolumePathNameW")
+	procGetVolumeInformationW = kernel32.NewProc("GetVolumeInformationW")
+	procGetDriveTypeW         = kernel32.NewProc("GetDriveTypeW")
+)
+
+const driveRemote = 4 // DRIVE_REMOTE
+
+func getFileSystemType(dir string) (*FileSystemInfo, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	path, err := syscall.UTF16PtrFromString(abs)
+	if err != nil {
+		return nil, err
+	}
+
+	volume := make([]uint16, syscall.MAX_PATH+1)
+	if ok, _, err := procGetVolumePathNameW.Call(
+		uintptr(unsafe.Pointer(path)), uintptr(unsafe.Pointer(&volume[0])), uintptr(len(volume)),
+	); ok == 0 {
+		return nil, &os.PathError{Op: "GetVolumePathName", Path: dir, Err: err}
+	}
+
+	var maxComponent, flags uint32
+	fsName := make([]uint16, syscall.MAX_PATH+1)
+	if ok, _, err := procGetVolumeInformationW.Call(
+		uintptr(unsafe.Pointer(&volume[0])), 0, 0, 0,
+		uintptr(unsafe.Pointer(&maxComponent)), uintptr(unsafe.Pointer(&flags)),
+		uintptr(unsafe.Pointer(&fsName[0])), uintptr(len(fsName)),
+	); ok == 0 {
+		return nil, &os.PathError{Op: "GetVolumeInformation", Path: dir, Err: err}
+	}
+
+	driveType, _, _ := procGetDriveTypeW.Call(uintptr(unsafe.Pointer(&volume[0])))
+
+	return &FileSystemInfo{
+		Type:          syscall.UTF16ToString(fsName),
+		IsNetwork:     driveType == driveRemote,
+		MaxNameLength: int(maxComponent),
+		MaxPathLength: 32767, // 使用 \\?\ 前缀的长路径。
+	}, nil
+}
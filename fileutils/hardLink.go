@@ -0,0 +1,34 @@
+package fileutils
+
+import "os"
+
+// fileID 唯一标识一个文件。指向同一文件的多个硬链接具有相同的 fileID。
+type fileID struct {
+	device uint64
+	inode  uint64
+}
+
+// hardLinkTracker 记录遍历过程中已遇到的多链接文件，用于识别同一文件的其它硬链接。
+type hardLinkTracker struct {
+	seen map[fileID]string
+}
+
+func newHardLinkTracker() *hardLinkTracker {
+	return &hardLinkTracker{seen: map[fileID]string{}}
+}
+
+// track 记录文件。如果已遇到过同一文件的其它硬链接，返回第一次遇到时的路径及 true。
+// 只有一个链接或无法获取文件标识时总是返回 false。
+func (t *hardLinkTracker) track(path string, info os.FileInfo) (string, bool) {
+	id, links, ok := getFileID(info)
+	if !ok || links <= 1 {
+		return "", false
+	}
+
+	if first, ok := t.seen[id]; ok {
+		return first, true
+	}
+
+	t.seen[id] = path
+	return "", false
+}
@@ -0,0 +1,10 @@
+//go:build !unix && !windows
+
+package fileutils
+
+import "os"
+
+// getFileID 在其它系统上总是返回 ok 为 false，因为没有可用的设备号及 inode，不识别硬链接。
+func getFileID(info os.FileInfo) (id fileID, links uint64, ok bool) {
+	return fileID{}, 0, false
+}
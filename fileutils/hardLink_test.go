@@ -0,0 +1,79 @@
+package fileutils
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// createHardLinkedDir 创建包含两个互为硬链接的文件及一个普通文件的目录。
+func createHardLinkedDir(t *testing.T) string {
+	if runtime.GOOS == "windows" {
+		t.Skip("hard links are not tracked on Windows")
+	}
+
+	root := t.TempDir()
+	assert.Nil(t, os.MkdirAll(filepath.Join(root, "sub"), 0755))
+	assert.Nil(t, os.WriteFile(filepath.Join(root, "a.txt"), []byte("0123456789"), 0644))
+	assert.Nil(t, os.Link(filepath.Join(root, "a.txt"), filepath.Join(root, "sub", "b.txt")))
+	assert.Nil(t, os.WriteFile(filepath.Join(root, "c.txt"), []byte("abc"), 0644))
+	return root
+}
+
+func TestGetDirStatisticsHardLinks(t *testing.T) {
+	root := createHardLinkedDir(t)
+
+	stat, err := GetDirStatistics(root, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, stat.FileCount)
+	assert.Equal(t, int64(23), stat.TotalSize)
+	assert.Equal(t, int64(13), stat.UniqueSize)
+	assert.Equal(t, 1, stat.LinkCount)
+}
+
+func TestCopyDirWithOptionPreserveHardLinks(t *testing.T) {
+	source := createHardLinkedDir(t)
+	target := filepath.Join(t.TempDir(), "target")
+
+	assert.Nil(t, CopyDirWithOption(source, target, nil))
+
+	a, err := os.Stat(filepath.Join(target, "a.txt"))
+	assert.Nil(t, err)
+	b, err := os.Stat(filepath.Join(target, "sub", "b.txt"))
+	assert.Nil(t, err)
+	assert.True(t, os.SameFile(a, b))
+
+	data, err := os.ReadFile(filepath.Join(target, "sub", "b.txt"))
+	assert.Nil(t, err)
+	assert.Equal(t, "0123456789", string(data))
+
+	stat, err := GetDirStatistics(target, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(13), stat.UniqueSize)
+}
+
+func TestCopyDirWithOptionDuplicateHardLinks(t *testing.T) {
+	source := createHardLinkedDir(t)
+	target := filepath.Join(t.TempDir(), "target")
+
+	option := NewCopyDirOption()
+	option.PreserveHardLinks = false
+	assert.Nil(t, CopyDirWithOption(source, target, option))
+
+	a, err := os.Stat(filepath.Join(target, "a.txt"))
+	assert.Nil(t, err)
+	b, err := os.Stat(filepath.Join(target, "sub", "b.txt"))
+	assert.Nil(t, err)
+	assert.False(t, os.SameFile(a, b))
+
+	// CopyDir 总是复制内容。
+	target = filepath.Join(t.TempDir(), "plain")
+	assert.Nil(t, CopyDir(source, target, nil))
+	stat, err := GetDirStatistics(target, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, stat.LinkCount)
+	assert.Equal(t, int64(23), stat.UniqueSize)
+}
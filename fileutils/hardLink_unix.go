@@ -0,0 +1,17 @@
+//go:build unix
+
+package fileutils
+
+import (
+	"os"
+	"syscall"
+)
+
+// getFileID 返回文件的设备号、inode 及链接数。
+func getFileID(info os.FileInfo) (id fileID, links uint64, ok bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return fileID{}, 0, false
+	}
+	return fileID{device: uint64(stat.Dev), inode: uint64(stat.Ino)}, uint64(stat.Nlink), true
+}
@@ -0,0 +1,10 @@
+//go:build windows
+
+package fileutils
+
+import "os"
+
+// getFileID 在 Windows 上总是返回 ok 为 false。文件索引需要打开文件才能获取，遍历时代价过高，因此不识别硬链接。
+func getFileID(info os.FileInfo) (id fileID, links uint64, ok bool) {
+	return fileID{}, 0, false
+}
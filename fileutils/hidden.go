@@ -0,0 +1,107 @@
+package fileutils
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+/*
+IsHidden checks whether the file or directory is hidden.
+On Unix, a name beginning with "." is hidden. On Windows, the hidden attribute is checked.
+
+Parameters:
+  - path: the file or directory.
+
+Returns:
+  - true if it is hidden.
+  - an error if any occurred.
+
+IsHidden 检查文件或目录是否隐藏。在 Unix 上，以 "." 开头的名称为隐藏。在 Windows 上，检查隐藏属性。
+
+参数:
+  - path: 文件或目录。
+
+返回:
+  - 隐藏时为 true。
+  - 错误信息。
+*/
+func IsHidden(path string) (bool, error) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return false, err
+	}
+	return IsHiddenInfo(info), nil
+}
+
+/*
+IsHiddenInfo is like [IsHidden], but uses the file info already obtained, such as the one passed to walker callbacks.
+No system call is made.
+
+IsHiddenInfo 与 [IsHidden] 相同，但使用已获得的文件信息，例如遍历回调函数得到的信息。不进行系统调用。
+*/
+func IsHiddenInfo(info os.FileInfo) bool {
+	return isHiddenInfo(info)
+}
+
+/*
+SetHidden hides or unhides the file or directory.
+On Unix, it is renamed to add or remove the leading ".", and the new path is returned.
+On Windows, the hidden attribute is set or cleared, and the path is not changed.
+
+Parameters:
+  - path: the file or directory.
+  - hidden: true to hide, false to unhide.
+
+Returns:
+  - the path of the file or directory after the change.
+  - an error if any occurred. On Unix, it is an error if the new name already exists.
+
+SetHidden 隐藏或取消隐藏文件或目录。
+在 Unix 上，通过改名添加或去除开头的 "."，并返回新的路径。在 Windows 上，设置或清除隐藏属性，路径不变。
+
+参数:
+  - path: 文件或目录。
+  - hidden: true 表示隐藏，false 表示取消隐藏。
+
+返回:
+  - 修改后文件或目录的路径。
+  - 错误信息。在 Unix 上，新的名称已存在时返回错误。
+*/
+func SetHidden(path string, hidden bool) (string, error) {
+	return setHidden(path, hidden)
+}
+
+// isDotName 检查名称是否以 "." 开头。"." 及 ".." 不算。
+func isDotName(name string) bool {
+	return strings.HasPrefix(name, ".") && name != "." && name != ".."
+}
+
+// renameDotName 通过改名添加或去除开头的 "."。
+func renameDotName(path string, hidden bool) (string, error) {
+	if _, err := os.Lstat(path); err != nil {
+		return path, err
+	}
+
+	dir, name := filepath.Split(filepath.Clean(path))
+	if isDotName(name) == hidden {
+		return path, nil
+	}
+
+	newName := "." + name
+	if !hidden {
+		newName = strings.TrimLeft(name, ".")
+		if newName == "" {
+			return path, &os.PathError{Op: "unhide", Path: path, Err: os.ErrInvalid}
+		}
+	}
+
+	newPath := filepath.Join(dir, newName)
+	if _, err := os.Lstat(newPath); err == nil {
+		return path, &os.PathError{Op: "rename", Path: newPath, Err: os.ErrExist}
+	}
+	if err := os.Rename(path, newPath); err != nil {
+		return path, err
+	}
+	return newPath, nil
+}
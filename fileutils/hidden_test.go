@@ -0,0 +1,99 @@
+package fileutils
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetHidden(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "file.txt")
+	assert.Nil(t, os.WriteFile(path, []byte("hello"), 0644))
+
+	hidden, err := IsHidden(path)
+	assert.Nil(t, err)
+	assert.False(t, hidden)
+
+	hiddenPath, err := SetHidden(path, true)
+	assert.Nil(t, err)
+	hidden, err = IsHidden(hiddenPath)
+	assert.Nil(t, err)
+	assert.True(t, hidden)
+
+	// 已隐藏时不变。
+	samePath, err := SetHidden(hiddenPath, true)
+	assert.Nil(t, err)
+	assert.Equal(t, hiddenPath, samePath)
+
+	visiblePath, err := SetHidden(hiddenPath, false)
+	assert.Nil(t, err)
+	assert.Equal(t, path, visiblePath)
+	hidden, err = IsHidden(visiblePath)
+	assert.Nil(t, err)
+	assert.False(t, hidden)
+
+	_, err = IsHidden(filepath.Join(root, "missing"))
+	assert.True(t, os.IsNotExist(err))
+	_, err = SetHidden(filepath.Join(root, "missing"), true)
+	assert.NotNil(t, err)
+}
+
+func TestSetHiddenDotName(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("dot names are not hidden on Windows")
+	}
+
+	root := t.TempDir()
+	path := filepath.Join(root, "file.txt")
+	assert.Nil(t, os.WriteFile(path, []byte("hello"), 0644))
+	assert.Nil(t, os.WriteFile(filepath.Join(root, ".file.txt"), []byte("exists"), 0644))
+
+	newPath, err := SetHidden(path, true)
+	assert.ErrorIs(t, err, os.ErrExist)
+	assert.Equal(t, path, newPath)
+
+	assert.False(t, isDotName("."))
+	assert.False(t, isDotName(".."))
+	assert.True(t, isDotName(".git"))
+}
+
+func TestFilterExcludeHidden(t *testing.T) {
+	root := t.TempDir()
+	visibleDir := filepath.Join(root, "dir")
+	assert.Nil(t, os.MkdirAll(visibleDir, 0755))
+	for _, path := range []string{filepath.Join(root, "a.txt"), filepath.Join(root, "b.txt"), filepath.Join(visibleDir, "c.txt")} {
+		assert.Nil(t, os.WriteFile(path, []byte("x"), 0644))
+	}
+
+	_, err := SetHidden(filepath.Join(root, "b.txt"), true)
+	assert.Nil(t, err)
+	hiddenDir, err := SetHidden(visibleDir, true)
+	assert.Nil(t, err)
+
+	filter := &Filter{Include: []string{"*"}}
+	files, err := filter.GetFiles(root, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(files))
+
+	filter.ExcludeHidden = true
+	files, err = filter.GetFiles(root, nil)
+	assert.Nil(t, err)
+	sort.Strings(files)
+	assert.Equal(t, []string{filepath.Join(root, "a.txt")}, files)
+
+	// 隐藏目录作为根目录时仍然扫描。
+	files, err = filter.GetFiles(hiddenDir, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(files))
+
+	info, err := os.Lstat(hiddenDir)
+	assert.Nil(t, err)
+	assert.True(t, IsHiddenInfo(info))
+	assert.True(t, IsRefusedReason(ErrReasonHidden))
+	assert.Equal(t, "Filter.ExcludeHidden", filter.Diff(&Filter{Include: []string{"*"}}))
+}
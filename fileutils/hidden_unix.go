@@ -0,0 +1,13 @@
+//go:build !windows
+
+package fileutils
+
+import "os"
+
+func isHiddenInfo(info os.FileInfo) bool {
+	return isDotName(info.Name())
+}
+
+func setHidden(path string, hidden bool) (string, error) {
+	return renameDotName(path, hidden)
+}
@@ -0,0 +1,38 @@
+//go:build windows
+
+package fileutils
+
+import (
+	"os"
+	"syscall"
+)
+
+func isHiddenInfo(info os.FileInfo) bool {
+	if data, ok := info.Sys().(*syscall.Win32FileAttributeData); ok {
+		return data.FileAttributes&syscall.FILE_ATTRIBUTE_HIDDEN != 0
+	}
+	return false
+}
+
+func setHidden(path string, hidden bool) (string, error) {
+	name, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return path, err
+	}
+
+	attributes, err := syscall.GetFileAttributes(name)
+	if err != nil {
+		return path, &os.PathError{Op: "GetFileAttributes", Path: path, Err: err}
+	}
+
+	if hidden {
+		attributes |= syscall.FILE_ATTRIBUTE_HIDDEN
+	} else {
+		attributes &^= syscall.FILE_ATTRIBUTE_HIDDEN
+	}
+
+	if err = syscall.SetFileAttributes(name, attributes); err != nil {
+		return path, &os.PathError{Op: "SetFileAttributes", Path: path, Err: err}
+	}
+	return path, nil
+}
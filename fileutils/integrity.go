@@ -0,0 +1,276 @@
+package fileutils
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+)
+
+/*
+IntegrityReport describes the differences between a directory and its baseline [Manifest].
+All paths are relative to the directory and sorted.
+
+IntegrityReport 描述了目录与其基线 [Manifest] 的差异。所有路径都是相对于该目录的，已排序。
+*/
+type IntegrityReport struct {
+	New       []string // Files not in the baseline.
+	Deleted   []string // Files in the baseline but no longer exist.
+	Modified  []string // Files whose size or modification time changed.
+	Corrupted []string // Files with same size and modification time but different checksum.
+	Unchanged []string // Files with same size, modification time and checksum.
+}
+
+/*
+IsClean returns true if no file is new, deleted, modified or corrupted.
+
+IsClean 在没有新增、删除、修改或损坏的文件时返回 true。
+*/
+func (r *IntegrityReport) IsClean() bool {
+	return len(r.New) == 0 && len(r.Deleted) == 0 && len(r.Modified) == 0 && len(r.Corrupted) == 0
+}
+
+/*
+IntegrityOption defines the options for [CreateBaseline] and [ScrubBaseline].
+See [NewIntegrityOption] for default settings.
+
+IntegrityOption 定义了 [CreateBaseline] 及 [ScrubBaseline] 的选项。默认设置参见 [NewIntegrityOption]。
+*/
+type IntegrityOption struct {
+	WalkOption
+	// whether to recalculate the checksum of files whose size and modification time have not changed,
+	// which is the only way to find silent corruption. If false, such files are trusted and reported as unchanged
+	Scrub bool
+	// whether to use the checksum stored in the extended attributes by [SetChecksumAttr] when its method and
+	// modification time match the file, and store the calculated checksums there for later use.
+	// It is ignored if extended attributes are not supported
+	UseChecksumAttr bool
+}
+
+/*
+NewIntegrityOption creates a new IntegrityOption with scan directory recursively,
+bypass permission denied error, scrub unchanged files and use the checksum stored in extended attributes.
+
+NewIntegrityOption 创建默认的 IntegrityOption。包含递归扫描目录、跳过没有权限的文件及目录、
+重新计算未变化的文件，以及使用扩展属性中保存的校验值。
+*/
+func NewIntegrityOption() *IntegrityOption {
+	return &IntegrityOption{
+		WalkOption: WalkOption{
+			Recursive:        true,
+			PathErrorHandler: SkipPermissionError,
+		},
+		Scrub:           true,
+		UseChecksumAttr: true,
+	}
+}
+
+/*
+CreateBaseline creates the baseline manifest of a directory like [CreateManifest].
+With option.UseChecksumAttr, the checksum stored in the extended attributes is used instead of reading the file
+if it is still valid, and the calculated checksums are stored, so later scrubs can reuse them.
+
+Parameters:
+  - root: the directory to scan.
+  - filter: the filter condition. nil means all files.
+  - option: the options. if nil, the default options will be used.
+  - buffer: buffer for reading files.
+  - provider: the object that performs the checksum calculation, cannot be nil.
+
+Returns:
+  - the manifest.
+  - an error if any occurred.
+
+CreateBaseline 与 [CreateManifest] 一样创建目录的基线文件清单。
+option.UseChecksumAttr 为 true 时，扩展属性中保存的校验值仍然有效就直接使用，不读取文件，并保存计算出的校验值，以便以后检查时使用。
+
+参数:
+  - root: 要扫描的目录。
+  - filter: 过滤条件。nil 表示所有文件。
+  - option: 选项。如果为 nil 则使用默认选项。
+  - buffer: 读取文件的缓冲区。
+  - provider: 执行校验和计算的对象，不能为 nil。
+
+返回:
+  - 文件清单。
+  - 错误信息。
+*/
+func CreateBaseline(
+	root string,
+	filter *Filter,
+	option *IntegrityOption,
+	buffer []byte,
+	provider FileChecksumCalculationProvider,
+) (*Manifest, error) {
+	if option == nil { // 保证 option 不为 nil。
+		option = NewIntegrityOption()
+	}
+	if provider == nil {
+		return nil, errors.New("provider must not be nil")
+	}
+
+	manifest := NewManifest(provider.Method())
+	err := walkFilteredFiles(root, filter, &option.WalkOption, func(relPath string, path string, info os.FileInfo) error {
+		entry, err := newBaselineEntry(relPath, path, info, buffer, provider, option.UseChecksumAttr)
+		if err != nil {
+			return err
+		}
+
+		manifest.Entries[relPath] = entry
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+/*
+ScrubBaseline rescans the directory and compares it with the baseline.
+Files whose size or modification time changed are reported as modified.
+With option.Scrub, the other files are read again to find those whose content changed silently.
+With option.UseChecksumAttr, the checksum of new and modified files is taken from the extended attributes if it is still valid.
+
+Parameters:
+  - root: the directory to scan.
+  - baseline: the baseline manifest created by [CreateBaseline]. Cannot be nil.
+  - filter: the filter condition. It should be the same as the one used to create the baseline. nil means all files.
+  - option: the options. if nil, the default options will be used.
+  - buffer: buffer for reading files.
+  - provider: the object that performs the checksum calculation, cannot be nil.
+    Its method must be the same as the baseline.
+
+Returns:
+  - the integrity report.
+  - the new baseline reflecting the current state. Entries of corrupted files keep their baseline values,
+    so they are reported again in the next scrub.
+  - an error if any occurred.
+
+ScrubBaseline 重新扫描目录，并与基线比较。大小或修改时间变化的文件报告为已修改。
+option.Scrub 为 true 时，重新读取其余的文件，找出内容在不知不觉中变化的文件。
+option.UseChecksumAttr 为 true 时，新增及修改的文件在扩展属性中的校验值仍然有效就直接使用。
+
+参数:
+  - root: 要扫描的目录。
+  - baseline: 由 [CreateBaseline] 创建的基线文件清单。不能为 nil。
+  - filter: 过滤条件。应与创建基线时相同。nil 表示所有文件。
+  - option: 选项。如果为 nil 则使用默认选项。
+  - buffer: 读取文件的缓冲区。
+  - provider: 执行校验和计算的对象，不能为 nil。其算法必须与基线相同。
+
+返回:
+  - 完整性报告。
+  - 反映当前状态的新基线。损坏文件的条目保留原基线的值，以便下次检查时再次报告。
+  - 错误信息。
+*/
+func ScrubBaseline(
+	root string,
+	baseline *Manifest,
+	filter *Filter,
+	option *IntegrityOption,
+	buffer []byte,
+	provider FileChecksumCalculationProvider,
+) (*IntegrityReport, *Manifest, error) {
+	if option == nil { // 保证 option 不为 nil。
+		option = NewIntegrityOption()
+	}
+	if baseline == nil {
+		return nil, nil, errors.New("baseline must not be nil")
+	} else if provider == nil {
+		return nil, nil, errors.New("provider must not be nil")
+	} else if baseline.Method != provider.Method() {
+		return nil, nil, fmt.Errorf("method mismatch: baseline is %s, provider is %s", baseline.Method, provider.Method())
+	}
+
+	report := &IntegrityReport{}
+	current := NewManifest(baseline.Method)
+
+	err := walkFilteredFiles(root, filter, &option.WalkOption, func(relPath string, path string, info os.FileInfo) error {
+		old, ok := baseline.Entries[relPath]
+		changed := !ok || old.Size != info.Size() || !old.ModTime.Equal(info.ModTime())
+
+		if !changed && !option.Scrub {
+			// 不重新读取未变化的文件，沿用基线中的值。
+			current.Entries[relPath] = old
+			report.Unchanged = append(report.Unchanged, relPath)
+			return nil
+		}
+
+		// 未变化的文件必须重新读取，扩展属性中的校验值与基线一样可能已经过时。
+		entry, err := newBaselineEntry(relPath, path, info, buffer, provider, changed && option.UseChecksumAttr)
+		if err != nil {
+			return err
+		}
+
+		current.Entries[relPath] = entry
+		if !ok {
+			report.New = append(report.New, relPath)
+		} else if changed {
+			report.Modified = append(report.Modified, relPath)
+		} else if old.Checksum != entry.Checksum {
+			// 大小和修改时间都没变，内容却变了，说明文件已损坏。
+			report.Corrupted = append(report.Corrupted, relPath)
+			current.Entries[relPath] = old
+		} else {
+			report.Unchanged = append(report.Unchanged, relPath)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for relPath := range baseline.Entries {
+		if _, ok := current.Entries[relPath]; !ok {
+			report.Deleted = append(report.Deleted, relPath)
+		}
+	}
+
+	// 遍历顺序已是有序的，但 Deleted 来自 map，需要排序。其余也一并排序，以保证结果稳定。
+	for _, paths := range [][]string{report.New, report.Deleted, report.Modified, report.Corrupted, report.Unchanged} {
+		sort.Strings(paths)
+	}
+
+	return report, current, nil
+}
+
+// newBaselineEntry 创建文件的清单条目。useAttr 为 true 时，优先使用扩展属性中算法及修改时间都相符的校验值，
+// 否则计算校验值并保存到扩展属性中。扩展属性只是缓存，读写失败时都忽略，例如不支持扩展属性或文件只读。
+func newBaselineEntry(
+	relPath string,
+	path string,
+	info os.FileInfo,
+	buffer []byte,
+	provider FileChecksumCalculationProvider,
+	useAttr bool,
+) (*ManifestEntry, error) {
+	if !useAttr {
+		return newManifestEntry(relPath, path, info, buffer, provider)
+	}
+
+	if attr, err := GetChecksumAttr(path); err == nil && attr.Method == provider.Method() && attr.ModTime.Equal(info.ModTime()) {
+		return &ManifestEntry{
+			Path:     relPath,
+			Size:     info.Size(),
+			ModTime:  info.ModTime(),
+			Checksum: hex.EncodeToString(attr.Checksum),
+		}, nil
+	}
+
+	entry, err := newManifestEntry(relPath, path, info, buffer, provider)
+	if err != nil {
+		return nil, err
+	}
+
+	SetChecksumAttr(path, &ChecksumAttr{
+		Method:   provider.Method(),
+		Checksum: provider.FullChecksum(),
+		ModTime:  info.ModTime(),
+	})
+	return entry, nil
+}
@@ -0,0 +1,128 @@
+package fileutils
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScrubBaseline(t *testing.T) {
+	root := t.TempDir()
+	assert.Nil(t, CopyDir("../test-data/fileutils/extension", root, nil))
+
+	buffer := make([]byte, 1024)
+	p := NewCommonFileChecksumProvider("MD5", md5.New())
+
+	baseline, err := CreateBaseline(root, nil, nil, buffer, p)
+	assert.Nil(t, err)
+	assert.Equal(t, 9, len(baseline.Entries))
+	assert.NotNil(t, baseline.Entries["sub1/011.TXT"])
+
+	// 保存后再读取，内容不变。
+	manifestFile := filepath.Join(t.TempDir(), "baseline.json")
+	assert.Nil(t, baseline.Save(manifestFile))
+	baseline, err = LoadManifest(manifestFile)
+	assert.Nil(t, err)
+	assert.Equal(t, 9, len(baseline.Entries))
+
+	report, _, err := ScrubBaseline(root, baseline, nil, nil, buffer, p)
+	assert.Nil(t, err)
+	assert.True(t, report.IsClean())
+	assert.Equal(t, 9, len(report.Unchanged))
+
+	// 新增、删除、修改及损坏各一个文件。
+	assert.Nil(t, os.WriteFile(filepath.Join(root, "new.txt"), []byte("new"), 0644))
+	assert.Nil(t, os.Remove(filepath.Join(root, "001.MD")))
+	assert.Nil(t, os.WriteFile(filepath.Join(root, "sub2", "022.txt"), []byte("modified"), 0644))
+
+	corrupted := filepath.Join(root, "sub1", "011.TXT")
+	info, err := os.Stat(corrupted)
+	assert.Nil(t, err)
+	data, err := os.ReadFile(corrupted)
+	assert.Nil(t, err)
+	data[0] ^= 0xff
+	assert.Nil(t, os.WriteFile(corrupted, data, 0644))
+	assert.Nil(t, os.Chtimes(corrupted, info.ModTime(), info.ModTime()))
+
+	report, current, err := ScrubBaseline(root, baseline, nil, nil, buffer, p)
+	assert.Nil(t, err)
+	assert.False(t, report.IsClean())
+	assert.Equal(t, []string{"new.txt"}, report.New)
+	assert.Equal(t, []string{"001.MD"}, report.Deleted)
+	assert.Equal(t, []string{"sub2/022.txt"}, report.Modified)
+	assert.Equal(t, []string{"sub1/011.TXT"}, report.Corrupted)
+	assert.Equal(t, 6, len(report.Unchanged))
+
+	// 损坏的文件在新基线中保留原值，仍会被报告。
+	report, _, err = ScrubBaseline(root, current, nil, nil, buffer, p)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"sub1/011.TXT"}, report.Corrupted)
+	assert.Equal(t, 0, len(report.New))
+
+	// 不重新读取未变化的文件时，发现不了损坏，但仍报告其它变化。
+	option := NewIntegrityOption()
+	option.Scrub = false
+	report, _, err = ScrubBaseline(root, baseline, nil, option, buffer, p)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(report.Corrupted))
+	assert.Equal(t, []string{"sub2/022.txt"}, report.Modified)
+	assert.Equal(t, 7, len(report.Unchanged))
+
+	// 算法不一致。
+	_, _, err = ScrubBaseline(root, baseline, nil, nil, buffer, NewCommonFileChecksumProvider("crc32", crc32.NewIEEE()))
+	assert.NotNil(t, err)
+}
+
+func TestBaselineChecksumAttr(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "data.txt")
+	assert.Nil(t, os.WriteFile(path, []byte("hello world"), 0644))
+	if _, err := GetChecksumAttr(path); err == ErrXattrNotSupported {
+		t.Skip("extended attributes are not supported")
+	}
+
+	buffer := make([]byte, 1024)
+	p := NewCommonFileChecksumProvider("MD5", md5.New())
+
+	// 创建基线时保存校验值。
+	baseline, err := CreateBaseline(root, nil, nil, buffer, p)
+	assert.Nil(t, err)
+	attr, err := GetChecksumAttr(path)
+	assert.Nil(t, err)
+	assert.Equal(t, baseline.Entries["data.txt"].Checksum, hex.EncodeToString(attr.Checksum))
+
+	// 修改后的文件使用扩展属性中仍然有效的校验值，而不读取文件。
+	assert.Nil(t, os.WriteFile(path, []byte("changed"), 0644))
+	info, err := os.Stat(path)
+	assert.Nil(t, err)
+	cached := &ChecksumAttr{Method: "MD5", Checksum: []byte{1, 2, 3}, ModTime: info.ModTime()}
+	assert.Nil(t, SetChecksumAttr(path, cached))
+
+	report, current, err := ScrubBaseline(root, baseline, nil, nil, buffer, p)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"data.txt"}, report.Modified)
+	assert.Equal(t, "010203", current.Entries["data.txt"].Checksum)
+
+	// 不使用扩展属性时重新计算。
+	option := NewIntegrityOption()
+	option.UseChecksumAttr = false
+	_, current, err = ScrubBaseline(root, baseline, nil, option, buffer, p)
+	assert.Nil(t, err)
+	sum := md5.Sum([]byte("changed"))
+	assert.Equal(t, hex.EncodeToString(sum[:]), current.Entries["data.txt"].Checksum)
+
+	// 过时的校验值不被使用，重新计算后更新。
+	assert.Nil(t, os.Chtimes(path, time.Now(), info.ModTime().Add(time.Second)))
+	_, current, err = ScrubBaseline(root, baseline, nil, nil, buffer, p)
+	assert.Nil(t, err)
+	assert.Equal(t, hex.EncodeToString(sum[:]), current.Entries["data.txt"].Checksum)
+	attr, err = GetChecksumAttr(path)
+	assert.Nil(t, err)
+	assert.Equal(t, sum[:], attr.Checksum)
+}
@@ -0,0 +1,233 @@
+package fileutils
+
+import (
+	"bytes"
+	"errors"
+	"os"
+)
+
+/*
+LineEnding is the line ending characters.
+
+LineEnding 是换行符。
+*/
+type LineEnding string
+
+const (
+	LineEndingLF   LineEnding = "\n"   // Unix
+	LineEndingCRLF LineEnding = "\r\n" // Windows
+)
+
+/*
+LineEndingStats is the number of each kind of line ending in the content.
+
+LineEndingStats 是内容中每种换行符的数量。
+*/
+type LineEndingStats struct {
+	LF   int // The number of "\n" not preceded by "\r".
+	CRLF int // The number of "\r\n".
+	CR   int // The number of "\r" not followed by "\n", used by classic Mac OS.
+}
+
+/*
+IsMixed returns true if more than one kind of line ending is used.
+
+IsMixed 在使用了多于一种换行符时返回 true。
+*/
+func (s *LineEndingStats) IsMixed() bool {
+	kinds := 0
+	for _, count := range []int{s.LF, s.CRLF, s.CR} {
+		if count > 0 {
+			kinds++
+		}
+	}
+	return kinds > 1
+}
+
+/*
+Dominant returns the most used line ending between LF and CRLF. CR is counted as LF.
+LF is returned if there is no line ending or they are equal.
+
+Dominant 返回 LF 与 CRLF 中使用最多的换行符。CR 按 LF 计算。没有换行符或者两者数量相等时返回 LF。
+*/
+func (s *LineEndingStats) Dominant() LineEnding {
+	if s.CRLF > s.LF+s.CR {
+		return LineEndingCRLF
+	}
+	return LineEndingLF
+}
+
+// changes 返回转换为给定换行符时需要修改的换行符数量。
+func (s *LineEndingStats) changes(to LineEnding) int {
+	if to == LineEndingCRLF {
+		return s.LF + s.CR
+	}
+	return s.CRLF + s.CR
+}
+
+/*
+CountLineEndings counts each kind of line ending in the data.
+
+CountLineEndings 统计数据中每种换行符的数量。
+*/
+func CountLineEndings(data []byte) LineEndingStats {
+	stats := LineEndingStats{}
+
+	for i := 0; i < len(data); i++ {
+		switch data[i] {
+		case '\n':
+			stats.LF++
+		case '\r':
+			if i+1 < len(data) && data[i+1] == '\n' {
+				stats.CRLF++
+				i++
+			} else {
+				stats.CR++
+			}
+		}
+	}
+
+	return stats
+}
+
+/*
+ConvertLineEndings converts all line endings, including CR, in the data to the given one.
+
+ConvertLineEndings 将数据中的所有换行符，包括 CR，转换为给定的换行符。
+*/
+func ConvertLineEndings(data []byte, to LineEnding) []byte {
+	result := bytes.Buffer{}
+	result.Grow(len(data))
+
+	for i := 0; i < len(data); i++ {
+		switch data[i] {
+		case '\n':
+			result.WriteString(string(to))
+		case '\r':
+			if i+1 < len(data) && data[i+1] == '\n' {
+				i++
+			}
+			result.WriteString(string(to))
+		default:
+			result.WriteByte(data[i])
+		}
+	}
+
+	return result.Bytes()
+}
+
+/*
+LineEndingOption defines the options for [NormalizeLineEndings].
+See [NewLineEndingOption] for default settings.
+
+LineEndingOption 定义了 [NormalizeLineEndings] 的选项。默认设置参见 [NewLineEndingOption]。
+*/
+type LineEndingOption struct {
+	WalkOption
+	DryRun bool // if true, only report the statistics without modifying any file
+}
+
+/*
+NewLineEndingOption creates a new LineEndingOption with scan directory recursively,
+bypass permission denied error and no dry run.
+
+NewLineEndingOption 创建默认的 LineEndingOption。包含递归扫描目录、跳过没有权限的文件及目录，以及实际执行转换。
+*/
+func NewLineEndingOption() *LineEndingOption {
+	return &LineEndingOption{
+		WalkOption: WalkOption{
+			Recursive:        true,
+			PathErrorHandler: SkipPermissionError,
+		},
+		DryRun: false,
+	}
+}
+
+/*
+LineEndingReport is the result of [NormalizeLineEndings].
+
+LineEndingReport 是 [NormalizeLineEndings] 的结果。
+*/
+type LineEndingReport struct {
+	Converted    map[string]LineEndingStats // The converted files and their line endings before conversion.
+	Unchanged    []string                   // The files already using the target line ending.
+	SkippedFiles []string                   // The binary files skipped.
+	Changes      int                        // The total number of line endings changed.
+}
+
+/*
+NormalizeLineEndings converts the line endings of text files under the given directory to the given one in place.
+Binary files are skipped. See [IsBinaryContent]. Changed files are written atomically.
+
+Parameters:
+  - root: the directory to scan.
+  - filter: only files matching the filter are processed. nil means all files.
+  - to: the target line ending. [LineEndingLF] or [LineEndingCRLF].
+  - option: the options. if nil, the default options will be used.
+
+Returns:
+  - the report. Partial report is returned along with the error.
+  - an error if any occurred.
+
+NormalizeLineEndings 将给定目录下文本文件的换行符就地转换为给定的换行符。跳过二进制文件，参见 [IsBinaryContent]。
+修改后的文件以原子方式写入。
+
+参数:
+  - root: 要扫描的目录。
+  - filter: 只处理满足过滤条件的文件。nil 表示所有文件。
+  - to: 目标换行符。[LineEndingLF] 或 [LineEndingCRLF]。
+  - option: 选项。如果为 nil 则使用默认选项。
+
+返回:
+  - 转换报告。出错时返回已完成的部分报告。
+  - 错误信息。
+*/
+func NormalizeLineEndings(root string, filter *Filter, to LineEnding, option *LineEndingOption) (*LineEndingReport, error) {
+	report := &LineEndingReport{
+		Converted:    map[string]LineEndingStats{},
+		Unchanged:    []string{},
+		SkippedFiles: []string{},
+	}
+
+	if to != LineEndingLF && to != LineEndingCRLF {
+		return report, errors.New("line ending must be LineEndingLF or LineEndingCRLF")
+	}
+	if option == nil { // 保证 option 不为 nil。
+		option = NewLineEndingOption()
+	}
+
+	err := walkFilteredFiles(root, filter, &option.WalkOption, func(relPath string, path string, info os.FileInfo) error {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		header := data
+		if len(header) > DefaultSniffSize {
+			header = header[:DefaultSniffSize]
+		}
+		if IsBinaryContent(header) {
+			report.SkippedFiles = append(report.SkippedFiles, path)
+			return nil
+		}
+
+		stats := CountLineEndings(data)
+		changes := stats.changes(to)
+		if changes == 0 {
+			report.Unchanged = append(report.Unchanged, path)
+			return nil
+		}
+
+		if !option.DryRun {
+			if err = writeFileAtomic(path, ConvertLineEndings(data, to)); err != nil {
+				return err
+			}
+		}
+
+		report.Converted[path] = stats
+		report.Changes += changes
+		return nil
+	})
+
+	return report, err
+}
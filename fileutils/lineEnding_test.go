@@ -0,0 +1,66 @@
+package fileutils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLineEndings(t *testing.T) {
+	data := []byte("a\r\nb\nc\rd\r\n")
+	stats := CountLineEndings(data)
+	assert.Equal(t, LineEndingStats{LF: 1, CRLF: 2, CR: 1}, stats)
+	assert.True(t, stats.IsMixed())
+	assert.Equal(t, LineEndingLF, stats.Dominant())
+
+	assert.Equal(t, "a\nb\nc\nd\n", string(ConvertLineEndings(data, LineEndingLF)))
+	assert.Equal(t, "a\r\nb\r\nc\r\nd\r\n", string(ConvertLineEndings(data, LineEndingCRLF)))
+
+	stats = CountLineEndings([]byte("a\r\nb\r\nc"))
+	assert.False(t, stats.IsMixed())
+	assert.Equal(t, LineEndingCRLF, stats.Dominant())
+}
+
+func TestNormalizeLineEndings(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"crlf.txt":  "a\r\nb\r\n",
+		"mixed.txt": "a\r\nb\nc\r",
+		"lf.txt":    "a\nb\n",
+		"bin.dat":   "a\r\n\x00",
+	}
+	for name, data := range files {
+		assert.Nil(t, os.WriteFile(filepath.Join(root, name), []byte(data), 0644))
+	}
+
+	option := NewLineEndingOption()
+	option.DryRun = true
+	report, err := NormalizeLineEndings(root, nil, LineEndingLF, option)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, report.Changes)
+	assert.Equal(t, LineEndingStats{LF: 1, CRLF: 1, CR: 1}, report.Converted[filepath.Join(root, "mixed.txt")])
+	assert.Equal(t, 2, len(report.Converted))
+	assert.Equal(t, []string{filepath.Join(root, "lf.txt")}, report.Unchanged)
+	assert.Equal(t, []string{filepath.Join(root, "bin.dat")}, report.SkippedFiles)
+
+	data, _ := os.ReadFile(filepath.Join(root, "crlf.txt"))
+	assert.Equal(t, files["crlf.txt"], string(data))
+
+	option.DryRun = false
+	report, err = NormalizeLineEndings(root, &Filter{Include: []string{"*.txt"}}, LineEndingCRLF, option)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, report.Changes)
+
+	for _, name := range []string{"crlf.txt", "mixed.txt", "lf.txt"} {
+		data, _ = os.ReadFile(filepath.Join(root, name))
+		stats := CountLineEndings(data)
+		assert.Equal(t, 0, stats.LF+stats.CR, name)
+	}
+	data, _ = os.ReadFile(filepath.Join(root, "bin.dat"))
+	assert.Equal(t, files["bin.dat"], string(data))
+
+	_, err = NormalizeLineEndings(root, nil, "\r", option)
+	assert.NotNil(t, err)
+}
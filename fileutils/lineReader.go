@@ -0,0 +1,161 @@
+package fileutils
+
+import (
+	"bufio"
+	"errors"
+	"io"
+)
+
+/*
+ErrLineTooLong is returned by [LineReader] when a line exceeds MaxLineLength.
+
+ErrLineTooLong 在行长度超过 MaxLineLength 时由 [LineReader] 返回。
+*/
+var ErrLineTooLong = errors.New("line too long")
+
+/*
+Line is a line read by [LineReader].
+
+Line 是 [LineReader] 读取的一行。
+*/
+type Line struct {
+	Text   string // The line without line ending.
+	Ending string // The line ending, "\r\n", "\n" or "" for the last line without line ending.
+	Number int    // The line number, starts from 1 at the start offset of the reader.
+	Offset int64  // The byte offset of the line start in the underlying reader.
+}
+
+/*
+LineReader iterates lines of a reader. Unlike bufio.Scanner, lines of any length are supported,
+"\r\n" and "\n" are both recognized, and the byte offset of each line is reported.
+
+Example:
+
+	reader := NewLineReader(file)
+	for reader.Next() {
+		line := reader.Line()
+		fmt.Println(line.Offset, line.Text)
+	}
+	if err := reader.Err(); err != nil {
+		....
+	}
+
+LineReader 逐行读取数据。与 bufio.Scanner 不同，支持任意长度的行，同时识别 "\r\n" 及 "\n"，并报告每行的字节偏移量。
+*/
+type LineReader struct {
+	MaxLineLength int // the maximum length of a line including line ending. 0 means no limit
+
+	reader *bufio.Reader
+	offset int64
+	number int
+	line   Line
+	err    error
+}
+
+/*
+NewLineReader creates a LineReader reading from the current position of reader, which is taken as offset 0.
+
+NewLineReader 创建从 reader 当前位置开始读取的 LineReader，该位置视为偏移量 0。
+*/
+func NewLineReader(reader io.Reader) *LineReader {
+	return &LineReader{reader: bufio.NewReaderSize(reader, 64*1024)}
+}
+
+/*
+NewLineReaderAt creates a LineReader starting from the given byte offset of reader.
+Offsets of lines are relative to the start of reader, and line numbers start from 1 at the offset.
+The offset is usually one previously reported by Line.Offset, so it is the start of a line.
+
+NewLineReaderAt 创建从 reader 给定字节偏移量开始读取的 LineReader。
+行的偏移量相对于 reader 的开头，行号则从该偏移量处的 1 开始。偏移量通常是以前由 Line.Offset 报告的，所以是一行的开始。
+*/
+func NewLineReaderAt(reader io.ReadSeeker, offset int64) (*LineReader, error) {
+	if _, err := reader.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	result := NewLineReader(reader)
+	result.offset = offset
+	return result, nil
+}
+
+/*
+Next reads the next line. It returns false at the end of input or on error. See Err.
+
+Next 读取下一行。到达结尾或出错时返回 false。参见 Err。
+*/
+func (r *LineReader) Next() bool {
+	if r.err != nil {
+		return false
+	}
+
+	var data []byte
+	for {
+		chunk, err := r.reader.ReadSlice('\n')
+		data = append(data, chunk...)
+
+		if r.MaxLineLength > 0 && len(data) > r.MaxLineLength {
+			r.err = ErrLineTooLong
+			return false
+		}
+
+		if err == bufio.ErrBufferFull {
+			continue // 行比缓冲区长，继续读取。
+		} else if err == io.EOF {
+			r.err = io.EOF
+			if len(data) == 0 {
+				return false
+			}
+			break
+		} else if err != nil {
+			r.err = err
+			return false
+		}
+		break
+	}
+
+	text := string(data)
+	ending := ""
+	if n := len(text); n > 0 && text[n-1] == '\n' {
+		ending = "\n"
+		if n > 1 && text[n-2] == '\r' {
+			ending = "\r\n"
+		}
+	}
+
+	r.number++
+	r.line = Line{Text: text[:len(text)-len(ending)], Ending: ending, Number: r.number, Offset: r.offset}
+	r.offset += int64(len(data))
+	return true
+}
+
+/*
+Line returns the line read by the last successful Next.
+
+Line 返回最后一次成功调用 Next 读取的行。
+*/
+func (r *LineReader) Line() Line {
+	return r.line
+}
+
+/*
+Offset returns the byte offset just after the last line read, which is the start of the next line.
+It can be saved and passed to [NewLineReaderAt] to resume reading.
+
+Offset 返回最后读取的行之后的字节偏移量，即下一行的开始。可保存该值并传给 [NewLineReaderAt] 以继续读取。
+*/
+func (r *LineReader) Offset() int64 {
+	return r.offset
+}
+
+/*
+Err returns the first error encountered, or nil at the normal end of input.
+
+Err 返回遇到的第一个错误。正常读到结尾时返回 nil。
+*/
+func (r *LineReader) Err() error {
+	if r.err == io.EOF {
+		return nil
+	}
+	return r.err
+}
@@ -0,0 +1,70 @@
+package fileutils
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLineReader(t *testing.T) {
+	long := strings.Repeat("x", 200*1024)
+	data := "first\r\n" + long + "\n\nlast"
+
+	reader := NewLineReader(strings.NewReader(data))
+	lines := []Line{}
+	for reader.Next() {
+		lines = append(lines, reader.Line())
+	}
+	assert.Nil(t, reader.Err())
+	assert.Equal(t, int64(len(data)), reader.Offset())
+
+	assert.Equal(t, 4, len(lines))
+	assert.Equal(t, Line{Text: "first", Ending: "\r\n", Number: 1, Offset: 0}, lines[0])
+	assert.Equal(t, long, lines[1].Text)
+	assert.Equal(t, int64(7), lines[1].Offset)
+	assert.Equal(t, Line{Text: "", Ending: "\n", Number: 3, Offset: int64(8 + len(long))}, lines[2])
+	assert.Equal(t, Line{Text: "last", Ending: "", Number: 4, Offset: int64(9 + len(long))}, lines[3])
+
+	// 空输入。
+	reader = NewLineReader(strings.NewReader(""))
+	assert.False(t, reader.Next())
+	assert.Nil(t, reader.Err())
+
+	// 超过最大长度。
+	reader = NewLineReader(strings.NewReader(data))
+	reader.MaxLineLength = 1024
+	assert.True(t, reader.Next())
+	assert.False(t, reader.Next())
+	assert.ErrorIs(t, reader.Err(), ErrLineTooLong)
+	assert.False(t, reader.Next())
+}
+
+func TestNewLineReaderAt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.log")
+	assert.Nil(t, os.WriteFile(path, []byte("a\nbb\nccc\n"), 0644))
+
+	file, err := os.Open(path)
+	assert.Nil(t, err)
+	defer file.Close()
+
+	// 读取一行后记录偏移量，再从该处继续读取。
+	reader := NewLineReader(file)
+	assert.True(t, reader.Next())
+	offset := reader.Offset()
+	assert.Equal(t, int64(2), offset)
+
+	reader, err = NewLineReaderAt(file, offset)
+	assert.Nil(t, err)
+	assert.True(t, reader.Next())
+	assert.Equal(t, Line{Text: "bb", Ending: "\n", Number: 1, Offset: 2}, reader.Line())
+	assert.True(t, reader.Next())
+	assert.Equal(t, int64(5), reader.Line().Offset)
+	assert.False(t, reader.Next())
+	assert.Nil(t, reader.Err())
+
+	_, err = NewLineReaderAt(file, -1)
+	assert.NotNil(t, err)
+}
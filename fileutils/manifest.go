@@ -0,0 +1,173 @@
+package fileutils
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"sort"
+	"time"
+)
+
+/*
+ManifestEntry describes a file recorded in a [Manifest].
+
+ManifestEntry 描述了 [Manifest] 中记录的文件信息。
+*/
+type ManifestEntry struct {
+	Path     string    `json:"path"`     // Path relative to the manifest root, slash separated.
+	Size     int64     `json:"size"`     // File size in bytes.
+	ModTime  time.Time `json:"modTime"`  // Modification time of the file.
+	Checksum string    `json:"checksum"` // Hex encoded full checksum of the file.
+}
+
+/*
+Manifest records the size, modification time and checksum of files under a directory.
+
+Manifest 记录了一个目录下文件的大小、修改时间及校验值。
+*/
+type Manifest struct {
+	Method  string                    `json:"method"`  // The digest algorithm name. See [FileChecksumCalculationProvider].
+	Created time.Time                 `json:"created"` // The time when the manifest was created.
+	Entries map[string]*ManifestEntry `json:"entries"` // Entries indexed by ManifestEntry.Path.
+}
+
+/*
+NewManifest creates an empty [Manifest] for the given digest algorithm.
+
+NewManifest 创建给定算法的空 [Manifest]。
+*/
+func NewManifest(method string) *Manifest {
+	return &Manifest{
+		Method:  method,
+		Created: time.Now(),
+		Entries: make(map[string]*ManifestEntry),
+	}
+}
+
+/*
+CreateManifest scans the directory and calculates the full checksum of each file that meets the filter condition.
+
+Parameters:
+  - root: the directory to scan.
+  - filter: the filter condition. nil means all files.
+  - option: the scan options. if nil, the default options will be used.
+  - buffer: buffer for reading files.
+  - provider: the object that performs the checksum calculation, cannot be nil.
+
+Returns:
+  - the manifest.
+  - an error if any occurred.
+
+CreateManifest 扫描目录，计算每个满足过滤条件的文件的完整校验值。
+
+参数:
+  - root: 要扫描的目录。
+  - filter: 过滤条件。nil 表示所有文件。
+  - option: 扫描选项。如果为 nil 则使用默认选项。
+  - buffer: 读取文件的缓冲区。
+  - provider: 执行校验和计算的对象，不能为 nil。
+
+返回:
+  - 文件清单。
+  - 错误信息。
+*/
+func CreateManifest(
+	root string,
+	filter *Filter,
+	option *WalkOption,
+	buffer []byte,
+	provider FileChecksumCalculationProvider,
+) (*Manifest, error) {
+	if provider == nil {
+		return nil, errors.New("provider must not be nil")
+	}
+
+	manifest := NewManifest(provider.Method())
+	err := walkFilteredFiles(root, filter, option, func(relPath string, path string, info os.FileInfo) error {
+		entry, err := newManifestEntry(relPath, path, info, buffer, provider)
+		if err != nil {
+			return err
+		}
+
+		manifest.Entries[relPath] = entry
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+/*
+Paths returns the sorted paths of all entries.
+
+Paths 返回所有条目的路径，已排序。
+*/
+func (m *Manifest) Paths() []string {
+	result := make([]string, 0, len(m.Entries))
+	for path := range m.Entries {
+		result = append(result, path)
+	}
+
+	sort.Strings(result)
+	return result
+}
+
+/*
+Save writes the manifest to the given file in JSON format.
+
+Save 将文件清单以 JSON 格式写入给定的文件。
+*/
+func (m *Manifest) Save(filename string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filename, data, 0644)
+}
+
+/*
+LoadManifest reads a manifest written by [Manifest.Save].
+
+LoadManifest 读取由 [Manifest.Save] 写入的文件清单。
+*/
+func LoadManifest(filename string) (*Manifest, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &Manifest{}
+	if err = json.Unmarshal(data, manifest); err != nil {
+		return nil, err
+	}
+	if manifest.Entries == nil {
+		manifest.Entries = make(map[string]*ManifestEntry)
+	}
+
+	return manifest, nil
+}
+
+// newManifestEntry 计算文件的完整校验值，并创建清单条目。
+func newManifestEntry(
+	relPath string,
+	path string,
+	info os.FileInfo,
+	buffer []byte,
+	provider FileChecksumCalculationProvider,
+) (*ManifestEntry, error) {
+	if err := GetFileChecksumWithProvider(path, 0, buffer, false, true, provider); err != nil {
+		return nil, err
+	}
+
+	return &ManifestEntry{
+		Path:     relPath,
+		Size:     info.Size(),
+		ModTime:  info.ModTime(),
+		Checksum: hex.EncodeToString(provider.FullChecksum()),
+	}, nil
+}
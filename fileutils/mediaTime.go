@@ -0,0 +1,539 @@
+package fileutils
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jqk/futool4go/timeutils"
+)
+
+/*
+FileTimeSource defines where the time of a file comes from.
+
+FileTimeSource 定义了文件时间的来源。
+*/
+type FileTimeSource int
+
+const (
+	FileTimeSourceNone     FileTimeSource = iota // No time found.
+	FileTimeSourceExif                           // EXIF of JPEG or the eXIf chunk of PNG.
+	FileTimeSourcePNG                            // "Creation Time" text or tIME chunk of PNG.
+	FileTimeSourceMP4                            // Creation time in the movie header of MP4, MOV and other ISO media files.
+	FileTimeSourceFileName                       // Date time parsed from the file name.
+	FileTimeSourceModTime                        // Modification time of the file.
+)
+
+// String returns the name of the source.
+func (s FileTimeSource) String() string {
+	switch s {
+	case FileTimeSourceExif:
+		return "exif"
+	case FileTimeSourcePNG:
+		return "png"
+	case FileTimeSourceMP4:
+		return "mp4"
+	case FileTimeSourceFileName:
+		return "filename"
+	case FileTimeSourceModTime:
+		return "mtime"
+	default:
+		return "none"
+	}
+}
+
+/*
+GetMediaTime extracts the capture time from the metadata of JPEG (EXIF), PNG (eXIf, "Creation Time" and tIME chunks)
+and ISO media files such as MP4 and MOV (movie header). Times without zone information are in time.Local.
+
+Parameters:
+  - path: the file path.
+
+Returns:
+  - the capture time.
+  - the source of the time. [FileTimeSourceNone] if the file is not supported or has no such metadata.
+  - an error if the file cannot be read. Malformed metadata is not an error.
+
+GetMediaTime 从 JPEG（EXIF）、PNG（eXIf、"Creation Time" 及 tIME 块）及 MP4、MOV 等 ISO 媒体文件（影片头）的元数据中提取拍摄时间。
+不包含时区信息的时间，其时区为 time.Local。
+
+参数:
+  - path: 文件路径。
+
+返回:
+  - 拍摄时间。
+  - 时间的来源。文件类型不支持或没有相关元数据时为 [FileTimeSourceNone]。
+  - 无法读取文件时的错误信息。元数据格式错误不视为错误。
+*/
+func GetMediaTime(path string) (time.Time, FileTimeSource, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return time.Time{}, FileTimeSourceNone, err
+	}
+	defer file.Close()
+
+	header := make([]byte, 16)
+	count, err := io.ReadFull(file, header)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return time.Time{}, FileTimeSourceNone, err
+	}
+	header = header[:count]
+
+	if _, err = file.Seek(0, io.SeekStart); err != nil {
+		return time.Time{}, FileTimeSourceNone, err
+	}
+
+	var tm time.Time
+	var source FileTimeSource
+
+	if prefixAt(0, "\xff\xd8\xff")(header) {
+		tm, err = readJPEGTime(bufio.NewReader(file))
+		source = FileTimeSourceExif
+	} else if prefixAt(0, "\x89PNG\r\n\x1a\n")(header) {
+		tm, source, err = readPNGTime(file)
+	} else if prefixAt(4, "ftyp")(header) {
+		tm, err = readMP4Time(file)
+		source = FileTimeSourceMP4
+	}
+
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return time.Time{}, FileTimeSourceNone, err
+	} else if tm.IsZero() {
+		return time.Time{}, FileTimeSourceNone, nil
+	}
+
+	return tm, source, nil
+}
+
+/*
+GetCaptureTime returns the capture time of the file. It tries the following sources in order:
+  - the metadata. See [GetMediaTime].
+  - the file name. See [timeutils.ParseDateTime] and [timeutils.ParseDate].
+  - the modification time of the file.
+
+Parameters:
+  - path: the file path.
+  - info: the file info. Can be nil, then it is read from the path.
+
+Returns:
+  - the capture time.
+  - the source of the time.
+  - an error if any occurred.
+
+GetCaptureTime 返回文件的拍摄时间。按以下顺序尝试各来源：
+  - 元数据。参见 [GetMediaTime]。
+  - 文件名。参见 [timeutils.ParseDateTime] 及 [timeutils.ParseDate]。
+  - 文件修改时间。
+
+参数:
+  - path: 文件路径。
+  - info: 文件信息。可为 nil，此时从 path 读取。
+
+返回:
+  - 拍摄时间。
+  - 时间的来源。
+  - 错误信息。
+*/
+func GetCaptureTime(path string, info os.FileInfo) (time.Time, FileTimeSource, error) {
+	tm, source, err := GetMediaTime(path)
+	if err != nil || source != FileTimeSourceNone {
+		return tm, source, err
+	}
+
+	if tm, ok := parseFileNameTime(path); ok {
+		return tm, FileTimeSourceFileName, nil
+	}
+	return getModTime(path, info)
+}
+
+/*
+ResolveFileTime returns the best known time of the file. It is the policy shared by features that organize or rename
+files by time, such as {date} of [BatchRename]. It tries the following sources in order:
+  - the file name. See [timeutils.ParseDateTime] and [timeutils.ParseDate].
+    A time in the name is usually given on purpose, and it is the cheapest to read.
+  - the metadata. See [GetMediaTime].
+  - the modification time of the file.
+
+Unlike [GetCaptureTime], the file name wins over the metadata, so a file renamed with a corrected time keeps that time.
+
+Parameters:
+  - path: the file path.
+  - info: the file info. Can be nil, then it is read from the path if needed.
+
+Returns:
+  - the chosen time.
+  - the source of the time.
+  - an error if the file cannot be read.
+
+ResolveFileTime 返回文件最合适的时间。按时间整理或改名的功能都使用此策略，例如 [BatchRename] 的 {date}。
+按以下顺序尝试各来源：
+  - 文件名。参见 [timeutils.ParseDateTime] 及 [timeutils.ParseDate]。文件名中的时间通常是特意给出的，且读取代价最小。
+  - 元数据。参见 [GetMediaTime]。
+  - 文件修改时间。
+
+与 [GetCaptureTime] 不同，文件名优先于元数据，所以用修正后的时间改名的文件保持该时间。
+
+参数:
+  - path: 文件路径。
+  - info: 文件信息。可为 nil，此时需要时从 path 读取。
+
+返回:
+  - 选择的时间。
+  - 时间的来源。
+  - 错误信息。无法读取文件时返回错误。
+*/
+func ResolveFileTime(path string, info os.FileInfo) (time.Time, FileTimeSource, error) {
+	if tm, ok := parseFileNameTime(path); ok {
+		return tm, FileTimeSourceFileName, nil
+	}
+
+	tm, source, err := GetMediaTime(path)
+	if err != nil || source != FileTimeSourceNone {
+		return tm, source, err
+	}
+	return getModTime(path, info)
+}
+
+// parseFileNameTime 从文件名中解析日期时间，没有时间时解析日期。不使用目录部分。
+func parseFileNameTime(path string) (time.Time, bool) {
+	name := filepath.Base(path)
+	if parsed := timeutils.ParseDateTime(name); parsed != nil {
+		return *parsed, true
+	} else if parsed = timeutils.ParseDate(name); parsed != nil {
+		return *parsed, true
+	}
+	return time.Time{}, false
+}
+
+// getModTime 返回文件的修改时间。info 为 nil 时从 path 读取。
+func getModTime(path string, info os.FileInfo) (time.Time, FileTimeSource, error) {
+	if info == nil {
+		var err error
+		if info, err = os.Stat(path); err != nil {
+			return time.Time{}, FileTimeSourceNone, err
+		}
+	}
+	return info.ModTime(), FileTimeSourceModTime, nil
+}
+
+// readJPEGTime 遍历 JPEG 的各个段，从 APP1 段的 EXIF 中读取时间。到达图像数据（SOS）时结束。
+func readJPEGTime(reader *bufio.Reader) (time.Time, error) {
+	// 跳过 SOI。
+	if _, err := reader.Discard(2); err != nil {
+		return time.Time{}, err
+	}
+
+	marker := make([]byte, 4)
+	for {
+		if _, err := io.ReadFull(reader, marker); err != nil {
+			return time.Time{}, err
+		} else if marker[0] != 0xff || marker[1] == 0xda || marker[1] == 0xd9 {
+			// 格式错误，或者已到达图像数据或文件结束。
+			return time.Time{}, nil
+		}
+
+		// 段长度包括长度字段本身的 2 个字节。
+		length := int(binary.BigEndian.Uint16(marker[2:])) - 2
+		if length < 0 {
+			return time.Time{}, nil
+		}
+
+		if marker[1] != 0xe1 {
+			if _, err := reader.Discard(length); err != nil {
+				return time.Time{}, err
+			}
+			continue
+		}
+
+		segment := make([]byte, length)
+		if _, err := io.ReadFull(reader, segment); err != nil {
+			return time.Time{}, err
+		}
+
+		if bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			if tm, ok := parseExifTime(segment[6:]); ok {
+				return tm, nil
+			}
+		}
+	}
+}
+
+// EXIF 中与时间相关的标签。
+const (
+	exifTagDateTime          = 0x0132
+	exifTagExifIFDPointer    = 0x8769
+	exifTagDateTimeOriginal  = 0x9003
+	exifTagDateTimeDigitized = 0x9004
+)
+
+// parseExifTime 从 TIFF 格式的 EXIF 数据中读取时间。依次使用 DateTimeOriginal、DateTimeDigitized 及 DateTime。
+func parseExifTime(data []byte) (time.Time, bool) {
+	if len(data) < 8 {
+		return time.Time{}, false
+	}
+
+	var order binary.ByteOrder
+	switch string(data[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return time.Time{}, false
+	}
+
+	if order.Uint16(data[2:]) != 42 {
+		return time.Time{}, false
+	}
+
+	ifd0 := readExifIFD(data, order, order.Uint32(data[4:]))
+	values := make([][]byte, 0, 3)
+
+	if pointer, ok := ifd0[exifTagExifIFDPointer]; ok {
+		exifIFD := readExifIFD(data, order, order.Uint32(pointer[6:]))
+		values = append(values, exifIFD[exifTagDateTimeOriginal], exifIFD[exifTagDateTimeDigitized])
+	}
+	values = append(values, ifd0[exifTagDateTime])
+
+	for _, entry := range values {
+		if entry == nil {
+			continue
+		}
+
+		s := strings.TrimRight(string(readExifASCII(data, order, entry)), "\x00 ")
+		if tm, err := time.ParseInLocation("2006:01:02 15:04:05", s, time.Local); err == nil {
+			return tm, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// readExifIFD 读取 IFD 中的所有条目。返回标签到条目的 map，条目为 10 字节：类型(2)、数量(4)及值或偏移(4)。
+func readExifIFD(data []byte, order binary.ByteOrder, offset uint32) map[uint16][]byte {
+	result := make(map[uint16][]byte)
+	if uint64(offset)+2 > uint64(len(data)) {
+		return result
+	}
+
+	count := int(order.Uint16(data[offset:]))
+	start := int(offset) + 2
+
+	for i := 0; i < count; i++ {
+		entry := start + i*12
+		if entry+12 > len(data) {
+			break
+		}
+		result[order.Uint16(data[entry:])] = data[entry+2 : entry+12]
+	}
+
+	return result
+}
+
+// readExifASCII 读取 ASCII 类型条目的值。值不超过 4 字节时直接保存在条目中，否则保存在偏移处。
+func readExifASCII(data []byte, order binary.ByteOrder, entry []byte) []byte {
+	if order.Uint16(entry) != 2 { // 2 表示 ASCII 类型。
+		return nil
+	}
+
+	count := order.Uint32(entry[2:])
+	if count <= 4 {
+		return entry[6 : 6+count]
+	}
+
+	offset := order.Uint32(entry[6:])
+	if uint64(offset)+uint64(count) > uint64(len(data)) {
+		return nil
+	}
+	return data[offset : offset+count]
+}
+
+// maxPNGChunkSize 是读取 PNG 元数据块的最大长度，超过此长度的块被跳过。
+const maxPNGChunkSize = 1 << 20
+
+// readPNGTime 遍历 PNG 的各个块，依次使用 eXIf、"Creation Time" 文本及 tIME 中的时间。
+func readPNGTime(file io.ReadSeeker) (time.Time, FileTimeSource, error) {
+	if _, err := file.Seek(8, io.SeekStart); err != nil {
+		return time.Time{}, FileTimeSourceNone, err
+	}
+
+	var creationTime, modTime time.Time
+	header := make([]byte, 8)
+
+	for {
+		if _, err := io.ReadFull(file, header); err != nil {
+			break
+		}
+
+		length := int64(binary.BigEndian.Uint32(header))
+		chunkType := string(header[4:])
+		if chunkType == "IEND" {
+			break
+		}
+
+		if length > maxPNGChunkSize || (chunkType != "eXIf" && chunkType != "tEXt" && chunkType != "iTXt" && chunkType != "tIME") {
+			// 跳过块数据及 4 字节的 CRC。
+			if _, err := file.Seek(length+4, io.SeekCurrent); err != nil {
+				return time.Time{}, FileTimeSourceNone, err
+			}
+			continue
+		}
+
+		data := make([]byte, length+4)
+		if _, err := io.ReadFull(file, data); err != nil {
+			break
+		}
+		data = data[:length]
+
+		switch chunkType {
+		case "eXIf":
+			if tm, ok := parseExifTime(data); ok {
+				return tm, FileTimeSourceExif, nil
+			}
+		case "tEXt", "iTXt":
+			if text, ok := readPNGCreationTimeText(chunkType, data); ok && creationTime.IsZero() {
+				creationTime = parsePNGCreationTime(text)
+			}
+		case "tIME":
+			if len(data) == 7 {
+				modTime = time.Date(int(binary.BigEndian.Uint16(data)), time.Month(data[2]), int(data[3]),
+					int(data[4]), int(data[5]), int(data[6]), 0, time.UTC).In(time.Local)
+			}
+		}
+	}
+
+	if !creationTime.IsZero() {
+		return creationTime, FileTimeSourcePNG, nil
+	} else if !modTime.IsZero() {
+		return modTime, FileTimeSourcePNG, nil
+	}
+
+	return time.Time{}, FileTimeSourceNone, nil
+}
+
+// readPNGCreationTimeText 读取关键字为 "Creation Time" 的文本块的内容。不支持压缩的 iTXt。
+func readPNGCreationTimeText(chunkType string, data []byte) (string, bool) {
+	parts := bytes.SplitN(data, []byte{0}, 2)
+	if len(parts) != 2 || string(parts[0]) != "Creation Time" {
+		return "", false
+	} else if chunkType == "tEXt" {
+		return string(parts[1]), true
+	}
+
+	// iTXt: 压缩标志(1)、压缩方法(1)、语言标签\0、翻译后的关键字\0、文本。
+	rest := parts[1]
+	if len(rest) < 2 || rest[0] != 0 {
+		return "", false
+	}
+
+	fields := bytes.SplitN(rest[2:], []byte{0}, 3)
+	if len(fields) != 3 {
+		return "", false
+	}
+	return string(fields[2]), true
+}
+
+// parsePNGCreationTime 解析 PNG 的 "Creation Time"。PNG 规范建议使用 RFC 1123 格式，但实际上各种格式都有。
+func parsePNGCreationTime(s string) time.Time {
+	s = strings.TrimSpace(s)
+	for _, layout := range []string{time.RFC1123Z, time.RFC1123, time.RFC3339} {
+		if tm, err := time.Parse(layout, s); err == nil {
+			return tm
+		}
+	}
+
+	if tm := timeutils.ParseDateTime(s); tm != nil {
+		return *tm
+	}
+	return time.Time{}
+}
+
+// mp4Epoch 是 ISO 媒体文件中时间的起点。
+var mp4Epoch = time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// mp4MaxSeconds 是有效的创建时间的最大秒数，即 9999 年年底。版本 1 的 64 位秒数可能超出 time.Duration 的范围。
+var mp4MaxSeconds = uint64(time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC).Unix() - mp4Epoch.Unix())
+
+// readMP4Time 从 moov/mvhd 中读取影片的创建时间。
+func readMP4Time(file io.ReadSeeker) (time.Time, error) {
+	size, err := file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	start, end, err := findMP4Box(file, 0, size, "moov")
+	if err != nil || start < 0 {
+		return time.Time{}, err
+	}
+
+	start, end, err = findMP4Box(file, start, end, "mvhd")
+	if err != nil || start < 0 {
+		return time.Time{}, err
+	}
+
+	// mvhd: 版本(1)、标志(3)、创建时间。版本 1 为 8 字节，否则为 4 字节。
+	data := make([]byte, 12)
+	if end-start < int64(len(data)) {
+		return time.Time{}, nil
+	} else if _, err = file.Seek(start, io.SeekStart); err != nil {
+		return time.Time{}, err
+	} else if _, err = io.ReadFull(file, data); err != nil {
+		return time.Time{}, err
+	}
+
+	var seconds uint64
+	if data[0] == 1 {
+		seconds = binary.BigEndian.Uint64(data[4:])
+	} else {
+		seconds = uint64(binary.BigEndian.Uint32(data[4:]))
+	}
+
+	if seconds == 0 || seconds >= mp4MaxSeconds {
+		return time.Time{}, nil
+	}
+	return time.Unix(mp4Epoch.Unix()+int64(seconds), 0).In(time.Local), nil
+}
+
+// findMP4Box 在 [start, end) 范围内查找给定类型的 box。返回其数据部分的范围，未找到时 start 为 -1。
+func findMP4Box(file io.ReadSeeker, start, end int64, boxType string) (int64, int64, error) {
+	header := make([]byte, 16)
+
+	for pos := start; pos+8 <= end; {
+		if _, err := file.Seek(pos, io.SeekStart); err != nil {
+			return -1, -1, err
+		} else if _, err = io.ReadFull(file, header[:8]); err != nil {
+			return -1, -1, err
+		}
+
+		size := int64(binary.BigEndian.Uint32(header))
+		headerSize := int64(8)
+
+		if size == 1 {
+			// 64 位长度。
+			if _, err := io.ReadFull(file, header[8:]); err != nil {
+				return -1, -1, err
+			}
+			size = int64(binary.BigEndian.Uint64(header[8:]))
+			headerSize = 16
+		} else if size == 0 {
+			// 一直到结尾。
+			size = end - pos
+		}
+
+		// 64 位长度可能超出 int64 的范围成为负数，或者使 pos+size 溢出，所以与剩余的长度比较。
+		if size < headerSize || size > end-pos {
+			return -1, -1, nil // 格式错误。
+		} else if string(header[4:8]) == boxType {
+			return pos + headerSize, pos + size, nil
+		}
+
+		pos += size
+	}
+
+	return -1, -1, nil
+}
@@ -0,0 +1,225 @@
+package fileutils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetMediaTime(t *testing.T) {
+	root := t.TempDir()
+	expect := time.Date(2021, 7, 8, 9, 10, 11, 0, time.Local)
+
+	// JPEG，EXIF 中有 DateTimeOriginal。
+	jpeg := filepath.Join(root, "photo.jpg")
+	assert.Nil(t, os.WriteFile(jpeg, buildJPEG("2021:07:08 09:10:11"), 0644))
+
+	tm, source, err := GetMediaTime(jpeg)
+	assert.Nil(t, err)
+	assert.Equal(t, FileTimeSourceExif, source)
+	assert.True(t, expect.Equal(tm))
+
+	// PNG，有 "Creation Time" 文本块。
+	png := filepath.Join(root, "image.png")
+	assert.Nil(t, os.WriteFile(png, buildPNG("tEXt", []byte("Creation Time\x00Thu, 08 Jul 2021 09:10:11 +0000")), 0644))
+
+	tm, source, err = GetMediaTime(png)
+	assert.Nil(t, err)
+	assert.Equal(t, FileTimeSourcePNG, source)
+	assert.True(t, time.Date(2021, 7, 8, 9, 10, 11, 0, time.UTC).Equal(tm))
+
+	// MP4，mvhd 中有创建时间。
+	mp4 := filepath.Join(root, "movie.mp4")
+	utc := time.Date(2021, 7, 8, 9, 10, 11, 0, time.UTC)
+	assert.Nil(t, os.WriteFile(mp4, buildMP4(uint32(utc.Sub(mp4Epoch)/time.Second)), 0644))
+
+	tm, source, err = GetMediaTime(mp4)
+	assert.Nil(t, err)
+	assert.Equal(t, FileTimeSourceMP4, source)
+	assert.True(t, utc.Equal(tm))
+
+	// 不支持的文件。
+	_, source, err = GetMediaTime("../test-data/fileutils/filter/001.MD")
+	assert.Nil(t, err)
+	assert.Equal(t, FileTimeSourceNone, source)
+
+	// 格式错误的 JPEG 不是错误。
+	broken := filepath.Join(root, "broken.jpg")
+	assert.Nil(t, os.WriteFile(broken, []byte("\xff\xd8\xff\xe1\x00"), 0644))
+	_, source, err = GetMediaTime(broken)
+	assert.Nil(t, err)
+	assert.Equal(t, FileTimeSourceNone, source)
+}
+
+func TestGetMediaTimeBrokenMP4(t *testing.T) {
+	root := t.TempDir()
+	ftyp := buildMP4(0)[:16]
+
+	// 64 位长度超出 int64 的范围，或者使位置溢出。
+	for _, largeSize := range []uint64{math.MaxUint64, math.MaxInt64 - 4} {
+		data := append([]byte{}, ftyp...)
+		data = binary.BigEndian.AppendUint32(data, 1)
+		data = append(data, "free"...)
+		data = binary.BigEndian.AppendUint64(data, largeSize)
+		data = append(data, make([]byte, 32)...)
+
+		path := filepath.Join(root, "large.mp4")
+		assert.Nil(t, os.WriteFile(path, data, 0644))
+		_, source, err := GetMediaTime(path)
+		assert.Nil(t, err)
+		assert.Equal(t, FileTimeSourceNone, source)
+	}
+
+	// 版本 1 的创建时间超出范围。
+	data := buildMP4(0)
+	mvhd := bytes.Index(data, []byte("mvhd")) + 4
+	data[mvhd] = 1
+	binary.BigEndian.PutUint64(data[mvhd+4:], math.MaxUint64)
+
+	path := filepath.Join(root, "future.mp4")
+	assert.Nil(t, os.WriteFile(path, data, 0644))
+	_, source, err := GetMediaTime(path)
+	assert.Nil(t, err)
+	assert.Equal(t, FileTimeSourceNone, source)
+}
+
+func TestGetCaptureTime(t *testing.T) {
+	root := t.TempDir()
+
+	// 没有元数据，使用文件名。
+	named := filepath.Join(root, "IMG_20210708_091011.jpg")
+	assert.Nil(t, os.WriteFile(named, []byte("\xff\xd8\xff\xd9"), 0644))
+
+	tm, source, err := GetCaptureTime(named, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, FileTimeSourceFileName, source)
+	assert.Equal(t, "2021-07-08 09:10:11", tm.Format("2006-01-02 15:04:05"))
+
+	// 文件名中也没有时间，使用修改时间。
+	plain := filepath.Join(root, "plain.txt")
+	assert.Nil(t, os.WriteFile(plain, []byte("text"), 0644))
+	mtime := time.Date(2020, 1, 2, 3, 4, 5, 0, time.Local)
+	assert.Nil(t, os.Chtimes(plain, mtime, mtime))
+
+	tm, source, err = GetCaptureTime(plain, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, FileTimeSourceModTime, source)
+	assert.Equal(t, "mtime", source.String())
+	assert.True(t, mtime.Equal(tm))
+
+	_, _, err = GetCaptureTime(filepath.Join(root, "not-exist"), nil)
+	assert.NotNil(t, err)
+}
+
+func TestResolveFileTime(t *testing.T) {
+	root := t.TempDir()
+
+	// 文件名优先于 EXIF。
+	named := filepath.Join(root, "IMG_20210708_091011.jpg")
+	assert.Nil(t, os.WriteFile(named, buildJPEG("2019:01:02 03:04:05"), 0644))
+
+	tm, source, err := ResolveFileTime(named, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, FileTimeSourceFileName, source)
+	assert.Equal(t, "2021-07-08 09:10:11", tm.Format("2006-01-02 15:04:05"))
+
+	// 文件名中没有时间，使用 EXIF。
+	photo := filepath.Join(root, "photo.jpg")
+	assert.Nil(t, os.WriteFile(photo, buildJPEG("2019:01:02 03:04:05"), 0644))
+
+	tm, source, err = ResolveFileTime(photo, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, FileTimeSourceExif, source)
+	assert.Equal(t, "2019-01-02 03:04:05", tm.Format("2006-01-02 15:04:05"))
+
+	// 都没有，使用修改时间。
+	plain := filepath.Join(root, "plain.txt")
+	assert.Nil(t, os.WriteFile(plain, []byte("text"), 0644))
+	mtime := time.Date(2020, 1, 2, 3, 4, 5, 0, time.Local)
+	assert.Nil(t, os.Chtimes(plain, mtime, mtime))
+
+	info, err := os.Stat(plain)
+	assert.Nil(t, err)
+	tm, source, err = ResolveFileTime(plain, info)
+	assert.Nil(t, err)
+	assert.Equal(t, FileTimeSourceModTime, source)
+	assert.True(t, mtime.Equal(tm))
+
+	_, _, err = ResolveFileTime(filepath.Join(root, "not-exist"), nil)
+	assert.NotNil(t, err)
+}
+
+// buildJPEG 创建只包含 EXIF 的 JPEG 数据。IFD0 指向 Exif IFD，其中有 DateTimeOriginal。
+func buildJPEG(dateTime string) []byte {
+	order := binary.LittleEndian
+	value := append([]byte(dateTime), 0)
+
+	tiff := &bytes.Buffer{}
+	tiff.WriteString("II\x2a\x00")
+	binary.Write(tiff, order, uint32(8)) // IFD0 偏移。
+
+	// IFD0: 1 个条目，ExifIFDPointer，类型 LONG(4)。
+	binary.Write(tiff, order, uint16(1))
+	binary.Write(tiff, order, []uint16{exifTagExifIFDPointer, 4})
+	binary.Write(tiff, order, []uint32{1, 26})
+	binary.Write(tiff, order, uint32(0))
+
+	// Exif IFD，偏移 26: 1 个条目，DateTimeOriginal，类型 ASCII(2)，值位于偏移 44。
+	binary.Write(tiff, order, uint16(1))
+	binary.Write(tiff, order, []uint16{exifTagDateTimeOriginal, 2})
+	binary.Write(tiff, order, []uint32{uint32(len(value)), 44})
+	binary.Write(tiff, order, uint32(0))
+	tiff.Write(value)
+
+	segment := append([]byte("Exif\x00\x00"), tiff.Bytes()...)
+
+	result := &bytes.Buffer{}
+	result.WriteString("\xff\xd8\xff\xe1")
+	binary.Write(result, binary.BigEndian, uint16(len(segment)+2))
+	result.Write(segment)
+	result.WriteString("\xff\xda\x00\x02\xff\xd9")
+	return result.Bytes()
+}
+
+// buildPNG 创建包含一个给定块的 PNG 数据。CRC 不做校验，所以填 0。
+func buildPNG(chunkType string, data []byte) []byte {
+	result := &bytes.Buffer{}
+	result.WriteString("\x89PNG\r\n\x1a\n")
+
+	for _, chunk := range []struct {
+		chunkType string
+		data      []byte
+	}{{"IHDR", make([]byte, 13)}, {chunkType, data}, {"IEND", nil}} {
+		binary.Write(result, binary.BigEndian, uint32(len(chunk.data)))
+		result.WriteString(chunk.chunkType)
+		result.Write(chunk.data)
+		binary.Write(result, binary.BigEndian, uint32(0))
+	}
+
+	return result.Bytes()
+}
+
+// buildMP4 创建包含 ftyp、mdat 及 moov/mvhd 的 MP4 数据。
+func buildMP4(seconds uint32) []byte {
+	box := func(boxType string, data []byte) []byte {
+		result := &bytes.Buffer{}
+		binary.Write(result, binary.BigEndian, uint32(len(data)+8))
+		result.WriteString(boxType)
+		result.Write(data)
+		return result.Bytes()
+	}
+
+	mvhd := make([]byte, 100)
+	binary.BigEndian.PutUint32(mvhd[4:], seconds)
+
+	result := box("ftyp", []byte("isom\x00\x00\x02\x00"))
+	result = append(result, box("mdat", make([]byte, 32))...)
+	result = append(result, box("moov", box("mvhd", mvhd))...)
+	return result
+}
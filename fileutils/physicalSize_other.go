@@ -0,0 +1,10 @@
+//go:build !unix && !windows
+
+package fileutils
+
+import "os"
+
+// physicalSize 在其它系统上返回逻辑大小，因为没有可用的块数。
+func physicalSize(info os.FileInfo) int64 {
+	return info.Size()
+}
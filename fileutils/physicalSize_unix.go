@@ -0,0 +1,16 @@
+//go:build unix
+
+package fileutils
+
+import (
+	"os"
+	"syscall"
+)
+
+// physicalSize 返回文件实际占用的磁盘空间。st_blocks 的单位总是 512 字节。
+func physicalSize(info os.FileInfo) int64 {
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		return int64(stat.Blocks) * 512
+	}
+	return info.Size()
+}
@@ -0,0 +1,10 @@
+//go:build windows
+
+package fileutils
+
+import "os"
+
+// physicalSize 在 Windows 上返回逻辑大小。获取稀疏文件的实际大小需要 GetCompressedFileSize，标准库未提供。
+func physicalSize(info os.FileInfo) int64 {
+	return info.Size()
+}
@@ -0,0 +1,131 @@
+package fileutils
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+/*
+ErrAlreadyRunning is returned by [WritePIDFile] when the PID file belongs to a running process.
+
+ErrAlreadyRunning 在 PID 文件属于正在运行的进程时由 [WritePIDFile] 返回。
+*/
+var ErrAlreadyRunning = errors.New("process already running")
+
+/*
+ErrInvalidPIDFile is returned by [CheckPIDFile] when the content of the PID file is not a valid process ID,
+such as an empty file left by a crash before the ID was written.
+
+ErrInvalidPIDFile 在 PID 文件的内容不是有效的进程号时由 [CheckPIDFile] 返回，例如写入进程号之前崩溃而留下的空文件。
+*/
+var ErrInvalidPIDFile = errors.New("invalid PID file")
+
+/*
+WritePIDFile creates the PID file exclusively and writes the current process ID to it.
+If the file exists but its process is no longer running, or its content is not a valid process ID,
+the stale file is replaced.
+On systems other than Unix and Windows, the process can not be checked, so an existing file with a valid process ID is never replaced.
+Call [RemovePIDFile] on exit to clean up.
+
+Returns:
+  - an error wrapping [ErrAlreadyRunning] if the PID file belongs to a running process, or other error if any occurred.
+
+Example:
+
+	if err := WritePIDFile("/var/run/scrubber.pid"); errors.Is(err, ErrAlreadyRunning) {
+		log.Fatal("scrubber is already running")
+	} else if err != nil {
+		log.Fatal(err)
+	}
+	defer RemovePIDFile("/var/run/scrubber.pid")
+
+WritePIDFile 以独占方式创建 PID 文件，并写入当前进程号。文件已存在但其进程已不在运行，或者其内容不是有效的进程号时，替换该过期文件。
+在 Unix 及 Windows 以外的系统上无法检查进程，所以从不替换已存在的、有有效进程号的文件。
+退出时调用 [RemovePIDFile] 进行清理。
+
+返回:
+  - PID 文件属于正在运行的进程时，返回包装了 [ErrAlreadyRunning] 的错误，或者其它错误信息。
+*/
+func WritePIDFile(path string) error {
+	// 第一次失败可能是过期文件，删除后再试一次。再次失败说明有其它进程同时创建了该文件。
+	for i := 0; i < 2; i++ {
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err == nil {
+			_, err = file.WriteString(strconv.Itoa(os.Getpid()) + "\n")
+			if closeErr := file.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				os.Remove(path)
+			}
+			return err
+		} else if !os.IsExist(err) {
+			return err
+		}
+
+		// 无效的内容，例如创建后写入前崩溃留下的空文件，与进程已不在运行一样视为过期。
+		pid, running, err := CheckPIDFile(path)
+		if err != nil && !errors.Is(err, ErrInvalidPIDFile) {
+			return err
+		} else if running {
+			return fmt.Errorf("%w: pid %d in %s", ErrAlreadyRunning, pid, path)
+		}
+
+		if err = os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	return fmt.Errorf("%w: %s is being created by another process", ErrAlreadyRunning, path)
+}
+
+/*
+CheckPIDFile reads the PID file and checks whether its process is running.
+
+Returns:
+  - the process ID in the file.
+  - true if the process is running. The current process is considered running.
+  - an error if the file cannot be read, or an error wrapping [ErrInvalidPIDFile] if its content is not a valid process ID.
+
+CheckPIDFile 读取 PID 文件，检查其进程是否正在运行。
+
+返回:
+  - 文件中的进程号。
+  - 进程正在运行时返回 true。当前进程视为正在运行。
+  - 文件无法读取时返回错误，其内容不是有效的进程号时返回包装了 [ErrInvalidPIDFile] 的错误。
+*/
+func CheckPIDFile(path string) (int, bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, false, err
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || pid <= 0 {
+		return 0, false, fmt.Errorf("%w: %s", ErrInvalidPIDFile, path)
+	}
+
+	return pid, pid == os.Getpid() || isProcessRunning(pid), nil
+}
+
+/*
+RemovePIDFile removes the PID file if it contains the current process ID.
+It is not an error if the file does not exist.
+
+RemovePIDFile 在 PID 文件中是当前进程号时删除该文件。文件不存在不是错误。
+*/
+func RemovePIDFile(path string) error {
+	pid, _, err := CheckPIDFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	} else if pid != os.Getpid() {
+		return fmt.Errorf("PID file %s belongs to process %d", path, pid)
+	}
+
+	return os.Remove(path)
+}
@@ -0,0 +1,8 @@
+//go:build !unix && !windows
+
+package fileutils
+
+// isProcessRunning 在其它系统上无法检查进程是否存在，总是视为正在运行，所以不会替换已存在的 PID 文件。
+func isProcessRunning(pid int) bool {
+	return true
+}
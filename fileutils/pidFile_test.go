@@ -0,0 +1,47 @@
+package fileutils
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPIDFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.pid")
+
+	assert.Nil(t, WritePIDFile(path))
+	pid, running, err := CheckPIDFile(path)
+	assert.Nil(t, err)
+	assert.Equal(t, os.Getpid(), pid)
+	assert.True(t, running)
+
+	// 当前进程正在运行，不能再次创建。
+	assert.ErrorIs(t, WritePIDFile(path), ErrAlreadyRunning)
+
+	assert.Nil(t, RemovePIDFile(path))
+	assert.Nil(t, RemovePIDFile(path))
+
+	// 过期的 PID 文件被替换。使用一个几乎不可能存在的进程号。
+	assert.Nil(t, os.WriteFile(path, []byte(strconv.Itoa(1<<22-3)+"\n"), 0644))
+	_, running, err = CheckPIDFile(path)
+	assert.Nil(t, err)
+	assert.False(t, running)
+	assert.NotNil(t, RemovePIDFile(path))
+
+	assert.Nil(t, WritePIDFile(path))
+	pid, _, _ = CheckPIDFile(path)
+	assert.Equal(t, os.Getpid(), pid)
+
+	// 无效的内容，或者写入前崩溃留下的空文件，视为过期而被替换。
+	for _, content := range []string{"abc", ""} {
+		assert.Nil(t, os.WriteFile(path, []byte(content), 0644))
+		_, _, err = CheckPIDFile(path)
+		assert.ErrorIs(t, err, ErrInvalidPIDFile)
+		assert.Nil(t, WritePIDFile(path))
+		pid, _, _ = CheckPIDFile(path)
+		assert.Equal(t, os.Getpid(), pid)
+	}
+}
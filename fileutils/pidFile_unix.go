@@ -0,0 +1,11 @@
+//go:build unix
+
+package fileutils
+
+import "syscall"
+
+// isProcessRunning 发送信号 0 检查进程是否存在。EPERM 说明进程存在但属于其它用户。
+func isProcessRunning(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || err == syscall.EPERM
+}
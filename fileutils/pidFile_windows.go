@@ -0,0 +1,23 @@
+//go:build windows
+
+package fileutils
+
+import "syscall"
+
+// isProcessRunning 打开进程并检查其退出码。无权打开的进程视为正在运行。
+func isProcessRunning(pid int) bool {
+	const processQueryLimitedInformation = 0x1000
+	const stillActive = 259 // GetExitCodeProcess 返回的进程仍在运行的退出码。
+
+	handle, err := syscall.OpenProcess(processQueryLimitedInformation, false, uint32(pid))
+	if err != nil {
+		return err == syscall.ERROR_ACCESS_DENIED
+	}
+	defer syscall.CloseHandle(handle)
+
+	var code uint32
+	if err = syscall.GetExitCodeProcess(handle, &code); err != nil {
+		return true
+	}
+	return code == stillActive
+}
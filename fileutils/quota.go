@@ -0,0 +1,171 @@
+package fileutils
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+/*
+QuotaExceededError is returned when writing more bytes than the quota allows.
+
+QuotaExceededError 在写入的字节数超出配额时返回。
+*/
+type QuotaExceededError struct {
+	Quota     int64 // The quota in bytes.
+	Used      int64 // The bytes already used before the failed operation.
+	Requested int64 // The bytes requested by the failed operation.
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("quota exceeded: quota %d bytes, used %d bytes, requested %d bytes", e.Quota, e.Used, e.Requested)
+}
+
+/*
+LimitedWriter writes to the underlying writer until the limit is reached.
+A write exceeding the limit writes the bytes that fit and returns a [*QuotaExceededError].
+
+LimitedWriter 向下层写入器写入数据，直到达到限制。超出限制的写入只写入限制内的部分，并返回 [*QuotaExceededError]。
+*/
+type LimitedWriter struct {
+	writer  io.Writer
+	limit   int64
+	written int64
+}
+
+/*
+NewLimitedWriter creates a LimitedWriter allowing at most limit bytes to be written.
+
+NewLimitedWriter 创建最多允许写入 limit 字节的 LimitedWriter。
+*/
+func NewLimitedWriter(writer io.Writer, limit int64) *LimitedWriter {
+	return &LimitedWriter{writer: writer, limit: limit}
+}
+
+func (w *LimitedWriter) Write(p []byte) (int, error) {
+	remaining := w.limit - w.written
+	if int64(len(p)) <= remaining {
+		n, err := w.writer.Write(p)
+		w.written += int64(n)
+		return n, err
+	}
+
+	quotaErr := &QuotaExceededError{Quota: w.limit, Used: w.written, Requested: int64(len(p))}
+	if remaining <= 0 {
+		return 0, quotaErr
+	}
+
+	n, err := w.writer.Write(p[:remaining])
+	w.written += int64(n)
+	if err != nil {
+		return n, err
+	}
+	return n, quotaErr
+}
+
+/*
+Written returns the number of bytes written.
+
+Written 返回已写入的字节数。
+*/
+func (w *LimitedWriter) Written() int64 {
+	return w.written
+}
+
+/*
+Remaining returns the number of bytes can still be written.
+
+Remaining 返回还可写入的字节数。
+*/
+func (w *LimitedWriter) Remaining() int64 {
+	return w.limit - w.written
+}
+
+/*
+CopyDirWithQuota copies the directory like [CopyDir], but only if the total size of the files fits in the quota.
+The size is checked before copying anything, so nothing is copied if the quota would be exceeded.
+Files may still grow during the copy, so the bytes actually copied are counted as well.
+If the quota is exceeded then, the files copied so far are removed, together with target if it did not exist before.
+
+Parameters:
+  - source: the source path of the directory to be copied.
+  - target: the target path where the directory and its contents will be copied to.
+  - quota: the maximum number of bytes to write to the target.
+  - option: the scan options. if nil, the default options will be used.
+
+Returns:
+  - a [*QuotaExceededError] if the quota would be exceeded, or other error if any occurred.
+
+CopyDirWithQuota 与 [CopyDir] 一样复制目录，但只在文件总大小不超过配额时复制。
+复制前先检查总大小，所以超出配额时不会复制任何内容。复制过程中文件仍可能变大，所以同时统计实际复制的字节数。
+此时超出配额，则删除已复制的文件，如果 target 原来不存在，也删除 target。
+
+参数:
+  - source: 要复制的源路径。
+  - target: 要复制的目标路径。
+  - quota: 向目标写入的最大字节数。
+  - option: 扫描选项。如果为 nil 则使用默认选项。
+
+返回:
+  - 超出配额时返回 [*QuotaExceededError]，或者其它错误信息。
+*/
+func CopyDirWithQuota(source, target string, quota int64, option *WalkOption) error {
+	if option == nil { // 保证 option 不为 nil。
+		option = NewWalkOption()
+	}
+
+	// WalkOption 有状态，统计与复制各用一份。
+	statOption, copyOption := *option, *option
+	statOption.isSubDir, copyOption.isSubDir = false, false
+
+	stat, err := GetDirStatistics(source, &statOption)
+	if err != nil {
+		return err
+	} else if stat.TotalSize > quota {
+		return &QuotaExceededError{Quota: quota, Used: 0, Requested: stat.TotalSize}
+	}
+
+	return copyDirWithinQuota(source, target, quota, &copyOption)
+}
+
+// copyDirWithinQuota 复制目录，统计实际复制的字节数。超出配额时删除已复制的文件，target 原来不存在时删除 target。
+func copyDirWithinQuota(source, target string, quota int64, option *WalkOption) error {
+	targetExists, _, err := FileExists(target)
+	if err != nil {
+		return err
+	}
+
+	var used int64
+	copied := []string{}
+	err = copyDir(source, target, option, false, func(path string, dst string, info os.FileInfo) error {
+		if used+info.Size() > quota {
+			return &QuotaExceededError{Quota: quota, Used: used, Requested: info.Size()}
+		}
+
+		// CopyFile 先写入临时文件，出错时不会留下只写入一部分的文件。
+		n, err := CopyFile(path, dst, nil)
+		if err != nil {
+			return err
+		}
+		copied = append(copied, dst)
+
+		if used+n > quota {
+			return &QuotaExceededError{Quota: quota, Used: used, Requested: n}
+		}
+		used += n
+		return nil
+	})
+
+	quotaErr := &QuotaExceededError{}
+	if errors.As(err, &quotaErr) {
+		if !targetExists {
+			os.RemoveAll(target)
+		} else {
+			for _, path := range copied {
+				os.Remove(path)
+			}
+		}
+	}
+	return err
+}
@@ -0,0 +1,91 @@
+package fileutils
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimitedWriter(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	w := NewLimitedWriter(buffer, 8)
+
+	n, err := w.Write([]byte("hello"))
+	assert.Nil(t, err)
+	assert.Equal(t, 5, n)
+	assert.Equal(t, int64(3), w.Remaining())
+
+	n, err = w.Write([]byte("world"))
+	assert.Equal(t, 3, n)
+	quotaErr := &QuotaExceededError{}
+	assert.True(t, errors.As(err, &quotaErr))
+	assert.Equal(t, &QuotaExceededError{Quota: 8, Used: 5, Requested: 5}, quotaErr)
+	assert.Equal(t, "hellowor", buffer.String())
+	assert.Equal(t, int64(8), w.Written())
+
+	n, err = w.Write([]byte("!"))
+	assert.Equal(t, 0, n)
+	assert.NotNil(t, err)
+	assert.Equal(t, "quota exceeded: quota 8 bytes, used 8 bytes, requested 1 bytes", err.Error())
+}
+
+func TestCopyDirWithQuota(t *testing.T) {
+	source := "../test-data/fileutils/extension"
+	stat, err := GetDirStatistics(source, nil)
+	assert.Nil(t, err)
+
+	// 超出配额时不复制任何内容。
+	target := filepath.Join(t.TempDir(), "target")
+	err = CopyDirWithQuota(source, target, stat.TotalSize-1, nil)
+	quotaErr := &QuotaExceededError{}
+	assert.True(t, errors.As(err, &quotaErr))
+	assert.Equal(t, stat.TotalSize, quotaErr.Requested)
+	exists, _, _ := FileExists(target)
+	assert.False(t, exists)
+
+	assert.Nil(t, CopyDirWithQuota(source, target, stat.TotalSize, nil))
+	copied, err := GetDirStatistics(target, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, stat.TotalSize, copied.TotalSize)
+	assert.Equal(t, stat.FileCount, copied.FileCount)
+
+	// 非递归时只统计并复制根目录下的文件。
+	option := NewWalkOption()
+	option.Recursive = false
+	target = filepath.Join(t.TempDir(), "flat")
+	assert.Nil(t, CopyDirWithQuota(source, target, stat.TotalSize, option))
+	entries, _ := os.ReadDir(target)
+	for _, entry := range entries {
+		assert.False(t, entry.IsDir())
+	}
+}
+
+func TestCopyDirWithinQuota(t *testing.T) {
+	source := "../test-data/fileutils/extension"
+	stat, err := GetDirStatistics(source, nil)
+	assert.Nil(t, err)
+
+	// 复制过程中超出配额时，删除原来不存在的目标目录。
+	target := filepath.Join(t.TempDir(), "target")
+	err = copyDirWithinQuota(source, target, stat.TotalSize/2, NewWalkOption())
+	quotaErr := &QuotaExceededError{}
+	assert.True(t, errors.As(err, &quotaErr))
+	exists, _, _ := FileExists(target)
+	assert.False(t, exists)
+
+	// 目标目录已存在时，只删除已复制的文件，保留原有的文件。
+	target = t.TempDir()
+	keep := filepath.Join(target, "keep.txt")
+	assert.Nil(t, os.WriteFile(keep, []byte("keep"), 0644))
+	err = copyDirWithinQuota(source, target, stat.TotalSize/2, NewWalkOption())
+	assert.True(t, errors.As(err, &quotaErr))
+	copied, err := GetDirStatistics(target, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, copied.FileCount)
+	data, _ := os.ReadFile(keep)
+	assert.Equal(t, "keep", string(data))
+}
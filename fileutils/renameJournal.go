@@ -0,0 +1,172 @@
+package fileutils
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+)
+
+/*
+RenameRecord records a rename operation.
+
+RenameRecord 记录一次改名操作。
+*/
+type RenameRecord struct {
+	From string `json:"from"` // The original path.
+	To   string `json:"to"`   // The new path.
+}
+
+/*
+RenameResult is the result of rename operations such as [NormalizeExtensions] and [BatchRename].
+
+RenameResult 是 [NormalizeExtensions]、[BatchRename] 等改名操作的处理结果。
+*/
+type RenameResult struct {
+	Renamed    []RenameRecord // Renames performed, or planned in dry run. Save it by SaveRenameJournal for rollback.
+	Collisions []RenameRecord // Files skipped because the new path exists. To is the conflicting path.
+}
+
+/*
+SaveRenameJournal writes the rename records to the given file in JSON format,
+so that they can be rolled back by [RollbackRenameJournal] later.
+
+SaveRenameJournal 将改名记录以 JSON 格式写入给定的文件，以便之后使用 [RollbackRenameJournal] 撤销。
+*/
+func SaveRenameJournal(filename string, records []RenameRecord) error {
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filename, data, 0644)
+}
+
+/*
+LoadRenameJournal reads the rename records written by [SaveRenameJournal].
+
+LoadRenameJournal 读取由 [SaveRenameJournal] 写入的改名记录。
+*/
+func LoadRenameJournal(filename string) ([]RenameRecord, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]RenameRecord, 0)
+	if err = json.Unmarshal(data, &records); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+/*
+RollbackRenames undoes the given rename records in reverse order.
+
+Parameters:
+  - records: the rename records, in the order they were performed.
+
+Returns:
+  - the records that have been rolled back, in the order they were rolled back.
+  - an error if any occurred. The rollback stops at the first error.
+
+RollbackRenames 按相反的顺序撤销给定的改名记录。
+
+参数:
+  - records: 改名记录，按执行的顺序排列。
+
+返回:
+  - 已撤销的记录，按撤销的顺序排列。
+  - 错误信息。遇到第一个错误即停止撤销。
+*/
+func RollbackRenames(records []RenameRecord) ([]RenameRecord, error) {
+	result := make([]RenameRecord, 0, len(records))
+
+	for i := len(records) - 1; i >= 0; i-- {
+		// 撤销时 From 必须不存在，以免覆盖改名之后新建的文件。
+		// 但在大小写不敏感的文件系统上，仅大小写不同的 From 和 To 是同一个文件，此时可以改名。
+		if exists, _, err := FileExists(records[i].From); err != nil {
+			return result, err
+		} else if exists && !isSameFile(records[i].From, records[i].To) {
+			return result, &os.LinkError{Op: "rollback", Old: records[i].To, New: records[i].From, Err: os.ErrExist}
+		}
+
+		if err := os.Rename(records[i].To, records[i].From); err != nil {
+			return result, err
+		}
+
+		result = append(result, records[i])
+	}
+
+	return result, nil
+}
+
+/*
+RollbackRenameJournal undoes the rename records in the journal file written by [SaveRenameJournal].
+See [RollbackRenames] for details.
+
+RollbackRenameJournal 撤销由 [SaveRenameJournal] 写入的日志文件中的改名记录。详见 [RollbackRenames]。
+*/
+func RollbackRenameJournal(filename string) ([]RenameRecord, error) {
+	records, err := LoadRenameJournal(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return RollbackRenames(records)
+}
+
+// executeRenames 执行计划的改名操作，并检测冲突。dryRun 为 true 时只检测冲突，不改名。
+// renameOnCollision 为 true 时，冲突的文件使用唯一的文件名，否则跳过。
+func executeRenames(plans []RenameRecord, dryRun bool, renameOnCollision bool) (*RenameResult, error) {
+	result := &RenameResult{
+		Renamed:    make([]RenameRecord, 0, len(plans)),
+		Collisions: make([]RenameRecord, 0),
+	}
+
+	// 已计划使用的路径，用于在执行改名前发现多个文件改为同一个名称的冲突。统一用小写，兼顾大小写不敏感的文件系统。
+	planned := make(map[string]bool, len(plans))
+	isTaken := func(path string) (bool, error) {
+		if planned[strings.ToLower(path)] {
+			return true, nil
+		}
+
+		exists, _, err := FileExists(path)
+		return exists, err
+	}
+
+	for _, plan := range plans {
+		taken, err := isTaken(plan.To)
+		if err != nil {
+			return result, err
+		}
+
+		// 在大小写不敏感的文件系统上，仅大小写不同的新路径与原文件是同一个文件，不算冲突。
+		if taken && !planned[strings.ToLower(plan.To)] && isSameFile(plan.From, plan.To) {
+			taken = false
+		}
+
+		if taken {
+			if !renameOnCollision {
+				result.Collisions = append(result.Collisions, plan)
+				continue
+			}
+
+			if plan.To, err = getUniqueFilePath(plan.To, isTaken); err != nil {
+				return result, err
+			}
+		}
+
+		planned[strings.ToLower(plan.To)] = true
+
+		if !dryRun {
+			if err = os.Rename(plan.From, plan.To); err != nil {
+				return result, err
+			}
+		}
+
+		result.Renamed = append(result.Renamed, plan)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,240 @@
+package fileutils
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+/*
+RotateBackups shifts the backups of the file by one generation and moves the file itself to the first generation.
+Backups are named path.1, path.2 ... path.N, where path.1 is the newest. Compressed backups have the ".gz" suffix.
+The oldest backup beyond keep generations is removed.
+
+Parameters:
+  - path: the file to back up. It no longer exists after rotation.
+  - keep: the number of backups to keep. 0 means the file is removed.
+  - compress: if true, the file is compressed by gzip to path.1.gz. See [CompressFile].
+
+RotateBackups 将文件的备份依次后移一代，并将文件本身移为第一代备份。
+备份命名为 path.1、path.2 ... path.N，其中 path.1 最新。压缩的备份带有 ".gz" 后缀。超出 keep 代的最旧备份被删除。
+
+参数:
+  - path: 要备份的文件。轮转后该文件不再存在。
+  - keep: 保留的备份数量。0 表示删除该文件。
+  - compress: 为 true 时，使用 gzip 将文件压缩为 path.1.gz。参见 [CompressFile]。
+*/
+func RotateBackups(path string, keep int, compress bool) error {
+	if keep < 0 {
+		return errors.New("keep must not be negative")
+	}
+
+	// 删除将被挤出的第 keep 代，再将其余各代后移。压缩及未压缩的都要处理。
+	for _, suffix := range []string{"", ".gz"} {
+		if keep > 0 {
+			if err := os.Remove(backupName(path, keep) + suffix); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+
+		for i := keep - 1; i >= 1; i-- {
+			if err := os.Rename(backupName(path, i)+suffix, backupName(path, i+1)+suffix); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+
+	if keep == 0 {
+		return os.Remove(path)
+	} else if !compress {
+		return os.Rename(path, backupName(path, 1))
+	}
+
+	if _, err := CompressFile(path, backupName(path, 1)+".gz", nil); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+func backupName(path string, generation int) string {
+	return fmt.Sprintf("%s.%d", path, generation)
+}
+
+/*
+RotatingWriterOption defines the options for [RotatingWriter].
+See [NewRotatingWriterOption] for default settings.
+
+RotatingWriterOption 定义了 [RotatingWriter] 的选项。默认设置参见 [NewRotatingWriterOption]。
+*/
+type RotatingWriterOption struct {
+	MaxSize  int64         // rotate when the file would exceed this size in bytes. 0 means no limit
+	MaxAge   time.Duration // rotate when the file has been written for this long since opened. 0 means no limit
+	Keep     int           // the number of backups to keep. See [RotateBackups]
+	Compress bool          // if true, backups are compressed by gzip
+}
+
+/*
+NewRotatingWriterOption creates a new RotatingWriterOption with 10MB max size, no max age,
+keep 5 backups and no compression.
+
+NewRotatingWriterOption 创建默认的 RotatingWriterOption。包含最大 10MB、无时间限制、保留 5 个备份，以及不压缩。
+*/
+func NewRotatingWriterOption() *RotatingWriterOption {
+	return &RotatingWriterOption{
+		MaxSize:  10 * 1024 * 1024,
+		MaxAge:   0,
+		Keep:     5,
+		Compress: false,
+	}
+}
+
+/*
+RotatingWriter is an io.WriteCloser appending to a file, which is rotated by size and/or age.
+A single write is never split between files, so a write larger than MaxSize goes to a file of its own.
+It is safe for concurrent use.
+
+RotatingWriter 是追加写入文件的 io.WriteCloser，按大小和/或时间轮转文件。
+单次写入不会被拆分到两个文件中，所以大于 MaxSize 的写入会单独占用一个文件。可以并发使用。
+*/
+type RotatingWriter struct {
+	lock   sync.Mutex
+	path   string
+	option RotatingWriterOption
+	file   *os.File
+	size   int64
+	opened time.Time
+	now    func() time.Time // 便于测试时替换。
+}
+
+/*
+NewRotatingWriter opens the file for appending, creating it if necessary.
+
+Parameters:
+  - path: the log file.
+  - option: the options. if nil, the default options will be used.
+
+NewRotatingWriter 以追加方式打开文件，不存在时创建。
+
+参数:
+  - path: 日志文件。
+  - option: 选项。如果为 nil 则使用默认选项。
+*/
+func NewRotatingWriter(path string, option *RotatingWriterOption) (*RotatingWriter, error) {
+	if option == nil { // 保证 option 不为 nil。
+		option = NewRotatingWriterOption()
+	}
+	if option.MaxSize < 0 || option.MaxAge < 0 || option.Keep < 0 {
+		return nil, errors.New("MaxSize, MaxAge and Keep must not be negative")
+	}
+
+	w := &RotatingWriter{path: path, option: *option, now: time.Now}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+/*
+Write writes data to the file, rotating it first if needed.
+
+Write 将数据写入文件，需要时先轮转文件。
+*/
+func (w *RotatingWriter) Write(p []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	if w.shouldRotate(len(p)) {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+/*
+Rotate rotates the file immediately.
+
+Rotate 立即轮转文件。
+*/
+func (w *RotatingWriter) Rotate() error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.file == nil {
+		return os.ErrClosed
+	}
+	return w.rotate()
+}
+
+/*
+Close closes the file. Further writes return os.ErrClosed.
+
+Close 关闭文件。之后的写入返回 os.ErrClosed。
+*/
+func (w *RotatingWriter) Close() error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.file == nil {
+		return nil
+	}
+
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
+
+// shouldRotate 检查写入 size 字节前是否需要轮转。空文件不轮转。
+func (w *RotatingWriter) shouldRotate(size int) bool {
+	if w.size == 0 {
+		return false
+	}
+	if w.option.MaxSize > 0 && w.size+int64(size) > w.option.MaxSize {
+		return true
+	}
+	return w.option.MaxAge > 0 && w.now().Sub(w.opened) >= w.option.MaxAge
+}
+
+func (w *RotatingWriter) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return err
+	}
+	w.file = nil
+
+	if err := RotateBackups(w.path, w.option.Keep, w.option.Compress); err != nil {
+		// 轮转失败时继续写入原文件，避免丢失日志。
+		if openErr := w.open(); openErr != nil {
+			return openErr
+		}
+		return err
+	}
+
+	return w.open()
+}
+
+func (w *RotatingWriter) open() error {
+	file, err := os.OpenFile(w.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	w.file = file
+	w.size = info.Size()
+	w.opened = w.now()
+	return nil
+}
@@ -0,0 +1,115 @@
+package fileutils
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRotateBackups(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "app.log")
+
+	for _, content := range []string{"1", "2", "3", "4"} {
+		assert.Nil(t, os.WriteFile(path, []byte(content), 0644))
+		assert.Nil(t, RotateBackups(path, 2, false))
+	}
+
+	exists, _, _ := FileExists(path)
+	assert.False(t, exists)
+	data, _ := os.ReadFile(path + ".1")
+	assert.Equal(t, "4", string(data))
+	data, _ = os.ReadFile(path + ".2")
+	assert.Equal(t, "3", string(data))
+	exists, _, _ = FileExists(path + ".3")
+	assert.False(t, exists)
+
+	// 压缩，与未压缩的备份一起轮转。
+	assert.Nil(t, os.WriteFile(path, []byte("5"), 0644))
+	assert.Nil(t, RotateBackups(path, 2, true))
+	assert.Equal(t, "5", readGzipFile(t, path+".1.gz"))
+	data, _ = os.ReadFile(path + ".2")
+	assert.Equal(t, "4", string(data))
+
+	assert.Nil(t, os.WriteFile(path, []byte("6"), 0644))
+	assert.Nil(t, RotateBackups(path, 0, false))
+	entries, _ := os.ReadDir(root)
+	assert.Equal(t, 2, len(entries))
+
+	assert.NotNil(t, RotateBackups(path, -1, false))
+}
+
+func TestRotatingWriter(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "app.log")
+
+	option := NewRotatingWriterOption()
+	option.MaxSize = 10
+	option.Keep = 2
+	w, err := NewRotatingWriter(path, option)
+	assert.Nil(t, err)
+
+	for _, line := range []string{"12345\n", "6789\n", "abcde\n", "a very long line\n", "x\n"} {
+		_, err = w.Write([]byte(line))
+		assert.Nil(t, err)
+	}
+
+	data, _ := os.ReadFile(path)
+	assert.Equal(t, "x\n", string(data))
+	data, _ = os.ReadFile(path + ".1")
+	assert.Equal(t, "a very long line\n", string(data))
+	data, _ = os.ReadFile(path + ".2")
+	assert.Equal(t, "abcde\n", string(data))
+	exists, _, _ := FileExists(path + ".3")
+	assert.False(t, exists)
+
+	assert.Nil(t, w.Close())
+	assert.Nil(t, w.Close())
+	_, err = w.Write([]byte("closed"))
+	assert.ErrorIs(t, err, os.ErrClosed)
+
+	// 按时间轮转，并压缩。重新打开时追加到已有文件。
+	option = NewRotatingWriterOption()
+	option.MaxSize = 0
+	option.MaxAge = time.Hour
+	option.Keep = 1
+	option.Compress = true
+	w, err = NewRotatingWriter(path, option)
+	assert.Nil(t, err)
+	defer w.Close()
+
+	now := time.Now()
+	w.now = func() time.Time { return now }
+	w.opened = now
+
+	_, err = w.Write([]byte("y\n"))
+	assert.Nil(t, err)
+
+	now = now.Add(time.Hour)
+	_, err = w.Write([]byte("z\n"))
+	assert.Nil(t, err)
+
+	data, _ = os.ReadFile(path)
+	assert.Equal(t, "z\n", string(data))
+	assert.Equal(t, "x\ny\n", readGzipFile(t, path+".1.gz"))
+
+	_, err = NewRotatingWriter(path, &RotatingWriterOption{Keep: -1})
+	assert.NotNil(t, err)
+}
+
+func readGzipFile(t *testing.T, path string) string {
+	file, err := os.Open(path)
+	assert.Nil(t, err)
+	defer file.Close()
+
+	reader, err := gzip.NewReader(file)
+	assert.Nil(t, err)
+	data, err := io.ReadAll(reader)
+	assert.Nil(t, err)
+	return string(data)
+}
@@ -0,0 +1,159 @@
+package fileutils
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/jqk/futool4go/common"
+)
+
+/*
+Shred overwrites the content of the file with random data for the given number of passes,
+then renames it to a random name and removes it.
+
+Shred only helps on file systems that overwrite data in place, such as ext4 or NTFS on a hard disk.
+It does NOT guarantee the data is unrecoverable on:
+  - SSDs and flash storage, whose wear leveling writes new data to different physical blocks.
+  - copy-on-write or journaling file systems that keep old blocks, such as btrfs, ZFS, APFS, or ext4 with data=journal.
+  - snapshots, backups and network file systems.
+
+For such storage, use full disk encryption or the device's secure erase instead.
+
+Parameters:
+  - path: the file to shred.
+  - passes: the number of overwrite passes. Must be at least 1.
+
+Shred 使用随机数据覆盖文件内容给定的次数，然后将其改为随机的名称并删除。
+
+Shred 只在就地覆盖数据的文件系统上有效，例如机械硬盘上的 ext4 或 NTFS。在以下情况下，不能保证数据无法恢复:
+  - SSD 及闪存。其磨损均衡机制会将新数据写入不同的物理块。
+  - 保留旧数据块的写时复制或日志文件系统。例如 btrfs、ZFS、APFS，或 data=journal 模式的 ext4。
+  - 快照、备份及网络文件系统。
+
+对于这些存储，请使用全盘加密或设备自身的安全擦除功能。
+
+参数:
+  - path: 要粉碎的文件。
+  - passes: 覆盖次数。至少为 1。
+*/
+func Shred(path string, passes int) error {
+	if passes < 1 {
+		return errors.New("passes must be at least 1")
+	}
+
+	info, err := os.Lstat(path)
+	if err != nil {
+		return err
+	} else if !info.Mode().IsRegular() {
+		return errors.New("not a regular file: " + path)
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+
+	buffer := common.DefaultBufferManager.Get(64 * 1024)
+	defer common.DefaultBufferManager.Put(buffer)
+	for i := 0; i < passes && err == nil; i++ {
+		err = overwriteFile(file, info.Size(), buffer)
+	}
+
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	// 改为随机名称，避免在目录项中留下原文件名。
+	name, err := common.RandomName(16)
+	if err != nil {
+		return err
+	}
+
+	randomPath := filepath.Join(filepath.Dir(path), "."+name)
+	if err = os.Rename(path, randomPath); err != nil {
+		return err
+	}
+	return os.Remove(randomPath)
+}
+
+// overwriteFile 从头开始用随机数据覆盖 size 字节，并写入磁盘。
+func overwriteFile(file *os.File, size int64, buffer []byte) error {
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	for remaining := size; remaining > 0; {
+		chunk := buffer
+		if remaining < int64(len(chunk)) {
+			chunk = chunk[:remaining]
+		}
+
+		if _, err := rand.Read(chunk); err != nil {
+			return err
+		}
+		if _, err := file.Write(chunk); err != nil {
+			return err
+		}
+		remaining -= int64(len(chunk))
+	}
+
+	return file.Sync()
+}
+
+/*
+ShredFiles shreds all files matching the filter under the given directory. See [Shred] for its limitations.
+Directories are kept.
+
+Parameters:
+  - root: the directory to scan.
+  - filter: only files matching the filter are shredded. nil means all files.
+  - passes: the number of overwrite passes. Must be at least 1.
+  - option: the scan options. if nil, the default options will be used.
+
+Returns:
+  - the shredded files. Partial result is returned along with the error.
+  - an error if any occurred.
+
+ShredFiles 粉碎给定目录下所有满足过滤条件的文件。其局限性参见 [Shred]。保留目录。
+
+参数:
+  - root: 要扫描的目录。
+  - filter: 只粉碎满足过滤条件的文件。nil 表示所有文件。
+  - passes: 覆盖次数。至少为 1。
+  - option: 扫描选项。如果为 nil 则使用默认选项。
+
+返回:
+  - 已粉碎的文件。出错时返回已完成的部分结果。
+  - 错误信息。
+*/
+func ShredFiles(root string, filter *Filter, passes int, option *WalkOption) ([]string, error) {
+	shredded := []string{}
+	if passes < 1 {
+		return shredded, errors.New("passes must be at least 1")
+	}
+
+	// 先收集文件，再粉碎，避免在遍历的同时修改目录内容。
+	paths := []string{}
+	err := walkFilteredFiles(root, filter, option, func(relPath string, path string, info os.FileInfo) error {
+		paths = append(paths, path)
+		return nil
+	})
+	if err != nil {
+		return shredded, err
+	}
+
+	for _, path := range paths {
+		if err = Shred(path, passes); err != nil {
+			return shredded, err
+		}
+		shredded = append(shredded, path)
+	}
+
+	return shredded, nil
+}
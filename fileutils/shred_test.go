@@ -0,0 +1,42 @@
+package fileutils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShred(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "secret.txt")
+	assert.Nil(t, os.WriteFile(path, make([]byte, 100*1024), 0644))
+
+	assert.Nil(t, Shred(path, 2))
+	entries, _ := os.ReadDir(root)
+	assert.Equal(t, 0, len(entries))
+
+	assert.NotNil(t, Shred(path, 1))
+	assert.NotNil(t, Shred(root, 1))
+
+	assert.Nil(t, os.WriteFile(path, []byte("secret"), 0644))
+	assert.NotNil(t, Shred(path, 0))
+}
+
+func TestShredFiles(t *testing.T) {
+	root := t.TempDir()
+	assert.Nil(t, os.MkdirAll(filepath.Join(root, "sub"), 0755))
+	for _, name := range []string{"a.key", "b.txt", "sub/c.key"} {
+		assert.Nil(t, os.WriteFile(filepath.Join(root, name), []byte(name), 0644))
+	}
+
+	shredded, err := ShredFiles(root, &Filter{Include: []string{"*.key"}}, 1, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{filepath.Join(root, "a.key"), filepath.Join(root, "sub", "c.key")}, shredded)
+
+	stat, err := GetDirStatistics(root, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, stat.FileCount)
+	assert.Equal(t, 2, stat.DirCount)
+}
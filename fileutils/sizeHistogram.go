@@ -0,0 +1,142 @@
+package fileutils
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/jqk/futool4go/common"
+)
+
+/*
+DefaultSizeBuckets defines the default bucket bounds of [SizeHistogram]:
+<1KB, 1KB-100KB, 100KB-10MB, 10MB-1GB and >=1GB.
+
+DefaultSizeBuckets 定义了 [SizeHistogram] 默认的分组边界：<1KB、1KB-100KB、100KB-10MB、10MB-1GB 及 >=1GB。
+*/
+var DefaultSizeBuckets = []int64{1 << 10, 100 << 10, 10 << 20, 1 << 30}
+
+/*
+SizeHistogram records the distribution of file sizes.
+
+SizeHistogram 记录文件大小的分布情况。
+*/
+type SizeHistogram struct {
+	/*
+		Upper bounds of the buckets in bytes, exclusive and ascending.
+		For example, bounds [1024, 4096] define 3 buckets: <1024, 1024-4095 and >=4096.
+	*/
+	Bounds []int64
+	Counts []int // File count of each bucket. Its length is len(Bounds)+1.
+}
+
+/*
+NewSizeHistogram creates a new [SizeHistogram] with the given bucket bounds.
+
+Parameters:
+  - bounds: upper bounds of the buckets in bytes, exclusive. Must be ascending without duplicates.
+
+Returns:
+  - the histogram.
+  - an error if the bounds are invalid.
+
+NewSizeHistogram 使用给定的分组边界创建 [SizeHistogram]。
+
+参数:
+  - bounds: 各分组的上界，单位为字节，不包含该值。必须升序且无重复。
+
+返回:
+  - 直方图。
+  - 错误信息。
+*/
+func NewSizeHistogram(bounds []int64) (*SizeHistogram, error) {
+	if err := validateSizeBuckets(bounds); err != nil {
+		return nil, err
+	}
+
+	return &SizeHistogram{
+		Bounds: append([]int64{}, bounds...),
+		Counts: make([]int, len(bounds)+1),
+	}, nil
+}
+
+/*
+Add counts a file of the given size into its bucket.
+
+Add 将给定大小的文件计入其所属的分组。
+*/
+func (h *SizeHistogram) Add(size int64) {
+	// 找到第一个大于 size 的上界，即为所属分组。都不大于时，为最后一个分组。
+	index := sort.Search(len(h.Bounds), func(i int) bool {
+		return h.Bounds[i] > size
+	})
+	h.Counts[index]++
+}
+
+/*
+Merge adds the counts of other histogram into this one. Both must have the same bounds.
+
+Merge 将另一个直方图的计数累加到本直方图中。两者的分组边界必须相同。
+*/
+func (h *SizeHistogram) Merge(other *SizeHistogram) error {
+	if len(h.Bounds) != len(other.Bounds) {
+		return errors.New("histogram bounds mismatch")
+	}
+	for i, bound := range h.Bounds {
+		if bound != other.Bounds[i] {
+			return errors.New("histogram bounds mismatch")
+		}
+	}
+
+	for i, count := range other.Counts {
+		h.Counts[i] += count
+	}
+	return nil
+}
+
+/*
+Labels returns human-readable labels of the buckets, such as "<1 KB", "1 KB-100 KB" and ">=1 GB".
+
+Labels 返回各分组的可读标签，例如 "<1 KB"、"1 KB-100 KB" 及 ">=1 GB"。
+*/
+func (h *SizeHistogram) Labels() []string {
+	labels := make([]string, 0, len(h.Counts))
+	format := func(size int64) string {
+		return common.ToSizeString(size, 0)
+	}
+
+	for i, bound := range h.Bounds {
+		if i == 0 {
+			labels = append(labels, "<"+format(bound))
+		} else {
+			labels = append(labels, fmt.Sprintf("%s-%s", format(h.Bounds[i-1]), format(bound)))
+		}
+	}
+
+	if len(h.Bounds) == 0 {
+		labels = append(labels, "all")
+	} else {
+		labels = append(labels, ">="+format(h.Bounds[len(h.Bounds)-1]))
+	}
+
+	return labels
+}
+
+// clone 返回直方图的深拷贝。
+func (h *SizeHistogram) clone() *SizeHistogram {
+	return &SizeHistogram{
+		Bounds: append([]int64{}, h.Bounds...),
+		Counts: append([]int{}, h.Counts...),
+	}
+}
+
+func validateSizeBuckets(bounds []int64) error {
+	for i, bound := range bounds {
+		if bound <= 0 {
+			return errors.New("size bucket bound must be greater than 0")
+		} else if i > 0 && bound <= bounds[i-1] {
+			return errors.New("size bucket bounds must be ascending without duplicates")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package fileutils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSizeHistogram(t *testing.T) {
+	_, err := NewSizeHistogram([]int64{100, 100})
+	assert.NotNil(t, err)
+	_, err = NewSizeHistogram([]int64{0})
+	assert.NotNil(t, err)
+
+	h, err := NewSizeHistogram(DefaultSizeBuckets)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"<1 KB", "1 KB-100 KB", "100 KB-10 MB", "10 MB-1 GB", ">=1 GB"}, h.Labels())
+
+	for _, size := range []int64{0, 1023, 1024, 100 << 10, 5 << 30} {
+		h.Add(size)
+	}
+	assert.Equal(t, []int{2, 1, 1, 0, 1}, h.Counts)
+
+	other, _ := NewSizeHistogram(DefaultSizeBuckets)
+	other.Add(2 << 20)
+	assert.Nil(t, h.Merge(other))
+	assert.Equal(t, []int{2, 1, 2, 0, 1}, h.Counts)
+
+	other, _ = NewSizeHistogram([]int64{1})
+	assert.NotNil(t, h.Merge(other))
+}
+
+func TestGetExtensionsWithHistogram(t *testing.T) {
+	option := NewWalkExtensionOption()
+	option.SizeBuckets = []int64{10, 50}
+
+	extensions, err := GetFileExtensions("../test-data/fileutils/extension", option, nil)
+	assert.Nil(t, err)
+
+	for _, ext := range extensions {
+		assert.NotNil(t, ext.Histogram)
+
+		total := 0
+		for _, count := range ext.Histogram.Counts {
+			total += count
+		}
+		assert.Equal(t, ext.Count, total)
+	}
+
+	option.SizeBuckets = []int64{50, 10}
+	_, err = GetFileExtensions("../test-data/fileutils/extension", option, nil)
+	assert.NotNil(t, err)
+}
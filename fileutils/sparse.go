@@ -0,0 +1,125 @@
+package fileutils
+
+import (
+	"io"
+	"os"
+)
+
+// sparseBlockSize 是检测全零块的单位，与常见文件系统的块大小相同。
+const sparseBlockSize = 4096
+
+/*
+holeReader 读取可能带有空洞的文件。在支持 SEEK_DATA/SEEK_HOLE 的系统上，空洞部分直接返回零而不读取磁盘。
+其它系统上与普通读取相同。
+*/
+type holeReader struct {
+	file     *os.File
+	size     int64
+	offset   int64
+	dataEnd  int64 // 当前数据区的结束位置。offset 小于该值时处于数据区。
+	holeEnd  int64 // 当前空洞的结束位置。offset 小于该值时处于空洞。
+	disabled bool  // 系统不支持检测空洞时为 true。
+}
+
+func newHoleReader(file *os.File, size int64) *holeReader {
+	return &holeReader{file: file, size: size}
+}
+
+func (r *holeReader) Read(p []byte) (int, error) {
+	if r.offset >= r.size {
+		return 0, io.EOF
+	}
+
+	if !r.disabled && r.offset >= r.dataEnd && r.offset >= r.holeEnd {
+		r.locate()
+	}
+
+	if r.offset < r.holeEnd {
+		n := len(p)
+		if int64(n) > r.holeEnd-r.offset {
+			n = int(r.holeEnd - r.offset)
+		}
+		for i := range p[:n] {
+			p[i] = 0
+		}
+		r.offset += int64(n)
+		return n, nil
+	}
+
+	if !r.disabled && int64(len(p)) > r.dataEnd-r.offset {
+		p = p[:r.dataEnd-r.offset]
+	}
+
+	n, err := r.file.ReadAt(p, r.offset)
+	r.offset += int64(n)
+	if err == io.EOF && n > 0 {
+		err = nil
+	}
+	return n, err
+}
+
+// locate 确定 offset 处是空洞还是数据区，以及其结束位置。
+func (r *holeReader) locate() {
+	data, hole, ok := seekDataAndHole(r.file, r.offset, r.size)
+	if !ok {
+		r.disabled = true
+		return
+	}
+
+	if data > r.offset {
+		r.holeEnd = data
+	} else {
+		r.dataEnd = hole
+	}
+}
+
+/*
+sparseWriter 写入文件时跳过全零的块，在目标文件中留下空洞。必须调用 finish 设置文件的最终长度。
+*/
+type sparseWriter struct {
+	file   *os.File
+	offset int64
+}
+
+func newSparseWriter(file *os.File) *sparseWriter {
+	return &sparseWriter{file: file}
+}
+
+func (w *sparseWriter) Write(p []byte) (int, error) {
+	written := 0
+	for written < len(p) {
+		// 按块对齐，使空洞与文件系统的块边界一致。
+		size := sparseBlockSize - int(w.offset%sparseBlockSize)
+		if size > len(p)-written {
+			size = len(p) - written
+		}
+		chunk := p[written : written+size]
+
+		if isAllZero(chunk) && size == sparseBlockSize {
+			w.offset += int64(size)
+		} else {
+			n, err := w.file.WriteAt(chunk, w.offset)
+			w.offset += int64(n)
+			if err != nil {
+				return written + n, err
+			}
+		}
+		written += size
+	}
+
+	return written, nil
+}
+
+// finish 设置文件长度，使结尾的空洞也计入文件长度。
+func (w *sparseWriter) finish() error {
+	return w.file.Truncate(w.offset)
+}
+
+func isAllZero(data []byte) bool {
+	for _, b := range data {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
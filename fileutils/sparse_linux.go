@@ -0,0 +1,30 @@
+//go:build linux
+
+package fileutils
+
+import (
+	"os"
+	"syscall"
+)
+
+const (
+	seekData = 3 // SEEK_DATA
+	seekHole = 4 // SEEK_HOLE
+)
+
+// seekDataAndHole 返回 offset 之后第一个数据区的开始位置，及其后第一个空洞的开始位置。
+// 没有更多数据时，data 与 hole 都为 size。系统或文件系统不支持时 ok 为 false。
+func seekDataAndHole(file *os.File, offset int64, size int64) (data int64, hole int64, ok bool) {
+	data, err := syscall.Seek(int(file.Fd()), offset, seekData)
+	if err == syscall.ENXIO {
+		return size, size, true // offset 之后都是空洞。
+	} else if err != nil {
+		return 0, 0, false
+	}
+
+	hole, err = syscall.Seek(int(file.Fd()), data, seekHole)
+	if err != nil {
+		return 0, 0, false
+	}
+	return data, hole, true
+}
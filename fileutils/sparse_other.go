@@ -0,0 +1,10 @@
+//go:build !linux
+
+package fileutils
+
+import "os"
+
+// seekDataAndHole 在不支持 SEEK_DATA/SEEK_HOLE 的系统上总是返回 ok 为 false。
+func seekDataAndHole(file *os.File, offset int64, size int64) (data int64, hole int64, ok bool) {
+	return 0, 0, false
+}
@@ -0,0 +1,112 @@
+package fileutils
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// createSparseFile 创建 1MB 的稀疏文件，只在开头、中间及结尾附近写入数据。
+func createSparseFile(t *testing.T, path string) []byte {
+	content := make([]byte, 1024*1024)
+	copy(content, "head")
+	copy(content[512*1024:], "middle")
+	copy(content[len(content)-100:], "tail")
+
+	file, err := os.Create(path)
+	assert.Nil(t, err)
+	defer file.Close()
+
+	assert.Nil(t, file.Truncate(int64(len(content))))
+	for _, offset := range []int{0, 512 * 1024, len(content) - 100} {
+		_, err = file.WriteAt(content[offset:offset+10], int64(offset))
+		assert.Nil(t, err)
+	}
+	return content
+}
+
+func TestCopyFileSparse(t *testing.T) {
+	root := t.TempDir()
+	src := filepath.Join(root, "src.bin")
+	dst := filepath.Join(root, "dst.bin")
+	content := createSparseFile(t, src)
+
+	option := NewCopyOption()
+	option.Verify = sha256.New()
+	copied, err := CopyFile(src, dst, option)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(len(content)), copied)
+
+	data, err := os.ReadFile(dst)
+	assert.Nil(t, err)
+	assert.True(t, bytes.Equal(content, data))
+
+	info, err := os.Stat(dst)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(len(content)), info.Size())
+	assert.LessOrEqual(t, physicalSize(info), info.Size())
+
+	// 不保留空洞时内容相同。
+	option.Sparse = false
+	_, err = CopyFile(src, dst, option)
+	assert.Nil(t, err)
+	data, err = os.ReadFile(dst)
+	assert.Nil(t, err)
+	assert.True(t, bytes.Equal(content, data))
+}
+
+func TestCopyFileSkipZeros(t *testing.T) {
+	root := t.TempDir()
+	src := filepath.Join(root, "zeros.bin")
+	dst := filepath.Join(root, "copy.bin")
+
+	// 以普通方式写入的全零文件，结尾为零时也要保持长度。
+	content := make([]byte, 256*1024+123)
+	content[100] = 1
+	assert.Nil(t, os.WriteFile(src, content, 0644))
+
+	_, err := CopyFile(src, dst, nil)
+	assert.Nil(t, err)
+
+	data, err := os.ReadFile(dst)
+	assert.Nil(t, err)
+	assert.True(t, bytes.Equal(content, data))
+}
+
+func TestSparseWriter(t *testing.T) {
+	file, err := os.Create(filepath.Join(t.TempDir(), "sparse.bin"))
+	assert.Nil(t, err)
+	defer file.Close()
+
+	writer := newSparseWriter(file)
+	content := make([]byte, 3*sparseBlockSize+10)
+	content[sparseBlockSize+1] = 7
+
+	n, err := writer.Write(content)
+	assert.Nil(t, err)
+	assert.Equal(t, len(content), n)
+	assert.Nil(t, writer.finish())
+
+	info, err := file.Stat()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(len(content)), info.Size())
+
+	data, err := os.ReadFile(file.Name())
+	assert.Nil(t, err)
+	assert.True(t, bytes.Equal(content, data))
+}
+
+func TestDirStatisticsPhysicalSize(t *testing.T) {
+	root := t.TempDir()
+	content := createSparseFile(t, filepath.Join(root, "sparse.bin"))
+
+	stat, err := GetDirStatistics(root, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(len(content)), stat.TotalSize)
+	assert.Greater(t, stat.PhysicalSize, int64(0))
+	assert.LessOrEqual(t, stat.PhysicalSize, stat.TotalSize)
+}
@@ -0,0 +1,531 @@
+package fileutils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/traditionalchinese"
+)
+
+/*
+TextEncoding is the name of a text encoding.
+
+TextEncoding 是文本编码的名称。
+*/
+type TextEncoding string
+
+const (
+	EncodingUnknown TextEncoding = ""
+	EncodingUTF8    TextEncoding = "UTF-8"
+	EncodingUTF16LE TextEncoding = "UTF-16LE"
+	EncodingUTF16BE TextEncoding = "UTF-16BE"
+	EncodingGBK     TextEncoding = "GBK"
+	EncodingBig5    TextEncoding = "Big5"
+)
+
+// encodingSampleSize 是检测编码时读取的最大字节数。
+const encodingSampleSize = 64 * 1024
+
+var (
+	utf8BOM    = []byte{0xef, 0xbb, 0xbf}
+	utf16LEBOM = []byte{0xff, 0xfe}
+	utf16BEBOM = []byte{0xfe, 0xff}
+)
+
+/*
+ErrUnknownEncoding is returned when the encoding of a file cannot be detected.
+
+ErrUnknownEncoding 在无法检测文件编码时返回。
+*/
+var ErrUnknownEncoding = errors.New("unknown text encoding")
+
+/*
+TextCodec converts text between an encoding and UTF-8.
+
+TextCodec 在某个编码与 UTF-8 之间转换文本。
+*/
+type TextCodec interface {
+	// Decode converts data in the encoding to UTF-8 text. The byte order mark, if any, has been removed.
+	Decode(data []byte) (string, error)
+	// Encode converts UTF-8 text to the encoding, without byte order mark.
+	Encode(text string) ([]byte, error)
+}
+
+var (
+	codecLock = sync.RWMutex{}
+	codecs    = map[TextEncoding]TextCodec{
+		EncodingUTF8:    utf8Codec{},
+		EncodingUTF16LE: utf16Codec{order: binary.LittleEndian},
+		EncodingUTF16BE: utf16Codec{order: binary.BigEndian},
+		EncodingGBK:     xtextCodec{name: EncodingGBK, encoding: simplifiedchinese.GBK},
+		EncodingBig5:    xtextCodec{name: EncodingBig5, encoding: traditionalchinese.Big5},
+	}
+)
+
+/*
+RegisterTextCodec registers the codec of an encoding, replacing the existing one.
+UTF-8, UTF-16LE, UTF-16BE, GBK and Big5 are built in. GBK and Big5 use golang.org/x/text/encoding.
+
+RegisterTextCodec 注册一个编码的转换器，替换已有的转换器。内置了 UTF-8、UTF-16LE、UTF-16BE、GBK 及 Big5。
+GBK 及 Big5 使用 golang.org/x/text/encoding。
+*/
+func RegisterTextCodec(encoding TextEncoding, codec TextCodec) {
+	codecLock.Lock()
+	defer codecLock.Unlock()
+	codecs[encoding] = codec
+}
+
+func getTextCodec(encoding TextEncoding) (TextCodec, error) {
+	codecLock.RLock()
+	defer codecLock.RUnlock()
+
+	codec, ok := codecs[encoding]
+	if !ok {
+		return nil, fmt.Errorf("no codec registered for encoding %q", encoding)
+	}
+	return codec, nil
+}
+
+/*
+DetectEncoding detects the text encoding of the file by the first 64KB.
+
+Returns:
+  - the encoding. [EncodingUnknown] for binary or unrecognized content.
+  - true if the file starts with a byte order mark.
+  - an error if any occurred.
+
+DetectEncoding 根据文件开始的 64KB 检测文本编码。
+
+返回:
+  - 编码。二进制或无法识别的内容返回 [EncodingUnknown]。
+  - 文件以字节顺序标记开始时返回 true。
+  - 错误信息。
+*/
+func DetectEncoding(path string) (TextEncoding, bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return EncodingUnknown, false, err
+	}
+	defer file.Close()
+
+	sample := make([]byte, encodingSampleSize)
+	count, err := io.ReadFull(file, sample)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return EncodingUnknown, false, err
+	}
+
+	encoding, hasBOM := detectEncoding(sample[:count], count == encodingSampleSize)
+	return encoding, hasBOM, nil
+}
+
+/*
+DetectEncodingBytes detects the text encoding of the data. See [DetectEncoding].
+
+DetectEncodingBytes 检测数据的文本编码。参见 [DetectEncoding]。
+*/
+func DetectEncodingBytes(data []byte) (TextEncoding, bool) {
+	return detectEncoding(data, false)
+}
+
+// detectEncoding 检测编码。truncated 为 true 时，data 只是开头的一部分，结尾可能有不完整的字符。
+func detectEncoding(data []byte, truncated bool) (TextEncoding, bool) {
+	switch {
+	case bytes.HasPrefix(data, utf8BOM):
+		return EncodingUTF8, true
+	case bytes.HasPrefix(data, utf16LEBOM):
+		return EncodingUTF16LE, true
+	case bytes.HasPrefix(data, utf16BEBOM):
+		return EncodingUTF16BE, true
+	}
+
+	if encoding := detectUTF16(data); encoding != EncodingUnknown {
+		return encoding, false
+	}
+
+	header := data
+	if len(header) > DefaultSniffSize {
+		header = header[:DefaultSniffSize]
+	}
+	if IsBinaryContent(header) {
+		return EncodingUnknown, false
+	}
+
+	if truncated {
+		// 去掉结尾可能不完整的 UTF-8 字符。
+		for i := 1; i < utf8.UTFMax && i <= len(data); i++ {
+			if utf8.RuneStart(data[len(data)-i]) {
+				if !utf8.FullRune(data[len(data)-i:]) {
+					data = data[:len(data)-i]
+				}
+				break
+			}
+		}
+	}
+
+	if utf8.Valid(data) {
+		return EncodingUTF8, false
+	}
+
+	return detectDoubleByte(data, truncated), false
+}
+
+// detectUTF16 检测没有字节顺序标记的 UTF-16。依据是以 ASCII 为主的文本中，每两个字节中有一个是 0。
+func detectUTF16(data []byte) TextEncoding {
+	if len(data) < 4 {
+		return EncodingUnknown
+	}
+
+	evenZeros, oddZeros := 0, 0
+	for i := 0; i+1 < len(data); i += 2 {
+		if data[i] == 0 {
+			evenZeros++
+		}
+		if data[i+1] == 0 {
+			oddZeros++
+		}
+	}
+
+	pairs := len(data) / 2
+	if oddZeros*10 >= pairs*4 && evenZeros*10 < pairs {
+		return EncodingUTF16LE
+	} else if evenZeros*10 >= pairs*4 && oddZeros*10 < pairs {
+		return EncodingUTF16BE
+	}
+	return EncodingUnknown
+}
+
+/*
+detectDoubleByte 根据字节结构区分 GBK 及 Big5，不使用码表。
+
+  - GBK: 首字节 0x81-0xFE，次字节 0x40-0xFE（0x7F 除外）。
+  - Big5: 首字节 0xA1-0xF9，次字节 0x40-0x7E 或 0xA1-0xFE。
+
+不满足 Big5 的结构时只能是 GBK。两者都有效时，常用汉字在 GB2312 中的次字节都是 0xA1-0xFE，首字节都不在 0xA4-0xAF，
+而 Big5 中大量常用汉字的次字节在 0x40-0x7E，或首字节在 0xA4-0xAF，据此判断。
+*/
+func detectDoubleByte(data []byte, truncated bool) TextEncoding {
+	validBig5 := true
+	pairs, big5Hints := 0, 0
+
+	for i := 0; i < len(data); i++ {
+		lead := data[i]
+		if lead < 0x80 {
+			continue
+		} else if lead == 0x80 || lead == 0xff {
+			return EncodingUnknown
+		}
+
+		if i+1 >= len(data) {
+			if truncated {
+				break
+			}
+			return EncodingUnknown
+		}
+
+		trail := data[i+1]
+		i++
+		pairs++
+
+		if trail < 0x40 || trail == 0x7f || trail == 0xff {
+			return EncodingUnknown
+		}
+		if lead < 0xa1 || lead > 0xf9 || trail >= 0x80 && trail <= 0xa0 {
+			validBig5 = false
+		}
+		if trail <= 0x7e || lead >= 0xa4 && lead <= 0xaf {
+			big5Hints++
+		}
+	}
+
+	switch {
+	case pairs == 0:
+		return EncodingUnknown
+	case !validBig5:
+		return EncodingGBK
+	case big5Hints*10 > pairs:
+		return EncodingBig5
+	default:
+		return EncodingGBK
+	}
+}
+
+/*
+ConvertEncoding converts the text file src from one encoding to another and writes it to dst atomically.
+src and dst can be the same file.
+
+The byte order mark of src is removed. A byte order mark is written for UTF-16, but not for UTF-8.
+
+Parameters:
+  - src: the source file.
+  - dst: the destination file.
+  - from: the encoding of src. [EncodingUnknown] means detect it by [DetectEncoding].
+  - to: the encoding of dst.
+
+ConvertEncoding 将文本文件 src 从一种编码转换为另一种，并以原子方式写入 dst。src 与 dst 可以是同一个文件。
+
+src 的字节顺序标记会被去掉。UTF-16 会写入字节顺序标记，UTF-8 则不写入。
+
+参数:
+  - src: 源文件。
+  - dst: 目标文件。
+  - from: src 的编码。[EncodingUnknown] 表示使用 [DetectEncoding] 检测。
+  - to: dst 的编码。
+*/
+func ConvertEncoding(src string, dst string, from TextEncoding, to TextEncoding) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+
+	converted, err := convertEncoding(data, from, to)
+	if err != nil {
+		return fmt.Errorf("%s: %w", src, err)
+	}
+
+	return writeFileAtomic(dst, converted)
+}
+
+// convertEncoding 转换数据的编码。from 为 EncodingUnknown 时自动检测。
+func convertEncoding(data []byte, from TextEncoding, to TextEncoding) ([]byte, error) {
+	if from == EncodingUnknown {
+		if from, _ = DetectEncodingBytes(data); from == EncodingUnknown {
+			return nil, ErrUnknownEncoding
+		}
+	}
+
+	decoder, err := getTextCodec(from)
+	if err != nil {
+		return nil, err
+	}
+	encoder, err := getTextCodec(to)
+	if err != nil {
+		return nil, err
+	}
+
+	switch from {
+	case EncodingUTF8:
+		data = bytes.TrimPrefix(data, utf8BOM)
+	case EncodingUTF16LE:
+		data = bytes.TrimPrefix(data, utf16LEBOM)
+	case EncodingUTF16BE:
+		data = bytes.TrimPrefix(data, utf16BEBOM)
+	}
+
+	text, err := decoder.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	converted, err := encoder.Encode(text)
+	if err != nil {
+		return nil, err
+	}
+
+	switch to {
+	case EncodingUTF16LE:
+		converted = append(append([]byte{}, utf16LEBOM...), converted...)
+	case EncodingUTF16BE:
+		converted = append(append([]byte{}, utf16BEBOM...), converted...)
+	}
+
+	return converted, nil
+}
+
+/*
+EncodingConvertOption defines the options for [ConvertTreeEncoding].
+See [NewEncodingConvertOption] for default settings.
+
+EncodingConvertOption 定义了 [ConvertTreeEncoding] 的选项。默认设置参见 [NewEncodingConvertOption]。
+*/
+type EncodingConvertOption struct {
+	WalkOption
+	From   TextEncoding // the encoding of the files. EncodingUnknown means detect it for each file
+	DryRun bool         // if true, only report the files to be converted without modifying any file
+}
+
+/*
+NewEncodingConvertOption creates a new EncodingConvertOption with scan directory recursively,
+bypass permission denied error, detect encoding for each file and no dry run.
+
+NewEncodingConvertOption 创建默认的 EncodingConvertOption。包含递归扫描目录、跳过没有权限的文件及目录、
+逐个文件检测编码，以及实际执行转换。
+*/
+func NewEncodingConvertOption() *EncodingConvertOption {
+	return &EncodingConvertOption{
+		WalkOption: WalkOption{
+			Recursive:        true,
+			PathErrorHandler: SkipPermissionError,
+		},
+		From:   EncodingUnknown,
+		DryRun: false,
+	}
+}
+
+/*
+EncodingConvertReport is the result of [ConvertTreeEncoding].
+
+EncodingConvertReport 是 [ConvertTreeEncoding] 的结果。
+*/
+type EncodingConvertReport struct {
+	Converted map[string]TextEncoding // The converted files and their original encodings.
+	Unchanged []string                // The files already in the target encoding.
+	Unknown   []string                // The binary files or files in unknown encoding, which are skipped.
+}
+
+/*
+ConvertTreeEncoding converts the text files under the given directory to the given encoding in place.
+See [ConvertEncoding] for byte order mark handling.
+
+Parameters:
+  - root: the directory to scan.
+  - filter: only files matching the filter are converted. nil means all files.
+  - to: the target encoding.
+  - option: the options. if nil, the default options will be used.
+
+Returns:
+  - the report. Partial report is returned along with the error.
+  - an error if any occurred.
+
+ConvertTreeEncoding 将给定目录下的文本文件就地转换为给定的编码。字节顺序标记的处理参见 [ConvertEncoding]。
+
+参数:
+  - root: 要扫描的目录。
+  - filter: 只转换满足过滤条件的文件。nil 表示所有文件。
+  - to: 目标编码。
+  - option: 选项。如果为 nil 则使用默认选项。
+
+返回:
+  - 转换报告。出错时返回已完成的部分报告。
+  - 错误信息。
+*/
+func ConvertTreeEncoding(root string, filter *Filter, to TextEncoding, option *EncodingConvertOption) (*EncodingConvertReport, error) {
+	report := &EncodingConvertReport{
+		Converted: map[string]TextEncoding{},
+		Unchanged: []string{},
+		Unknown:   []string{},
+	}
+
+	if option == nil { // 保证 option 不为 nil。
+		option = NewEncodingConvertOption()
+	}
+
+	// 提前检查，避免转换到一半才发现没有注册转换器。
+	if _, err := getTextCodec(to); err != nil {
+		return report, err
+	}
+
+	err := walkFilteredFiles(root, filter, &option.WalkOption, func(relPath string, path string, info os.FileInfo) error {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		from, hasBOM := option.From, false
+		if from == EncodingUnknown {
+			if from, hasBOM = DetectEncodingBytes(data); from == EncodingUnknown {
+				report.Unknown = append(report.Unknown, path)
+				return nil
+			}
+		}
+
+		// UTF-8 带 BOM 时，转换为 UTF-8 也需要去掉 BOM。
+		if from == to && !(to == EncodingUTF8 && hasBOM) {
+			report.Unchanged = append(report.Unchanged, path)
+			return nil
+		}
+
+		converted, err := convertEncoding(data, from, to)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		if !option.DryRun {
+			if err = writeFileAtomic(path, converted); err != nil {
+				return err
+			}
+		}
+
+		report.Converted[path] = from
+		return nil
+	})
+
+	return report, err
+}
+
+type utf8Codec struct{}
+
+func (utf8Codec) Decode(data []byte) (string, error) {
+	if !utf8.Valid(data) {
+		return "", errors.New("invalid UTF-8 data")
+	}
+	return string(data), nil
+}
+
+func (utf8Codec) Encode(text string) ([]byte, error) {
+	return []byte(text), nil
+}
+
+type utf16Codec struct {
+	order binary.ByteOrder
+}
+
+func (c utf16Codec) Decode(data []byte) (string, error) {
+	if len(data)%2 != 0 {
+		return "", errors.New("invalid UTF-16 data: odd length")
+	}
+
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		units[i] = c.order.Uint16(data[2*i:])
+	}
+
+	return string(utf16.Decode(units)), nil
+}
+
+func (c utf16Codec) Encode(text string) ([]byte, error) {
+	units := utf16.Encode([]rune(text))
+	result := make([]byte, len(units)*2)
+
+	for i, unit := range units {
+		c.order.PutUint16(result[2*i:], unit)
+	}
+
+	return result, nil
+}
+
+// xtextCodec 使用 golang.org/x/text/encoding 转换，用于 GBK 及 Big5。
+type xtextCodec struct {
+	name     TextEncoding
+	encoding encoding.Encoding
+}
+
+func (c xtextCodec) Decode(data []byte) (string, error) {
+	text, err := c.encoding.NewDecoder().String(string(data))
+	if err != nil {
+		return "", err
+	}
+
+	// 解码器将无效的字节替换为 U+FFFD，而这两种编码中没有该字符，所以出现时说明数据无效。
+	if strings.ContainsRune(text, utf8.RuneError) {
+		return "", fmt.Errorf("invalid %s data", c.name)
+	}
+	return text, nil
+}
+
+func (c xtextCodec) Encode(text string) ([]byte, error) {
+	result, err := c.encoding.NewEncoder().String(text)
+	if err != nil {
+		return nil, fmt.Errorf("cannot encode text to %s: %w", c.name, err)
+	}
+	return []byte(result), nil
+}
@@ -0,0 +1,169 @@
+package fileutils
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 测试数据: "中文测试，hello" 的 GBK 编码及 "中文測試，這是" 的 Big5 编码。
+var (
+	gbkSample  = []byte("\xd6\xd0\xce\xc4\xb2\xe2\xca\xd4\xa3\xac" + "hello")
+	big5Sample = []byte("\xa4\xa4\xa4\xe5\xb4\xfa\xb8\xd5\xa1\x41\xb3\x6f\xac\x4f")
+)
+
+func TestDetectEncodingBytes(t *testing.T) {
+	cases := []struct {
+		data     []byte
+		encoding TextEncoding
+		hasBOM   bool
+	}{
+		{[]byte("plain ascii"), EncodingUTF8, false},
+		{[]byte("中文"), EncodingUTF8, false},
+		{[]byte("\xef\xbb\xbf中文"), EncodingUTF8, true},
+		{[]byte("\xff\xfeh\x00i\x00"), EncodingUTF16LE, true},
+		{[]byte("\xfe\xff\x00h\x00i"), EncodingUTF16BE, true},
+		{[]byte("h\x00e\x00l\x00l\x00o\x00"), EncodingUTF16LE, false},
+		{[]byte("\x00h\x00e\x00l\x00l\x00o"), EncodingUTF16BE, false},
+		{gbkSample, EncodingGBK, false},
+		{big5Sample, EncodingBig5, false},
+		{[]byte("\x81\x40\x81\x41"), EncodingGBK, false},
+		{[]byte("%PDF-1.7\n"), EncodingUnknown, false},
+		{[]byte("\x01\x00\x00\x00\x02\x03"), EncodingUnknown, false},
+		{[]byte("abc\xff"), EncodingUnknown, false},
+	}
+
+	for _, c := range cases {
+		encoding, hasBOM := DetectEncodingBytes(c.data)
+		assert.Equal(t, c.encoding, encoding, "%q", c.data)
+		assert.Equal(t, c.hasBOM, hasBOM, "%q", c.data)
+	}
+
+	// 截断处的不完整字符不影响检测。
+	root := t.TempDir()
+	path := filepath.Join(root, "long.txt")
+	assert.Nil(t, os.WriteFile(path, []byte(strings.Repeat("a", encodingSampleSize-1)+"中"), 0644))
+	encoding, _, err := DetectEncoding(path)
+	assert.Nil(t, err)
+	assert.Equal(t, EncodingUTF8, encoding)
+
+	_, _, err = DetectEncoding(filepath.Join(root, "not-exist"))
+	assert.NotNil(t, err)
+}
+
+func TestConvertEncoding(t *testing.T) {
+	root := t.TempDir()
+	src := filepath.Join(root, "src.txt")
+	dst := filepath.Join(root, "dst.txt")
+	assert.Nil(t, os.WriteFile(src, []byte("\xef\xbb\xbf中文 text 😀"), 0644))
+
+	// UTF-8 转 UTF-16LE，写入 BOM。
+	assert.Nil(t, ConvertEncoding(src, dst, EncodingUnknown, EncodingUTF16LE))
+	data, _ := os.ReadFile(dst)
+	assert.Equal(t, []byte("\xff\xfe\x2d\x4e\x87\x65"), data[:6])
+
+	// 转回 UTF-8，不写入 BOM。
+	assert.Nil(t, ConvertEncoding(dst, dst, EncodingUnknown, EncodingUTF8))
+	data, _ = os.ReadFile(dst)
+	assert.Equal(t, "中文 text 😀", string(data))
+
+	assert.Nil(t, ConvertEncoding(src, dst, EncodingUTF8, EncodingUTF16BE))
+	assert.Nil(t, ConvertEncoding(dst, dst, EncodingUTF16BE, EncodingUTF8))
+	data, _ = os.ReadFile(dst)
+	assert.Equal(t, "中文 text 😀", string(data))
+
+	// GBK 及 Big5 转为 UTF-8，再转回。
+	assert.Nil(t, os.WriteFile(src, gbkSample, 0644))
+	assert.Nil(t, ConvertEncoding(src, dst, EncodingUnknown, EncodingUTF8))
+	data, _ = os.ReadFile(dst)
+	assert.Equal(t, "中文测试，hello", string(data))
+	assert.Nil(t, ConvertEncoding(dst, dst, EncodingUTF8, EncodingGBK))
+	data, _ = os.ReadFile(dst)
+	assert.Equal(t, gbkSample, data)
+
+	assert.Nil(t, os.WriteFile(src, big5Sample, 0644))
+	assert.Nil(t, ConvertEncoding(src, dst, EncodingBig5, EncodingUTF8))
+	data, _ = os.ReadFile(dst)
+	assert.Equal(t, "中文測試，這是", string(data))
+
+	// GBK 中没有的字符，及无效的 GBK 数据。
+	assert.Nil(t, os.WriteFile(src, []byte("😀"), 0644))
+	assert.NotNil(t, ConvertEncoding(src, dst, EncodingUTF8, EncodingGBK))
+	assert.Nil(t, os.WriteFile(src, []byte("\xd6\xd0\x81"), 0644))
+	assert.NotNil(t, ConvertEncoding(src, dst, EncodingGBK, EncodingUTF8))
+
+	// 无法识别的编码。
+	assert.Nil(t, os.WriteFile(src, []byte("abc\xff"), 0644))
+	assert.ErrorIs(t, ConvertEncoding(src, dst, EncodingUnknown, EncodingUTF8), ErrUnknownEncoding)
+}
+
+// upperCodec 是用于测试的转换器，编码时转为大写。
+type upperCodec struct{}
+
+func (upperCodec) Decode(data []byte) (string, error) { return string(data), nil }
+func (upperCodec) Encode(text string) ([]byte, error) { return []byte(strings.ToUpper(text)), nil }
+
+func TestConvertTreeEncoding(t *testing.T) {
+	root := t.TempDir()
+	files := map[string][]byte{
+		"utf8.txt":    []byte("hello"),
+		"bom.txt":     []byte("\xef\xbb\xbfhello"),
+		"utf16.txt":   []byte("\xff\xfeh\x00i\x00"),
+		"binary.txt":  []byte("\x01\x00\x00\x00\x02\x03"),
+		"ignored.bin": []byte("\xff\xfeh\x00i\x00"),
+	}
+	for name, data := range files {
+		assert.Nil(t, os.WriteFile(filepath.Join(root, name), data, 0644))
+	}
+
+	filter := &Filter{Include: []string{"*.txt"}}
+	option := NewEncodingConvertOption()
+	option.DryRun = true
+	report, err := ConvertTreeEncoding(root, filter, EncodingUTF8, option)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]TextEncoding{
+		filepath.Join(root, "bom.txt"):   EncodingUTF8,
+		filepath.Join(root, "utf16.txt"): EncodingUTF16LE,
+	}, report.Converted)
+	assert.Equal(t, []string{filepath.Join(root, "utf8.txt")}, report.Unchanged)
+	assert.Equal(t, []string{filepath.Join(root, "binary.txt")}, report.Unknown)
+
+	data, _ := os.ReadFile(filepath.Join(root, "bom.txt"))
+	assert.Equal(t, files["bom.txt"], data)
+
+	option.DryRun = false
+	_, err = ConvertTreeEncoding(root, filter, EncodingUTF8, option)
+	assert.Nil(t, err)
+
+	for _, name := range []string{"bom.txt", "utf16.txt"} {
+		data, _ = os.ReadFile(filepath.Join(root, name))
+		assert.Equal(t, strings.TrimPrefix(string(data), "\xef\xbb\xbf"), string(data))
+	}
+	data, _ = os.ReadFile(filepath.Join(root, "utf16.txt"))
+	assert.Equal(t, "hi", string(data))
+	data, _ = os.ReadFile(filepath.Join(root, "ignored.bin"))
+	assert.Equal(t, files["ignored.bin"], data)
+
+	// 转换为 GBK。
+	gbkRoot := t.TempDir()
+	assert.Nil(t, os.WriteFile(filepath.Join(gbkRoot, "a.txt"), []byte("中文测试，hello"), 0644))
+	report, err = ConvertTreeEncoding(gbkRoot, nil, EncodingGBK, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(report.Converted))
+	data, _ = os.ReadFile(filepath.Join(gbkRoot, "a.txt"))
+	assert.Equal(t, gbkSample, data)
+
+	// 未注册的目标编码。
+	_, err = ConvertTreeEncoding(root, filter, "EBCDIC", option)
+	assert.NotNil(t, err)
+
+	// 注册自定义转换器。
+	RegisterTextCodec("UPPER", upperCodec{})
+	_, err = ConvertTreeEncoding(root, &Filter{Include: []string{"utf8.txt"}}, "UPPER", nil)
+	assert.Nil(t, err)
+	data, _ = os.ReadFile(filepath.Join(root, "utf8.txt"))
+	assert.Equal(t, "HELLO", string(data))
+}
@@ -0,0 +1,273 @@
+package timeutils
+
+import "time"
+
+/*
+HolidayCalendar decides which days are holidays and which weekend days are workdays.
+It is used by [IsBusinessDay] and [AddBusinessDays]. Only the date of the given time is used.
+
+HolidayCalendar 决定哪些天是节假日，以及哪些周末是工作日。
+由 [IsBusinessDay] 及 [AddBusinessDays] 使用。只使用给定时间的日期部分。
+*/
+type HolidayCalendar interface {
+	// IsHoliday returns whether the date is a holiday, on which business is closed even if it is a weekday.
+	// IsHoliday 返回该日期是否为节假日，即使是工作日也不营业。
+	IsHoliday(date time.Time) bool
+
+	// IsExtraWorkday returns whether the date is a weekend day on which business is open, such as a makeup workday in China.
+	// IsExtraWorkday 返回该日期是否为营业的周末，例如中国的调休上班日。
+	IsExtraWorkday(date time.Time) bool
+}
+
+/*
+IsBusinessDay returns whether the date of t is a business day.
+It is a business day if it is a weekday and not a holiday, or it is an extra workday of the calendar.
+
+Parameters:
+  - t: The time to check. Only its date is used.
+  - calendar: The holiday calendar. If nil, only Saturdays and Sundays are not business days.
+
+Returns:
+  - Whether it is a business day.
+
+IsBusinessDay 返回 t 的日期是否为工作日。不是周末且不是节假日，或者是日历中的调休上班日时为工作日。
+
+参数:
+  - t: 要检查的时间。只使用其日期部分。
+  - calendar: 节假日日历。为 nil 时只有星期六及星期日不是工作日。
+
+返回:
+  - 是否为工作日。
+*/
+func IsBusinessDay(t time.Time, calendar HolidayCalendar) bool {
+	weekend := t.Weekday() == time.Saturday || t.Weekday() == time.Sunday
+	if calendar == nil {
+		return !weekend
+	} else if weekend {
+		return calendar.IsExtraWorkday(t)
+	}
+	return !calendar.IsHoliday(t)
+}
+
+/*
+AddBusinessDays returns the time n business days after t, the time of day is kept.
+The date of t itself is not counted, so it does not need to be a business day.
+
+Parameters:
+  - t: The start time.
+  - n: The number of business days. Negative means before t, 0 returns t.
+  - calendar: The holiday calendar. If nil, only Saturdays and Sundays are not business days.
+
+Returns:
+  - The result time.
+
+Example:
+
+	t := time.Date(2023, 1, 6, 10, 0, 0, 0, time.Local) // Friday
+	t1 := AddBusinessDays(t, 1, nil)                   // 2023-01-09 10:00:00, Monday
+	t2 := AddBusinessDays(t, -5, nil)                  // 2022-12-30 10:00:00, Friday
+
+AddBusinessDays 返回 t 之后第 n 个工作日的时间，保留一天中的时间。不计算 t 本身的日期，所以它不必是工作日。
+
+参数:
+  - t: 开始时间。
+  - n: 工作日的数量。负数表示 t 之前，0 则返回 t。
+  - calendar: 节假日日历。为 nil 时只有星期六及星期日不是工作日。
+
+返回:
+  - 结果时间。
+*/
+func AddBusinessDays(t time.Time, n int, calendar HolidayCalendar) time.Time {
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+
+	for n > 0 {
+		t = t.AddDate(0, 0, step)
+		if IsBusinessDay(t, calendar) {
+			n--
+		}
+	}
+	return t
+}
+
+// civilDate 是不含时区的日期，用作 DateCalendar 的键。
+type civilDate struct {
+	year  int
+	month time.Month
+	day   int
+}
+
+func toCivilDate(t time.Time) civilDate {
+	year, month, day := t.Date()
+	return civilDate{year, month, day}
+}
+
+/*
+DateCalendar is a [HolidayCalendar] of listed dates. Dates are compared in the location of the given time.
+Add the dates before use, it is safe for concurrent reads but not for concurrent changes.
+
+DateCalendar 是由日期列表组成的 [HolidayCalendar]。日期在给定时间的时区中比较。
+使用前添加日期，可以并发读取，但不能并发修改。
+*/
+type DateCalendar struct {
+	holidays map[civilDate]bool
+	workdays map[civilDate]bool
+}
+
+/*
+NewDateCalendar creates an empty DateCalendar.
+
+NewDateCalendar 创建空的 DateCalendar。
+*/
+func NewDateCalendar() *DateCalendar {
+	return &DateCalendar{
+		holidays: map[civilDate]bool{},
+		workdays: map[civilDate]bool{},
+	}
+}
+
+/*
+AddHolidays adds holidays to the calendar.
+
+AddHolidays 向日历添加节假日。
+*/
+func (c *DateCalendar) AddHolidays(dates ...time.Time) {
+	for _, date := range dates {
+		c.holidays[toCivilDate(date)] = true
+	}
+}
+
+/*
+AddExtraWorkdays adds weekend days on which business is open to the calendar.
+
+AddExtraWorkdays 向日历添加营业的周末。
+*/
+func (c *DateCalendar) AddExtraWorkdays(dates ...time.Time) {
+	for _, date := range dates {
+		c.workdays[toCivilDate(date)] = true
+	}
+}
+
+// IsHoliday implements [HolidayCalendar].
+func (c *DateCalendar) IsHoliday(date time.Time) bool {
+	return c.holidays[toCivilDate(date)]
+}
+
+// IsExtraWorkday implements [HolidayCalendar].
+func (c *DateCalendar) IsExtraWorkday(date time.Time) bool {
+	return c.workdays[toCivilDate(date)]
+}
+
+// cnHolidays 及 cnWorkdays 是国务院办公厅公布的放假及调休上班安排，格式为 {年, 月, 日}。
+var (
+	cnHolidays = [][3]int{
+		// 2023 年。
+		{2023, 1, 2}, {2023, 1, 23}, {2023, 1, 24}, {2023, 1, 25}, {2023, 1, 26}, {2023, 1, 27},
+		{2023, 4, 5}, {2023, 5, 1}, {2023, 5, 2}, {2023, 5, 3}, {2023, 6, 22}, {2023, 6, 23},
+		{2023, 9, 29}, {2023, 10, 2}, {2023, 10, 3}, {2023, 10, 4}, {2023, 10, 5}, {2023, 10, 6},
+		// 2024 年。
+		{2024, 1, 1}, {2024, 2, 12}, {2024, 2, 13}, {2024, 2, 14}, {2024, 2, 15}, {2024, 2, 16},
+		{2024, 4, 4}, {2024, 4, 5}, {2024, 5, 1}, {2024, 5, 2}, {2024, 5, 3}, {2024, 6, 10},
+		{2024, 9, 16}, {2024, 9, 17}, {2024, 10, 1}, {2024, 10, 2}, {2024, 10, 3}, {2024, 10, 4}, {2024, 10, 7},
+		// 2025 年。
+		{2025, 1, 1}, {2025, 1, 28}, {2025, 1, 29}, {2025, 1, 30}, {2025, 1, 31}, {2025, 2, 3}, {2025, 2, 4},
+		{2025, 4, 4}, {2025, 5, 1}, {2025, 5, 2}, {2025, 5, 5}, {2025, 6, 2},
+		{2025, 10, 1}, {2025, 10, 2}, {2025, 10, 3}, {2025, 10, 6}, {2025, 10, 7}, {2025, 10, 8},
+	}
+	cnWorkdays = [][3]int{
+		{2023, 1, 28}, {2023, 1, 29}, {2023, 4, 23}, {2023, 5, 6}, {2023, 6, 25}, {2023, 10, 7}, {2023, 10, 8},
+		{2024, 2, 4}, {2024, 2, 18}, {2024, 4, 7}, {2024, 4, 28}, {2024, 5, 11}, {2024, 9, 14}, {2024, 9, 29}, {2024, 10, 12},
+		{2025, 1, 26}, {2025, 2, 8}, {2025, 4, 27}, {2025, 9, 28}, {2025, 10, 11},
+	}
+)
+
+/*
+NewCNHolidayCalendar creates a DateCalendar of the public holidays and makeup workdays of China from 2023 to 2025.
+Only the holidays on weekdays are listed, since weekends are not business days anyway.
+Later years are announced annually, add them with [DateCalendar.AddHolidays] and [DateCalendar.AddExtraWorkdays].
+
+NewCNHolidayCalendar 创建包含 2023 年至 2025 年中国法定节假日及调休上班日的 DateCalendar。
+只列出在工作日的节假日，因为周末本来就不是工作日。
+之后的年份每年公布，使用 [DateCalendar.AddHolidays] 及 [DateCalendar.AddExtraWorkdays] 添加。
+*/
+func NewCNHolidayCalendar() *DateCalendar {
+	calendar := NewDateCalendar()
+	for _, d := range cnHolidays {
+		calendar.AddHolidays(time.Date(d[0], time.Month(d[1]), d[2], 0, 0, 0, 0, time.UTC))
+	}
+	for _, d := range cnWorkdays {
+		calendar.AddExtraWorkdays(time.Date(d[0], time.Month(d[1]), d[2], 0, 0, 0, 0, time.UTC))
+	}
+	return calendar
+}
+
+/*
+USHolidayCalendar is a [HolidayCalendar] of the federal holidays of the United States, calculated for any year.
+A holiday on Saturday is observed on the previous Friday, and on Sunday on the next Monday.
+There are no extra workdays.
+
+USHolidayCalendar 是美国联邦节假日的 [HolidayCalendar]，可以计算任何年份。
+星期六的节假日在之前的星期五补假，星期日的在之后的星期一补假。没有调休上班日。
+*/
+type USHolidayCalendar struct{}
+
+// IsHoliday implements [HolidayCalendar].
+func (USHolidayCalendar) IsHoliday(date time.Time) bool {
+	year, month, day := date.Date()
+	weekday := date.Weekday()
+
+	// 固定日期的节假日，包括补假。12 月 31 日可能是下一年元旦的补假。
+	for _, fixed := range []struct {
+		month time.Month
+		day   int
+	}{{time.January, 1}, {time.June, 19}, {time.July, 4}, {time.November, 11}, {time.December, 25}} {
+		if fixed.month == time.June && year < 2021 { // 六月节从 2021 年开始。
+			continue
+		}
+
+		holiday := time.Date(year, fixed.month, fixed.day, 0, 0, 0, 0, time.UTC)
+		if fixed.month == time.January && month == time.December {
+			holiday = holiday.AddDate(1, 0, 0)
+		}
+		switch holiday.Weekday() {
+		case time.Saturday:
+			holiday = holiday.AddDate(0, 0, -1)
+		case time.Sunday:
+			holiday = holiday.AddDate(0, 0, 1)
+		}
+		if holiday.Month() == month && holiday.Day() == day {
+			return true
+		}
+	}
+
+	// 某月第几个星期几的节假日。
+	nth := (day-1)/7 + 1
+	last := day+7 > daysIn(year, month)
+	switch {
+	case month == time.January && weekday == time.Monday && nth == 3: // Martin Luther King Jr. Day
+		return true
+	case month == time.February && weekday == time.Monday && nth == 3: // Washington's Birthday
+		return true
+	case month == time.May && weekday == time.Monday && last: // Memorial Day
+		return true
+	case month == time.September && weekday == time.Monday && nth == 1: // Labor Day
+		return true
+	case month == time.October && weekday == time.Monday && nth == 2: // Columbus Day
+		return true
+	case month == time.November && weekday == time.Thursday && nth == 4: // Thanksgiving Day
+		return true
+	}
+	return false
+}
+
+// IsExtraWorkday implements [HolidayCalendar].
+func (USHolidayCalendar) IsExtraWorkday(date time.Time) bool {
+	return false
+}
+
+// daysIn 返回某年某月的天数。
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
@@ -0,0 +1,107 @@
+package timeutils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func day(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 10, 30, 0, 0, time.UTC)
+}
+
+func TestBusinessDayWithoutCalendar(t *testing.T) {
+	// 2023-01-06 是星期五。
+	assert.True(t, IsBusinessDay(day(2023, 1, 6), nil))
+	assert.False(t, IsBusinessDay(day(2023, 1, 7), nil))
+	assert.False(t, IsBusinessDay(day(2023, 1, 8), nil))
+
+	assert.Equal(t, day(2023, 1, 9), AddBusinessDays(day(2023, 1, 6), 1, nil))
+	assert.Equal(t, day(2023, 1, 13), AddBusinessDays(day(2023, 1, 6), 5, nil))
+	assert.Equal(t, day(2022, 12, 30), AddBusinessDays(day(2023, 1, 6), -5, nil))
+	assert.Equal(t, day(2023, 1, 7), AddBusinessDays(day(2023, 1, 7), 0, nil))
+
+	// 从周末开始时不计算当天。
+	assert.Equal(t, day(2023, 1, 9), AddBusinessDays(day(2023, 1, 7), 1, nil))
+	assert.Equal(t, day(2023, 1, 6), AddBusinessDays(day(2023, 1, 8), -1, nil))
+}
+
+func TestDateCalendar(t *testing.T) {
+	calendar := NewDateCalendar()
+	calendar.AddHolidays(day(2023, 1, 9))
+	calendar.AddExtraWorkdays(day(2023, 1, 7))
+
+	// 在其它时区中也按日期比较。
+	loc := time.FixedZone("", 8*3600)
+	assert.True(t, calendar.IsHoliday(time.Date(2023, 1, 9, 23, 0, 0, 0, loc)))
+	assert.False(t, IsBusinessDay(day(2023, 1, 9), calendar))
+	assert.True(t, IsBusinessDay(day(2023, 1, 7), calendar))
+	assert.False(t, IsBusinessDay(day(2023, 1, 8), calendar))
+
+	assert.Equal(t, day(2023, 1, 7), AddBusinessDays(day(2023, 1, 6), 1, calendar))
+	assert.Equal(t, day(2023, 1, 10), AddBusinessDays(day(2023, 1, 6), 2, calendar))
+}
+
+func TestCNHolidayCalendar(t *testing.T) {
+	// 节假日都在工作日，调休上班日都在周末。
+	for _, d := range cnHolidays {
+		weekday := day(d[0], time.Month(d[1]), d[2]).Weekday()
+		assert.True(t, weekday != time.Saturday && weekday != time.Sunday, d)
+	}
+	for _, d := range cnWorkdays {
+		weekday := day(d[0], time.Month(d[1]), d[2]).Weekday()
+		assert.True(t, weekday == time.Saturday || weekday == time.Sunday, d)
+	}
+
+	calendar := NewCNHolidayCalendar()
+
+	// 2024 年国庆节，9 月 29 日星期日上班，10 月 1 日至 7 日放假。
+	assert.True(t, IsBusinessDay(day(2024, 9, 29), calendar))
+	assert.False(t, IsBusinessDay(day(2024, 10, 7), calendar))
+	assert.Equal(t, day(2024, 10, 8), AddBusinessDays(day(2024, 9, 30), 1, calendar))
+
+	// 2025 年春节，1 月 26 日星期日上班，1 月 28 日至 2 月 4 日放假。
+	assert.Equal(t, day(2025, 1, 27), AddBusinessDays(day(2025, 1, 24), 2, calendar))
+	assert.Equal(t, day(2025, 2, 5), AddBusinessDays(day(2025, 1, 24), 3, calendar))
+	assert.Equal(t, day(2025, 1, 26), AddBusinessDays(day(2025, 2, 5), -2, calendar))
+}
+
+func TestUSHolidayCalendar(t *testing.T) {
+	calendar := USHolidayCalendar{}
+
+	holidays := []time.Time{
+		day(2023, 1, 2),   // New Year's Day, observed on Monday.
+		day(2023, 1, 16),  // Martin Luther King Jr. Day
+		day(2023, 2, 20),  // Washington's Birthday
+		day(2023, 5, 29),  // Memorial Day
+		day(2023, 6, 19),  // Juneteenth
+		day(2023, 7, 4),   // Independence Day
+		day(2023, 9, 4),   // Labor Day
+		day(2023, 10, 9),  // Columbus Day
+		day(2023, 11, 10), // Veterans Day, observed on Friday.
+		day(2023, 11, 23), // Thanksgiving Day
+		day(2023, 12, 25), // Christmas Day
+		day(2021, 12, 31), // New Year's Day of 2022, observed on Friday.
+		day(2024, 5, 27),  // Memorial Day
+	}
+	for _, h := range holidays {
+		assert.True(t, calendar.IsHoliday(h), h)
+		assert.False(t, IsBusinessDay(h, calendar), h)
+	}
+
+	notHolidays := []time.Time{
+		day(2023, 1, 1),  // Sunday, observed on the next day.
+		day(2023, 1, 9),  // the 2nd Monday of January.
+		day(2023, 5, 22), // not the last Monday of May.
+		day(2020, 6, 19), // before Juneteenth became a federal holiday.
+		day(2023, 12, 29),
+	}
+	for _, h := range notHolidays {
+		assert.False(t, calendar.IsHoliday(h), h)
+	}
+	assert.False(t, calendar.IsExtraWorkday(day(2023, 1, 7)))
+
+	// 感恩节前的星期三之后的第 1 个工作日是星期五。
+	assert.Equal(t, day(2023, 11, 24), AddBusinessDays(day(2023, 11, 22), 1, calendar))
+}
@@ -0,0 +1,102 @@
+package timeutils
+
+import (
+	"sync"
+	"time"
+)
+
+/*
+Clock is the source of the current time, so code using [Stopwatch] can be tested with simulated time.
+
+Clock 是当前时间的来源，以便使用 [Stopwatch] 的代码可以用模拟的时间进行测试。
+*/
+type Clock interface {
+	Now() time.Time                  // Returns the current time.
+	Since(t time.Time) time.Duration // Returns the time elapsed since t.
+}
+
+// systemClock 使用 time.Now() 及 time.Since()。
+type systemClock struct{}
+
+func (systemClock) Now() time.Time                  { return time.Now() }
+func (systemClock) Since(t time.Time) time.Duration { return time.Since(t) }
+
+/*
+SystemClock is the [Clock] of the system, used when no clock is given.
+
+SystemClock 是系统的 [Clock]，未指定时钟时使用。
+*/
+var SystemClock Clock = systemClock{}
+
+/*
+ManualClock is a [Clock] that only moves when told to, for deterministic tests without real sleeps.
+It is thread safe.
+
+Example:
+
+	clock := NewManualClock(time.Date(2023, 1, 5, 0, 0, 0, 0, time.UTC))
+	sw := Stopwatch{Clock: clock}
+	sw.Start()
+	clock.Advance(2 * time.Second)
+	d := sw.ElapsedTime() // 2s
+
+ManualClock 是只在指定时才前进的 [Clock]，用于不需要真实等待的确定性测试。多线程安全。
+*/
+type ManualClock struct {
+	now  time.Time
+	lock sync.RWMutex
+}
+
+/*
+NewManualClock creates a ManualClock starting at the given time.
+
+NewManualClock 创建从给定时间开始的 ManualClock。
+*/
+func NewManualClock(start time.Time) *ManualClock {
+	return &ManualClock{now: start}
+}
+
+/*
+Now returns the current time of the clock.
+
+Now 返回时钟的当前时间。
+*/
+func (c *ManualClock) Now() time.Time {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.now
+}
+
+/*
+Since returns the time elapsed since t by the clock.
+
+Since 按时钟返回从 t 开始经过的时间。
+*/
+func (c *ManualClock) Since(t time.Time) time.Duration {
+	return c.Now().Sub(t)
+}
+
+/*
+Advance moves the clock forward by d. A negative d moves it backward.
+
+Advance 将时钟向前拨 d。d 为负数时向后拨。
+*/
+func (c *ManualClock) Advance(d time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+/*
+Set sets the current time of the clock.
+
+Set 设置时钟的当前时间。
+*/
+func (c *ManualClock) Set(t time.Time) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.now = t
+}
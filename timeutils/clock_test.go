@@ -0,0 +1,50 @@
+package timeutils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManualClock(t *testing.T) {
+	start := time.Date(2023, 1, 5, 0, 0, 0, 0, time.UTC)
+	clock := NewManualClock(start)
+	assert.Equal(t, start, clock.Now())
+
+	clock.Advance(90 * time.Second)
+	assert.Equal(t, 90*time.Second, clock.Since(start))
+
+	clock.Set(start.Add(-time.Hour))
+	assert.Equal(t, -time.Hour, clock.Since(start))
+
+	// 系统时钟。
+	before := time.Now()
+	assert.False(t, SystemClock.Now().Before(before))
+	assert.True(t, SystemClock.Since(before) >= 0)
+}
+
+func TestStopwatchWithClock(t *testing.T) {
+	clock := NewManualClock(time.Date(2023, 1, 5, 0, 0, 0, 0, time.UTC))
+	sw := Stopwatch{Clock: clock}
+
+	sw.Start()
+	clock.Advance(2 * time.Second)
+	sw.RecordNamed("walk")
+	clock.Advance(3 * time.Second)
+	assert.Equal(t, 3*time.Second, sw.LapTime())
+	sw.Stop()
+
+	// 停止期间的时间不计入。
+	clock.Advance(time.Hour)
+	sw.Start()
+	clock.Advance(time.Second)
+	laps := sw.RecordNamed("hash")
+	sw.Stop()
+
+	assert.Equal(t, 6*time.Second, sw.ElapsedTime())
+	assert.Equal(t, []Lap{
+		{Label: "walk", Cumulative: 2 * time.Second, Delta: 2 * time.Second},
+		{Label: "hash", Cumulative: 6 * time.Second, Delta: 4 * time.Second},
+	}, laps)
+}
@@ -0,0 +1,327 @@
+package timeutils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+CronSchedule is a parsed cron expression, see [ParseCron]. It is immutable, so it is safe for concurrent use.
+
+CronSchedule 是解析后的 cron 表达式，参见 [ParseCron]。不可修改，所以可以并发使用。
+*/
+type CronSchedule struct {
+	expr     string
+	seconds  uint64 // 每一位表示一个允许的值，下同。
+	minutes  uint64
+	hours    uint64
+	days     uint64
+	months   uint64
+	weekdays uint64
+	anyDay   bool // 日以“*”开始或为“?”。
+	anyWeek  bool // 星期以“*”开始或为“?”。
+}
+
+// cronField 定义 cron 表达式中一个字段的范围及名称。
+type cronField struct {
+	name     string
+	min      int
+	max      int
+	names    map[string]int
+	question bool // 是否可以使用“?”。
+}
+
+var (
+	cronSecond = cronField{name: "second", min: 0, max: 59}
+	cronMinute = cronField{name: "minute", min: 0, max: 59}
+	cronHour   = cronField{name: "hour", min: 0, max: 23}
+	cronDay    = cronField{name: "day of month", min: 1, max: 31, question: true}
+	cronMonth  = cronField{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	cronWeekday = cronField{name: "day of week", min: 0, max: 7, question: true, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+// cronMacros 是预定义的表达式。
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// cronSearchYears 是 Next 及 Prev 搜索的年数。2 月 29 日最多 8 年出现一次，例如 2096 年之后是 2104 年。
+const cronSearchYears = 10
+
+/*
+ParseCron parses a cron expression with 5 fields "minute hour day-of-month month day-of-week",
+or 6 fields with a leading second field.
+
+Each field can be "*", a value, a range "1-5", a step "1-30/5", "0/15" or "*" with a step, or a list of them separated by ",".
+"?" is the same as "*" for day of month and day of week.
+Months and days of week can be names like "JAN" or "MON", case insensitive. Both 0 and 7 mean Sunday.
+If both day of month and day of week are restricted, a day matching either of them matches, as in standard cron.
+Macros "@yearly", "@annually", "@monthly", "@weekly", "@daily", "@midnight" and "@hourly" are also supported.
+
+Parameters:
+  - expr: The cron expression.
+
+Returns:
+  - The schedule.
+  - An error if the expression is invalid.
+
+Example:
+
+	schedule, err := ParseCron("30 2 * * MON-FRI")                  // 02:30 on weekdays.
+	next := schedule.Next(time.Date(2023, 1, 6, 3, 0, 0, 0, time.UTC)) // 2023-01-09 02:30:00, Monday
+	schedule, err = ParseCron("0/10 * * * * *")                     // every 10 seconds.
+
+ParseCron 解析 cron 表达式，5 个字段为“分 时 日 月 星期”，6 个字段时最前面是秒。
+
+每个字段可以是“*”、一个值、范围“1-5”、步长“1-30/5”、“0/15”或者带步长的“*”，或者由“,”分隔的它们的列表。
+对于日及星期，“?”与“*”相同。月及星期可以是“JAN”或“MON”这样的名称，不区分大小写。0 及 7 都表示星期日。
+与标准的 cron 一样，日及星期都有限制时，与任何一个匹配的日子都匹配。
+也支持宏“@yearly”、“@annually”、“@monthly”、“@weekly”、“@daily”、“@midnight”及“@hourly”。
+
+参数:
+  - expr: cron 表达式。
+
+返回:
+  - 计划。
+  - 错误信息。表达式无效时返回错误。
+*/
+func ParseCron(expr string) (*CronSchedule, error) {
+	text := strings.TrimSpace(expr)
+	if macro, ok := cronMacros[strings.ToLower(text)]; ok {
+		text = macro
+	}
+
+	fields := strings.Fields(text)
+	if len(fields) == 5 {
+		fields = append([]string{"0"}, fields...)
+	} else if len(fields) != 6 {
+		return nil, fmt.Errorf("cron expression must have 5 or 6 fields: %q", expr)
+	}
+
+	schedule := &CronSchedule{expr: expr}
+	var err error
+	for i, field := range []struct {
+		def  cronField
+		bits *uint64
+	}{
+		{cronSecond, &schedule.seconds},
+		{cronMinute, &schedule.minutes},
+		{cronHour, &schedule.hours},
+		{cronDay, &schedule.days},
+		{cronMonth, &schedule.months},
+		{cronWeekday, &schedule.weekdays},
+	} {
+		if *field.bits, err = parseCronField(fields[i], field.def); err != nil {
+			return nil, fmt.Errorf("invalid cron expression %q: %w", expr, err)
+		}
+	}
+
+	// 7 也表示星期日。
+	if schedule.weekdays&(1<<7) != 0 {
+		schedule.weekdays |= 1
+	}
+	// 与标准的 cron 一样，以“*”开始的字段都视为没有限制，例如“*/2”。
+	schedule.anyDay = strings.HasPrefix(fields[3], "*") || fields[3] == "?"
+	schedule.anyWeek = strings.HasPrefix(fields[5], "*") || fields[5] == "?"
+	return schedule, nil
+}
+
+// parseCronField 将一个字段解析为位集合。
+func parseCronField(text string, def cronField) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(text, ",") {
+		rangeText, stepText, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepText); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step %q in %s field", stepText, def.name)
+			}
+		}
+
+		var low, high int
+		if rangeText == "*" || (rangeText == "?" && def.question) {
+			low, high = def.min, def.max
+		} else if lowText, highText, isRange := strings.Cut(rangeText, "-"); isRange {
+			var err error
+			if low, err = def.value(lowText); err != nil {
+				return 0, err
+			}
+			if high, err = def.value(highText); err != nil {
+				return 0, err
+			}
+			if low > high {
+				return 0, fmt.Errorf("invalid range %q in %s field", rangeText, def.name)
+			}
+		} else {
+			var err error
+			if low, err = def.value(rangeText); err != nil {
+				return 0, err
+			}
+			high = low
+			if hasStep { // “5/10”表示从 5 开始，每 10 个。
+				high = def.max
+			}
+		}
+
+		for v := low; v <= high; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+// value 将字段中的一个值或名称转换为整数，并检查范围。
+func (def cronField) value(text string) (int, error) {
+	if v, ok := def.names[strings.ToLower(text)]; ok {
+		return v, nil
+	}
+
+	v, err := strconv.Atoi(text)
+	if err != nil || v < def.min || v > def.max {
+		return 0, fmt.Errorf("invalid value %q in %s field, must be %d to %d", text, def.name, def.min, def.max)
+	}
+	return v, nil
+}
+
+/*
+String returns the original expression.
+
+String 返回原始的表达式。
+*/
+func (s *CronSchedule) String() string {
+	return s.expr
+}
+
+/*
+Next returns the first time strictly after the given time that matches the schedule, in the location of after.
+Times are matched by the wall clock, so a time skipped by daylight saving does not match,
+and a time repeated by it matches only once.
+
+Parameters:
+  - after: The time to search from.
+
+Returns:
+  - The next time. The zero time if nothing matches within 10 years, such as "0 0 30 2 *".
+
+Next 返回严格在给定时间之后、与计划匹配的第一个时间，使用 after 的时区。
+按本地时间匹配，所以夏令时跳过的时间不匹配，重复的时间只匹配一次。
+
+参数:
+  - after: 开始搜索的时间。
+
+返回:
+  - 下一个时间。10 年内都不匹配时返回零值，例如“0 0 30 2 *”。
+*/
+func (s *CronSchedule) Next(after time.Time) time.Time {
+	t := after.Truncate(time.Second).Add(time.Second)
+	limit := after.Year() + cronSearchYears
+
+	// 每次将不匹配的字段前进到下一个值，并将更小的字段置为最小值。
+	// 按本地时间计算，夏令时切换时结果可能不前进，此时按绝对时间前进。
+	for t.Year() <= limit {
+		year, month, day := t.Date()
+		hour, minute, second := t.Clock()
+		loc := t.Location()
+
+		var next time.Time
+		switch {
+		case s.months&(1<<month) == 0:
+			next = time.Date(year, month+1, 1, 0, 0, 0, 0, loc)
+		case !s.matchDay(t):
+			next = time.Date(year, month, day+1, 0, 0, 0, 0, loc)
+		case s.hours&(1<<hour) == 0:
+			next = time.Date(year, month, day, hour+1, 0, 0, 0, loc)
+		case s.minutes&(1<<minute) == 0:
+			next = time.Date(year, month, day, hour, minute+1, 0, 0, loc)
+		case s.seconds&(1<<second) == 0:
+			next = t.Add(time.Second)
+		default:
+			return t
+		}
+
+		if !next.After(t) {
+			next = t.Add(time.Second)
+		}
+		t = next
+	}
+	return time.Time{}
+}
+
+/*
+Prev returns the last time strictly before the given time that matches the schedule, in the location of before.
+
+Parameters:
+  - before: The time to search from.
+
+Returns:
+  - The previous time. The zero time if nothing matches within 10 years.
+
+Prev 返回严格在给定时间之前、与计划匹配的最后一个时间，使用 before 的时区。
+
+参数:
+  - before: 开始搜索的时间。
+
+返回:
+  - 上一个时间。10 年内都不匹配时返回零值。
+*/
+func (s *CronSchedule) Prev(before time.Time) time.Time {
+	t := before.Truncate(time.Second)
+	if t.Equal(before) {
+		t = t.Add(-time.Second)
+	}
+	limit := before.Year() - cronSearchYears
+
+	// 每次将不匹配的字段后退到上一个值的最后一秒。
+	for t.Year() >= limit {
+		year, month, day := t.Date()
+		hour, minute, second := t.Clock()
+		loc := t.Location()
+
+		var prev time.Time
+		switch {
+		case s.months&(1<<month) == 0:
+			prev = time.Date(year, month, 1, 0, 0, 0, 0, loc).Add(-time.Second)
+		case !s.matchDay(t):
+			prev = time.Date(year, month, day, 0, 0, 0, 0, loc).Add(-time.Second)
+		case s.hours&(1<<hour) == 0:
+			prev = time.Date(year, month, day, hour, 0, 0, 0, loc).Add(-time.Second)
+		case s.minutes&(1<<minute) == 0:
+			prev = time.Date(year, month, day, hour, minute, 0, 0, loc).Add(-time.Second)
+		case s.seconds&(1<<second) == 0:
+			prev = t.Add(-time.Second)
+		default:
+			return t
+		}
+
+		if !prev.Before(t) {
+			prev = t.Add(-time.Second)
+		}
+		t = prev
+	}
+	return time.Time{}
+}
+
+// matchDay 检查日及星期。两者都有限制时，任何一个匹配即可。
+func (s *CronSchedule) matchDay(t time.Time) bool {
+	dayMatch := s.days&(1<<t.Day()) != 0
+	weekMatch := s.weekdays&(1<<t.Weekday()) != 0
+	if s.anyDay || s.anyWeek {
+		return dayMatch && weekMatch
+	}
+	return dayMatch || weekMatch
+}
@@ -0,0 +1,113 @@
+package timeutils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func cronTime(month time.Month, day, hour, minute, second int) time.Time {
+	return time.Date(2023, month, day, hour, minute, second, 0, time.UTC)
+}
+
+func TestParseCron(t *testing.T) {
+	for _, expr := range []string{
+		"* * * * *", "0/10 * * * * *", "30 2 * * MON-FRI", "0 0 1,15 * ?", "0 */6 * jan-mar 0,7", "@daily", " @Hourly ",
+	} {
+		schedule, err := ParseCron(expr)
+		assert.Nil(t, err, expr)
+		assert.Equal(t, expr, schedule.String())
+	}
+
+	for _, expr := range []string{
+		"", "* * * *", "* * * * * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8",
+		"5-1 * * * *", "*/0 * * * *", "a * * * *", "? * * * *", "* * * foo *",
+	} {
+		_, err := ParseCron(expr)
+		assert.NotNil(t, err, expr)
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	next := func(expr string, after time.Time) time.Time {
+		schedule, err := ParseCron(expr)
+		assert.Nil(t, err, expr)
+		return schedule.Next(after)
+	}
+
+	// 2023-01-06 是星期五。
+	from := cronTime(1, 6, 3, 0, 0)
+	assert.Equal(t, cronTime(1, 9, 2, 30, 0), next("30 2 * * MON-FRI", from))
+	assert.Equal(t, cronTime(1, 6, 3, 1, 0), next("* * * * *", from))
+	assert.Equal(t, cronTime(1, 6, 3, 0, 10), next("0/10 * * * * *", from))
+	assert.Equal(t, cronTime(1, 6, 6, 0, 0), next("0 */6 * * *", from))
+	assert.Equal(t, cronTime(1, 7, 0, 0, 0), next("@daily", from))
+	assert.Equal(t, cronTime(1, 8, 0, 0, 0), next("@weekly", from))
+	assert.Equal(t, cronTime(2, 1, 0, 0, 0), next("@monthly", from))
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), next("@yearly", from))
+
+	// 严格在之后，且忽略秒以下的部分。
+	assert.Equal(t, cronTime(1, 6, 3, 1, 0), next("* * * * *", cronTime(1, 6, 3, 0, 59).Add(time.Millisecond)))
+	assert.Equal(t, cronTime(1, 7, 0, 0, 0), next("0 0 * * *", cronTime(1, 6, 0, 0, 0)))
+
+	// 日及星期都有限制时，任何一个匹配即可：15 日或星期一。
+	assert.Equal(t, cronTime(1, 9, 0, 0, 0), next("0 0 15 * MON", from))
+	assert.Equal(t, cronTime(1, 15, 0, 0, 0), next("0 0 15 * MON", cronTime(1, 10, 0, 0, 0)))
+	// 7 也表示星期日。
+	assert.Equal(t, cronTime(1, 8, 0, 0, 0), next("0 0 * * 7", from))
+
+	// 2 月 29 日。
+	assert.Equal(t, time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), next("0 0 29 2 *", from))
+	// 不存在的日期。
+	assert.True(t, next("0 0 30 2 *", from).IsZero())
+
+	// 使用 after 的时区。
+	loc := time.FixedZone("", 8*3600)
+	tm := next("0 8 * * *", time.Date(2023, 1, 6, 9, 0, 0, 0, loc))
+	assert.Equal(t, time.Date(2023, 1, 7, 8, 0, 0, 0, loc), tm)
+}
+
+func TestCronPrev(t *testing.T) {
+	prev := func(expr string, before time.Time) time.Time {
+		schedule, err := ParseCron(expr)
+		assert.Nil(t, err, expr)
+		return schedule.Prev(before)
+	}
+
+	from := cronTime(1, 9, 2, 0, 0) // 星期一。
+	assert.Equal(t, cronTime(1, 6, 2, 30, 0), prev("30 2 * * MON-FRI", from))
+	assert.Equal(t, cronTime(1, 9, 1, 59, 0), prev("* * * * *", from))
+	assert.Equal(t, cronTime(1, 9, 1, 59, 50), prev("0/10 * * * * *", from))
+	assert.Equal(t, cronTime(1, 9, 0, 0, 0), prev("@daily", from))
+	assert.Equal(t, cronTime(1, 1, 0, 0, 0), prev("@monthly", from))
+	assert.Equal(t, time.Date(2020, 2, 29, 12, 0, 0, 0, time.UTC), prev("0 12 29 2 *", from))
+	assert.True(t, prev("0 0 31 4 *", from).IsZero())
+
+	// 严格在之前，秒以下的部分被截断。
+	assert.Equal(t, cronTime(1, 9, 2, 0, 0), prev("0 2 * * *", cronTime(1, 9, 2, 0, 0).Add(time.Millisecond)))
+	assert.Equal(t, cronTime(1, 8, 2, 0, 0), prev("0 2 * * *", cronTime(1, 9, 2, 0, 0)))
+}
+
+func TestCronDaylightSaving(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("time zone database not available")
+	}
+
+	// 2023-03-12 02:00 跳到 03:00，不存在的 02:30 被跳过。
+	schedule, err := ParseCron("30 * * * *")
+	assert.Nil(t, err)
+	tm := schedule.Next(time.Date(2023, 3, 12, 1, 45, 0, 0, loc))
+	assert.Equal(t, time.Date(2023, 3, 12, 3, 30, 0, 0, loc), tm)
+
+	// 2023-11-05 02:00 回到 01:00，重复的 01:30 只匹配一次。
+	tm = time.Date(2023, 11, 5, 0, 0, 0, 0, loc)
+	for _, hour := range []int{0, 1, 2, 3} {
+		next := schedule.Next(tm)
+		assert.True(t, next.After(tm))
+		assert.Equal(t, hour, next.Hour())
+		assert.Equal(t, 30, next.Minute())
+		tm = next
+	}
+}
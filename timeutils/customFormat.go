@@ -0,0 +1,184 @@
+package timeutils
+
+import (
+	"errors"
+	"regexp"
+	"sync"
+	"time"
+)
+
+/*
+FormatPriority defines whether a custom format is tried before or after the built-in formats.
+
+FormatPriority 定义自定义格式在内置格式之前还是之后尝试。
+*/
+type FormatPriority int
+
+const (
+	BeforeBuiltins FormatPriority = iota // tried before the built-in formats
+	AfterBuiltins                        // tried only if the built-in formats fail
+)
+
+/*
+PatternBuilder builds the time from the submatches of a registered pattern.
+
+Parameters:
+  - subs: the submatches returned by regexp.FindStringSubmatch. subs[0] is the whole match.
+  - loc: the location of the parser.
+
+Returns:
+  - the time.
+  - an error if the submatches are not a valid time, then the next format is tried.
+
+PatternBuilder 根据注册的正则表达式的匹配结果构建时间。
+
+参数:
+  - subs: regexp.FindStringSubmatch 返回的匹配结果。subs[0] 为整个匹配。
+  - loc: 解析器的时区。
+
+返回:
+  - 时间。
+  - 错误信息。匹配结果不是有效的时间时返回错误，然后尝试下一个格式。
+*/
+type PatternBuilder func(subs []string, loc *time.Location) (time.Time, error)
+
+// customFormat 是注册的自定义格式，layout 与 regex 二者只有一个有效。
+type customFormat struct {
+	layout  string
+	regex   *regexp.Regexp
+	builder PatternBuilder
+}
+
+// customFormats 保存注册的自定义格式，可以并发使用。
+type customFormats struct {
+	lock   sync.RWMutex
+	before []customFormat
+	after  []customFormat
+}
+
+func (c *customFormats) add(format customFormat, priority FormatPriority) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if priority == BeforeBuiltins {
+		c.before = append(c.before, format)
+	} else {
+		c.after = append(c.after, format)
+	}
+}
+
+// parse 按注册的顺序尝试给定优先级的格式，同时返回被解析的子串。
+func (c *customFormats) parse(s string, loc *time.Location, priority FormatPriority, mode MatchMode) (time.Time, string, bool) {
+	c.lock.RLock()
+	formats := c.after
+	if priority == BeforeBuiltins {
+		formats = c.before
+	}
+	c.lock.RUnlock()
+
+	for _, format := range formats {
+		if t, matched, err := format.parse(s, loc, mode); err == nil {
+			return t, matched, true
+		}
+	}
+	return time.Time{}, "", false
+}
+
+func (f *customFormat) parse(s string, loc *time.Location, mode MatchMode) (time.Time, string, error) {
+	if f.regex != nil {
+		subs := f.regex.FindStringSubmatch(s)
+		if subs == nil || (mode == MatchWhole && subs[0] != s) {
+			return time.Time{}, "", errNoMatch
+		}
+		t, err := f.builder(subs, loc)
+		return t, subs[0], err
+	}
+
+	// 先匹配整个字符串，再匹配与格式长度相同的每个子串，以便处理文件名中的前缀及后缀。
+	if t, err := time.ParseInLocation(f.layout, s, loc); err == nil {
+		return t, s, nil
+	}
+	if mode == MatchWhole {
+		return time.Time{}, "", errNoMatch
+	}
+	for i := 1; i+len(f.layout) <= len(s); i++ {
+		if t, err := time.ParseInLocation(f.layout, s[i:i+len(f.layout)], loc); err == nil {
+			return t, s[i : i+len(f.layout)], nil
+		}
+	}
+	return time.Time{}, "", errNoMatch
+}
+
+/*
+RegisterLayout registers a layout of time.Parse, which is tried by [Parser.ParseDateTime] and [Parser.ParseDate].
+The layout is matched against the whole string first, then against each substring of the same length as the layout.
+So prefixes and suffixes are allowed for fixed-width layouts, such as "02.01.2006", unless the parser uses [MatchWhole].
+The location of the parser is used if the layout has no time zone.
+
+Parameters:
+  - layout: the layout, see time.Layout.
+  - priority: whether to try it before or after the built-in formats.
+
+RegisterLayout 注册 time.Parse 的格式，由 [Parser.ParseDateTime] 及 [Parser.ParseDate] 尝试。
+先与整个字符串匹配，再与格式长度相同的每个子串匹配。所以对于 "02.01.2006" 这样的定长格式，允许有前缀及后缀，除非解析器使用 [MatchWhole]。
+格式中没有时区时使用解析器的时区。
+
+参数:
+  - layout: 格式，参见 time.Layout。
+  - priority: 在内置格式之前还是之后尝试。
+*/
+func (p *Parser) RegisterLayout(layout string, priority FormatPriority) {
+	p.custom.add(customFormat{layout: layout}, priority)
+}
+
+/*
+RegisterPattern registers a regular expression, which is tried by [Parser.ParseDateTime] and [Parser.ParseDate].
+If the parser uses [MatchWhole], the whole match must be the whole string.
+
+Parameters:
+  - regex: the regular expression. Cannot be nil.
+  - builder: builds the time from the submatches. Cannot be nil.
+  - priority: whether to try it before or after the built-in formats.
+
+Returns:
+  - an error if regex or builder is nil.
+
+RegisterPattern 注册正则表达式，由 [Parser.ParseDateTime] 及 [Parser.ParseDate] 尝试。
+解析器使用 [MatchWhole] 时，整个匹配必须是整个字符串。
+
+参数:
+  - regex: 正则表达式。不能为 nil。
+  - builder: 根据匹配结果构建时间。不能为 nil。
+  - priority: 在内置格式之前还是之后尝试。
+
+返回:
+  - 错误信息。regex 或 builder 为 nil 时返回错误。
+*/
+func (p *Parser) RegisterPattern(regex *regexp.Regexp, builder PatternBuilder, priority FormatPriority) error {
+	if regex == nil || builder == nil {
+		return errors.New("regex and builder cannot be nil")
+	}
+
+	p.custom.add(customFormat{regex: regex, builder: builder}, priority)
+	return nil
+}
+
+/*
+RegisterLayout registers a layout with the default parser used by package functions such as [ParseDateTime].
+See [Parser.RegisterLayout].
+
+RegisterLayout 向 [ParseDateTime] 等包函数使用的默认解析器注册格式。参见 [Parser.RegisterLayout]。
+*/
+func RegisterLayout(layout string, priority FormatPriority) {
+	strictParser.RegisterLayout(layout, priority)
+}
+
+/*
+RegisterPattern registers a regular expression with the default parser used by package functions such as [ParseDateTime].
+See [Parser.RegisterPattern].
+
+RegisterPattern 向 [ParseDateTime] 等包函数使用的默认解析器注册正则表达式。参见 [Parser.RegisterPattern]。
+*/
+func RegisterPattern(regex *regexp.Regexp, builder PatternBuilder, priority FormatPriority) error {
+	return strictParser.RegisterPattern(regex, builder, priority)
+}
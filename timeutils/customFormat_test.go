@@ -0,0 +1,145 @@
+package timeutils
+
+import (
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterLayout(t *testing.T) {
+	parser, err := NewParser(nil)
+	assert.Nil(t, err)
+
+	_, err = parser.ParseDate("23.02.2010")
+	assert.NotNil(t, err)
+
+	parser.RegisterLayout("02.01.2006", AfterBuiltins)
+	tm, err := parser.ParseDate("23.02.2010")
+	assert.Nil(t, err)
+	assert.Equal(t, "2010-02-23", tm.Format("2006-01-02"))
+
+	// 允许有前缀及后缀。
+	tm, err = parser.ParseDate("photo_23.02.2010_001.jpg")
+	assert.Nil(t, err)
+	assert.Equal(t, "2010-02-23", tm.Format("2006-01-02"))
+
+	// 不影响其它解析器。
+	other, err := NewParser(nil)
+	assert.Nil(t, err)
+	_, err = other.ParseDate("23.02.2010")
+	assert.NotNil(t, err)
+
+	// 日期时间格式，使用解析器的时区。
+	option := NewParserOption()
+	option.Location = time.UTC
+	parser, err = NewParser(option)
+	assert.Nil(t, err)
+	parser.RegisterLayout("02.01.2006 15h04", AfterBuiltins)
+	tm, err = parser.ParseDateTime("23.02.2010 15h34")
+	assert.Nil(t, err)
+	assert.Equal(t, "2010-02-23 15:34:00", tm.Format("2006-01-02 15:04:05"))
+	assert.Equal(t, time.UTC, tm.Location())
+
+	// ParseDate 只保留日期部分。
+	tm, err = parser.ParseDate("23.02.2010 15h34")
+	assert.Nil(t, err)
+	assert.Equal(t, "2010-02-23 00:00:00", tm.Format("2006-01-02 15:04:05"))
+}
+
+func TestRegisterLayoutPriority(t *testing.T) {
+	// 内置格式将 "2010-02-03" 解析为 2 月 3 日，自定义格式解析为 3 月 2 日。
+	before, err := NewParser(nil)
+	assert.Nil(t, err)
+	before.RegisterLayout("2006-02-01", BeforeBuiltins)
+	tm, err := before.ParseDate("2010-02-03")
+	assert.Nil(t, err)
+	assert.Equal(t, "2010-03-02", tm.Format("2006-01-02"))
+
+	after, err := NewParser(nil)
+	assert.Nil(t, err)
+	after.RegisterLayout("2006-02-01", AfterBuiltins)
+	tm, err = after.ParseDate("2010-02-03")
+	assert.Nil(t, err)
+	assert.Equal(t, "2010-02-03", tm.Format("2006-01-02"))
+}
+
+func TestRegisterPattern(t *testing.T) {
+	parser, err := NewParser(nil)
+	assert.Nil(t, err)
+
+	assert.NotNil(t, parser.RegisterPattern(nil, nil, AfterBuiltins))
+
+	// 英文描述的格式，例如 "day 23 of month 2 in 2010"。
+	regex := regexp.MustCompile(`day (\d+) of month (\d+) in (\d{4})`)
+	builder := func(subs []string, loc *time.Location) (time.Time, error) {
+		day, _ := strconv.Atoi(subs[1])
+		month, _ := strconv.Atoi(subs[2])
+		year, _ := strconv.Atoi(subs[3])
+		if month < 1 || month > 12 {
+			return time.Time{}, newParseError(subs[0], "month out of range")
+		}
+		return time.Date(year, time.Month(month), day, 0, 0, 0, 0, loc), nil
+	}
+	err = parser.RegisterPattern(regex, builder, AfterBuiltins)
+	assert.Nil(t, err)
+
+	tm, err := parser.ParseDate("day 23 of month 2 in 2010")
+	assert.Nil(t, err)
+	assert.Equal(t, "2010-02-23", tm.Format("2006-01-02"))
+
+	tm, err = parser.ParseDateTime("day 23 of month 2 in 2010")
+	assert.Nil(t, err)
+	assert.Equal(t, "2010-02-23 00:00:00", tm.Format("2006-01-02 15:04:05"))
+
+	// builder 返回错误时视为不匹配。
+	_, err = parser.ParseDate("day 23 of month 13 in 2010")
+	assert.NotNil(t, err)
+}
+
+// restoreDefaultFormats 保存默认解析器中注册的自定义格式，返回恢复的函数，使测试不影响其它测试。
+func restoreDefaultFormats() func() {
+	custom := strictParser.custom
+	custom.lock.Lock()
+	before, after := custom.before, custom.after
+	custom.lock.Unlock()
+
+	return func() {
+		custom.lock.Lock()
+		defer custom.lock.Unlock()
+		custom.before, custom.after = before, after
+	}
+}
+
+func TestRegisterDefault(t *testing.T) {
+	defer restoreDefaultFormats()()
+
+	_, err := ParseDateE("23.02.2010")
+	assert.NotNil(t, err)
+
+	RegisterLayout("02.01.2006", AfterBuiltins)
+
+	// 严格及宽松模式都可以使用。
+	tm, err := ParseDateE("23.02.2010")
+	assert.Nil(t, err)
+	assert.Equal(t, "2010-02-23", tm.Format("2006-01-02"))
+
+	RequireDateTimeFieldValid = false
+	defer func() { RequireDateTimeFieldValid = true }()
+	tm, err = ParseDateE("23.02.2010")
+	assert.Nil(t, err)
+	assert.Equal(t, "2010-02-23", tm.Format("2006-01-02"))
+
+	regex := regexp.MustCompile(`^(\d{4})/Q([1-4])$`)
+	err = RegisterPattern(regex, func(subs []string, loc *time.Location) (time.Time, error) {
+		year, _ := strconv.Atoi(subs[1])
+		quarter, _ := strconv.Atoi(subs[2])
+		return time.Date(year, time.Month(quarter*3-2), 1, 0, 0, 0, 0, loc), nil
+	}, BeforeBuiltins)
+	assert.Nil(t, err)
+	tm, err = ParseDateE("2010/Q3")
+	assert.Nil(t, err)
+	assert.Equal(t, "2010-07-01", tm.Format("2006-01-02"))
+}
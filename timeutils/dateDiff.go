@@ -0,0 +1,165 @@
+package timeutils
+
+import (
+	"strings"
+	"time"
+)
+
+/*
+DateDiff is the calendar-aware difference between two times, see [DiffBreakdown].
+All fields are non-negative, Negative tells the direction.
+
+DateDiff 是按日历计算的两个时间的差，参见 [DiffBreakdown]。各字段都不为负数，Negative 表示方向。
+*/
+type DateDiff struct {
+	Years    int
+	Months   int  // 0 to 11.
+	Days     int  // 0 to 30.
+	Hours    int  // 0 to 23, may be more on days changed by daylight saving.
+	Minutes  int  // 0 to 59.
+	Seconds  int  // 0 to 59. Smaller parts are truncated.
+	Negative bool // Whether b is before a.
+}
+
+/*
+String returns the non-zero fields in English, such as "2 years 3 months 5 days", or "0 seconds" if all are zero.
+A negative difference is prefixed with "-".
+
+String 以英文返回不为 0 的字段，例如 "2 years 3 months 5 days"。全部为 0 时返回 "0 seconds"。负数以 "-" 开始。
+*/
+func (d DateDiff) String() string {
+	parts := []string{}
+	for _, field := range []struct {
+		value int
+		name  string
+	}{
+		{d.Years, "year"}, {d.Months, "month"}, {d.Days, "day"},
+		{d.Hours, "hour"}, {d.Minutes, "minute"}, {d.Seconds, "second"},
+	} {
+		if field.value != 0 {
+			parts = append(parts, formatDurationUnit(int64(field.value), field.name, true, false, nil))
+		}
+	}
+
+	if len(parts) == 0 {
+		return "0 seconds"
+	}
+
+	result := strings.Join(parts, " ")
+	if d.Negative {
+		result = "-" + result
+	}
+	return result
+}
+
+/*
+DiffBreakdown returns the calendar-aware difference from a to b, in years, months, days, hours, minutes and seconds.
+Whole months are counted first. Adding months to a day that the target month does not have
+gives the last day of that month, so January 31 plus 1 month is February 28 or 29.
+b is converted to the location of a before calculating.
+
+Parameters:
+  - a: The start time.
+  - b: The end time. If it is before a, the difference is calculated from b to a and Negative is true.
+
+Returns:
+  - The difference.
+
+Example:
+
+	a := time.Date(2021, 1, 31, 10, 0, 0, 0, time.UTC)
+	b := time.Date(2023, 3, 1, 12, 30, 0, 0, time.UTC)
+	d := DiffBreakdown(a, b) // 2 years 1 month 1 day 2 hours 30 minutes, since 2023-02-28 10:00 is 2 years 1 month after a.
+
+DiffBreakdown 返回从 a 到 b 按日历计算的差，包括年、月、日、时、分及秒。
+先计算整月数。目标月份没有该日时得到该月的最后一天，所以 1 月 31 日加 1 个月是 2 月 28 日或 29 日。
+计算前将 b 转换为 a 的时区。
+
+参数:
+  - a: 开始时间。
+  - b: 结束时间。在 a 之前时，计算从 b 到 a 的差，且 Negative 为 true。
+
+返回:
+  - 时间差。
+*/
+func DiffBreakdown(a, b time.Time) DateDiff {
+	b = b.In(a.Location())
+
+	result := DateDiff{}
+	if b.Before(a) {
+		a, b = b, a
+		result.Negative = true
+	}
+
+	// 先按年月估算整月数，超出时减 1。
+	months := (b.Year()-a.Year())*12 + int(b.Month()-a.Month())
+	if addMonthsClamped(a, months).After(b) {
+		months--
+	}
+	anchor := addMonthsClamped(a, months)
+
+	// 再按日期估算整天数，超出时减 1。
+	days := civilDays(anchor, b)
+	if anchor.AddDate(0, 0, days).After(b) {
+		days--
+	}
+	anchor = anchor.AddDate(0, 0, days)
+
+	rest := b.Sub(anchor)
+	result.Years = months / 12
+	result.Months = months % 12
+	result.Days = days
+	result.Hours = int(rest / time.Hour)
+	result.Minutes = int(rest % time.Hour / time.Minute)
+	result.Seconds = int(rest % time.Minute / time.Second)
+	return result
+}
+
+/*
+Age returns the number of whole years from birth to ref, such as the age of a person or a file.
+Someone born on February 29 becomes a year older on February 28 in common years.
+
+Parameters:
+  - birth: The time of birth.
+  - ref: The reference time, usually time.Now().
+
+Returns:
+  - The age in years. It is negative if ref is before birth.
+
+Age 返回从 birth 到 ref 的整年数，例如人或文件的年龄。2 月 29 日出生的，在平年的 2 月 28 日长一岁。
+
+参数:
+  - birth: 出生时间。
+  - ref: 参考时间，通常为 time.Now()。
+
+返回:
+  - 年龄。ref 在 birth 之前时为负数。
+*/
+func Age(birth, ref time.Time) int {
+	diff := DiffBreakdown(birth, ref)
+	if diff.Negative {
+		return -diff.Years
+	}
+	return diff.Years
+}
+
+// addMonthsClamped 增加 n 个月，目标月份没有该日时使用该月的最后一天。
+func addMonthsClamped(t time.Time, n int) time.Time {
+	year, month, day := t.Date()
+	hour, minute, second := t.Clock()
+
+	target := time.Date(year, month+time.Month(n), 1, 0, 0, 0, 0, time.UTC)
+	if last := daysIn(target.Year(), target.Month()); day > last {
+		day = last
+	}
+	return time.Date(target.Year(), target.Month(), day, hour, minute, second, t.Nanosecond(), t.Location())
+}
+
+// civilDays 返回两个时间的日期相差的天数，不考虑时间部分及夏令时。
+func civilDays(a, b time.Time) int {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	start := time.Date(ay, am, ad, 0, 0, 0, 0, time.UTC)
+	end := time.Date(by, bm, bd, 0, 0, 0, 0, time.UTC)
+	return int(end.Sub(start) / Day)
+}
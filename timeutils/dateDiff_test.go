@@ -0,0 +1,56 @@
+package timeutils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffBreakdown(t *testing.T) {
+	utc := func(year int, month time.Month, day, hour, minute, second int) time.Time {
+		return time.Date(year, month, day, hour, minute, second, 0, time.UTC)
+	}
+
+	d := DiffBreakdown(utc(2021, 1, 31, 10, 0, 0), utc(2023, 3, 1, 12, 30, 15))
+	assert.Equal(t, DateDiff{Years: 2, Months: 1, Days: 1, Hours: 2, Minutes: 30, Seconds: 15}, d)
+	assert.Equal(t, "2 years 1 month 1 day 2 hours 30 minutes 15 seconds", d.String())
+
+	// 反向时各字段相同，Negative 为 true。
+	d = DiffBreakdown(utc(2023, 3, 1, 12, 30, 15), utc(2021, 1, 31, 10, 0, 0))
+	assert.Equal(t, DateDiff{Years: 2, Months: 1, Days: 1, Hours: 2, Minutes: 30, Seconds: 15, Negative: true}, d)
+	assert.Equal(t, "-2 years 1 month 1 day 2 hours 30 minutes 15 seconds", d.String())
+
+	// 时间部分不足一天时借位。
+	d = DiffBreakdown(utc(2023, 1, 5, 22, 0, 0), utc(2023, 2, 5, 6, 0, 0))
+	assert.Equal(t, DateDiff{Days: 30, Hours: 8}, d)
+	assert.Equal(t, "30 days 8 hours", d.String())
+
+	// 1 月 31 日加 1 个月是 2 月的最后一天。
+	assert.Equal(t, DateDiff{Months: 1}, DiffBreakdown(utc(2023, 1, 31, 0, 0, 0), utc(2023, 2, 28, 0, 0, 0)))
+	assert.Equal(t, DateDiff{Days: 28}, DiffBreakdown(utc(2024, 1, 31, 0, 0, 0), utc(2024, 2, 28, 0, 0, 0)))
+	assert.Equal(t, DateDiff{Months: 1}, DiffBreakdown(utc(2024, 1, 31, 0, 0, 0), utc(2024, 2, 29, 0, 0, 0)))
+
+	d = DiffBreakdown(utc(2023, 1, 1, 0, 0, 0), utc(2023, 1, 1, 0, 0, 0))
+	assert.Equal(t, DateDiff{}, d)
+	assert.Equal(t, "0 seconds", d.String())
+
+	// b 转换为 a 的时区。
+	loc := time.FixedZone("", 8*3600)
+	d = DiffBreakdown(time.Date(2023, 1, 1, 8, 0, 0, 0, loc), utc(2023, 2, 1, 0, 0, 0))
+	assert.Equal(t, DateDiff{Months: 1}, d)
+}
+
+func TestAge(t *testing.T) {
+	birth := time.Date(1990, 6, 15, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, 32, Age(birth, time.Date(2023, 6, 14, 23, 59, 59, 0, time.UTC)))
+	assert.Equal(t, 33, Age(birth, time.Date(2023, 6, 15, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 0, Age(birth, birth))
+	assert.Equal(t, -1, Age(birth, time.Date(1989, 6, 15, 0, 0, 0, 0, time.UTC)))
+
+	// 2 月 29 日出生，平年的 2 月 28 日长一岁。
+	birth = time.Date(2020, 2, 29, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, 0, Age(birth, time.Date(2021, 2, 27, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 1, Age(birth, time.Date(2021, 2, 28, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 4, Age(birth, time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)))
+}
@@ -1,14 +1,63 @@
 /*
 timeutils provides a set of time handling functions.
 
-[Stopwatch] is a timer, thread safe.
+[Stopwatch] is a timer, thread safe. It can be driven by a [ManualClock] in tests,
+calls optional OnStart, OnStop and OnRecord hooks, and tracks paused time against wall time.
+[Stopwatch.RecordNamed] records labeled laps for multi-phase tasks, and [Stopwatch.Report] summarizes them.
+[Stopwatch.ElapsingWithStats] also reports memory allocation and GC counts of a task.
+[StopwatchStats] collects the durations of many runs and reports min, max, mean, median and p95,
+and [ElapsingN] runs a task repeatedly with warmup for ad-hoc benchmarks.
+[StopwatchRegistry] exports named stats via expvar and the Prometheus text format.
+[RateMeter] reports the throughput of copy or hash jobs, such as "12.500 MB/s", and the estimated remaining time.
+[StartOfDay], [EndOfMonth] and the like return the start or end of a day, week, month, quarter or year.
+[DiffBreakdown] and [Age] calculate calendar-aware differences, such as "2 years 3 months".
+[TimeRange] is a half-open time range with set operations and splitting by day or week.
+[IsBusinessDay] and [AddBusinessDays] work with a pluggable [HolidayCalendar], such as [NewCNHolidayCalendar] or [USHolidayCalendar].
+[ParseCron] parses cron expressions, and [CronSchedule] calculates the next and previous run times.
+[FormatDuration] converts a duration to a human-friendly string, such as "2d 3h 15m".
+[ParseDuration] parses it back, and also accepts the format of time.ParseDuration.
+[DurationFlag] and [TimeFlag] bind command line options like "--timeout 2m --since 2023-01-01" to variables.
 
-Time string parsing functions, the returned time zone is time.Local.
+Time string parsing functions, the returned time zone is time.Local,
+unless a location is given by the InLocation variants or a time zone is in the string.
+Chinese date time strings like "2023年1月5日 14时30分" are also recognized.
+[ParseISO8601] parses ISO 8601 strings, including week dates, ordinal dates and fractional seconds of any precision.
+[ResolveZone] resolves zone abbreviations like "CST" and offsets like "+0800", with a configurable [ZoneResolver] table.
+[FromExcelSerial], [FromDotNetTicks] and [FromFileTime] convert numeric timestamps found in exported metadata.
+[NormalizeDateTime] and [NormalizeFileName] rewrite messy date time spellings in a canonical layout.
+[ParseRelative] parses relative time strings like "yesterday", "3 days ago" or "下周一".
+[Parser] parses with its own options, such as strictness, location, separators, two-digit years and numeric timestamps.
+[Parser.FindDateTime] also returns the parsed substring, and [MatchWhole] requires the whole string to be the date time.
+Custom layouts and patterns can be registered with [Parser.RegisterLayout] and [Parser.RegisterPattern].
 
 timeutils 提供一组时间处理函数。
 
-[Stopwatch] 计时器，多线程安全。
+[Stopwatch] 计时器，多线程安全。测试时可以由 [ManualClock] 驱动，
+调用可选的 OnStart、OnStop 及 OnRecord 回调，并统计暂停时间以对比实际经过的时间。
+[Stopwatch.RecordNamed] 为多阶段的任务记录带标签的时间段，[Stopwatch.Report] 汇总这些时间段。
+[Stopwatch.ElapsingWithStats] 同时报告任务的内存分配及 GC 次数。
+[StopwatchStats] 收集多次运行的时长，统计最小值、最大值、平均值、中位数及 p95，
+[ElapsingN] 预热后重复运行任务，用于临时的性能比较。
+[StopwatchRegistry] 通过 expvar 及 Prometheus 文本格式导出命名的统计信息。
+[RateMeter] 报告复制或计算校验和等任务的吞吐量，例如“12.500 MB/s”，以及估计的剩余时间。
+[StartOfDay]、[EndOfMonth] 等函数返回日、周、月、季度或年的开始或结束。
+[DiffBreakdown] 及 [Age] 按日历计算时间差，例如 "2 years 3 months"。
+[TimeRange] 半开的时间范围，可以求交集、并集，以及按天或周拆分。
+[IsBusinessDay] 及 [AddBusinessDays] 使用可替换的 [HolidayCalendar]，例如 [NewCNHolidayCalendar] 或 [USHolidayCalendar]。
+[ParseCron] 解析 cron 表达式，[CronSchedule] 计算下一次及上一次运行的时间。
+[FormatDuration] 将时长转换为 "2d 3h 15m" 这样易读的字符串。
+[ParseDuration] 将其解析回时长，也接受 time.ParseDuration 的格式。
+[DurationFlag] 及 [TimeFlag] 将“--timeout 2m --since 2023-01-01”这样的命令行选项绑定到变量。
 
-时间字符解析函数，返回的时区都是 time.Local。
+时间字符解析函数，返回的时区都是 time.Local。除非使用 InLocation 系列函数指定时区，或者字符串中包含时区。
+也可以识别“2023年1月5日 14时30分”这样的中文日期时间字符串。
+[ParseISO8601] 解析 ISO 8601 字符串，包括周日期、序数日期及任意精度的小数秒。
+[ResolveZone] 解析“CST”这样的时区缩写及“+0800”这样的偏移量，对照表可以由 [ZoneResolver] 配置。
+[FromExcelSerial]、[FromDotNetTicks] 及 [FromFileTime] 转换导出的元数据中常见的数字时间戳。
+[NormalizeDateTime] 及 [NormalizeFileName] 将各种写法的日期时间改写为统一的格式。
+[ParseRelative] 解析“yesterday”、“3 days ago”或“下周一”这样的相对时间字符串。
+[Parser] 使用自己的选项解析，例如严格程度、时区、分隔符、两位年份及数字时间戳。
+[Parser.FindDateTime] 同时返回被解析的子串，[MatchWhole] 要求整个字符串都是日期时间。
+可以使用 [Parser.RegisterLayout] 及 [Parser.RegisterPattern] 注册自定义格式。
 */
 package timeutils
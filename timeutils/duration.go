@@ -0,0 +1,277 @@
+package timeutils
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jqk/futool4go/common"
+)
+
+/*
+Day is the duration of a day, the largest unit used by [FormatDuration].
+
+Day 是一天的时长，[FormatDuration] 使用的最大单位。
+*/
+const Day = 24 * time.Hour
+
+const (
+	LocaleEnglish = common.LocaleEnglish // English, such as "2d 3h" or "2 days 3 hours"
+	LocaleChinese = common.LocaleChinese // Chinese, such as "2天3时" or "2天3小时"
+)
+
+/*
+DurationOption defines the options for [FormatDuration]. See [NewDurationOption] for default settings.
+
+DurationOption 定义了 [FormatDuration] 的选项。默认设置参见 [NewDurationOption]。
+*/
+type DurationOption struct {
+	Precision    int                  // the max number of units counted from the largest non-zero one. 0 or negative means all units
+	LargestUnit  time.Duration        // one of Day, time.Hour, time.Minute, time.Second and time.Millisecond. Larger parts are added to it
+	SmallestUnit time.Duration        // one of Day, time.Hour, time.Minute, time.Second and time.Millisecond. Smaller parts are truncated
+	Long         bool                 // use full unit names, such as "2 days 3 hours" instead of "2d 3h"
+	Locale       string               // LocaleEnglish or LocaleChinese. Others mean LocaleEnglish
+	Format       *common.NumberFormat // the number format, such as digit grouping. If not nil, its Locale overrides Locale
+}
+
+/*
+NewDurationOption creates a new DurationOption with precision 3, units from days to seconds,
+short unit names and English.
+
+NewDurationOption 创建默认的 DurationOption。包含精度 3、从天到秒的单位、简短的单位名称及英文。
+*/
+func NewDurationOption() *DurationOption {
+	return &DurationOption{
+		Precision:    3,
+		LargestUnit:  Day,
+		SmallestUnit: time.Second,
+		Long:         false,
+		Locale:       LocaleEnglish,
+		Format:       nil,
+	}
+}
+
+// durationUnit 是 FormatDuration 使用的单位，names 依次为英文简短、英文完整、中文简短及中文完整的名称。
+type durationUnit struct {
+	value time.Duration
+	names [4]string
+}
+
+// durationUnits 从大到小排列。
+var durationUnits = []durationUnit{
+	{Day, [4]string{"d", "day", "天", "天"}},
+	{time.Hour, [4]string{"h", "hour", "时", "小时"}},
+	{time.Minute, [4]string{"m", "minute", "分", "分钟"}},
+	{time.Second, [4]string{"s", "second", "秒", "秒"}},
+	{time.Millisecond, [4]string{"ms", "millisecond", "毫秒", "毫秒"}},
+}
+
+/*
+FormatDuration converts a duration to a human-friendly string, such as "2d 3h 15m" or "2 days 3 hours".
+Zero units are omitted, and the remainder smaller than the last shown unit is truncated.
+
+Parameters:
+  - d: The duration. A negative duration is prefixed with "-".
+  - option: The options. if nil, the default options will be used. Invalid units are replaced by the default ones.
+
+Returns:
+  - Formatted string. If all shown units are zero, it is zero of the smallest unit, such as "0s".
+
+Example:
+
+	d := 2*Day + 3*time.Hour + 15*time.Minute + 30*time.Second
+	s := FormatDuration(d, nil) // "2d 3h 15m"
+
+	option := NewDurationOption()
+	option.Precision = 2
+	option.Long = true
+	s = FormatDuration(d, option) // "2 days 3 hours"
+
+	option.Locale = LocaleChinese
+	s = FormatDuration(d, option) // "2天3小时"
+
+FormatDuration 将时长转换为易读的字符串，例如 "2d 3h 15m" 或 "2 days 3 hours"。
+省略为 0 的单位，比最后显示的单位更小的部分被截断。
+
+参数:
+  - d: 时长。负数以 "-" 开始。
+  - option: 选项。如果为 nil 则使用默认选项。无效的单位被替换为默认值。
+
+返回:
+  - 格式化后的字符串。如果显示的单位都为 0，则为最小单位的 0，例如 "0s"。
+*/
+func FormatDuration(d time.Duration, option *DurationOption) string {
+	if option == nil { // 保证 option 不为 nil。
+		option = NewDurationOption()
+	}
+
+	largest := unitIndex(option.LargestUnit, 0)
+	smallest := unitIndex(option.SmallestUnit, 3)
+	if largest > smallest {
+		largest, smallest = smallest, largest
+	}
+
+	locale := option.Locale
+	if option.Format != nil {
+		locale = option.Format.Locale
+	}
+
+	nameIndex := 0
+	if locale == LocaleChinese {
+		nameIndex = 2
+	}
+	if option.Long {
+		nameIndex++
+	}
+
+	negative := d < 0
+	if negative {
+		d = -d
+		if d < 0 { // 最小值取反后仍为负数，少 1 纳秒不影响结果，因为纳秒总是被截断。
+			d = math.MaxInt64
+		}
+	}
+
+	chinese := locale == LocaleChinese
+	parts := []string{}
+	count := 0 // 从最大的非 0 单位开始计数。
+	for i := largest; i <= smallest; i++ {
+		if option.Precision > 0 && count >= option.Precision {
+			break
+		}
+
+		unit := durationUnits[i]
+		value := int64(d / unit.value)
+		d %= unit.value
+		if value == 0 && count == 0 {
+			continue
+		}
+
+		count++
+		if value != 0 {
+			parts = append(parts, formatDurationUnit(value, unit.names[nameIndex], option.Long, chinese, option.Format))
+		}
+	}
+
+	if len(parts) == 0 {
+		return formatDurationUnit(0, durationUnits[smallest].names[nameIndex], option.Long, chinese, option.Format)
+	}
+
+	separator := " "
+	if chinese {
+		separator = ""
+	}
+	result := strings.Join(parts, separator)
+	if negative {
+		result = "-" + result
+	}
+	return result
+}
+
+// unitIndex 返回单位在 durationUnits 中的序号，不是有效的单位时返回 defaultIndex。
+func unitIndex(unit time.Duration, defaultIndex int) int {
+	for i, u := range durationUnits {
+		if u.value == unit {
+			return i
+		}
+	}
+	return defaultIndex
+}
+
+// formatDurationUnit 格式化一个单位的值及名称。英文完整的名称与数值之间有空格，且有复数。format 可以为 nil。
+func formatDurationUnit(value int64, name string, long bool, chinese bool, format *common.NumberFormat) string {
+	result := format.FormatInt(value)
+	if !long || chinese {
+		return result + name
+	}
+
+	result += " " + name
+	if value != 1 {
+		result += "s"
+	}
+	return result
+}
+
+// regexDurationPart 是时长中的一个数值及其单位，例如 "2d"、"3 hours" 或 "15分钟"。
+var regexDurationPart = regexp.MustCompile(`^\s*(\d+(?:\.\d+)?)\s*([a-zA-Zµ]+|\p{Han}+)\s*`)
+
+// parseDurationUnits 是 ParseDuration 识别的单位名称，包括 FormatDuration 使用的名称、复数及周。
+var parseDurationUnits = map[string]time.Duration{
+	"w": 7 * Day, "week": 7 * Day, "weeks": 7 * Day, "周": 7 * Day,
+	"us": time.Microsecond, "µs": time.Microsecond, "microsecond": time.Microsecond, "microseconds": time.Microsecond,
+	"ns": time.Nanosecond, "nanosecond": time.Nanosecond, "nanoseconds": time.Nanosecond,
+}
+
+func init() {
+	for _, unit := range durationUnits {
+		for _, name := range unit.names {
+			parseDurationUnits[name] = unit.value
+		}
+		parseDurationUnits[unit.names[1]+"s"] = unit.value
+	}
+}
+
+/*
+ParseDuration parses a duration string. Besides the format of time.ParseDuration, such as "1h30m",
+it accepts days and weeks, spaces, full unit names and Chinese units, such as the output of [FormatDuration].
+Unit names are case insensitive.
+
+Parameters:
+  - s: The duration string, such as "2d 3h", "1.5 days", "2 weeks" or "2天3小时". A leading "-" negates it.
+
+Returns:
+  - The duration.
+  - An error if the string is not a valid duration.
+
+ParseDuration 解析时长字符串。除 time.ParseDuration 的格式，例如 "1h30m" 之外，
+还接受天及周、空格、完整的单位名称及中文单位，例如 [FormatDuration] 的输出。单位名称不区分大小写。
+
+参数:
+  - s: 时长字符串，例如 "2d 3h"、"1.5 days"、"2 weeks" 或 "2天3小时"。以 "-" 开始时为负数。
+
+返回:
+  - 时长。
+  - 错误信息。字符串不是有效的时长时返回错误。
+*/
+func ParseDuration(s string) (time.Duration, error) {
+	text := strings.TrimSpace(s)
+	if d, err := time.ParseDuration(text); err == nil {
+		return d, nil
+	}
+
+	negative := strings.HasPrefix(text, "-")
+	if negative || strings.HasPrefix(text, "+") {
+		text = text[1:]
+	}
+	if text == "" {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+
+	var total float64
+	for text != "" {
+		subs := regexDurationPart.FindStringSubmatch(text)
+		if subs == nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+
+		unit, ok := parseDurationUnits[strings.ToLower(subs[2])]
+		if !ok {
+			return 0, fmt.Errorf("unknown unit %q in duration %q", subs[2], s)
+		}
+
+		value, _ := strconv.ParseFloat(subs[1], 64)
+		total += value * float64(unit)
+		text = text[len(subs[0]):]
+	}
+
+	if total > math.MaxInt64 {
+		return 0, fmt.Errorf("duration %q out of range", s)
+	}
+	if negative {
+		total = -total
+	}
+	return time.Duration(total), nil
+}
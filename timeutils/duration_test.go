@@ -0,0 +1,80 @@
+package timeutils
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/jqk/futool4go/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatDuration(t *testing.T) {
+	d := 2*Day + 3*time.Hour + 15*time.Minute + 30*time.Second + 500*time.Millisecond
+
+	// 默认精度为 3，从天到秒。
+	assert.Equal(t, "2d 3h 15m", FormatDuration(d, nil))
+	assert.Equal(t, "15m 30s", FormatDuration(15*time.Minute+30*time.Second, nil))
+	assert.Equal(t, "-1h 1s", FormatDuration(-time.Hour-time.Second, nil))
+
+	// 为 0 的单位被省略，但计入精度。
+	assert.Equal(t, "1d", FormatDuration(Day+5*time.Second, nil))
+	assert.Equal(t, "1d 5m", FormatDuration(Day+5*time.Minute+10*time.Second, nil))
+
+	// 都为 0 时显示最小单位的 0。
+	assert.Equal(t, "0s", FormatDuration(0, nil))
+	assert.Equal(t, "0s", FormatDuration(999*time.Millisecond, nil))
+	assert.Equal(t, "0s", FormatDuration(-999*time.Millisecond, nil))
+
+	option := NewDurationOption()
+	option.Precision = 2
+	option.Long = true
+	assert.Equal(t, "2 days 3 hours", FormatDuration(d, option))
+	assert.Equal(t, "1 hour", FormatDuration(time.Hour+time.Second, option))
+	assert.Equal(t, "1 minute 1 second", FormatDuration(time.Minute+time.Second, option))
+	assert.Equal(t, "0 seconds", FormatDuration(0, option))
+
+	option.Locale = LocaleChinese
+	assert.Equal(t, "2天3小时", FormatDuration(d, option))
+	option.Long = false
+	assert.Equal(t, "2天3时", FormatDuration(d, option))
+
+	// 精度为 0 时显示全部单位，单位范围可以调整。
+	option = NewDurationOption()
+	option.Precision = 0
+	option.SmallestUnit = time.Millisecond
+	assert.Equal(t, "2d 3h 15m 30s 500ms", FormatDuration(d, option))
+
+	option.LargestUnit = time.Hour
+	assert.Equal(t, "51h 15m 30s 500ms", FormatDuration(d, option))
+
+	option.LargestUnit = time.Minute
+	option.SmallestUnit = time.Minute
+	assert.Equal(t, "3075m", FormatDuration(d, option))
+
+	// 无效的单位使用默认值，最大与最小单位颠倒时交换。
+	option.LargestUnit = 3 * time.Second
+	option.SmallestUnit = 0
+	assert.Equal(t, "2d 3h 15m 30s", FormatDuration(d, option))
+	option.LargestUnit = time.Second
+	option.SmallestUnit = time.Hour
+	assert.Equal(t, "51h 15m 30s", FormatDuration(d, option))
+
+	// 未知语言使用英文。
+	option = NewDurationOption()
+	option.Locale = "fr"
+	assert.Equal(t, "2d 3h 15m", FormatDuration(d, option))
+
+	assert.Equal(t, "-106751d 23h 47m", FormatDuration(math.MinInt64, nil))
+}
+
+func TestFormatDurationWithNumberFormat(t *testing.T) {
+	option := NewDurationOption()
+	option.LargestUnit = time.Hour
+	option.Format = common.NewNumberFormat(LocaleChinese)
+	assert.Equal(t, "1,234时5分", FormatDuration(1234*time.Hour+5*time.Minute, option))
+
+	option.Format = common.NewNumberFormat(LocaleEnglish)
+	option.Long = true
+	assert.Equal(t, "1,234 hours 5 minutes", FormatDuration(1234*time.Hour+5*time.Minute, option))
+}
@@ -0,0 +1,173 @@
+package timeutils
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// ticksPerSecond 是每秒的 100 纳秒数，.NET ticks 及 Windows FILETIME 都使用该单位。
+	ticksPerSecond = 10_000_000
+	ticksPerDay    = ticksPerSecond * 24 * 3600
+
+	// fileTimeUnixSeconds 是 1601-01-01 到 1970-01-01 的秒数。
+	fileTimeUnixSeconds = 11_644_473_600
+
+	// excelMaxSerial 是 Excel 支持的最后一天 9999-12-31 的序号。
+	excelMaxSerial = 2_958_465
+)
+
+var (
+	// regexExcelSerial 是 Excel 序号，整数部分为天数，小数部分为一天中的时间。
+	regexExcelSerial = regexp.MustCompile(`^\d{1,7}(\.\d+)?$`)
+	// regexTicks 是 .NET ticks 或 Windows FILETIME，1900 年之后的值都是 17 至 19 位数字。
+	regexTicks = regexp.MustCompile(`^\d{17,19}$`)
+)
+
+/*
+FromExcelSerial converts an Excel serial date of the 1900 date system to time.
+The integer part is the number of days, 1 is 1900-01-01. The fraction is the time of day, rounded to milliseconds.
+Excel treats 1900 as a leap year, so 60 is its nonexistent 1900-02-29 and is returned as 1900-03-01, the same day as 61.
+
+Parameters:
+  - serial: The serial date.
+  - loc: The location of the wall clock. nil means time.Local.
+
+Returns:
+  - The converted time.
+
+Example:
+
+	tm := FromExcelSerial(45000, time.UTC)     // 2023-03-15 00:00:00
+	tm = FromExcelSerial(45000.75, time.UTC)   // 2023-03-15 18:00:00
+
+FromExcelSerial 将 Excel 1900 日期系统的序号转换为时间。
+整数部分为天数，1 为 1900-01-01。小数部分为一天中的时间，舍入到毫秒。
+Excel 将 1900 年视为闰年，所以 60 是其中不存在的 1900-02-29，返回 1900-03-01，与 61 同一天。
+
+参数:
+  - serial: 序号。
+  - loc: 本地时间的时区。nil 表示 time.Local。
+
+返回:
+  - 转换后的时间。
+*/
+func FromExcelSerial(serial float64, loc *time.Location) time.Time {
+	if loc == nil {
+		loc = time.Local
+	}
+
+	// Excel 多算了 1900-02-29，所以 61 及之后的序号从 1899-12-30 开始计算，之前的从 1899-12-31 开始。
+	base := 30
+	if serial < 61 {
+		base = 31
+	}
+
+	days := int(serial)
+	milliseconds := int((serial-float64(days))*24*3600*1000 + 0.5)
+	return time.Date(1899, 12, base+days, 0, 0, 0, milliseconds*1000_000, loc)
+}
+
+/*
+FromDotNetTicks converts .NET DateTime.Ticks, the number of 100 nanoseconds since 0001-01-01 00:00:00, to time.
+The ticks do not carry a time zone, so they are treated as the wall clock in loc.
+
+Parameters:
+  - ticks: The ticks.
+  - loc: The location of the wall clock. nil means time.Local.
+
+Returns:
+  - The converted time.
+
+Example:
+
+	tm := FromDotNetTicks(638084736000000000, time.UTC) // 2023-01-05 00:00:00
+
+FromDotNetTicks 将 .NET DateTime.Ticks，即从 0001-01-01 00:00:00 开始的 100 纳秒数，转换为时间。
+ticks 不含时区，所以按 loc 中的本地时间处理。
+
+参数:
+  - ticks: ticks 值。
+  - loc: 本地时间的时区。nil 表示 time.Local。
+
+返回:
+  - 转换后的时间。
+*/
+func FromDotNetTicks(ticks int64, loc *time.Location) time.Time {
+	if loc == nil {
+		loc = time.Local
+	}
+
+	// 分别计算天、秒及纳秒，避免 time.Duration 溢出。
+	days := ticks / ticksPerDay
+	rest := ticks % ticksPerDay
+	return time.Date(1, 1, 1+int(days), 0, 0, int(rest/ticksPerSecond), int(rest%ticksPerSecond*100), loc)
+}
+
+/*
+FromFileTime converts Windows FILETIME, the number of 100 nanoseconds since 1601-01-01 00:00:00 UTC, to time.
+
+Parameters:
+  - fileTime: The FILETIME value, with the high and low parts combined.
+  - loc: The location of the result. nil means time.Local.
+
+Returns:
+  - The converted time.
+
+Example:
+
+	tm := FromFileTime(133173504000000000, time.UTC) // 2023-01-05 00:00:00
+
+FromFileTime 将 Windows FILETIME，即从 UTC 1601-01-01 00:00:00 开始的 100 纳秒数，转换为时间。
+
+参数:
+  - fileTime: FILETIME 值，高位及低位已经合并。
+  - loc: 结果的时区。nil 表示 time.Local。
+
+返回:
+  - 转换后的时间。
+*/
+func FromFileTime(fileTime int64, loc *time.Location) time.Time {
+	if loc == nil {
+		loc = time.Local
+	}
+
+	seconds := fileTime/ticksPerSecond - fileTimeUnixSeconds
+	nanoseconds := fileTime % ticksPerSecond * 100
+	return time.Unix(seconds, nanoseconds).In(loc)
+}
+
+// parseEpoch 按选项中启用的格式解析整个字符串，首尾的空格被忽略。
+// 同时启用 .NET ticks 及 FILETIME 时，先尝试 ticks，结果在 1900 年之前时再尝试 FILETIME。
+func (p *Parser) parseEpoch(s string) (time.Time, bool) {
+	text := strings.TrimSpace(s)
+
+	if p.option.ExcelSerial && regexExcelSerial.MatchString(text) {
+		serial, err := strconv.ParseFloat(text, 64)
+		if err == nil && serial >= 1 && serial < excelMaxSerial+1 {
+			return FromExcelSerial(serial, p.option.Location), true
+		}
+	}
+
+	if (p.option.DotNetTicks || p.option.FileTime) && regexTicks.MatchString(text) {
+		ticks, err := strconv.ParseInt(text, 10, 64)
+		if err != nil {
+			return time.Time{}, false
+		}
+
+		if p.option.DotNetTicks {
+			if t := FromDotNetTicks(ticks, p.option.Location); t.Year() >= 1900 && t.Year() <= 9999 {
+				return t, true
+			}
+		}
+		if p.option.FileTime {
+			if t := FromFileTime(ticks, p.option.Location); t.Year() >= 1900 && t.Year() <= 9999 {
+				return t, true
+			}
+		}
+	}
+
+	return time.Time{}, false
+}
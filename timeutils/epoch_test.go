@@ -0,0 +1,74 @@
+package timeutils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromEpoch(t *testing.T) {
+	const layout = "2006-01-02 15:04:05.000"
+
+	assert.Equal(t, "1900-01-01 00:00:00.000", FromExcelSerial(1, time.UTC).Format(layout))
+	assert.Equal(t, "1900-02-28 00:00:00.000", FromExcelSerial(59, time.UTC).Format(layout))
+	assert.Equal(t, "1900-03-01 00:00:00.000", FromExcelSerial(60, time.UTC).Format(layout))
+	assert.Equal(t, "1900-03-01 00:00:00.000", FromExcelSerial(61, time.UTC).Format(layout))
+	assert.Equal(t, "2023-03-15 18:00:00.000", FromExcelSerial(45000.75, time.UTC).Format(layout))
+	// 14:30:15 不能精确表示，舍入到毫秒。
+	assert.Equal(t, "2023-01-05 14:30:15.000", FromExcelSerial(44931.604340277778, time.UTC).Format(layout))
+
+	assert.Equal(t, "2023-01-05 00:00:00.000", FromDotNetTicks(638084736000000000, time.UTC).Format(layout))
+	assert.Equal(t, "2023-01-05 14:30:15.123", FromDotNetTicks(638085258151230000, time.UTC).Format(layout))
+	assert.Equal(t, "0001-01-01 00:00:00.000", FromDotNetTicks(0, time.UTC).Format(layout))
+
+	assert.Equal(t, "2023-01-05 00:00:00.000", FromFileTime(133173504000000000, time.UTC).Format(layout))
+	assert.Equal(t, "1970-01-01 00:00:00.000", FromFileTime(116444736000000000, time.UTC).Format(layout))
+
+	loc := time.FixedZone("", 8*3600)
+	assert.Equal(t, "2023-01-05 08:00:00.000", FromFileTime(133173504000000000, loc).Format(layout))
+	assert.Equal(t, "2023-01-05 00:00:00.000", FromDotNetTicks(638084736000000000, loc).Format(layout))
+}
+
+func TestParserEpoch(t *testing.T) {
+	const layout = "2006-01-02 15:04:05.000"
+
+	// 默认不解析。
+	parser, err := NewParser(nil)
+	assert.Nil(t, err)
+	_, err = parser.ParseDateTime("45000.75")
+	assert.NotNil(t, err)
+
+	option := NewParserOption()
+	option.Location = time.UTC
+	option.ExcelSerial = true
+	option.DotNetTicks = true
+	option.FileTime = true
+	parser, err = NewParser(option)
+	assert.Nil(t, err)
+
+	for _, v := range []struct {
+		s        string
+		expected string
+	}{
+		{"45000.75", "2023-03-15 18:00:00.000"},
+		{" 44931 ", "2023-01-05 00:00:00.000"},
+		{"638085258151230000", "2023-01-05 14:30:15.123"},
+		{"133173504000000000", "2023-01-05 00:00:00.000"}, // 作为 ticks 在 1900 年之前，按 FILETIME 解析。
+		{"20230105143015", "2023-01-05 14:30:15.000"},     // 不是启用的数字格式，使用内置格式。
+	} {
+		tm, err := parser.ParseDateTime(v.s)
+		assert.Nil(t, err, v.s)
+		assert.Equal(t, v.expected, tm.Format(layout), v.s)
+	}
+
+	tm, err := parser.ParseDate("45000.75")
+	assert.Nil(t, err)
+	assert.Equal(t, "2023-03-15 00:00:00.000", tm.Format(layout))
+
+	// 需要整个字符串都是数字。
+	_, err = parser.ParseDateTime("photo_45000.75.jpg")
+	assert.NotNil(t, err)
+	_, err = parser.ParseDateTime("0")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,164 @@
+package timeutils
+
+import (
+	"fmt"
+	"time"
+)
+
+/*
+DurationFlag is a flag.Value that parses a duration string into a time.Duration variable.
+Strings are parsed by [ParseDuration], so "2d", "1.5 hours" and "1h30m" are all accepted.
+
+Example:
+
+	timeout := 30 * time.Second
+	flag.Var(timeutils.NewDurationFlag(&timeout), "timeout", "timeout, such as 90s or 2m")
+
+DurationFlag 是将时长字符串解析到 time.Duration 变量的 flag.Value。
+字符串使用 [ParseDuration] 解析，所以 "2d"、"1.5 hours" 及 "1h30m" 都可以接受。
+*/
+type DurationFlag struct {
+	value *time.Duration
+}
+
+/*
+NewDurationFlag creates a DurationFlag that stores the parsed duration in value. The current value is the default.
+
+NewDurationFlag 创建 DurationFlag，解析出的时长保存在 value 中。value 当前的值为默认值。
+*/
+func NewDurationFlag(value *time.Duration) *DurationFlag {
+	return &DurationFlag{value: value}
+}
+
+/*
+String returns the duration like time.Duration.String, such as "1h30m0s".
+
+String 与 time.Duration.String 一样返回时长，例如 "1h30m0s"。
+*/
+func (f *DurationFlag) String() string {
+	// flag 包会使用零值调用 String，此时 value 为 nil。
+	if f == nil || f.value == nil {
+		return ""
+	}
+	return f.value.String()
+}
+
+/*
+Set parses the duration string for flag.Value.
+
+Set 为 flag.Value 解析时长字符串。
+*/
+func (f *DurationFlag) Set(s string) error {
+	d, err := ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*f.value = d
+	return nil
+}
+
+/*
+Get returns the time.Duration for flag.Getter.
+
+Get 为 flag.Getter 返回 time.Duration。
+*/
+func (f *DurationFlag) Get() any {
+	return *f.value
+}
+
+/*
+TimeFlag is a flag.Value that parses a time string into a time.Time variable.
+It tries, in order, the date time and date formats of [Parser], ISO 8601 and relative times like "yesterday"
+or "3 days ago", see [ParseRelative]. The whole string must be the time.
+
+Example:
+
+	var since time.Time
+	flag.Var(timeutils.NewTimeFlag(&since, nil), "since", "start time, such as 2023-01-01 or yesterday")
+
+TimeFlag 是将时间字符串解析到 time.Time 变量的 flag.Value。
+依次尝试 [Parser] 的日期时间及日期格式、ISO 8601，以及“yesterday”或“3 days ago”这样的相对时间，参见 [ParseRelative]。
+整个字符串必须是时间。
+*/
+type TimeFlag struct {
+	value  *time.Time
+	parser *Parser
+	clock  Clock
+}
+
+/*
+NewTimeFlag creates a TimeFlag that stores the parsed time in value. The current value is the default.
+
+Parameters:
+  - value: The variable to store the time.
+  - loc: The location for strings without a time zone, and for relative times. nil means time.Local.
+
+Returns:
+  - The created TimeFlag.
+
+NewTimeFlag 创建 TimeFlag，解析出的时间保存在 value 中。value 当前的值为默认值。
+
+参数:
+  - value: 保存时间的变量。
+  - loc: 字符串中没有时区时，以及相对时间使用的时区。nil 表示 time.Local。
+
+返回:
+  - 创建的 TimeFlag。
+*/
+func NewTimeFlag(value *time.Time, loc *time.Location) *TimeFlag {
+	if loc == nil {
+		loc = time.Local
+	}
+
+	option := NewParserOption()
+	option.Location = loc
+	option.Match = MatchWhole
+	// 默认选项总是有效的，所以不会返回错误。
+	parser, _ := NewParser(option)
+
+	return &TimeFlag{value: value, parser: parser, clock: SystemClock}
+}
+
+/*
+String returns the time in RFC 3339 format, or an empty string for the zero time.
+
+String 返回 RFC 3339 格式的时间，零值时返回空字符串。
+*/
+func (f *TimeFlag) String() string {
+	// flag 包会使用零值调用 String，此时 value 为 nil。
+	if f == nil || f.value == nil || f.value.IsZero() {
+		return ""
+	}
+	return f.value.Format(time.RFC3339)
+}
+
+/*
+Set parses the time string for flag.Value.
+
+Set 为 flag.Value 解析时间字符串。
+*/
+func (f *TimeFlag) Set(s string) error {
+	loc := f.parser.Option().Location
+
+	if t, err := f.parser.ParseDateTime(s); err == nil {
+		*f.value = t
+	} else if t, err = f.parser.ParseDate(s); err == nil {
+		*f.value = t
+	} else if t, err = f.parser.ParseISO8601(s); err == nil {
+		*f.value = t
+	} else if t, err = ParseRelative(s, f.clock.Now().In(loc)); err == nil {
+		*f.value = t
+	} else {
+		return fmt.Errorf("invalid time %q", s)
+	}
+	return nil
+}
+
+/*
+Get returns the time.Time for flag.Getter.
+
+Get 为 flag.Getter 返回 time.Time。
+*/
+func (f *TimeFlag) Get() any {
+	return *f.value
+}
@@ -0,0 +1,99 @@
+package timeutils
+
+import (
+	"flag"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDuration(t *testing.T) {
+	for s, expected := range map[string]time.Duration{
+		"1h30m":             90 * time.Minute,
+		"2d":                2 * Day,
+		"2d 3h 15m":         2*Day + 3*time.Hour + 15*time.Minute,
+		"1.5 days":          36 * time.Hour,
+		"2 weeks":           14 * Day,
+		"1 hour 1 minute":   61 * time.Minute,
+		"2天3小时":             2*Day + 3*time.Hour,
+		"3时15分20秒":          3*time.Hour + 15*time.Minute + 20*time.Second,
+		"-1d 12h":           -36 * time.Hour,
+		"500ms":             500 * time.Millisecond,
+		"10 Seconds 300 ms": 10*time.Second + 300*time.Millisecond,
+		" 1W ":              7 * Day,
+	} {
+		d, err := ParseDuration(s)
+		assert.Nil(t, err, s)
+		assert.Equal(t, expected, d, s)
+	}
+
+	// FormatDuration 的输出可以解析回来。
+	d := 2*Day + 3*time.Hour + 15*time.Minute + 30*time.Second
+	for _, locale := range []string{LocaleEnglish, LocaleChinese} {
+		for _, long := range []bool{false, true} {
+			option := NewDurationOption()
+			option.Precision = 0
+			option.Locale = locale
+			option.Long = long
+			parsed, err := ParseDuration(FormatDuration(d, option))
+			assert.Nil(t, err)
+			assert.Equal(t, d, parsed)
+		}
+	}
+
+	for _, s := range []string{"", "-", "abc", "2x", "2d foo", "1e400d"} {
+		_, err := ParseDuration(s)
+		assert.NotNil(t, err, s)
+	}
+}
+
+func TestDurationFlag(t *testing.T) {
+	timeout := 30 * time.Second
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Var(NewDurationFlag(&timeout), "timeout", "")
+
+	assert.Equal(t, "30s", fs.Lookup("timeout").DefValue)
+	assert.Nil(t, fs.Parse([]string{"--timeout", "1d 2h"}))
+	assert.Equal(t, 26*time.Hour, timeout)
+	assert.Equal(t, "26h0m0s", fs.Lookup("timeout").Value.String())
+	assert.Equal(t, 26*time.Hour, fs.Lookup("timeout").Value.(flag.Getter).Get())
+
+	fs.SetOutput(io.Discard)
+	assert.NotNil(t, fs.Parse([]string{"--timeout", "soon"}))
+}
+
+func TestTimeFlag(t *testing.T) {
+	var since time.Time
+	f := NewTimeFlag(&since, time.UTC)
+	clock := NewManualClock(time.Date(2023, 1, 5, 14, 30, 0, 0, time.UTC))
+	f.clock = clock
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Var(f, "since", "")
+	assert.Equal(t, "", fs.Lookup("since").DefValue)
+
+	assert.Nil(t, fs.Parse([]string{"--since", "2023-01-01"}))
+	assert.Equal(t, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), since)
+
+	assert.Nil(t, f.Set("2023-01-02 08:30:00"))
+	assert.Equal(t, time.Date(2023, 1, 2, 8, 30, 0, 0, time.UTC), since)
+	assert.Equal(t, "2023-01-02T08:30:00Z", f.String())
+
+	assert.Nil(t, f.Set("2023-W01-1"))
+	assert.Equal(t, time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC), since)
+
+	assert.Nil(t, f.Set("yesterday"))
+	assert.Equal(t, time.Date(2023, 1, 4, 0, 0, 0, 0, time.UTC), since)
+	assert.Nil(t, f.Set("3 days ago"))
+	assert.Equal(t, time.Date(2023, 1, 2, 14, 30, 0, 0, time.UTC), f.Get())
+
+	// 只有 ISO 8601 能解析的字符串也使用 TimeFlag 的时区。
+	assert.Nil(t, f.Set("2023002T0830"))
+	assert.Equal(t, time.Date(2023, 1, 2, 8, 30, 0, 0, time.UTC), since)
+	assert.Equal(t, time.UTC, since.Location())
+
+	assert.NotNil(t, f.Set("photo 2023-01-01.jpg"))
+	assert.NotNil(t, f.Set("someday"))
+}
@@ -0,0 +1,208 @@
+package timeutils
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// regexISODate 是 ISO 8601 的日期部分，扩展格式（有“-”）与基本格式（无“-”）都可以：
+//  1. 年月日：2023-02-01 或 20230201。
+//  2. 周日期：2023-W05-3 或 2023W053，星期几可以省略，表示星期一。
+//  3. 序数日期：2023-032 或 2023032，表示一年中的第几天。
+//
+// 分组依次为：年；扩展格式的月、日；基本格式的月、日；扩展格式的周、星期；基本格式的周、星期；扩展及基本格式的序数。
+var regexISODate = regexp.MustCompile(
+	`^(\d{4})(?:-(\d{2})-(\d{2})|(\d{2})(\d{2})|-W(\d{2})(?:-([1-7]))?|W(\d{2})([1-7])?|-(\d{3})|(\d{3}))$`)
+
+// regexISOTime 是 ISO 8601 的时间部分，扩展格式（有“:”）与基本格式（无“:”）都可以：
+//  1. 精确到小时、分钟或秒，秒之后可以有任意位数的小数，小数点为“.”或“,”。
+//  2. 之后可以有时区，“Z”或者“+08”、“+08:00”、“+0800”这样的偏移量。
+//
+// 分组依次为：时；扩展格式的分、秒、小数；基本格式的分、秒、小数；时区。
+var regexISOTime = regexp.MustCompile(
+	`^(\d{2})(?::(\d{2})(?::(\d{2})(?:[.,](\d+))?)?|(\d{2})(?:(\d{2})(?:[.,](\d+))?)?)?(Z|[-+]\d{2}(?::?\d{2})?)?$`)
+
+/*
+ParseISO8601 parses the ISO 8601 string like [ParseISO8601E], using the options of the parser.
+The strictness and separator options are not used, since ISO 8601 defines its own ranges and separators.
+[Parser.ParseDateTime] and [Parser.ParseDate] only try ISO 8601 if its separators are allowed by the options,
+and never try the basic ordinal date such as "2023123", which only this method parses.
+
+ParseISO8601 与 [ParseISO8601E] 一样解析 ISO 8601 字符串，但使用解析器的选项。
+不使用严格程度及分隔符选项，因为 ISO 8601 规定了自己的范围及分隔符。
+[Parser.ParseDateTime] 及 [Parser.ParseDate] 仅在选项允许 ISO 8601 的分隔符时尝试，
+且不尝试“2023123”这样基本格式的序数日期，它只能由该方法解析。
+*/
+func (p *Parser) ParseISO8601(s string) (time.Time, error) {
+	result, _, err := parseISO8601(s, p.option.Location)
+	return result, err
+}
+
+/*
+ParseISO8601 parses ISO 8601 strings into time variables.
+Unlike [ParseDateTime], the whole string must be in ISO 8601 format, no prefix or suffix is allowed.
+
+The date can be a calendar date, a week date or an ordinal date, in extended or basic format.
+The time is optional and follows a "T". It can be accurate to hours, minutes or seconds,
+and seconds can have a fraction of any precision, digits beyond nanoseconds are truncated.
+"24:00" means the start of the next day.
+A time zone can follow the time, either "Z" for UTC or an offset like "+08", "+08:00" or "-0530".
+The result is in that zone if it is given in the string, otherwise in time.Local.
+
+Parameters:
+  - s: The string to parse.
+
+Returns:
+  - The parsed time. nil is returned on failure.
+
+Example:
+
+	tm = ParseISO8601("2023-02-01")                          // 2023-02-01 00:00:00
+	tm = ParseISO8601("2023-W05-3")                          // 2023-02-01 00:00:00, Wednesday of week 5
+	tm = ParseISO8601("2023-032")                            // 2023-02-01 00:00:00, the 32nd day of 2023
+	tm = ParseISO8601("20230201T153456,123456789Z")          // 2023-02-01 15:34:56.123456789 UTC
+	tm = ParseISO8601("2023-02-01T15:34:56.123456789123+08") // 2023-02-01 15:34:56.123456789 +0800
+
+	tm = ParseISO8601("2023-W53-1")                          // nil because 2023 has only 52 weeks.
+
+ParseISO8601 将 ISO 8601 字符串转换为时间变量。
+与 [ParseDateTime] 不同，整个字符串必须是 ISO 8601 格式，不能有前缀或后缀。
+
+日期可以是年月日、周日期或序数日期，可以是扩展格式或基本格式。
+时间可以省略，在“T”之后。可以精确到小时、分钟或秒，秒可以有任意精度的小数，超出纳秒的部分被截断。
+“24:00”表示下一天的开始。
+时间之后可以有时区，可以是表示 UTC 的“Z”，或者“+08”、“+08:00”、“-0530”这样的偏移量。
+字符串中给出时区时，结果使用该时区，否则使用 time.Local。
+
+参数:
+  - s: 待解析的字符串。
+
+返回:
+  - 解析后的时间。失败均返回 nil。
+*/
+func ParseISO8601(s string) *time.Time {
+	return toPointer(ParseISO8601E(s))
+}
+
+/*
+ParseISO8601E is like [ParseISO8601], but returns an error describing why parsing failed.
+
+ParseISO8601E 与 [ParseISO8601] 相同，但返回说明解析失败原因的错误。
+*/
+func ParseISO8601E(s string) (time.Time, error) {
+	return defaultParser(nil).ParseISO8601(s)
+}
+
+// parseISO8601 解析 ISO 8601 字符串，没有时区时使用 loc。hasTime 表示字符串中是否有时间部分。
+func parseISO8601(s string, loc *time.Location) (result time.Time, hasTime bool, err error) {
+	datePart, timePart, hasTime := strings.Cut(s, "T")
+
+	date, err := parseISODate(s, datePart, loc)
+	if err != nil || !hasTime {
+		return date, hasTime, err
+	}
+
+	subs := regexISOTime.FindStringSubmatch(timePart)
+	if len(subs) == 0 {
+		return time.Time{}, hasTime, newParseError(s, "not an ISO 8601 time")
+	}
+
+	hour, _ := strconv.Atoi(subs[1])
+	minute, _ := strconv.Atoi(subs[2] + subs[5]) // 扩展与基本格式只有一个不为空，下同。
+	second, _ := strconv.Atoi(subs[3] + subs[6])
+	nanosecond := parseFraction(subs[4] + subs[7])
+
+	// 24:00 表示下一天的开始，time.Date 会自动进位。
+	if hour == 24 && (minute != 0 || second != 0 || nanosecond != 0) {
+		return time.Time{}, hasTime, newParseError(s, "only 24:00 is allowed for hour 24")
+	} else if hour != 24 {
+		if err := validateDateTimeFields(date.Year(), int(date.Month()), date.Day(), hour, minute, second); err != nil {
+			return time.Time{}, hasTime, newParseError(s, err.Error())
+		}
+	}
+
+	zone, err := parseISOZone(subs[8], loc)
+	if err != nil {
+		return time.Time{}, hasTime, newParseError(s, err.Error())
+	}
+
+	result = time.Date(date.Year(), date.Month(), date.Day(), hour, minute, second, nanosecond, zone)
+	return result, hasTime, nil
+}
+
+// parseISODate 解析 ISO 8601 的日期部分。s 为完整的字符串，用于错误信息。
+func parseISODate(s string, datePart string, loc *time.Location) (time.Time, error) {
+	subs := regexISODate.FindStringSubmatch(datePart)
+	if len(subs) == 0 {
+		return time.Time{}, newParseError(s, "not an ISO 8601 date")
+	}
+
+	year, _ := strconv.Atoi(subs[1])
+
+	if month := subs[2] + subs[4]; month != "" {
+		m, _ := strconv.Atoi(month)
+		day, _ := strconv.Atoi(subs[3] + subs[5])
+		if err := validateDateTimeFields(year, m, day, 0, 0, 0); err != nil {
+			return time.Time{}, newParseError(s, err.Error())
+		}
+		return time.Date(year, time.Month(m), day, 0, 0, 0, 0, loc), nil
+	}
+
+	if week := subs[6] + subs[8]; week != "" {
+		w, _ := strconv.Atoi(week)
+		weekday := 1
+		if d := subs[7] + subs[9]; d != "" {
+			weekday, _ = strconv.Atoi(d)
+		}
+
+		// 12 月 28 日总是在最后一周。
+		_, weeks := time.Date(year, 12, 28, 0, 0, 0, 0, time.UTC).ISOWeek()
+		if w < 1 || w > weeks {
+			return time.Time{}, newParseError(s, fmt.Sprintf("week %d out of range for %d", w, year))
+		}
+
+		// 1 月 4 日总是在第 1 周，先找到第 1 周的星期一。
+		jan4 := time.Date(year, 1, 4, 0, 0, 0, 0, loc)
+		offset := (int(jan4.Weekday()) + 6) % 7
+		return jan4.AddDate(0, 0, (w-1)*7+weekday-1-offset), nil
+	}
+
+	day, _ := strconv.Atoi(subs[10] + subs[11])
+	days := time.Date(year, 12, 31, 0, 0, 0, 0, time.UTC).YearDay()
+	if day < 1 || day > days {
+		return time.Time{}, newParseError(s, fmt.Sprintf("day %d out of range for %d", day, year))
+	}
+	return time.Date(year, 1, day, 0, 0, 0, 0, loc), nil
+}
+
+// parseFraction 将秒的小数部分转换为纳秒，超过 9 位的部分被截断。
+func parseFraction(fraction string) int {
+	if fraction == "" {
+		return 0
+	}
+	if len(fraction) > 9 {
+		fraction = fraction[:9]
+	}
+
+	nanosecond, _ := strconv.Atoi(fraction + strings.Repeat("0", 9-len(fraction)))
+	return nanosecond
+}
+
+// parseISOZone 解析 regexISOTime 匹配的时区。没有时区时返回 loc。
+func parseISOZone(zone string, loc *time.Location) (*time.Location, error) {
+	if zone == "" || zone == "Z" {
+		return parseZone(zone, "", "", loc), nil
+	}
+
+	hours := zone[:3]
+	minutes := strings.TrimPrefix(zone[3:], ":")
+	h, _ := strconv.Atoi(hours)
+	m, _ := strconv.Atoi(minutes)
+	if h < -14 || h > 14 || m > 59 {
+		return nil, fmt.Errorf("time zone offset %s out of range", zone)
+	}
+	return parseZone("", hours, minutes, loc), nil
+}
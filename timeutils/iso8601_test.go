@@ -0,0 +1,155 @@
+package timeutils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseISO8601Date(t *testing.T) {
+	// 年月日、周日期及序数日期，扩展格式及基本格式都表示同一天。
+	for _, s := range []string{"2023-02-01", "20230201", "2023-W05-3", "2023W053", "2023-032", "2023032"} {
+		tm, err := ParseISO8601E(s)
+		assert.Nil(t, err, s)
+		assert.Equal(t, "2023-02-01 00:00:00", tm.Format("2006-01-02 15:04:05"), s)
+		assert.Equal(t, time.Local, tm.Location(), s)
+	}
+
+	// 省略星期几表示星期一。
+	tm := ParseISO8601("2023-W05")
+	assert.NotNil(t, tm)
+	assert.Equal(t, "2023-01-30", tm.Format("2006-01-02"))
+
+	// 第 1 周可能从上一年开始，第 53 周可能在下一年结束。
+	tm = ParseISO8601("2021-W01-1")
+	assert.NotNil(t, tm)
+	assert.Equal(t, "2021-01-04", tm.Format("2006-01-02"))
+	tm = ParseISO8601("2020-W01-1")
+	assert.NotNil(t, tm)
+	assert.Equal(t, "2019-12-30", tm.Format("2006-01-02"))
+	tm = ParseISO8601("2020-W53-7")
+	assert.NotNil(t, tm)
+	assert.Equal(t, "2021-01-03", tm.Format("2006-01-02"))
+
+	// 闰年有 366 天。
+	tm = ParseISO8601("2020-366")
+	assert.NotNil(t, tm)
+	assert.Equal(t, "2020-12-31", tm.Format("2006-01-02"))
+
+	// 超出范围。
+	for _, s := range []string{"2023-W53-1", "2023-W00", "2023-366", "2023-000", "2023-02-29", "2023-13-01"} {
+		_, err := ParseISO8601E(s)
+		assert.NotNil(t, err, s)
+	}
+
+	// 不是 ISO 8601 格式，包括有前缀或后缀。
+	for _, s := range []string{"2023/02/01", "2023-2-1", "2023-W05-8", "IMG_2023-02-01", "2023-02-01.jpg", "2023-0201"} {
+		_, err := ParseISO8601E(s)
+		assert.NotNil(t, err, s)
+	}
+}
+
+func TestParseISO8601Time(t *testing.T) {
+	tm, err := ParseISO8601E("2023-02-01T15:34:56.123456789123+08:00")
+	assert.Nil(t, err)
+	assert.Equal(t, "2023-02-01 15:34:56.123456789 +0800", tm.Format("2006-01-02 15:04:05.000000000 -0700"))
+
+	// 基本格式，“,”作为小数点。
+	tm, err = ParseISO8601E("20230201T153456,5Z")
+	assert.Nil(t, err)
+	assert.Equal(t, "2023-02-01 15:34:56.500", tm.Format("2006-01-02 15:04:05.000"))
+	assert.Equal(t, time.UTC, tm.Location())
+
+	// 精确到分钟或小时，各种时区格式。
+	tm, err = ParseISO8601E("2023-02-01T15:34-0530")
+	assert.Nil(t, err)
+	assert.Equal(t, "2023-02-01 15:34:00 -0530", tm.Format("2006-01-02 15:04:05 -0700"))
+	tm, err = ParseISO8601E("2023-032T15+08")
+	assert.Nil(t, err)
+	assert.Equal(t, "2023-02-01 15:00:00 +0800", tm.Format("2006-01-02 15:04:05 -0700"))
+
+	// 周日期也可以有时间，没有时区时使用 time.Local。
+	tm, err = ParseISO8601E("2023-W05-3T08:00:00")
+	assert.Nil(t, err)
+	assert.Equal(t, "2023-02-01 08:00:00", tm.Format("2006-01-02 15:04:05"))
+	assert.Equal(t, time.Local, tm.Location())
+
+	// 24:00 表示下一天的开始。
+	tm, err = ParseISO8601E("2023-12-31T24:00:00Z")
+	assert.Nil(t, err)
+	assert.Equal(t, "2024-01-01 00:00:00", tm.Format("2006-01-02 15:04:05"))
+
+	for _, s := range []string{
+		"2023-02-01T24:00:01", "2023-02-01T25:00", "2023-02-01T15:60", "2023-02-01T15:34:56+15:00",
+		"2023-02-01T", "2023-02-01T15:34:56.", "2023-02-01 15:34:56", "2023-02-01T15:3",
+	} {
+		_, err := ParseISO8601E(s)
+		assert.NotNil(t, err, s)
+	}
+
+	// 解析器使用自己的时区。
+	option := NewParserOption()
+	option.Location = time.UTC
+	parser, err := NewParser(option)
+	assert.Nil(t, err)
+	tm, err = parser.ParseISO8601("2023-02-01T15:34")
+	assert.Nil(t, err)
+	assert.Equal(t, time.UTC, tm.Location())
+}
+
+func TestParseISO8601Fallback(t *testing.T) {
+	// 内置格式只能解析 3 位毫秒，且无法识别其后的时区。
+	tm := ParseDateTime("2023-02-01T15:34:56.123456Z")
+	assert.NotNil(t, tm)
+	assert.Equal(t, "2023-02-01 15:34:56.123456", tm.Format("2006-01-02 15:04:05.000000"))
+	assert.Equal(t, time.UTC, tm.Location())
+
+	tm = ParseDateTime("2023-W05-3T15:34:56")
+	assert.NotNil(t, tm)
+	assert.Equal(t, "2023-02-01 15:34:56", tm.Format("2006-01-02 15:04:05"))
+
+	// 没有时间部分。
+	_, err := ParseDateTimeE("2023-W05-3")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "no time component")
+
+	// 日期只使用日期部分。
+	tm = ParseDate("2023-W05-3")
+	assert.NotNil(t, tm)
+	assert.Equal(t, "2023-02-01", tm.Format("2006-01-02"))
+	tm = ParseDate("2023-032T23:30:00-10:00")
+	assert.NotNil(t, tm)
+	assert.Equal(t, "2023-02-01 00:00:00", tm.Format("2006-01-02 15:04:05"))
+	assert.Equal(t, time.Local, tm.Location())
+
+	// 基本格式的序数日期可能是缺少数字的日期，只能由 ParseISO8601 解析，有分隔符时才在默认的解析中尝试。
+	assert.Nil(t, ParseDate("2023123"))
+	assert.Nil(t, ParseDateTime("2023123T1200"))
+	assert.Equal(t, "2023-05-03", ParseISO8601("2023123").Format("2006-01-02"))
+	tm = ParseDate("2023-123")
+	assert.NotNil(t, tm)
+	assert.Equal(t, "2023-05-03", tm.Format("2006-01-02"))
+
+	// 不是完整的 ISO 8601 字符串时仍然使用内置格式。
+	tm = ParseDateTime("IMG_2023-02-01T15:34:56.123456Z.jpg")
+	assert.NotNil(t, tm)
+	assert.Equal(t, "2023-02-01 15:34:56.123", tm.Format("2006-01-02 15:04:05.000"))
+
+	// 解析器只在选项允许 ISO 8601 的分隔符时尝试。
+	option := NewParserOption()
+	option.DateTimeSeparators = " "
+	parser, err := NewParser(option)
+	assert.Nil(t, err)
+	_, err = parser.ParseDateTime("2023-W05-3T15:34:56")
+	assert.NotNil(t, err)
+	_, err = parser.ParseISO8601("2023-W05-3T15:34:56")
+	assert.Nil(t, err)
+
+	// 宽松模式下，超出范围的 ISO 8601 字符串由内置格式解析。
+	RequireDateTimeFieldValid = false
+	defer func() { RequireDateTimeFieldValid = true }()
+	tm = ParseDateTime("2010-13-23T15:34:56")
+	assert.NotNil(t, tm)
+	assert.Equal(t, "2011-01-23 15:34:56", tm.Format("2006-01-02 15:04:05"))
+}
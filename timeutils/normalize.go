@@ -0,0 +1,115 @@
+package timeutils
+
+import (
+	"strings"
+	"time"
+)
+
+/*
+NormalizeDateTime parses the date time string like [Parser.ParseDateTime] and formats it with the layout,
+so different spellings of the same time become the same string.
+
+Parameters:
+  - s: The string to parse. Any prefix and suffix are dropped.
+  - layout: The layout of time.Format, such as "2006-01-02 15:04:05".
+
+Returns:
+  - The formatted string.
+  - An error if s has no date time.
+
+NormalizeDateTime 与 [Parser.ParseDateTime] 一样解析日期时间字符串，再使用 layout 格式化，使同一时间的不同写法变为同一字符串。
+
+参数:
+  - s: 待解析的字符串。丢弃前缀及后缀。
+  - layout: time.Format 的格式，例如 "2006-01-02 15:04:05"。
+
+返回:
+  - 格式化后的字符串。
+  - 错误信息。s 中没有日期时间时返回错误。
+*/
+func (p *Parser) NormalizeDateTime(s string, layout string) (string, error) {
+	t, err := p.ParseDateTime(s)
+	if err != nil {
+		return "", err
+	}
+	return t.Format(layout), nil
+}
+
+/*
+NormalizeFileName replaces the date time in the file name with it formatted by dateTimeLayout,
+keeping the prefix, the suffix and the directory, so file names across a tree can use the same spelling.
+Only the last element of the path is rewritten. A time zone after the time, such as " PDT", is kept.
+
+Parameters:
+  - name: The file name or path.
+  - dateTimeLayout: The layout of time.Format for a date time, such as "20060102_150405".
+  - dateLayout: The layout of time.Format for a name with only a date, such as "20060102".
+    Empty means such a name is not rewritten and an error is returned.
+
+Returns:
+  - The rewritten name.
+  - An error if the name has no date time, or no date when dateLayout is not empty.
+
+Example:
+
+	parser, _ := NewParser(nil)
+	name, err := parser.NormalizeFileName("photos/IMG_2023-1-5 14.30.00.jpg", "20060102_150405", "20060102")
+	// photos/IMG_20230105_143000.jpg
+	name, err = parser.NormalizeFileName("scan 2023年1月5日.pdf", "20060102_150405", "20060102")
+	// scan 20230105.pdf
+
+NormalizeFileName 将文件名中的日期时间替换为使用 dateTimeLayout 格式化的结果，保留前缀、后缀及目录，
+使整个目录树中的文件名使用相同的写法。只改写路径的最后一部分。保留时间之后的时区，例如“ PDT”。
+
+参数:
+  - name: 文件名或路径。
+  - dateTimeLayout: 日期时间的 time.Format 格式，例如 "20060102_150405"。
+  - dateLayout: 只有日期的文件名使用的 time.Format 格式，例如 "20060102"。为空时不改写这样的文件名，并返回错误。
+
+返回:
+  - 改写后的文件名。
+  - 错误信息。文件名中没有日期时间，或者 dateLayout 不为空时也没有日期，则返回错误。
+*/
+func (p *Parser) NormalizeFileName(name string, dateTimeLayout string, dateLayout string) (string, error) {
+	// 目录中也可能有日期，所以只处理最后一部分。
+	dir, base := "", name
+	if i := strings.LastIndexAny(name, `/\`); i >= 0 {
+		dir, base = name[:i+1], name[i+1:]
+	}
+
+	// 保留时区，格式化的时间仍然是该时区的时间。
+	t, matched, zoneLength, err := p.findDateTime(base)
+	layout := dateTimeLayout
+	if err != nil && dateLayout != "" {
+		var dateErr error
+		if t, matched, dateErr = p.FindDate(base); dateErr == nil {
+			zoneLength = 0
+			err = nil
+			layout = dateLayout
+		}
+	}
+	if err != nil {
+		return name, err
+	}
+
+	zone := matched[len(matched)-zoneLength:]
+	return dir + strings.Replace(base, matched, t.Format(layout)+zone, 1), nil
+}
+
+/*
+NormalizeDateTime is like [Parser.NormalizeDateTime], using the default parser of [ParseDateTime].
+
+NormalizeDateTime 与 [Parser.NormalizeDateTime] 相同，但使用 [ParseDateTime] 的默认解析器。
+*/
+func NormalizeDateTime(s string, layout string) (string, error) {
+	return defaultParser(time.Local).NormalizeDateTime(s, layout)
+}
+
+/*
+NormalizeFileName is like [Parser.NormalizeFileName], using the default parser of [ParseDateTime].
+
+NormalizeFileName 与 [Parser.NormalizeFileName] 相同，但使用 [ParseDateTime] 的默认解析器。
+*/
+func NormalizeFileName(name string, dateTimeLayout string, dateLayout string) (string, error) {
+	return defaultParser(time.Local).NormalizeFileName(name, dateTimeLayout, dateLayout)
+}
@@ -0,0 +1,61 @@
+package timeutils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeDateTime(t *testing.T) {
+	const layout = "2006-01-02 15:04:05"
+
+	for _, s := range []string{
+		"IMG_20230105_143000.jpg",
+		"2023-1-5 14.30.00",
+		"2023.01.05T14-30-00",
+		"照片2023年1月5日 14时30分00秒.jpg",
+	} {
+		result, err := NormalizeDateTime(s, layout)
+		assert.Nil(t, err, s)
+		assert.Equal(t, "2023-01-05 14:30:00", result, s)
+	}
+
+	_, err := NormalizeDateTime("2023-01-05", layout)
+	assert.NotNil(t, err)
+}
+
+func TestNormalizeFileName(t *testing.T) {
+	const dateTimeLayout = "20060102_150405"
+	const dateLayout = "20060102"
+
+	for _, v := range []struct {
+		name     string
+		expected string
+	}{
+		{"IMG_2023-1-5 14.30.00.jpg", "IMG_20230105_143000.jpg"},
+		{"photos/2022-12-31/IMG_2023-1-5 14.30.00.jpg", "photos/2022-12-31/IMG_20230105_143000.jpg"},
+		{`C:\photos\VID_20230105143000123.mp4`, `C:\photos\VID_20230105_143000.mp4`},
+		{"scan 2023年1月5日.pdf", "scan 20230105.pdf"},
+		{"report-2023.01.05-final.doc", "report-20230105-final.doc"},
+		// 时间之后的数字及未知的缩写不是时区，保留时区。
+		{"IMG_20230105_143000-0001.jpg", "IMG_20230105_143000-0001.jpg"},
+		{"IMG_20230105_143000-0002.jpg", "IMG_20230105_143000-0002.jpg"},
+		{"DSC_20230105_1430_HDR.jpg", "DSC_20230105_143000_HDR.jpg"},
+		{"report 2023-01-05 14-30-00 PDT.txt", "report 20230105_143000 PDT.txt"},
+		{"IMG_2023-01-05T14:30:00+08:00.jpg", "IMG_20230105_143000+08:00.jpg"},
+	} {
+		result, err := NormalizeFileName(v.name, dateTimeLayout, dateLayout)
+		assert.Nil(t, err, v.name)
+		assert.Equal(t, v.expected, result, v.name)
+	}
+
+	// 不改写只有日期的文件名。
+	result, err := NormalizeFileName("scan 2023年1月5日.pdf", dateTimeLayout, "")
+	assert.NotNil(t, err)
+	assert.Equal(t, "scan 2023年1月5日.pdf", result)
+
+	// 没有日期。
+	result, err = NormalizeFileName("photos/2022-12-31/readme.txt", dateTimeLayout, dateLayout)
+	assert.NotNil(t, err)
+	assert.Equal(t, "photos/2022-12-31/readme.txt", result)
+}
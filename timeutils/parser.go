@@ -0,0 +1,764 @@
+package timeutils
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+ParserOption defines the options for [Parser]. See [NewParserOption] for default settings.
+
+ParserOption 定义了 [Parser] 的选项。默认设置参见 [NewParserOption]。
+*/
+type ParserOption struct {
+	Strict             bool           // require the date time fields to be within valid ranges. See [RequireDateTimeFieldValid]
+	Location           *time.Location // the location used when the string has no time zone. nil means time.Local
+	DateSeparators     string         // the characters allowed between year, month and day. Empty means only no separator format is parsed
+	TimeSeparators     string         // the characters allowed between hour, minute and second. Empty means only no separator format is parsed
+	DateTimeSeparators string         // the characters allowed between date and time. Empty means date and time are adjacent
+	TwoDigitYear       bool           // also parse two-digit years like "23-01-05" or "230105", tried only if four-digit years fail
+	PivotYear          int            // two-digit years are in [PivotYear, PivotYear+99]. 0 means 1970
+	ExcelSerial        bool           // also parse the whole string as an Excel serial date like "45000.75". See [FromExcelSerial]
+	DotNetTicks        bool           // also parse the whole string as .NET ticks. See [FromDotNetTicks]
+	FileTime           bool           // also parse the whole string as Windows FILETIME. See [FromFileTime]
+	Zones              *ZoneResolver  // resolves zone abbreviations like "CST" after the time. nil means abbreviations are ignored
+	Match              MatchMode      // whether the date time can have a prefix and a suffix. See [MatchMode]
+}
+
+/*
+MatchMode defines how much of the string must be the date time.
+
+MatchMode 定义字符串中多少部分必须是日期时间。
+*/
+type MatchMode int
+
+const (
+	MatchAnywhere MatchMode = iota // lenient, the date time can have a prefix and a suffix, such as in a file name
+	MatchWhole                     // strict, the whole string must be the date time. Leading and trailing spaces are ignored
+)
+
+/*
+NewParserOption creates a new ParserOption with strict field check, time.Local,
+date separators "-_./", time separators "-_.:", date time separators "-_. T",
+two-digit years disabled with pivot year 1970, Excel serial dates, .NET ticks and FILETIME disabled,
+zone abbreviations resolved by the default table, see [RegisterZone], and prefixes and suffixes allowed.
+
+NewParserOption 创建默认的 ParserOption。包含严格检查字段范围、time.Local 时区、
+日期分隔符 "-_./"、时间分隔符 "-_.:"、日期与时间之间的分隔符 "-_. T"，
+不解析两位年份、两位年份的基准年为 1970，不解析 Excel 序号、.NET ticks 及 FILETIME，
+使用默认的对照表解析时区缩写，参见 [RegisterZone]，以及允许前缀及后缀。
+*/
+func NewParserOption() *ParserOption {
+	return &ParserOption{
+		Strict:             true,
+		Location:           nil,
+		DateSeparators:     "-_./",
+		TimeSeparators:     "-_.:",
+		DateTimeSeparators: "-_. T",
+		TwoDigitYear:       false,
+		PivotYear:          1970,
+		ExcelSerial:        false,
+		DotNetTicks:        false,
+		FileTime:           false,
+		Zones:              defaultZones,
+		Match:              MatchAnywhere,
+	}
+}
+
+/*
+Parser parses date time strings with its own options, instead of the global [RequireDateTimeFieldValid].
+Its options are immutable after created, and custom formats can be registered at any time. It is safe for concurrent use.
+Create it once and reuse it, such as while walking millions of file names. Its patterns are compiled once,
+and they are only tried at positions where a date time can start, so strings without such digits are rejected quickly.
+
+Parser 使用自己的选项解析日期时间字符串，而不是全局的 [RequireDateTimeFieldValid]。
+创建后选项不可修改，可以随时注册自定义格式。可以并发使用。
+创建一次并重复使用，例如在遍历数百万个文件名时。其格式只编译一次，且只在日期时间可能开始的位置尝试，
+所以没有这样的数字的字符串很快就被拒绝。
+*/
+type Parser struct {
+	option              ParserOption
+	custom              *customFormats // 注册的自定义格式。
+	regexDateTimeHasSep *scanPattern   // 没有分隔符时为 nil。
+	regexDateTimeNoSep  *scanPattern
+	regexDateHasSep     *scanPattern // 没有分隔符时为 nil。
+	regexDateNoSep      *scanPattern
+	regexTimeHasSep     *scanPattern // 没有分隔符时为 nil。
+	regexTimeNoSep      *scanPattern
+
+	// 两位年份，不解析两位年份时都为 nil。
+	regexShortDateTimeHasSep *scanPattern // 没有分隔符时也为 nil。
+	regexShortDateTimeNoSep  *scanPattern
+	regexShortDateHasSep     *scanPattern // 没有分隔符时也为 nil。
+	regexShortDateNoSep      *scanPattern
+}
+
+/*
+NewParser creates a parser with the given options.
+
+Parameters:
+  - option: the options. if nil, the default options will be used.
+
+Returns:
+  - the parser.
+  - an error if any separator is a digit, or the pivot year is negative.
+
+NewParser 使用给定的选项创建解析器。
+
+参数:
+  - option: 选项。如果为 nil 则使用默认选项。
+
+返回:
+  - 解析器。
+  - 错误信息。分隔符为数字或基准年为负数时返回错误。
+*/
+func NewParser(option *ParserOption) (*Parser, error) {
+	if option == nil { // 保证 option 不为 nil。
+		option = NewParserOption()
+	}
+
+	for _, separators := range []string{option.DateSeparators, option.TimeSeparators, option.DateTimeSeparators} {
+		if strings.ContainsAny(separators, "0123456789") {
+			return nil, fmt.Errorf("separators must not contain digits: %q", separators)
+		}
+	}
+	if option.PivotYear < 0 {
+		return nil, fmt.Errorf("pivot year must not be negative: %d", option.PivotYear)
+	}
+
+	p := &Parser{option: *option, custom: &customFormats{}}
+	if p.option.Location == nil {
+		p.option.Location = time.Local
+	}
+	if p.option.PivotYear == 0 {
+		p.option.PivotYear = 1970
+	}
+
+	dateSep := separatorClass(option.DateSeparators)
+	timeSep := separatorClass(option.TimeSeparators)
+	dateTimeSep := separatorClass(option.DateTimeSeparators)
+
+	// 无分隔符的日期时间：
+	//  1. 可以有字符前缀及后缀。
+	//  2. 日期数字之间无分隔符，需要至少 8 位数字表示 YYYYMMDD。
+	//  3. 日期与时间之间可以有分隔符，也可以无分隔符。
+	//  4. 时间数字之间无分隔符，可以是 4 位数字，6 位数字，或者 9 位数字。
+	//     分别表示 HHMM，HHMMSS 及 HHMMSSSS。也就是说，可以精确到分钟、秒或毫秒。
+	//  5. 毫秒数为 3 位，与秒数之间可以有“.”作为分隔符，也可以无分隔符。
+	//  6. 时间之后可以紧跟时区，参见 regexZone。
+	//
+	// 前缀由 scanPattern 处理，所以正则表达式都从年份或小时开始。
+	//
+	// note: 最后的 (\.?(\d{3}))? 不要外圈这括号也行，但加上后解析结果数组与有分隔符的一致。
+	p.regexDateTimeNoSep = newScanPattern(
+		`(\d{4})(\d{2})(\d{2})`+optional(dateTimeSep)+
+			`(\d{2})(\d{2})((\d{2})(\.?(\d{3}))?)?`+regexZone, 8, 0, false, "")
+
+	// 无分隔符的日期：
+	//  1. 可以有字符前缀及后缀。
+	//  2. 日期数字之间无分隔符，需要至少 8 位数字表示 YYYYMMDD。
+	p.regexDateNoSep = newScanPattern(`(\d{4})(\d{2})(\d{2})`, 8, 0, false, "")
+
+	// 无分隔符的时间，与日期时间中的时间部分相同。
+	p.regexTimeNoSep = newScanPattern(`(\d{2})(\d{2})((\d{2})(\.?(\d{3}))?)?`, 4, 0, false, "")
+
+	// 有分隔符的日期时间：
+	//  1. 可以有字符前缀及后缀。
+	//  2. 日期数字之间有分隔符，年 4 位，月 1 或 2 位，日 1 或 2 位。
+	//  3. 日期与时间之间必须有分隔符，除非没有给出日期与时间之间的分隔符。
+	//  4. 时间数字之间有分隔符。秒与毫秒之间只能是“.”。
+	//     小时 1 或 2 位，分钟和秒都是 2 位，毫秒是 3 位。可以精确到分钟、秒或毫秒。
+	//  5. 时间之后可以紧跟时区，参见 regexZone。
+	if dateSep != "" && timeSep != "" {
+		p.regexDateTimeHasSep = newScanPattern(
+			`(\d{4})`+dateSep+`(\d{1,2})`+dateSep+`(\d{1,2})`+dateTimeSep+
+				`(\d{1,2})`+timeSep+`(\d{2})(`+timeSep+`(\d{2})(\.(\d{3}))?)?`+regexZone, 4, 4, false, "")
+	}
+	if dateSep != "" {
+		p.regexDateHasSep = newScanPattern(`(\d{4})`+dateSep+`(\d{1,2})`+dateSep+`(\d{1,2})`, 4, 4, false, "")
+	}
+	if timeSep != "" {
+		p.regexTimeHasSep = newScanPattern(`(\d{1,2})`+timeSep+`(\d{2})(`+timeSep+`(\d{2})(\.(\d{3}))?)?`, 1, 2, false, "")
+	}
+
+	// 两位年份的日期时间及日期，分组与四位年份的相同：
+	//  1. 年份之前不能紧跟数字，避免从更长的数字中间开始匹配。由 scanPattern 检查。
+	//  2. 只有日期时，日期之后也不能紧跟数字。
+	//  3. 与四位年份有歧义时，例如 "230105143000"，按四位年份解析。
+	if p.option.TwoDigitYear {
+		p.regexShortDateTimeNoSep = newScanPattern(
+			`(\d{2})(\d{2})(\d{2})`+optional(dateTimeSep)+
+				`(\d{2})(\d{2})((\d{2})(\.?(\d{3}))?)?`+regexZone, 6, 0, true, "")
+		p.regexShortDateNoSep = newScanPattern(`(\d{2})(\d{2})(\d{2})(?:\D.*)?$`, 6, 6, true, "")
+
+		if dateSep != "" && timeSep != "" {
+			p.regexShortDateTimeHasSep = newScanPattern(
+				`(\d{2})`+dateSep+`(\d{1,2})`+dateSep+`(\d{1,2})`+dateTimeSep+
+					`(\d{1,2})`+timeSep+`(\d{2})(`+timeSep+`(\d{2})(\.(\d{3}))?)?`+regexZone, 2, 2, true, "")
+		}
+		if dateSep != "" {
+			p.regexShortDateHasSep = newScanPattern(
+				`(\d{2})`+dateSep+`(\d{1,2})`+dateSep+`(\d{1,2})(?:\D.*)?$`, 2, 2, true, "")
+		}
+	}
+
+	return p, nil
+}
+
+// separatorClass 将分隔符转换为正则表达式的字符类。没有分隔符时返回空字符串。
+func separatorClass(separators string) string {
+	if separators == "" {
+		return ""
+	}
+
+	builder := strings.Builder{}
+	builder.WriteByte('[')
+	for _, c := range separators {
+		if c == '-' || c == '^' || c == ']' || c == '\\' {
+			builder.WriteByte('\\')
+		}
+		builder.WriteRune(c)
+	}
+	builder.WriteByte(']')
+	return builder.String()
+}
+
+// optional 使正则表达式的片段可选。
+func optional(class string) string {
+	if class == "" {
+		return ""
+	}
+	return class + "?"
+}
+
+// regexZone 是紧跟在时间之后的时区，追加在日期时间正则表达式的最后，不影响之前各分组的序号：
+//  1. “Z”表示 UTC。其后不能紧跟字母，避免将“Zoo”之类的后缀当作时区。
+//  2. 或者“+”或“-”开始的偏移量，小时和分钟都是 2 位，之间可以有“:”。
+//  3. 或者“CST”这样 2 至 5 个大写字母的缩写，之前可以有一个空格或“_”，之后不能紧跟字母。由 ParserOption.Zones 解析。
+//
+// 匹配的只是候选的时区，是否接受由 Parser.zone 决定。
+const regexZone = `(?:(Z)(?:[^A-Za-z]|$)|([-+]\d{2}):?(\d{2})|[ _]?([A-Z]{2,5})(?:[^A-Za-z]|$))?`
+
+// regexChineseDate 是中文日期，例如“2023年1月5日”或“2023年01月05日”：
+//  1. 可以有字符前缀及后缀。
+//  2. 年 4 位，月 1 或 2 位，日 1 或 2 位，“日”也可以是“号”。
+//
+// 不受分隔符选项的影响，分组与其它日期的相同。最后的“日”也是分组，以便作为被解析的子串的一部分。
+var regexChineseDate = newScanPattern(`(\d{4})年(\d{1,2})月(\d{1,2})([日号])`, 4, 4, false, "年")
+
+// regexChineseDateTime 是中文日期时间，例如“2023年1月5日 14时30分”或“2023年01月05日14点30分15秒”：
+//  1. 日期与 regexChineseDate 相同，与时间之间可以有一个非数字的分隔符。
+//  2. 小时 1 或 2 位，之后是“时”或“点”，也可以是“:”。分钟及秒 1 或 2 位，之后的“分”或“秒”可以省略。
+//  3. 秒之后可以有 3 位的毫秒，与秒之间是“.”。
+//
+// 不受分隔符选项的影响，分组与其它日期时间的相同，中文时间之后一般没有时区，但与其它日期时间一样识别。
+// 没有秒时，分钟之后的“分”由秒所在的分组匹配，以便作为被解析的子串的一部分。
+var regexChineseDateTime = newScanPattern(
+	`(\d{4})年(\d{1,2})月(\d{1,2})[日号]\D?`+
+		`(\d{1,2})[时点:](\d{1,2})(分?:?(\d{1,2})(\.(\d{3}))?秒?|分)?`+regexZone, 4, 4, false, "年")
+
+/*
+Option returns a copy of the options of the parser.
+
+Option 返回解析器选项的副本。
+*/
+func (p *Parser) Option() ParserOption {
+	return p.option
+}
+
+/*
+ParseDateTime parses the date time string like [ParseDateTimeInLocationE], using the options of the parser.
+
+ParseDateTime 与 [ParseDateTimeInLocationE] 一样解析日期时间字符串，但使用解析器的选项。
+*/
+func (p *Parser) ParseDateTime(s string) (time.Time, error) {
+	result, _, err := p.FindDateTime(s)
+	return result, err
+}
+
+/*
+FindDateTime is like [Parser.ParseDateTime], but also returns the substring that was parsed,
+so callers can see what was consumed and what is the prefix or suffix.
+
+Example:
+
+	parser, _ := NewParser(nil)
+	tm, matched, err := parser.FindDateTime("IMG_20230105_143000.jpg") // 2023-01-05 14:30:00, "20230105_143000"
+
+FindDateTime 与 [Parser.ParseDateTime] 相同，但同时返回被解析的子串，以便调用者知道哪些字符被使用，哪些是前缀或后缀。
+*/
+func (p *Parser) FindDateTime(s string) (time.Time, string, error) {
+	result, matched, _, err := p.findDateTime(s)
+	return result, matched, err
+}
+
+// findDateTime 与 FindDateTime 相同，同时返回被解析的子串最后的时区的长度，包括时区之前的空格或“_”。
+func (p *Parser) findDateTime(s string) (time.Time, string, int, error) {
+	text := p.matchText(s)
+	if t, matched, ok := p.custom.parse(text, p.option.Location, BeforeBuiltins, p.option.Match); ok {
+		return t, matched, 0, nil
+	}
+
+	// 先尝试完整的 ISO 8601 字符串，它可以有任意精度的小数秒，内置的正则表达式只能解析 3 位毫秒。
+	if p.allowsISO8601(text) {
+		if t, hasTime, err := parseISO8601(text, p.option.Location); err == nil && hasTime {
+			return t, text, 0, nil
+		}
+	}
+
+	// 启用的数字格式需要整个字符串都是数字，先于内置格式尝试，避免被当作无分隔符的日期时间。
+	if t, ok := p.parseEpoch(text); ok {
+		return t, strings.TrimSpace(text), 0, nil
+	}
+
+	matched, zoneLength := "", 0
+	parse := func(s string, pattern *scanPattern) (time.Time, error) {
+		subs, index := p.find(pattern, s)
+		if len(subs) == 0 {
+			// 没有配置的日期时间字符串，所以数组长度为 0，说明转换不成功。
+			return time.Time{}, errNoMatch
+		}
+
+		// subs[10] 至 subs[13] 为时区。
+		zone, groups := p.zone(s, subs, index)
+		found, ok := p.substring(s, index, groups)
+		if !ok {
+			return time.Time{}, errNoMatch
+		}
+
+		year := p.fullYear(subs[1])
+		m, _ := strconv.Atoi(subs[2])
+		month := time.Month(m)
+		day, _ := strconv.Atoi(subs[3])
+		hour, _ := strconv.Atoi(subs[4])
+		minute, _ := strconv.Atoi(subs[5])
+		// subs[6] 包含了秒和毫秒。
+		second, _ := strconv.Atoi(subs[7])
+
+		if err := p.checkFields(year, m, day, hour, minute, second); err != nil {
+			return time.Time{}, err
+		}
+
+		// subs[8] 包含了"."和毫秒。
+		millisecond, _ := strconv.Atoi(subs[9])
+		nanosecond := millisecond * 1000_000
+
+		matched = found
+		zoneLength = groupsEnd(index, groups) - groupsEnd(index, dateTimeGroups)
+		return time.Date(year, month, day, hour, minute, second, nanosecond, zone), nil
+	}
+
+	result, err := parseEither(text, p.regexDateTimeHasSep, p.regexDateTimeNoSep, parse)
+	if err != nil {
+		result, err = parseFallback(text, result, err, nil, regexChineseDateTime, parse)
+	}
+	if err != nil && p.option.TwoDigitYear {
+		result, err = parseFallback(text, result, err, p.regexShortDateTimeHasSep, p.regexShortDateTimeNoSep, parse)
+	}
+	if err != nil {
+		if t, matched, ok := p.custom.parse(text, p.option.Location, AfterBuiltins, p.option.Match); ok {
+			return t, matched, 0, nil
+		}
+	}
+
+	if err == errNoMatch {
+		// 能解析出日期，说明缺少的是时间部分。
+		if _, dateErr := p.ParseDate(s); dateErr == nil {
+			return result, "", 0, newParseError(s, "no time component")
+		}
+		return result, "", 0, newParseError(s, "no date time found")
+	} else if err != nil {
+		return result, "", 0, newParseError(s, err.Error())
+	}
+	return result, matched, zoneLength, nil
+}
+
+/*
+ParseDate parses the date string like [ParseDateE], using the options of the parser.
+
+ParseDate 与 [ParseDateE] 一样解析日期字符串，但使用解析器的选项。
+*/
+func (p *Parser) ParseDate(s string) (time.Time, error) {
+	result, _, err := p.FindDate(s)
+	return result, err
+}
+
+/*
+FindDate is like [Parser.ParseDate], but also returns the substring that was parsed.
+
+FindDate 与 [Parser.ParseDate] 相同，但同时返回被解析的子串。
+*/
+func (p *Parser) FindDate(s string) (time.Time, string, error) {
+	text := p.matchText(s)
+	if t, matched, ok := p.custom.parse(text, p.option.Location, BeforeBuiltins, p.option.Match); ok {
+		return StartOfDay(t), matched, nil
+	}
+
+	// 周日期及序数日期只能由 ISO 8601 解析。只使用日期部分，与内置格式一样忽略之后的时间及时区。
+	datePart, _, _ := strings.Cut(text, "T")
+	if p.allowsISO8601(datePart) && (p.option.Match == MatchAnywhere || datePart == text) {
+		if t, err := parseISODate(text, datePart, p.option.Location); err == nil {
+			return t, datePart, nil
+		}
+	}
+
+	if t, ok := p.parseEpoch(text); ok {
+		return StartOfDay(t), strings.TrimSpace(text), nil
+	}
+
+	matched := ""
+	parse := func(s string, pattern *scanPattern) (time.Time, error) {
+		subs, index := p.find(pattern, s)
+		if len(subs) == 0 {
+			// 没有配置的日期字符串，所以数组长度为 0，说明转换不成功。
+			return time.Time{}, errNoMatch
+		}
+		found, ok := p.substring(s, index, len(subs))
+		if !ok {
+			return time.Time{}, errNoMatch
+		}
+
+		year := p.fullYear(subs[1])
+		m, _ := strconv.Atoi(subs[2])
+		month := time.Month(m)
+		day, _ := strconv.Atoi(subs[3])
+
+		if err := p.checkFields(year, m, day, 0, 0, 0); err != nil {
+			return time.Time{}, err
+		}
+
+		matched = found
+		return time.Date(year, month, day, 0, 0, 0, 0, p.option.Location), nil
+	}
+
+	result, err := parseEither(text, p.regexDateHasSep, p.regexDateNoSep, parse)
+	if err != nil {
+		result, err = parseFallback(text, result, err, nil, regexChineseDate, parse)
+	}
+	if err != nil && p.option.TwoDigitYear {
+		result, err = parseFallback(text, result, err, p.regexShortDateHasSep, p.regexShortDateNoSep, parse)
+	}
+	if err != nil {
+		if t, matched, ok := p.custom.parse(text, p.option.Location, AfterBuiltins, p.option.Match); ok {
+			return StartOfDay(t), matched, nil
+		}
+	}
+
+	if err == errNoMatch {
+		return result, "", newParseError(s, "no date found")
+	} else if err != nil {
+		return result, "", newParseError(s, err.Error())
+	}
+	return result, matched, nil
+}
+
+/*
+ParseTime parses the time string like [ParseTimeE], using the options of the parser.
+
+ParseTime 与 [ParseTimeE] 一样解析时间字符串，但使用解析器的选项。
+*/
+func (p *Parser) ParseTime(s string) (time.Time, error) {
+	result, _, err := p.FindTime(s)
+	return result, err
+}
+
+/*
+FindTime is like [Parser.ParseTime], but also returns the substring that was parsed.
+
+FindTime 与 [Parser.ParseTime] 相同，但同时返回被解析的子串。
+*/
+func (p *Parser) FindTime(s string) (time.Time, string, error) {
+	matched := ""
+	parse := func(s string, pattern *scanPattern) (time.Time, error) {
+		subs, index := p.find(pattern, s)
+		if len(subs) == 0 {
+			// 没有配置的时间字符串，所以数组长度为 0，说明转换不成功。
+			return time.Time{}, errNoMatch
+		}
+		found, ok := p.substring(s, index, len(subs))
+		if !ok {
+			return time.Time{}, errNoMatch
+		}
+
+		hour, _ := strconv.Atoi(subs[1])
+		minute, _ := strconv.Atoi(subs[2])
+		second, _ := strconv.Atoi(subs[4])
+		millisecond, _ := strconv.Atoi(subs[6])
+		nanosecond := millisecond * 1000_000
+
+		if err := p.checkFields(0, 1, 1, hour, minute, second); err != nil {
+			return time.Time{}, err
+		}
+
+		// time.Parse() 只解析时间时，使用的日期就是 0，1，1。
+		matched = found
+		return time.Date(0, 1, 1, hour, minute, second, nanosecond, p.option.Location), nil
+	}
+
+	result, err := parseEither(p.matchText(s), p.regexTimeHasSep, p.regexTimeNoSep, parse)
+	if err == errNoMatch {
+		return result, "", newParseError(s, "no time found")
+	} else if err != nil {
+		return result, "", newParseError(s, err.Error())
+	}
+	return result, matched, nil
+}
+
+// matchText 返回要解析的字符串。MatchWhole 时忽略首尾的空格。
+func (p *Parser) matchText(s string) string {
+	if p.option.Match == MatchWhole {
+		return strings.TrimSpace(s)
+	}
+	return s
+}
+
+// find 使用内置格式匹配，返回各分组及其在 s 中的位置。没有匹配时返回 nil。
+func (p *Parser) find(pattern *scanPattern, s string) ([]string, []int) {
+	index := pattern.index(s)
+	if index == nil {
+		return nil, nil
+	}
+
+	subs := make([]string, len(index)/2)
+	for i := range subs {
+		if index[2*i] >= 0 {
+			subs[i] = s[index[2*i]:index[2*i+1]]
+		}
+	}
+	return subs, index
+}
+
+// substring 返回被解析的子串。子串从第 1 个分组开始，到前 groups 个分组中最后结束的为止，
+// 不包括正则表达式中后缀及边界检查所匹配的字符。MatchWhole 时子串必须是整个字符串，否则返回 false。
+func (p *Parser) substring(s string, index []int, groups int) (string, bool) {
+	start, end := index[2], groupsEnd(index, groups)
+	if p.option.Match == MatchWhole && (start != 0 || end != len(s)) {
+		return "", false
+	}
+	return s[start:end], true
+}
+
+// groupsEnd 返回前 groups 个分组中最后结束的位置，不包括第 0 个分组，即整个匹配。
+func groupsEnd(index []int, groups int) int {
+	end := index[3]
+	for i := 1; i < groups; i++ {
+		if index[2*i+1] > end {
+			end = index[2*i+1]
+		}
+	}
+	return end
+}
+
+// fullYear 将年份转换为整数，两位年份按基准年转换为 [PivotYear, PivotYear+99] 之间的年份。
+func (p *Parser) fullYear(year string) int {
+	y, _ := strconv.Atoi(year)
+	if len(year) != 2 {
+		return y
+	}
+
+	y += p.option.PivotYear - p.option.PivotYear%100
+	if y < p.option.PivotYear {
+		y += 100
+	}
+	return y
+}
+
+// allowsISO8601 判断是否尝试 ISO 8601。字符串必须以 4 位年份开始，且其中的分隔符都在解析器的选项中，
+// 即扩展格式的“-”及“:”分别在日期及时间分隔符中，“T”在日期与时间之间的分隔符中。
+// 基本格式的序数日期，例如“2023123”，与缺少数字的日期无法区分，不尝试，只能由 ParseISO8601 解析。
+func (p *Parser) allowsISO8601(s string) bool {
+	if len(s) < 4 || !isDigit(s[0]) || !isDigit(s[1]) || !isDigit(s[2]) || !isDigit(s[3]) {
+		return false
+	}
+
+	datePart, timePart, hasTime := strings.Cut(s, "T")
+	if len(datePart) == 7 && strings.Trim(datePart, "0123456789") == "" {
+		return false
+	}
+	if strings.Contains(datePart, "-") && !strings.Contains(p.option.DateSeparators, "-") {
+		return false
+	}
+	if hasTime && !strings.Contains(p.option.DateTimeSeparators, "T") {
+		return false
+	}
+	if len(timePart) > 2 && timePart[2] == ':' && !strings.Contains(p.option.TimeSeparators, ":") {
+		return false
+	}
+	return true
+}
+
+// checkFields 在严格模式下检查各字段的范围。
+func (p *Parser) checkFields(year, month, day, hour, minute, second int) error {
+	if !p.option.Strict {
+		return nil
+	}
+	return validateDateTimeFields(year, month, day, hour, minute, second)
+}
+
+// dateTimeGroups 及 zoneGroups 分别是日期时间正则表达式中不包括及包括 regexZone 的分组数量。
+const (
+	dateTimeGroups = 10
+	zoneGroups     = dateTimeGroups + 4
+)
+
+// zone 根据 regexZone 的 4 个分组返回时区，以及被解析的子串包括的分组数量。
+// 文件名中时间之后的数字或字母通常不是时区，所以只接受以下时区，其它的不属于被解析的子串，并使用选项中的时区：
+//  1. 偏移量或“Z”之后是字符串的结尾，且日期时间从字符串开头开始，即整个字符串是带时区的日期时间。
+//  2. 或者时间使用 ISO 8601 的“T”或“:”分隔，且偏移量是“+08:00”这样带“:”的形式，或者是“Z”。
+//  3. 缩写在 ParserOption.Zones 中。
+func (p *Parser) zone(s string, subs []string, index []int) (*time.Location, int) {
+	if abbr := subs[dateTimeGroups+3]; abbr != "" {
+		if p.option.Zones != nil {
+			if loc := p.option.Zones.lookup(abbr); loc != nil {
+				return loc, zoneGroups
+			}
+		}
+		return p.option.Location, dateTimeGroups
+	}
+
+	z, hours, minutes := subs[dateTimeGroups], subs[dateTimeGroups+1], subs[dateTimeGroups+2]
+	loc := parseZone(z, hours, minutes, nil)
+	if loc == nil {
+		return p.option.Location, dateTimeGroups
+	}
+
+	start, end := index[2], groupsEnd(index, dateTimeGroups)
+	whole := start == 0 && groupsEnd(index, zoneGroups) == len(s)
+	iso := strings.ContainsAny(s[start:end], "T:")
+	// 小时与分钟之间有“:”时，分钟的分组不紧跟在小时之后。
+	colon := z != "" || index[2*(dateTimeGroups+2)] > index[2*(dateTimeGroups+1)+1]
+	if whole || (iso && colon) {
+		return loc, zoneGroups
+	}
+	return p.option.Location, dateTimeGroups
+}
+
+// parseZone 根据 regexZone 的匹配结果返回时区。没有时区或者偏移量超出范围时返回 loc。
+func parseZone(z string, hours string, minutes string, loc *time.Location) *time.Location {
+	if z != "" {
+		return time.UTC
+	} else if hours == "" {
+		return loc
+	}
+
+	h, _ := strconv.Atoi(hours)
+	m, _ := strconv.Atoi(minutes)
+	if h < -14 || h > 14 || m > 59 {
+		return loc
+	}
+
+	offset := h*3600 + m*60
+	if hours[0] == '-' {
+		offset = h*3600 - m*60
+	}
+	if offset == 0 {
+		return time.UTC
+	}
+	return time.FixedZone("", offset)
+}
+
+/*
+ParseError is returned by the E variants of parsing functions, such as [ParseDateTimeE], and by [Parser].
+
+ParseError 由 [ParseDateTimeE] 等解析函数的 E 版本及 [Parser] 返回。
+*/
+type ParseError struct {
+	Input  string // The string to parse.
+	Reason string // Why parsing failed, such as "month 13 out of range" or "no time component".
+}
+
+func newParseError(input string, reason string) *ParseError {
+	return &ParseError{Input: input, Reason: reason}
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("cannot parse %q: %s", e.Input, e.Reason)
+}
+
+// errNoMatch 表示正则表达式没有匹配，仅在内部使用。
+var errNoMatch = errors.New("no match")
+
+// parseEither 先使用有分隔符的正则表达式解析，失败后再使用无分隔符的。hasSep 为 nil 时只使用无分隔符的。
+// 两者都失败时，优先返回字段超出范围的错误，因为它比没有匹配更能说明原因。
+func parseEither(
+	s string,
+	hasSep *scanPattern,
+	noSep *scanPattern,
+	parse func(s string, pattern *scanPattern) (time.Time, error),
+) (time.Time, error) {
+	err := errNoMatch
+	if hasSep != nil {
+		result, hasSepErr := parse(s, hasSep)
+		if hasSepErr == nil {
+			return result, nil
+		}
+		err = hasSepErr
+	}
+
+	result, noSepErr := parse(s, noSep)
+	if noSepErr == nil {
+		return result, nil
+	} else if err == errNoMatch {
+		err = noSepErr
+	}
+	return result, err
+}
+
+// parseFallback 在之前的格式解析失败后，使用其它格式的正则表达式再解析，例如两位年份或中文日期。
+// 两者都失败时，与 parseEither 一样优先返回字段超出范围的错误。
+func parseFallback(
+	s string,
+	result time.Time,
+	err error,
+	hasSep *scanPattern,
+	noSep *scanPattern,
+	parse func(s string, pattern *scanPattern) (time.Time, error),
+) (time.Time, error) {
+	shortResult, shortErr := parseEither(s, hasSep, noSep, parse)
+	if shortErr == nil {
+		return shortResult, nil
+	} else if err == errNoMatch {
+		return shortResult, shortErr
+	}
+	return result, err
+}
+
+// 默认的解析器，分别对应 RequireDateTimeFieldValid 为 true 及 false 的情况。两者共用自定义格式。
+var (
+	strictParser  = mustNewParser(true, nil)
+	lenientParser = mustNewParser(false, strictParser.custom)
+)
+
+func mustNewParser(strict bool, custom *customFormats) *Parser {
+	option := NewParserOption()
+	option.Strict = strict
+
+	parser, err := NewParser(option)
+	if err != nil {
+		panic(err)
+	}
+	if custom != nil {
+		parser.custom = custom
+	}
+	return parser
+}
+
+// defaultParser 返回与 RequireDateTimeFieldValid 对应的默认解析器，loc 不为 time.Local 时使用其副本。
+func defaultParser(loc *time.Location) *Parser {
+	parser := strictParser
+	if !RequireDateTimeFieldValid {
+		parser = lenientParser
+	}
+
+	if loc == nil || loc == time.Local {
+		return parser
+	}
+
+	// 正则表达式可以共用，只需复制结构并修改时区。
+	copy := *parser
+	copy.option.Location = loc
+	return &copy
+}
@@ -0,0 +1,330 @@
+package timeutils
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParserStrict(t *testing.T) {
+	option := NewParserOption()
+	option.Strict = false
+	lenient, err := NewParser(option)
+	assert.Nil(t, err)
+
+	strict, err := NewParser(nil)
+	assert.Nil(t, err)
+
+	// 与全局设置无关。
+	tm, err := lenient.ParseDateTime("201022231234")
+	assert.Nil(t, err)
+	assert.Equal(t, "2011-10-23 12:34:00", tm.Format("2006-01-02 15:04:05"))
+
+	_, err = strict.ParseDateTime("201022231234")
+	assert.NotNil(t, err)
+	assert.True(t, RequireDateTimeFieldValid)
+
+	// 并发使用不同的解析器。
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, err := lenient.ParseDate("2010-13-23")
+			assert.Nil(t, err)
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := strict.ParseDate("2010-13-23")
+			assert.NotNil(t, err)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestParserLocation(t *testing.T) {
+	option := NewParserOption()
+	option.Location = time.UTC
+	parser, err := NewParser(option)
+	assert.Nil(t, err)
+	assert.Equal(t, time.UTC, parser.Option().Location)
+
+	tm, err := parser.ParseDateTime("2010-02-23 15:34:56")
+	assert.Nil(t, err)
+	assert.Equal(t, time.UTC, tm.Location())
+
+	tm, err = parser.ParseDate("2010-02-23")
+	assert.Nil(t, err)
+	assert.Equal(t, time.UTC, tm.Location())
+
+	tm, err = parser.ParseTime("15:34")
+	assert.Nil(t, err)
+	assert.Equal(t, time.UTC, tm.Location())
+
+	// 默认使用 time.Local。
+	parser, err = NewParser(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Local, parser.Option().Location)
+}
+
+func TestParserSeparators(t *testing.T) {
+	option := NewParserOption()
+	option.DateSeparators = "/"
+	option.TimeSeparators = ":"
+	option.DateTimeSeparators = " "
+	parser, err := NewParser(option)
+	assert.Nil(t, err)
+
+	tm, err := parser.ParseDateTime("2010/2/23 15:34:56")
+	assert.Nil(t, err)
+	assert.Equal(t, "2010-02-23 15:34:56", tm.Format("2006-01-02 15:04:05"))
+
+	// 不在分隔符中的字符不能作为分隔符。
+	_, err = parser.ParseDateTime("2010-02-23 15:34:56")
+	assert.NotNil(t, err)
+
+	// 无分隔符的格式总是可以解析。
+	tm, err = parser.ParseDateTime("20100223 153456")
+	assert.Nil(t, err)
+	assert.Equal(t, "2010-02-23 15:34:56", tm.Format("2006-01-02 15:04:05"))
+
+	// 需要转义的字符。
+	option.DateSeparators = `-^]\`
+	parser, err = NewParser(option)
+	assert.Nil(t, err)
+	tm, err = parser.ParseDate(`2010^02\23`)
+	assert.Nil(t, err)
+	assert.Equal(t, "2010-02-23", tm.Format("2006-01-02"))
+
+	// 没有分隔符时只解析无分隔符的格式。
+	option.DateSeparators = ""
+	option.TimeSeparators = ""
+	option.DateTimeSeparators = ""
+	parser, err = NewParser(option)
+	assert.Nil(t, err)
+	_, err = parser.ParseDate("2010-02-23")
+	assert.NotNil(t, err)
+	tm, err = parser.ParseDateTime("201002231534")
+	assert.Nil(t, err)
+	assert.Equal(t, "2010-02-23 15:34", tm.Format("2006-01-02 15:04"))
+
+	option.TimeSeparators = "1"
+	_, err = NewParser(option)
+	assert.NotNil(t, err)
+}
+
+func TestParserTwoDigitYear(t *testing.T) {
+	// 默认不解析两位年份。
+	parser, err := NewParser(nil)
+	assert.Nil(t, err)
+	_, err = parser.ParseDate("IMG_230105.jpg")
+	assert.NotNil(t, err)
+
+	option := NewParserOption()
+	option.TwoDigitYear = true
+	parser, err = NewParser(option)
+	assert.Nil(t, err)
+
+	tm, err := parser.ParseDate("IMG_230105.jpg")
+	assert.Nil(t, err)
+	assert.Equal(t, "2023-01-05", tm.Format("2006-01-02"))
+
+	tm, err = parser.ParseDate("scan 70-1-5")
+	assert.Nil(t, err)
+	assert.Equal(t, "1970-01-05", tm.Format("2006-01-02"))
+
+	tm, err = parser.ParseDateTime("IMG_691231_235959.jpg")
+	assert.Nil(t, err)
+	assert.Equal(t, "2069-12-31 23:59:59", tm.Format("2006-01-02 15:04:05"))
+
+	tm, err = parser.ParseDateTime("23-01-05 14:30:15.123")
+	assert.Nil(t, err)
+	assert.Equal(t, "2023-01-05 14:30:15.123", tm.Format("2006-01-02 15:04:05.000"))
+
+	// 四位年份优先。
+	tm, err = parser.ParseDate("20230105")
+	assert.Nil(t, err)
+	assert.Equal(t, "2023-01-05", tm.Format("2006-01-02"))
+
+	// 不从更长的数字中间匹配。
+	_, err = parser.ParseDate("a1230105")
+	assert.NotNil(t, err)
+	_, err = parser.ParseDate("x2301051y")
+	assert.NotNil(t, err)
+
+	// 闰年按完整的年份判断，并且优先返回超出范围的错误。
+	_, err = parser.ParseDate("00-02-29")
+	assert.Nil(t, err)
+	_, err = parser.ParseDate("23-02-29")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "out of range")
+
+	// 基准年。
+	option.PivotYear = 1950
+	parser, err = NewParser(option)
+	assert.Nil(t, err)
+	tm, err = parser.ParseDate("49-01-05")
+	assert.Nil(t, err)
+	assert.Equal(t, "2049-01-05", tm.Format("2006-01-02"))
+	tm, err = parser.ParseDate("50-01-05")
+	assert.Nil(t, err)
+	assert.Equal(t, "1950-01-05", tm.Format("2006-01-02"))
+
+	option.PivotYear = -1
+	_, err = NewParser(option)
+	assert.NotNil(t, err)
+
+	// 0 表示默认的 1970。
+	option.PivotYear = 0
+	parser, err = NewParser(option)
+	assert.Nil(t, err)
+	assert.Equal(t, 1970, parser.Option().PivotYear)
+}
+
+func TestParserFind(t *testing.T) {
+	parser, err := NewParser(nil)
+	assert.Nil(t, err)
+
+	for _, v := range []struct {
+		s       string
+		matched string
+	}{
+		{"IMG_20230105_143000.jpg", "20230105_143000"},
+		{"abc2010-02-23-15:34ddd.jpg", "2010-02-23-15:34"},
+		{"2010-02-23 15:34:56.7", "2010-02-23 15:34:56"},
+		{"log 2023-05-01T12:00:00Z.txt", "2023-05-01T12:00:00Z"},
+		{"2023-05-01 12:00:00 PST.log", "2023-05-01 12:00:00 PST"},
+		{"照片2023年1月5日 14时30分.jpg", "2023年1月5日 14时30分"},
+		{"2023-05-01T12:00:00.123456+08:00", "2023-05-01T12:00:00.123456+08:00"},
+	} {
+		_, matched, err := parser.FindDateTime(v.s)
+		assert.Nil(t, err, v.s)
+		assert.Equal(t, v.matched, matched, v.s)
+	}
+
+	_, matched, err := parser.FindDate("照片2023年1月5日.jpg")
+	assert.Nil(t, err)
+	assert.Equal(t, "2023年1月5日", matched)
+
+	_, matched, err = parser.FindTime("at 15:34:56.789.")
+	assert.Nil(t, err)
+	assert.Equal(t, "15:34:56.789", matched)
+
+	_, matched, err = parser.FindDateTime("no date")
+	assert.NotNil(t, err)
+	assert.Equal(t, "", matched)
+}
+
+func TestParserMatchWhole(t *testing.T) {
+	option := NewParserOption()
+	option.Match = MatchWhole
+	parser, err := NewParser(option)
+	assert.Nil(t, err)
+
+	// 整个字符串是日期时间，忽略首尾的空格。
+	for _, s := range []string{
+		"20230105_143000",
+		" 2010-02-23 15:34:56.789 ",
+		"2023-05-01 12:00:00 PST",
+		"2023年1月5日 14时30分",
+		"2023-05-01T12:00:00.123456+08:00",
+	} {
+		_, matched, err := parser.FindDateTime(s)
+		assert.Nil(t, err, s)
+		assert.Equal(t, strings.TrimSpace(s), matched, s)
+	}
+
+	// 有前缀或后缀。
+	for _, s := range []string{
+		"IMG_20230105_143000.jpg",
+		"2010-02-23 15:34:56.7",
+		"x2023-05-01 12:00",
+		"2023年1月5日 14时30分.jpg",
+	} {
+		_, err := parser.ParseDateTime(s)
+		assert.NotNil(t, err, s)
+	}
+
+	_, err = parser.ParseDate("2023-01-05")
+	assert.Nil(t, err)
+	_, err = parser.ParseDate("2023-01-05 14:30")
+	assert.NotNil(t, err)
+	_, err = parser.ParseDate("2023-W01-4T10:00")
+	assert.NotNil(t, err)
+	_, err = parser.ParseTime("14:30:00")
+	assert.Nil(t, err)
+	_, err = parser.ParseTime("14:30:00 tomorrow")
+	assert.NotNil(t, err)
+
+	// 自定义格式同样需要匹配整个字符串。
+	parser.RegisterLayout("02.01.2006", BeforeBuiltins)
+	_, err = parser.ParseDate("05.01.2023")
+	assert.Nil(t, err)
+	_, err = parser.ParseDate("photo_05.01.2023.jpg")
+	assert.NotNil(t, err)
+}
+
+func TestParserScanPosition(t *testing.T) {
+	parser, err := NewParser(nil)
+	assert.Nil(t, err)
+
+	// 与从左向右逐个位置尝试的结果相同，可以从一串数字的中间开始。
+	for _, v := range []struct {
+		s        string
+		expected string
+	}{
+		{"x12345-01-02", "2345-01-02"},
+		{"v2_12_2023-01-05", "2023-01-05"},
+		{"1234567_20230105", "2023-01-05"},
+		{"a1_b22_2023年1月5日", "2023-01-05"},
+	} {
+		tm, err := parser.ParseDate(v.s)
+		assert.Nil(t, err, v.s)
+		assert.Equal(t, v.expected, tm.Format("2006-01-02"), v.s)
+	}
+
+	_, err = parser.ParseDate("no digits at all")
+	assert.NotNil(t, err)
+}
+
+func BenchmarkParserParseDateTime(b *testing.B) {
+	parser, err := NewParser(nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for _, s := range []string{
+		"IMG_20230105_143000.jpg",
+		"photo 2023-01-05 14.30.00.jpg",
+		"scan_2023-01-05.pdf",
+		"some_document_final_v2.docx",
+	} {
+		b.Run(s, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_, _ = parser.ParseDateTime(s)
+			}
+		})
+	}
+}
+
+func BenchmarkParserParseDate(b *testing.B) {
+	parser, err := NewParser(nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for _, s := range []string{
+		"scan_2023-01-05.pdf",
+		"照片2023年1月5日.jpg",
+		"some_document_final_v2.docx",
+	} {
+		b.Run(s, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_, _ = parser.ParseDate(s)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package timeutils
+
+import "time"
+
+/*
+StartOfDay returns the start of the day of t, in the location of t.
+
+StartOfDay 返回 t 所在日的开始，使用 t 的时区。
+*/
+func StartOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+/*
+StartOfWeek returns the start of the week of t, in the location of t.
+
+Parameters:
+  - t: The time.
+  - weekStart: The first day of a week, such as time.Monday or time.Sunday.
+
+Returns:
+  - The start of the week.
+
+StartOfWeek 返回 t 所在周的开始，使用 t 的时区。
+
+参数:
+  - t: 时间。
+  - weekStart: 每周的第一天，例如 time.Monday 或 time.Sunday。
+
+返回:
+  - 周的开始。
+*/
+func StartOfWeek(t time.Time, weekStart time.Weekday) time.Time {
+	days := (int(t.Weekday()) - int(weekStart) + 7) % 7
+	return StartOfDay(t).AddDate(0, 0, -days)
+}
+
+/*
+StartOfMonth returns the start of the month of t, in the location of t.
+
+StartOfMonth 返回 t 所在月的开始，使用 t 的时区。
+*/
+func StartOfMonth(t time.Time) time.Time {
+	year, month, _ := t.Date()
+	return time.Date(year, month, 1, 0, 0, 0, 0, t.Location())
+}
+
+/*
+StartOfQuarter returns the start of the quarter of t, in the location of t.
+Quarters start in January, April, July and October.
+
+StartOfQuarter 返回 t 所在季度的开始，使用 t 的时区。季度从 1 月、4 月、7 月及 10 月开始。
+*/
+func StartOfQuarter(t time.Time) time.Time {
+	year, month, _ := t.Date()
+	month -= (month - 1) % 3
+	return time.Date(year, month, 1, 0, 0, 0, 0, t.Location())
+}
+
+/*
+StartOfYear returns the start of the year of t, in the location of t.
+
+StartOfYear 返回 t 所在年的开始，使用 t 的时区。
+*/
+func StartOfYear(t time.Time) time.Time {
+	return time.Date(t.Year(), 1, 1, 0, 0, 0, 0, t.Location())
+}
+
+/*
+EndOfDay returns the last nanosecond of the day of t, in the location of t.
+
+EndOfDay 返回 t 所在日的最后一纳秒，使用 t 的时区。
+*/
+func EndOfDay(t time.Time) time.Time {
+	return StartOfDay(t).AddDate(0, 0, 1).Add(-time.Nanosecond)
+}
+
+/*
+EndOfWeek returns the last nanosecond of the week of t, in the location of t.
+See [StartOfWeek] for weekStart.
+
+EndOfWeek 返回 t 所在周的最后一纳秒，使用 t 的时区。weekStart 参见 [StartOfWeek]。
+*/
+func EndOfWeek(t time.Time, weekStart time.Weekday) time.Time {
+	return StartOfWeek(t, weekStart).AddDate(0, 0, 7).Add(-time.Nanosecond)
+}
+
+/*
+EndOfMonth returns the last nanosecond of the month of t, in the location of t.
+
+EndOfMonth 返回 t 所在月的最后一纳秒，使用 t 的时区。
+*/
+func EndOfMonth(t time.Time) time.Time {
+	return StartOfMonth(t).AddDate(0, 1, 0).Add(-time.Nanosecond)
+}
+
+/*
+EndOfQuarter returns the last nanosecond of the quarter of t, in the location of t.
+
+EndOfQuarter 返回 t 所在季度的最后一纳秒，使用 t 的时区。
+*/
+func EndOfQuarter(t time.Time) time.Time {
+	return StartOfQuarter(t).AddDate(0, 3, 0).Add(-time.Nanosecond)
+}
+
+/*
+EndOfYear returns the last nanosecond of the year of t, in the location of t.
+
+EndOfYear 返回 t 所在年的最后一纳秒，使用 t 的时区。
+*/
+func EndOfYear(t time.Time) time.Time {
+	return StartOfYear(t).AddDate(1, 0, 0).Add(-time.Nanosecond)
+}
@@ -0,0 +1,56 @@
+package timeutils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStartOfPeriod(t *testing.T) {
+	loc := time.FixedZone("", 8*3600)
+	// 2023-08-17 是星期四。
+	tm := time.Date(2023, 8, 17, 14, 30, 15, 123, loc)
+
+	assert.Equal(t, time.Date(2023, 8, 17, 0, 0, 0, 0, loc), StartOfDay(tm))
+	assert.Equal(t, time.Date(2023, 8, 14, 0, 0, 0, 0, loc), StartOfWeek(tm, time.Monday))
+	assert.Equal(t, time.Date(2023, 8, 13, 0, 0, 0, 0, loc), StartOfWeek(tm, time.Sunday))
+	assert.Equal(t, time.Date(2023, 8, 17, 0, 0, 0, 0, loc), StartOfWeek(tm, time.Thursday))
+	assert.Equal(t, time.Date(2023, 8, 11, 0, 0, 0, 0, loc), StartOfWeek(tm, time.Friday))
+	assert.Equal(t, time.Date(2023, 8, 1, 0, 0, 0, 0, loc), StartOfMonth(tm))
+	assert.Equal(t, time.Date(2023, 7, 1, 0, 0, 0, 0, loc), StartOfQuarter(tm))
+	assert.Equal(t, time.Date(2023, 1, 1, 0, 0, 0, 0, loc), StartOfYear(tm))
+
+	// 每个季度的第一个月及最后一个月。
+	for month, start := range map[time.Month]time.Month{1: 1, 3: 1, 4: 4, 6: 4, 7: 7, 9: 7, 10: 10, 12: 10} {
+		assert.Equal(t, start, StartOfQuarter(time.Date(2023, month, 5, 0, 0, 0, 0, loc)).Month(), month)
+	}
+}
+
+func TestEndOfPeriod(t *testing.T) {
+	loc := time.FixedZone("", 8*3600)
+	tm := time.Date(2024, 2, 17, 14, 30, 15, 123, loc)
+	last := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 23, 59, 59, 999999999, loc)
+	}
+
+	assert.Equal(t, last(2024, 2, 17), EndOfDay(tm))
+	assert.Equal(t, last(2024, 2, 18), EndOfWeek(tm, time.Monday))
+	assert.Equal(t, last(2024, 2, 17), EndOfWeek(tm, time.Sunday))
+	assert.Equal(t, last(2024, 2, 29), EndOfMonth(tm))
+	assert.Equal(t, last(2024, 3, 31), EndOfQuarter(tm))
+	assert.Equal(t, last(2024, 12, 31), EndOfYear(tm))
+	assert.Equal(t, last(2024, 1, 31), EndOfMonth(time.Date(2024, 1, 31, 0, 0, 0, 0, loc)))
+}
+
+func TestPeriodDaylightSaving(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("time zone database not available")
+	}
+
+	// 2023-03-12 只有 23 小时。
+	tm := time.Date(2023, 3, 12, 12, 0, 0, 0, loc)
+	assert.Equal(t, time.Date(2023, 3, 12, 0, 0, 0, 0, loc), StartOfDay(tm))
+	assert.Equal(t, 23*time.Hour, EndOfDay(tm).Sub(StartOfDay(tm))+time.Nanosecond)
+}
@@ -0,0 +1,149 @@
+package timeutils
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/jqk/futool4go/common"
+)
+
+/*
+RateMeter measures the throughput of a copy or hash job, such as "12.500 MB/s", and estimates the remaining time.
+Timing starts with the first Start() or Add(). The zero value is ready to use. It is thread safe.
+
+Example:
+
+	meter := RateMeter{}
+	option.Progress = func(p *fileutils.CopyProgress) {
+		meter.Add(p.IntervalBytes)
+		fmt.Println(meter.RateString(), FormatDuration(meter.ETA(p.Total), nil))
+	}
+
+RateMeter 测量复制或计算校验和等任务的吞吐量，例如“12.500 MB/s”，并估计剩余时间。
+第一次 Start() 或 Add() 时开始计时。零值即可使用。多线程安全。
+*/
+type RateMeter struct {
+	Clock Clock // The source of time. nil means SystemClock. Set it before the first Start() or Add().
+
+	stopwatch Stopwatch
+	bytes     int64
+	lock      sync.Mutex
+}
+
+/*
+Start starts timing if it has not started, so the time before the first Add() is counted.
+
+Start 尚未开始计时时开始计时，使第一次 Add() 之前的时间也被计算。
+*/
+func (m *RateMeter) Start() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.start()
+}
+
+func (m *RateMeter) start() {
+	if !m.stopwatch.IsRunning() {
+		m.stopwatch.Clock = m.Clock
+		m.stopwatch.Start()
+	}
+}
+
+/*
+Add adds the number of bytes processed, starting timing if it has not started.
+
+Add 增加已处理的字节数，尚未开始计时时开始计时。
+*/
+func (m *RateMeter) Add(bytes int64) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.start()
+	m.bytes += bytes
+}
+
+/*
+Bytes returns the total number of bytes added.
+
+Bytes 返回已增加的总字节数。
+*/
+func (m *RateMeter) Bytes() int64 {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return m.bytes
+}
+
+/*
+Rate returns the average throughput since timing started, in bytes per second. It is 0 before timing starts.
+
+Rate 返回开始计时以来的平均吞吐量，单位为字节每秒。开始计时之前为 0。
+*/
+func (m *RateMeter) Rate() float64 {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return m.rate()
+}
+
+func (m *RateMeter) rate() float64 {
+	elapsed := m.stopwatch.ElapsedTime()
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(m.bytes) / elapsed.Seconds()
+}
+
+/*
+RateString returns the throughput formatted by [common.ToSizeString], such as "12.500 MB/s".
+
+RateString 返回使用 [common.ToSizeString] 格式化的吞吐量，例如“12.500 MB/s”。
+*/
+func (m *RateMeter) RateString() string {
+	return common.ToSizeString(int64(m.Rate())) + "/s"
+}
+
+/*
+ETA estimates the remaining time to process total bytes at the average throughput.
+
+Parameters:
+  - total: The total number of bytes of the job.
+
+Returns:
+  - The estimated remaining time. 0 if all bytes are processed, -1 if unknown,
+    such as before any byte is added or when total is negative.
+    The maximum time.Duration, about 292 years, if the estimate is longer than that.
+
+ETA 按平均吞吐量估计处理完 total 字节所需的剩余时间。
+
+参数:
+  - total: 任务的总字节数。
+
+返回:
+  - 估计的剩余时间。已全部处理时为 0，无法估计时为 -1，例如尚未增加字节数或 total 为负数时。
+    超过 time.Duration 的最大值（约 292 年）时返回该最大值。
+*/
+func (m *RateMeter) ETA(total int64) time.Duration {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if total < 0 {
+		return -1
+	}
+	if m.bytes >= total {
+		return 0
+	}
+
+	rate := m.rate()
+	if rate <= 0 {
+		return -1
+	}
+
+	// 吞吐量很低时结果可能超出 time.Duration 的范围，转换会溢出。
+	eta := float64(total-m.bytes) / rate * float64(time.Second)
+	if eta >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return time.Duration(eta)
+}
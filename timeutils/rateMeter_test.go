@@ -0,0 +1,43 @@
+package timeutils
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateMeter(t *testing.T) {
+	clock := NewManualClock(time.Date(2023, 1, 5, 0, 0, 0, 0, time.UTC))
+	meter := RateMeter{Clock: clock}
+	assert.Equal(t, 0.0, meter.Rate())
+	assert.Equal(t, time.Duration(-1), meter.ETA(100))
+
+	meter.Start()
+	clock.Advance(time.Second)
+	assert.Equal(t, time.Duration(-1), meter.ETA(100))
+
+	meter.Add(10 << 20)
+	clock.Advance(time.Second)
+	meter.Add(10 << 20)
+
+	assert.Equal(t, int64(20<<20), meter.Bytes())
+	assert.Equal(t, float64(10<<20), meter.Rate())
+	assert.Equal(t, "10.000 MB/s", meter.RateString())
+	assert.Equal(t, 3*time.Second, meter.ETA(50<<20))
+	assert.Equal(t, time.Duration(0), meter.ETA(20<<20))
+	assert.Equal(t, time.Duration(-1), meter.ETA(-1))
+
+	// 超出 time.Duration 范围的估计值。
+	slow := RateMeter{Clock: clock}
+	slow.Add(1)
+	clock.Advance(time.Hour)
+	assert.Equal(t, time.Duration(math.MaxInt64), slow.ETA(math.MaxInt64))
+
+	// 第一次 Add() 时开始计时。
+	meter = RateMeter{Clock: clock}
+	meter.Add(1024)
+	clock.Advance(2 * time.Second)
+	assert.Equal(t, "512 bytes/s", meter.RateString())
+}
@@ -0,0 +1,216 @@
+package timeutils
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// relativeDays 是表示某一天的词，值为与参考时间相差的天数。
+var relativeDays = map[string]int{
+	"today":     0,
+	"yesterday": -1,
+	"tomorrow":  1,
+	"今天":        0,
+	"今日":        0,
+	"昨天":        -1,
+	"昨日":        -1,
+	"前天":        -2,
+	"明天":        1,
+	"明日":        1,
+	"后天":        2,
+}
+
+// relativeUnits 将时间单位转换为 unitSecond 等常量。
+var relativeUnits = map[string]int{
+	"second": unitSecond, "sec": unitSecond, "秒": unitSecond, "秒钟": unitSecond,
+	"minute": unitMinute, "min": unitMinute, "分": unitMinute, "分钟": unitMinute,
+	"hour": unitHour, "小时": unitHour, "个小时": unitHour, "钟头": unitHour, "个钟头": unitHour,
+	"day": unitDay, "天": unitDay, "日": unitDay,
+	"week": unitWeek, "周": unitWeek, "星期": unitWeek, "个星期": unitWeek, "礼拜": unitWeek, "个礼拜": unitWeek,
+	"month": unitMonth, "月": unitMonth, "个月": unitMonth,
+	"year": unitYear, "年": unitYear,
+}
+
+const (
+	unitSecond = iota
+	unitMinute
+	unitHour
+	unitDay
+	unitWeek
+	unitMonth
+	unitYear
+)
+
+// relativeWeekdays 将星期几转换为 time.Weekday。
+var relativeWeekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday, "日": time.Sunday, "天": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday, "一": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "二": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday, "三": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "四": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday, "五": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday, "六": time.Saturday,
+}
+
+var (
+	// regexRelativeAgo 例如“3 days ago”、“an hour ago”。
+	regexRelativeAgo = regexp.MustCompile(`^(\d+|an?)\s*([a-z]+?)s?\s+ago$`)
+	// regexRelativeIn 例如“in 3 days”、“in a week”。
+	regexRelativeIn = regexp.MustCompile(`^in\s+(\d+|an?)\s*([a-z]+?)s?$`)
+	// regexRelativeChinese 例如“3天前”、“2个月后”、“1小时以后”。
+	regexRelativeChinese = regexp.MustCompile(`^(\d+)\s*(\p{Han}+?)\s*(前|后|以前|以后|之前|之后)$`)
+	// regexRelativeWeekday 例如“next monday”、“last fri”、“this sunday”。
+	regexRelativeWeekday = regexp.MustCompile(`^(next|last|this)\s+([a-z]+)$`)
+	// regexRelativeChineseWeekday 例如“下周一”、“上星期五”、“本周日”、“周三”。
+	regexRelativeChineseWeekday = regexp.MustCompile(`^(上|下|本|这)?个?(?:周|星期|礼拜)([一二三四五六日天])$`)
+)
+
+/*
+ParseRelative parses human-friendly relative time strings in English or Chinese, relative to the reference time.
+The string is case insensitive and leading and trailing spaces are ignored. The result is in the location of ref.
+
+Supported strings:
+  - "now", "现在": the reference time.
+  - "today", "yesterday", "tomorrow", "今天", "昨天", "前天", "明天", "后天": the start of that day.
+  - "3 days ago", "an hour ago", "in 2 weeks", "3天前", "2个月后", "1小时以后": the reference time moved by that amount.
+    Units are second, minute, hour, day, week, month and year. Months and years are added by time.AddDate.
+  - "next monday", "last friday": the start of the nearest Monday after, or Friday before, the day of ref.
+  - "this monday": the start of Monday in the same week of ref. Weeks start on Monday.
+  - "下周一", "上星期五", "本周日", "周三": the start of that day in the next, last or same week of ref.
+
+Parameters:
+  - s: The string to parse.
+  - ref: The reference time, usually time.Now().
+
+Returns:
+  - The parsed time.
+  - An error if the string is not recognized.
+
+Example:
+
+	ref := time.Date(2023, 1, 5, 14, 30, 0, 0, time.Local) // Thursday
+	tm, err := ParseRelative("yesterday", ref)           // 2023-01-04 00:00:00
+	tm, err = ParseRelative("3 days ago", ref)           // 2023-01-02 14:30:00
+	tm, err = ParseRelative("next monday", ref)          // 2023-01-09 00:00:00
+	tm, err = ParseRelative("下周一", ref)                 // 2023-01-09 00:00:00
+	tm, err = ParseRelative("2小时后", ref)                // 2023-01-05 16:30:00
+
+ParseRelative 解析英文或中文的相对时间字符串，相对于参考时间。
+不区分大小写，忽略首尾的空格。结果使用 ref 的时区。
+
+支持的字符串：
+  - "now"、"现在"：参考时间。
+  - "today"、"yesterday"、"tomorrow"、"今天"、"昨天"、"前天"、"明天"、"后天"：那一天的开始。
+  - "3 days ago"、"an hour ago"、"in 2 weeks"、"3天前"、"2个月后"、"1小时以后"：参考时间移动相应的量。
+    单位可以是秒、分钟、小时、天、周、月及年。月及年使用 time.AddDate 计算。
+  - "next monday"、"last friday"：ref 那天之后最近的星期一或者之前最近的星期五的开始。
+  - "this monday"：与 ref 同一周的星期一的开始。每周从星期一开始。
+  - "下周一"、"上星期五"、"本周日"、"周三"：ref 下一周、上一周或同一周的那一天的开始。
+
+参数:
+  - s: 待解析的字符串。
+  - ref: 参考时间，通常为 time.Now()。
+
+返回:
+  - 解析后的时间。
+  - 错误信息。不能识别字符串时返回错误。
+*/
+func ParseRelative(s string, ref time.Time) (time.Time, error) {
+	text := strings.ToLower(strings.TrimSpace(s))
+	today := StartOfDay(ref)
+
+	if text == "now" || text == "现在" {
+		return ref, nil
+	}
+	if days, ok := relativeDays[text]; ok {
+		return today.AddDate(0, 0, days), nil
+	}
+
+	if subs := regexRelativeAgo.FindStringSubmatch(text); subs != nil {
+		return addRelative(s, ref, subs[1], subs[2], -1)
+	}
+	if subs := regexRelativeIn.FindStringSubmatch(text); subs != nil {
+		return addRelative(s, ref, subs[1], subs[2], 1)
+	}
+	if subs := regexRelativeChinese.FindStringSubmatch(text); subs != nil {
+		sign := 1
+		if strings.HasSuffix(subs[3], "前") {
+			sign = -1
+		}
+		return addRelative(s, ref, subs[1], subs[2], sign)
+	}
+
+	if subs := regexRelativeWeekday.FindStringSubmatch(text); subs != nil {
+		weekday, ok := relativeWeekdays[subs[2]]
+		if !ok {
+			return time.Time{}, newParseError(s, "unknown weekday "+subs[2])
+		}
+
+		diff := int(weekday - today.Weekday())
+		switch subs[1] {
+		case "next":
+			if diff <= 0 {
+				diff += 7
+			}
+		case "last":
+			if diff >= 0 {
+				diff -= 7
+			}
+		default:
+			return StartOfWeek(today, time.Monday).AddDate(0, 0, mondayBased(weekday)), nil
+		}
+		return today.AddDate(0, 0, diff), nil
+	}
+	if subs := regexRelativeChineseWeekday.FindStringSubmatch(text); subs != nil {
+		days := mondayBased(relativeWeekdays[subs[2]])
+		if subs[1] == "上" {
+			days -= 7
+		} else if subs[1] == "下" {
+			days += 7
+		}
+		return StartOfWeek(today, time.Monday).AddDate(0, 0, days), nil
+	}
+
+	return time.Time{}, newParseError(s, "unknown relative time")
+}
+
+// addRelative 将 ref 移动 sign 倍的 count 个 unit。
+func addRelative(s string, ref time.Time, count string, unit string, sign int) (time.Time, error) {
+	u, ok := relativeUnits[unit]
+	if !ok {
+		return time.Time{}, newParseError(s, "unknown unit "+unit)
+	}
+
+	n := 1 // “a”或“an”。
+	if count != "a" && count != "an" {
+		var err error
+		if n, err = strconv.Atoi(count); err != nil {
+			return time.Time{}, newParseError(s, err.Error())
+		}
+	}
+	n *= sign
+
+	switch u {
+	case unitSecond:
+		return ref.Add(time.Duration(n) * time.Second), nil
+	case unitMinute:
+		return ref.Add(time.Duration(n) * time.Minute), nil
+	case unitHour:
+		return ref.Add(time.Duration(n) * time.Hour), nil
+	case unitDay:
+		return ref.AddDate(0, 0, n), nil
+	case unitWeek:
+		return ref.AddDate(0, 0, n*7), nil
+	case unitMonth:
+		return ref.AddDate(0, n, 0), nil
+	default:
+		return ref.AddDate(n, 0, 0), nil
+	}
+}
+
+// mondayBased 将星期几转换为从星期一开始的序号，星期一为 0，星期日为 6。
+func mondayBased(weekday time.Weekday) int {
+	return (int(weekday) + 6) % 7
+}
@@ -0,0 +1,57 @@
+package timeutils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRelative(t *testing.T) {
+	// 2023-01-05 是星期四。
+	ref := time.Date(2023, 1, 5, 14, 30, 0, 0, time.UTC)
+
+	cases := map[string]string{
+		"now":           "2023-01-05 14:30:00",
+		"现在":            "2023-01-05 14:30:00",
+		" Today ":       "2023-01-05 00:00:00",
+		"yesterday":     "2023-01-04 00:00:00",
+		"tomorrow":      "2023-01-06 00:00:00",
+		"昨天":            "2023-01-04 00:00:00",
+		"前天":            "2023-01-03 00:00:00",
+		"后天":            "2023-01-07 00:00:00",
+		"3 days ago":    "2023-01-02 14:30:00",
+		"an hour ago":   "2023-01-05 13:30:00",
+		"1 minute ago":  "2023-01-05 14:29:00",
+		"30 secs ago":   "2023-01-05 14:29:30",
+		"in 2 weeks":    "2023-01-19 14:30:00",
+		"in a month":    "2023-02-05 14:30:00",
+		"2 years ago":   "2021-01-05 14:30:00",
+		"3天前":           "2023-01-02 14:30:00",
+		"2个月后":          "2023-03-05 14:30:00",
+		"1小时以后":         "2023-01-05 15:30:00",
+		"10 分钟之前":       "2023-01-05 14:20:00",
+		"next monday":   "2023-01-09 00:00:00",
+		"next thu":      "2023-01-12 00:00:00",
+		"last friday":   "2022-12-30 00:00:00",
+		"last thursday": "2022-12-29 00:00:00",
+		"this monday":   "2023-01-02 00:00:00",
+		"this sunday":   "2023-01-08 00:00:00",
+		"下周一":           "2023-01-09 00:00:00",
+		"上星期五":          "2022-12-30 00:00:00",
+		"本周日":           "2023-01-08 00:00:00",
+		"周三":            "2023-01-04 00:00:00",
+		"下个礼拜天":         "2023-01-15 00:00:00",
+	}
+	for s, expect := range cases {
+		tm, err := ParseRelative(s, ref)
+		assert.Nil(t, err, s)
+		assert.Equal(t, expect, tm.Format("2006-01-02 15:04:05"), s)
+		assert.Equal(t, time.UTC, tm.Location(), s)
+	}
+
+	for _, s := range []string{"", "someday", "3 fortnights ago", "next moonday", "3天", "in days"} {
+		_, err := ParseRelative(s, ref)
+		assert.NotNil(t, err, s)
+	}
+}
@@ -0,0 +1,72 @@
+package timeutils
+
+import (
+	"regexp"
+	"strings"
+)
+
+/*
+scanPattern 是内置格式的正则表达式，只在可能的起始位置尝试，避免每次调用都从头用正则表达式扫描整个字符串。
+
+内置格式都从数字开始，并且可以有任意前缀。原来的 `^.*?X` 在每个位置都尝试 X，且需要记录分组，非常慢。
+现在先用简单的循环找出连续数字个数符合要求的位置，再从该位置尝试以“^”开始的 X，按位置从前往后第一个匹配的结果与原来相同。
+例如有分隔符的日期从 4 位数字开始，之后是分隔符，所以只需在之后正好有 4 位数字的位置尝试。
+没有数字或者没有 literal 的字符串不会运行正则表达式，遍历大量文件名时大多数是这种情况。
+*/
+type scanPattern struct {
+	regex     *regexp.Regexp // 以“^”开始，不含前缀的正则表达式。
+	minDigits int            // 起始位置开始至少需要的连续数字个数。
+	maxDigits int            // 起始位置开始最多允许的连续数字个数，0 表示不限。
+	boundary  bool           // 起始位置之前不能紧跟数字，用于两位年份。
+	literal   string         // 字符串中必须包含的文字，例如“年”。为空时不检查。
+}
+
+// newScanPattern 编译不含前缀的正则表达式 expr，它从起始位置开始匹配。
+func newScanPattern(expr string, minDigits int, maxDigits int, boundary bool, literal string) *scanPattern {
+	return &scanPattern{
+		regex:     regexp.MustCompile(`^` + expr),
+		minDigits: minDigits,
+		maxDigits: maxDigits,
+		boundary:  boundary,
+		literal:   literal,
+	}
+}
+
+// index 与 regexp.FindStringSubmatchIndex 相同，返回 s 中第一个匹配的各分组的位置，没有匹配时返回 nil。
+func (sp *scanPattern) index(s string) []int {
+	if sp.literal != "" && !strings.Contains(s, sp.literal) {
+		return nil
+	}
+
+	// run 为从 i 开始的连续数字个数，每段数字只计算一次。
+	run := 0
+	for i := 0; i < len(s); i++ {
+		if !isDigit(s[i]) {
+			run = 0
+			continue
+		}
+		if run == 0 {
+			for run = 1; i+run < len(s) && isDigit(s[i+run]); run++ {
+			}
+		} else {
+			run--
+		}
+
+		if run < sp.minDigits || (sp.maxDigits > 0 && run > sp.maxDigits) || (sp.boundary && i > 0 && isDigit(s[i-1])) {
+			continue
+		}
+		if index := sp.regex.FindStringSubmatchIndex(s[i:]); index != nil {
+			for j := range index {
+				if index[j] >= 0 {
+					index[j] += i
+				}
+			}
+			return index
+		}
+	}
+	return nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
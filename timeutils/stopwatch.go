@@ -1,23 +1,49 @@
 package timeutils
 
 import (
+	"runtime"
 	"sync"
 	"time"
 )
 
 /*
-Stopwatch is a stopwatch.
+Stopwatch is a stopwatch. The zero value is ready to use with the system clock.
 
-Stopwatch 定义了一个计时器。
+Stopwatch 定义了一个计时器。零值即可使用，使用系统时钟。
 */
 type Stopwatch struct {
+	Clock Clock // The source of time. nil means SystemClock. Set it before the first Start().
+
+	// Optional hooks, such as logging or pushing to metrics. They are called after the lock is released,
+	// so they may call other methods of the Stopwatch. Set them before the first Start().
+	//
+	// 可选的回调，例如记录日志或推送到监控。它们在释放锁之后调用，所以可以调用 Stopwatch 的其它方法。请在第一次 Start() 之前设置。
+	OnStart  func(s *Stopwatch)                        // Called when timing starts or resumes.
+	OnStop   func(s *Stopwatch, elapsed time.Duration) // Called when timing stops, with the total elapsed time.
+	OnRecord func(s *Stopwatch, lap Lap)               // Called when a lap is recorded.
+
 	isRunning   bool
 	startTime   time.Time
 	elapsedTime time.Duration
 	records     []time.Duration
+	labels      []string      // 与 records 一一对应，Record() 记录的为空字符串。
+	cycles      int           // Start 的次数。
+	stopTime    time.Time     // 最近一次 stop 的时间。
+	pausedTime  time.Duration // 各次 stop 到之后 start 之间的时间之和。
 	lock        sync.RWMutex
 }
 
+/*
+Lap is a recorded split of the [Stopwatch].
+
+Lap 是 [Stopwatch] 记录的一段时间。
+*/
+type Lap struct {
+	Label      string        `json:"label"`      // The label given to RecordNamed(), empty for Record().
+	Cumulative time.Duration `json:"cumulative"` // The elapsed time from the first Start().
+	Delta      time.Duration `json:"delta"`      // The elapsed time since the previous lap, or from the first Start() for the first lap.
+}
+
 /*
 IsRunning indicates whether the stopwatch is currently running.
 
@@ -34,11 +60,19 @@ Restart 重置 Stopwatch 的所有信息，并且重新开始计时。
 */
 func (s *Stopwatch) Restart() {
 	s.lock.Lock()
-	defer s.lock.Unlock()
-
+	wasRunning := s.isRunning
 	stop(s)
+	elapsed := s.elapsedTime
 	reset(s)
 	start(s)
+	s.lock.Unlock()
+
+	if wasRunning && s.OnStop != nil {
+		s.OnStop(s, elapsed)
+	}
+	if s.OnStart != nil {
+		s.OnStart(s)
+	}
 }
 
 /*
@@ -58,6 +92,9 @@ func (s *Stopwatch) Reset() {
 func reset(s *Stopwatch) {
 	s.elapsedTime = 0
 	s.records = s.records[0:0]
+	s.labels = s.labels[0:0]
+	s.cycles = 0
+	s.pausedTime = 0
 }
 
 /*
@@ -67,16 +104,24 @@ Start 开始或继续计时。如果 Stopwatch 当前正在运行，则无操作
 */
 func (s *Stopwatch) Start() {
 	s.lock.Lock()
-	defer s.lock.Unlock()
-
-	if !s.isRunning {
+	started := !s.isRunning
+	if started {
 		start(s)
 	}
+	s.lock.Unlock()
+
+	if started && s.OnStart != nil {
+		s.OnStart(s)
+	}
 }
 
 func start(s *Stopwatch) {
 	s.isRunning = true
-	s.startTime = time.Now()
+	s.startTime = clock(s).Now()
+	if s.cycles > 0 {
+		s.pausedTime += s.startTime.Sub(s.stopTime)
+	}
+	s.cycles++
 }
 
 /*
@@ -86,16 +131,34 @@ Stop 停止计时。如果 Stopwatch 当前未运行，则无操作。
 */
 func (s *Stopwatch) Stop() {
 	s.lock.Lock()
-	defer s.lock.Unlock()
-
-	if s.isRunning {
+	stopped := s.isRunning
+	if stopped {
 		stop(s)
 	}
+	elapsed := s.elapsedTime
+	s.lock.Unlock()
+
+	if stopped && s.OnStop != nil {
+		s.OnStop(s, elapsed)
+	}
+}
+
+func clock(s *Stopwatch) Clock {
+	if s.Clock == nil {
+		return SystemClock
+	}
+	return s.Clock
+}
+
+// since 返回从最近一次 start 开始的时间。
+func since(s *Stopwatch) time.Duration {
+	return clock(s).Since(s.startTime)
 }
 
 func stop(s *Stopwatch) {
 	s.isRunning = false
-	s.elapsedTime += time.Since(s.startTime)
+	s.stopTime = clock(s).Now()
+	s.elapsedTime += s.stopTime.Sub(s.startTime)
 }
 
 /*
@@ -104,22 +167,103 @@ Record records the lap time or split time when stopwatch is running.
 Returns:
   - Elapsed time array, arranged in the order of calling Record().
     The elapsed time for all is calculated from the first Start().
+    Use [Stopwatch.Laps] to get the delta since the previous record as well.
 
 Record 在 Stopwatch 正在运行时记录当前的一段时间。
 
 返回:
   - 耗时数组，按调用 Record() 的顺序排列。所有耗时时间都是从第一次 Start() 开始计算的。
+    使用 [Stopwatch.Laps] 可以同时得到与前一次记录的差。
 */
 func (s *Stopwatch) Record() []time.Duration {
+	s.lock.Lock()
+	lap, recorded := record(s, "")
+	result := append([]time.Duration{}, s.records...)
+	s.lock.Unlock()
+
+	if recorded && s.OnRecord != nil {
+		s.OnRecord(s, lap)
+	}
+	return result
+}
+
+/*
+RecordNamed records the lap time with a label when stopwatch is running,
+so multi-phase tasks such as "walk", "hash" and "report" can be told apart.
+
+Parameters:
+  - label: The label of the lap.
+
+Returns:
+  - All laps, arranged in the order of recording. See [Stopwatch.Laps].
+
+Example:
+
+	sw := Stopwatch{}
+	sw.Start()
+	walk()
+	sw.RecordNamed("walk")
+	hash()
+	laps := sw.RecordNamed("hash")
+	// laps[1].Label is "hash", laps[1].Delta is the time of hash().
+
+RecordNamed 在 Stopwatch 正在运行时记录当前的一段时间及其标签，以便区分“walk”、“hash”、“report”这样的多个阶段。
+
+参数:
+  - label: 该段时间的标签。
+
+返回:
+  - 所有记录，按记录的顺序排列。参见 [Stopwatch.Laps]。
+*/
+func (s *Stopwatch) RecordNamed(label string) []Lap {
+	s.lock.Lock()
+	lap, recorded := record(s, label)
+	result := laps(s)
+	s.lock.Unlock()
+
+	if recorded && s.OnRecord != nil {
+		s.OnRecord(s, lap)
+	}
+	return result
+}
+
+// record 在运行时记录一段时间，返回该记录及是否已记录。
+func record(s *Stopwatch, label string) (Lap, bool) {
+	if !s.isRunning {
+		return Lap{}, false
+	}
+
+	elapsed := s.elapsedTime + since(s)
+	lap := Lap{Label: label, Cumulative: elapsed, Delta: elapsed}
+	if len(s.records) > 0 {
+		lap.Delta -= s.records[len(s.records)-1]
+	}
+
+	s.records = append(s.records, elapsed)
+	s.labels = append(s.labels, label)
+	return lap, true
+}
+
+/*
+Laps returns all laps recorded by Record() and RecordNamed(), arranged in the order of recording.
+
+Laps 返回 Record() 及 RecordNamed() 记录的所有时间段，按记录的顺序排列。
+*/
+func (s *Stopwatch) Laps() []Lap {
 	s.lock.RLock()
 	defer s.lock.RUnlock()
 
-	if s.isRunning {
-		elapsed := s.elapsedTime + time.Since(s.startTime)
-		s.records = append(s.records, elapsed)
-	}
+	return laps(s)
+}
 
-	return append([]time.Duration{}, s.records...)
+func laps(s *Stopwatch) []Lap {
+	result := make([]Lap, len(s.records))
+	previous := time.Duration(0)
+	for i, elapsed := range s.records {
+		result[i] = Lap{Label: s.labels[i], Cumulative: elapsed, Delta: elapsed - previous}
+		previous = elapsed
+	}
+	return result
 }
 
 // ElapsedTime returns the elapsed time of the Stopwatch.
@@ -130,12 +274,76 @@ func (s *Stopwatch) ElapsedTime() time.Duration {
 	defer s.lock.RUnlock()
 
 	if s.isRunning {
-		return s.elapsedTime + time.Since(s.startTime)
+		return s.elapsedTime + since(s)
 	} else {
 		return s.elapsedTime
 	}
 }
 
+/*
+Cycles returns the number of Start() since the last reset, counting only those that actually started timing.
+
+Cycles 返回最近一次重置之后 Start() 的次数，只计算真正开始计时的。
+*/
+func (s *Stopwatch) Cycles() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.cycles
+}
+
+/*
+PausedTime returns the total time between each Stop() and the following Start(),
+so an interruptible job can report its active time against its wall time. See [Stopwatch.WallTime].
+
+PausedTime 返回每次 Stop() 到之后 Start() 之间的时间之和，以便可中断的任务对比有效时间与实际经过的时间。
+参见 [Stopwatch.WallTime]。
+*/
+func (s *Stopwatch) PausedTime() time.Duration {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.pausedTime
+}
+
+/*
+WallTime returns the time from the first Start() to now if running, or to the last Stop() if not.
+It is the sum of ElapsedTime() and PausedTime().
+
+WallTime 返回从第一次 Start() 到现在的时间，未运行时到最后一次 Stop()。即 ElapsedTime() 与 PausedTime() 之和。
+*/
+func (s *Stopwatch) WallTime() time.Duration {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	elapsed := s.elapsedTime + s.pausedTime
+	if s.isRunning {
+		elapsed += since(s)
+	}
+	return elapsed
+}
+
+/*
+LapTime returns the elapsed time since the last Record() or RecordNamed(),
+or from the first Start() if nothing is recorded. It is the Delta that the next record would have.
+
+LapTime 返回从最后一次 Record() 或 RecordNamed() 开始的运行时间，没有记录时从第一次 Start() 开始计算。
+即下一次记录的 Delta。
+*/
+func (s *Stopwatch) LapTime() time.Duration {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	elapsed := s.elapsedTime
+	if s.isRunning {
+		elapsed += since(s)
+	}
+	if len(s.records) > 0 {
+		elapsed -= s.records[len(s.records)-1]
+	}
+	return elapsed
+}
+
 /*
 Elapsing runs the given function and returns the elapsed time.
 
@@ -162,3 +370,57 @@ func (s *Stopwatch) Elapsing(task func() error) (time.Duration, error) {
 	err := task()
 	return s.ElapsedTime(), err
 }
+
+/*
+ElapsingStats is the result of [Stopwatch.ElapsingWithStats].
+The memory fields are deltas of runtime.MemStats around the task, so they include other goroutines running meanwhile.
+
+ElapsingStats 是 [Stopwatch.ElapsingWithStats] 的结果。
+内存相关字段是任务前后 runtime.MemStats 的差，所以也包括同时运行的其它 goroutine。
+*/
+type ElapsingStats struct {
+	Duration   time.Duration // The elapsed time.
+	Allocs     uint64        // The number of heap objects allocated.
+	AllocBytes uint64        // The bytes allocated for heap objects, not reduced when freed.
+	HeapDelta  int64         // The change of the live heap bytes. Negative if more is freed than allocated.
+	GCCount    uint32        // The number of completed GC cycles.
+}
+
+/*
+ElapsingWithStats runs the given function like [Stopwatch.Elapsing],
+and also returns the memory allocation and GC counts during it, useful when tuning buffer sizes.
+Reading the memory statistics stops the world briefly, so avoid it in hot paths.
+
+Parameters:
+  - task: The function to execute. Can't be nil.
+
+Returns:
+  - The elapsed time and the memory statistics.
+  - Error message.
+
+ElapsingWithStats 与 [Stopwatch.Elapsing] 一样运行给定的函数，同时返回运行期间的内存分配及 GC 次数，用于调整缓冲区大小等。
+读取内存统计信息会短暂地暂停所有 goroutine，请避免在频繁调用的代码中使用。
+
+参数:
+  - task: 要执行的函数。不能为 nil。
+
+返回:
+  - 运行时长及内存统计信息。
+  - 错误信息。
+*/
+func (s *Stopwatch) ElapsingWithStats(task func() error) (ElapsingStats, error) {
+	before := runtime.MemStats{}
+	after := runtime.MemStats{}
+
+	runtime.ReadMemStats(&before)
+	d, err := s.Elapsing(task)
+	runtime.ReadMemStats(&after)
+
+	return ElapsingStats{
+		Duration:   d,
+		Allocs:     after.Mallocs - before.Mallocs,
+		AllocBytes: after.TotalAlloc - before.TotalAlloc,
+		HeapDelta:  int64(after.HeapAlloc) - int64(before.HeapAlloc),
+		GCCount:    after.NumGC - before.NumGC,
+	}, err
+}
@@ -0,0 +1,223 @@
+package timeutils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+/*
+DefaultHistogramBuckets are the upper bounds of the histogram buckets, the same as the default of Prometheus clients.
+
+DefaultHistogramBuckets 是直方图各桶的上限，与 Prometheus 客户端的默认值相同。
+*/
+var DefaultHistogramBuckets = []time.Duration{
+	5 * time.Millisecond, 10 * time.Millisecond, 25 * time.Millisecond, 50 * time.Millisecond,
+	100 * time.Millisecond, 250 * time.Millisecond, 500 * time.Millisecond,
+	time.Second, 2500 * time.Millisecond, 5 * time.Second, 10 * time.Second,
+}
+
+/*
+ErrMetricNameConflict is returned by [StopwatchRegistry.WritePrometheus] when two names become the same metric name.
+
+ErrMetricNameConflict 在两个名称对应同一个指标名称时由 [StopwatchRegistry.WritePrometheus] 返回。
+*/
+var ErrMetricNameConflict = errors.New("names conflict in Prometheus format")
+
+/*
+StopwatchRegistry holds named [StopwatchStats] and exports them for monitoring long-running programs.
+It implements expvar.Var, so it can be published by expvar.Publish("stopwatches", registry),
+and http.Handler, serving the Prometheus text format. It is thread safe.
+
+Example:
+
+	registry := NewStopwatchRegistry()
+	expvar.Publish("stopwatches", registry)
+	http.Handle("/metrics", registry)
+
+	registry.Stats("scan").Elapsing(scan)
+
+StopwatchRegistry 保存命名的 [StopwatchStats]，并导出这些数据用于监控长时间运行的程序。
+它实现了 expvar.Var，可以使用 expvar.Publish("stopwatches", registry) 发布，
+也实现了 http.Handler，输出 Prometheus 文本格式。多线程安全。
+*/
+type StopwatchRegistry struct {
+	Buckets []time.Duration // The upper bounds of the histogram buckets, in ascending order. nil means DefaultHistogramBuckets. Set it before the first Stats
+
+	stats map[string]*StopwatchStats
+	lock  sync.RWMutex
+}
+
+/*
+NewStopwatchRegistry creates an empty StopwatchRegistry with the default buckets.
+
+NewStopwatchRegistry 创建使用默认桶的空 StopwatchRegistry。
+*/
+func NewStopwatchRegistry() *StopwatchRegistry {
+	return &StopwatchRegistry{stats: map[string]*StopwatchStats{}}
+}
+
+/*
+Stats returns the StopwatchStats of the name, creating it if it does not exist.
+
+The new StopwatchStats counts the histogram buckets of the current Buckets as durations are added.
+
+Parameters:
+  - name: The name. Characters other than letters, digits and "_" are replaced by "_" in the Prometheus format.
+
+Stats 返回指定名称的 StopwatchStats，不存在时创建。新建的 StopwatchStats 在添加时长时按当前的 Buckets 对直方图各桶计数。
+
+参数:
+  - name: 名称。在 Prometheus 格式中，字母、数字及“_”以外的字符被替换为“_”。
+*/
+func (r *StopwatchRegistry) Stats(name string) *StopwatchStats {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	st, ok := r.stats[name]
+	if !ok {
+		buckets := r.Buckets
+		if buckets == nil {
+			buckets = DefaultHistogramBuckets
+		}
+
+		st = &StopwatchStats{
+			buckets: append([]time.Duration{}, buckets...),
+			counts:  make([]int, len(buckets)),
+		}
+		r.stats[name] = st
+	}
+	return st
+}
+
+/*
+Names returns the names of all StopwatchStats in ascending order.
+
+Names 按升序返回所有 StopwatchStats 的名称。
+*/
+func (r *StopwatchRegistry) Names() []string {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	names := make([]string, 0, len(r.stats))
+	for name := range r.stats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+/*
+String returns the summaries of all StopwatchStats as a JSON object keyed by name. See [DurationSummary].
+It is the method of expvar.Var.
+
+String 以名称为键的 JSON 对象返回所有 StopwatchStats 的统计信息。参见 [DurationSummary]。这是 expvar.Var 的方法。
+*/
+func (r *StopwatchRegistry) String() string {
+	summaries := map[string]DurationSummary{}
+	for _, name := range r.Names() {
+		summaries[name] = r.Stats(name).Summary()
+	}
+
+	data, err := json.Marshal(summaries)
+	if err != nil { // 只包含数字及字符串，不会出错。
+		return "{}"
+	}
+	return string(data)
+}
+
+/*
+WritePrometheus writes all StopwatchStats as histograms in the Prometheus text format.
+Each name becomes a metric "<name>_duration_seconds" with buckets, sum and count.
+The histograms cover all added durations, not only the ones kept by StopwatchStats.
+
+Returns:
+  - Error message. It wraps [ErrMetricNameConflict] if two names become the same metric name, such as "a.b" and "a-b",
+    and nothing is written.
+
+WritePrometheus 以 Prometheus 文本格式将所有 StopwatchStats 输出为直方图。
+每个名称对应一个“<name>_duration_seconds”指标，包括各桶、总和及次数。
+直方图包括所有添加的时长，而不只是 StopwatchStats 保留的时长。
+
+返回:
+  - 错误信息。两个名称对应同一个指标名称时，例如“a.b”及“a-b”，返回包装了 [ErrMetricNameConflict] 的错误，且不输出任何内容。
+*/
+func (r *StopwatchRegistry) WritePrometheus(w io.Writer) error {
+	names := r.Names()
+	metrics := make([]string, len(names))
+	owners := map[string]string{}
+	for i, name := range names {
+		metrics[i] = prometheusName(name) + "_duration_seconds"
+		if other, ok := owners[metrics[i]]; ok {
+			return fmt.Errorf("%w: %q and %q are both %s", ErrMetricNameConflict, other, name, metrics[i])
+		}
+		owners[metrics[i]] = name
+	}
+
+	for i, name := range names {
+		metric := metrics[i]
+		buckets, counts, count, total := r.Stats(name).histogram()
+
+		if _, err := fmt.Fprintf(w, "# HELP %s Durations of %s.\n# TYPE %s histogram\n", metric, escapeHelp(name), metric); err != nil {
+			return err
+		}
+
+		for j, bound := range buckets {
+			if _, err := fmt.Fprintf(w, "%s_bucket{le=\"%s\"} %d\n", metric, formatSeconds(bound), counts[j]); err != nil {
+				return err
+			}
+		}
+
+		if _, err := fmt.Fprintf(w, "%s_bucket{le=\"+Inf\"} %d\n%s_sum %s\n%s_count %d\n",
+			metric, count, metric, formatSeconds(total), metric, count,
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/*
+ServeHTTP serves the Prometheus text format. See [StopwatchRegistry.WritePrometheus].
+
+ServeHTTP 输出 Prometheus 文本格式。参见 [StopwatchRegistry.WritePrometheus]。
+*/
+func (r *StopwatchRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	if err := r.WritePrometheus(w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// prometheusName 将字母、数字及“_”以外的字符替换为“_”，以数字开始时加上“_”。
+func prometheusName(name string) string {
+	result := strings.Map(func(c rune) rune {
+		if c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') {
+			return c
+		}
+		return '_'
+	}, name)
+
+	if result == "" || isDigit(result[0]) {
+		result = "_" + result
+	}
+	return result
+}
+
+// escapeHelp 按 Prometheus 格式转义 HELP 文本中的“\”及换行符。
+func escapeHelp(text string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(text)
+}
+
+// formatSeconds 以秒为单位输出时长，使用最短的表示。
+func formatSeconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'g', -1, 64)
+}
@@ -0,0 +1,118 @@
+package timeutils
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRegistry() *StopwatchRegistry {
+	registry := NewStopwatchRegistry()
+	registry.Buckets = []time.Duration{10 * time.Millisecond, time.Second}
+
+	scan := registry.Stats("scan")
+	scan.Add(5 * time.Millisecond)
+	scan.Add(200 * time.Millisecond)
+	scan.Add(2 * time.Second)
+	registry.Stats("file.hash").Add(time.Millisecond)
+	return registry
+}
+
+func TestStopwatchRegistryString(t *testing.T) {
+	registry := newTestRegistry()
+	assert.Equal(t, []string{"file.hash", "scan"}, registry.Names())
+	assert.Same(t, registry.Stats("scan"), registry.Stats("scan"))
+
+	summaries := map[string]DurationSummary{}
+	assert.Nil(t, json.Unmarshal([]byte(registry.String()), &summaries))
+	assert.Equal(t, 3, summaries["scan"].Count)
+	assert.Equal(t, 2*time.Second, summaries["scan"].Max)
+	assert.Equal(t, 1, summaries["file.hash"].Count)
+
+	assert.Equal(t, "{}", NewStopwatchRegistry().String())
+}
+
+func TestStopwatchRegistryWritePrometheus(t *testing.T) {
+	buf := &bytes.Buffer{}
+	assert.Nil(t, newTestRegistry().WritePrometheus(buf))
+
+	expected := `# HELP file_hash_duration_seconds Durations of file.hash.
+# TYPE file_hash_duration_seconds histogram
+file_hash_duration_seconds_bucket{le="0.01"} 1
+file_hash_duration_seconds_bucket{le="1"} 1
+file_hash_duration_seconds_bucket{le="+Inf"} 1
+file_hash_duration_seconds_sum 0.001
+file_hash_duration_seconds_count 1
+# HELP scan_duration_seconds Durations of scan.
+# TYPE scan_duration_seconds histogram
+scan_duration_seconds_bucket{le="0.01"} 1
+scan_duration_seconds_bucket{le="1"} 2
+scan_duration_seconds_bucket{le="+Inf"} 3
+scan_duration_seconds_sum 2.205
+scan_duration_seconds_count 3
+`
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestStopwatchRegistryWritePrometheusAllDurations(t *testing.T) {
+	registry := NewStopwatchRegistry()
+	registry.Buckets = []time.Duration{time.Second}
+	stats := registry.Stats("copy\nfile")
+	stats.MaxSamples = 2
+	for i := 0; i < 5; i++ {
+		stats.Add(time.Duration(i) * 500 * time.Millisecond)
+	}
+	assert.Equal(t, 2, len(stats.Durations()))
+
+	// 直方图包括不再保留的时长，HELP 文本中的换行符被转义。
+	buf := &bytes.Buffer{}
+	assert.Nil(t, registry.WritePrometheus(buf))
+	expected := `# HELP copy_file_duration_seconds Durations of copy\nfile.
+# TYPE copy_file_duration_seconds histogram
+copy_file_duration_seconds_bucket{le="1"} 3
+copy_file_duration_seconds_bucket{le="+Inf"} 5
+copy_file_duration_seconds_sum 5
+copy_file_duration_seconds_count 5
+`
+	assert.Equal(t, expected, buf.String())
+
+	stats.Reset()
+	buf.Reset()
+	assert.Nil(t, registry.WritePrometheus(buf))
+	assert.True(t, strings.Contains(buf.String(), `copy_file_duration_seconds_bucket{le="1"} 0`))
+}
+
+func TestStopwatchRegistryNameConflict(t *testing.T) {
+	registry := newTestRegistry()
+	registry.Stats("file-hash").Add(time.Millisecond)
+
+	buf := &bytes.Buffer{}
+	err := registry.WritePrometheus(buf)
+	assert.True(t, errors.Is(err, ErrMetricNameConflict))
+	assert.Equal(t, 0, buf.Len())
+
+	recorder := httptest.NewRecorder()
+	registry.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, 500, recorder.Code)
+}
+
+func TestStopwatchRegistryServeHTTP(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	newTestRegistry().ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+
+	assert.Equal(t, 200, recorder.Code)
+	assert.True(t, strings.HasPrefix(recorder.Header().Get("Content-Type"), "text/plain"))
+	assert.True(t, strings.Contains(recorder.Body.String(), "scan_duration_seconds_count 3\n"))
+}
+
+func TestPrometheusName(t *testing.T) {
+	assert.Equal(t, "walk_dir", prometheusName("walk-dir"))
+	assert.Equal(t, "_2pass", prometheusName("2pass"))
+	assert.Equal(t, "_", prometheusName(""))
+}
@@ -0,0 +1,157 @@
+package timeutils
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jqk/futool4go/common"
+)
+
+// noLabelName 是没有标签的时间段在文本报告中显示的名称。
+const noLabelName = "(none)"
+
+/*
+StopwatchReportRow is the laps with the same label in the [StopwatchReport].
+
+StopwatchReportRow 是 [StopwatchReport] 中相同标签的时间段。
+*/
+type StopwatchReportRow struct {
+	Label          string        `json:"label"`          // The label. "" means laps recorded by Record().
+	Count          int           `json:"count"`          // The number of laps with the label.
+	Duration       time.Duration `json:"duration"`       // The sum of Delta of the laps, in nanoseconds.
+	DurationString string        `json:"durationString"` // Human-readable duration. See [FormatDuration].
+	Percent        float64       `json:"percent"`        // Percentage of the duration in total, 0 to 100.
+}
+
+/*
+StopwatchReport is the timing summary of a [Stopwatch].
+The total is the elapsed time, so the rows do not add up to 100% if the stopwatch ran after the last lap.
+
+StopwatchReport 是 [Stopwatch] 的计时汇总。合计为总运行时间，所以最后一次记录后继续运行时，各行的和不到 100%。
+*/
+type StopwatchReport struct {
+	Laps  []Lap                `json:"laps"`  // All laps in the order of recording.
+	Rows  []StopwatchReportRow `json:"rows"`  // Laps grouped by label, in the order of first appearance.
+	Total StopwatchReportRow   `json:"total"` // The total row. Its label is "total", and its count is the number of laps.
+}
+
+/*
+Report returns the timing summary of the Stopwatch, with laps grouped by label, counts and percentages.
+A phase recorded repeatedly in a loop, such as "hash", becomes one row.
+
+Report 返回 Stopwatch 的计时汇总，包括按标签分组的时间段、次数及百分比。在循环中多次记录的阶段，例如“hash”，合并为一行。
+*/
+func (s *Stopwatch) Report() *StopwatchReport {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	total := s.elapsedTime
+	if s.isRunning {
+		total += since(s)
+	}
+
+	report := &StopwatchReport{
+		Laps: laps(s),
+		Rows: []StopwatchReportRow{},
+		Total: StopwatchReportRow{
+			Label:    "total",
+			Count:    len(s.records),
+			Duration: total,
+		},
+	}
+
+	indexes := map[string]int{}
+	for _, lap := range report.Laps {
+		i, ok := indexes[lap.Label]
+		if !ok {
+			i = len(report.Rows)
+			indexes[lap.Label] = i
+			report.Rows = append(report.Rows, StopwatchReportRow{Label: lap.Label})
+		}
+		report.Rows[i].Count++
+		report.Rows[i].Duration += lap.Delta
+	}
+
+	option := NewDurationOption()
+	option.SmallestUnit = time.Millisecond
+	for i := range report.Rows {
+		row := &report.Rows[i]
+		row.DurationString = FormatDuration(row.Duration, option)
+		if total > 0 {
+			row.Percent = float64(row.Duration) * 100 / float64(total)
+		}
+	}
+
+	report.Total.DurationString = FormatDuration(total, option)
+	if total > 0 {
+		report.Total.Percent = 100
+	}
+
+	return report
+}
+
+/*
+WriteJSON writes the report in JSON format. Durations are in nanoseconds, with human-readable strings beside.
+
+WriteJSON 以 JSON 格式输出报告。时长的单位为纳秒，同时包含易读的字符串。
+*/
+func (r *StopwatchReport) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+/*
+WriteText writes the report as an aligned text table, including a header line and a total line.
+
+Example:
+
+	label   count  duration  percent
+	walk    1      1s 200ms  40.00
+	hash    2      1s 500ms  50.00
+	total   3      3s        100.00
+
+WriteText 以对齐的文本表格输出报告，包括标题行和合计行。
+*/
+func (r *StopwatchReport) WriteText(w io.Writer) error {
+	return r.WriteTextIn(w, nil)
+}
+
+/*
+WriteTextIn is like [StopwatchReport.WriteText], writing numbers and duration units by the format,
+such as "1,200" and "1秒200毫秒".
+
+WriteTextIn 与 [StopwatchReport.WriteText] 相同，但按 format 输出数字及时长单位，例如 "1,200" 及 "1秒200毫秒"。
+*/
+func (r *StopwatchReport) WriteTextIn(w io.Writer, format *common.NumberFormat) error {
+	writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(writer, "label\tcount\tduration\tpercent"); err != nil {
+		return err
+	}
+
+	option := NewDurationOption()
+	option.SmallestUnit = time.Millisecond
+	option.Format = format
+
+	for _, row := range append(r.Rows, r.Total) {
+		label := row.Label
+		if label == "" {
+			label = noLabelName
+		}
+
+		duration := row.DurationString
+		if format != nil {
+			duration = FormatDuration(row.Duration, option)
+		}
+		if _, err := fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n",
+			label, format.FormatInt(int64(row.Count)), duration, format.FormatFloat(row.Percent, 2),
+		); err != nil {
+			return err
+		}
+	}
+
+	return writer.Flush()
+}
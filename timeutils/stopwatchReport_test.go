@@ -0,0 +1,89 @@
+package timeutils
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jqk/futool4go/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// newReportStopwatch 创建已停止的 Stopwatch，各段时间是确定的。
+func newReportStopwatch() *Stopwatch {
+	return &Stopwatch{
+		elapsedTime: 3 * time.Second,
+		records: []time.Duration{
+			1200 * time.Millisecond, 1900 * time.Millisecond, 2100 * time.Millisecond, 2700 * time.Millisecond,
+		},
+		labels: []string{"walk", "hash", "", "hash"},
+	}
+}
+
+func TestStopwatchReport(t *testing.T) {
+	report := newReportStopwatch().Report()
+
+	assert.Equal(t, 4, len(report.Laps))
+	assert.Equal(t, 3, len(report.Rows))
+
+	assert.Equal(t, "hash", report.Rows[1].Label)
+	assert.Equal(t, 2, report.Rows[1].Count)
+	assert.Equal(t, 1300*time.Millisecond, report.Rows[1].Duration)
+	assert.Equal(t, "1s 300ms", report.Rows[1].DurationString)
+	assert.InDelta(t, 43.33, report.Rows[1].Percent, 0.01)
+
+	assert.Equal(t, "", report.Rows[2].Label)
+	assert.Equal(t, 200*time.Millisecond, report.Rows[2].Duration)
+
+	assert.Equal(t, "total", report.Total.Label)
+	assert.Equal(t, 4, report.Total.Count)
+	assert.Equal(t, "3s", report.Total.DurationString)
+	assert.Equal(t, 100.0, report.Total.Percent)
+
+	// 没有运行过。
+	report = (&Stopwatch{}).Report()
+	assert.Equal(t, 0, len(report.Rows))
+	assert.Equal(t, 0.0, report.Total.Percent)
+}
+
+func TestStopwatchReportWriteJSON(t *testing.T) {
+	buf := &bytes.Buffer{}
+	assert.Nil(t, newReportStopwatch().Report().WriteJSON(buf))
+
+	report := &StopwatchReport{}
+	assert.Nil(t, json.Unmarshal(buf.Bytes(), report))
+	assert.Equal(t, "walk", report.Laps[0].Label)
+	assert.Equal(t, 700*time.Millisecond, report.Laps[1].Delta)
+	assert.Equal(t, 1200*time.Millisecond, report.Rows[0].Duration)
+	assert.Equal(t, 3*time.Second, report.Total.Duration)
+}
+
+func TestStopwatchReportWriteText(t *testing.T) {
+	buf := &bytes.Buffer{}
+	assert.Nil(t, newReportStopwatch().Report().WriteText(buf))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Equal(t, 5, len(lines))
+	assert.Equal(t, "label   count  duration  percent", lines[0])
+	assert.Equal(t, "walk    1      1s 200ms  40.00", lines[1])
+	assert.Equal(t, "(none)  1      200ms     6.67", lines[3])
+	assert.Equal(t, "total   4      3s        100.00", lines[4])
+}
+
+func TestStopwatchReportWriteTextIn(t *testing.T) {
+	buf := &bytes.Buffer{}
+	assert.Nil(t, newReportStopwatch().Report().WriteTextIn(buf, common.NewNumberFormat(LocaleChinese)))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Equal(t, 5, len(lines))
+	assert.True(t, strings.HasPrefix(lines[1], "walk    1      1秒200毫秒"), lines[1])
+	assert.True(t, strings.HasSuffix(lines[1], "40.00"))
+
+	format := &common.NumberFormat{DecimalSeparator: ",", GroupSeparator: "."}
+	buf.Reset()
+	assert.Nil(t, newReportStopwatch().Report().WriteTextIn(buf, format))
+	lines = strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Equal(t, "total   4      3s        100,00", lines[4])
+}
@@ -0,0 +1,291 @@
+package timeutils
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jqk/futool4go/collections"
+)
+
+/*
+DurationSummary is the statistics of a set of durations. All fields are zero if there is no duration.
+
+DurationSummary 是一组时长的统计信息。没有时长时所有字段都为 0。
+*/
+type DurationSummary struct {
+	Count  int           `json:"count"`  // The number of durations.
+	Total  time.Duration `json:"total"`  // The sum.
+	Min    time.Duration `json:"min"`    // The minimum.
+	Max    time.Duration `json:"max"`    // The maximum.
+	Mean   time.Duration `json:"mean"`   // The arithmetic mean.
+	Median time.Duration `json:"median"` // The median. The mean of the two middle values for an even count.
+	P95    time.Duration `json:"p95"`    // The 95th percentile, by the nearest-rank method.
+}
+
+/*
+DefaultMaxSamples is the default number of recent durations kept by [StopwatchStats].
+
+DefaultMaxSamples 是 [StopwatchStats] 默认保留的最近时长的数量。
+*/
+const DefaultMaxSamples = 10000
+
+/*
+StopwatchStats collects the durations of many runs and summarizes them,
+for quick micro-benchmarks such as comparing checksum buffer sizes. It is thread safe.
+
+Only the most recent MaxSamples durations are kept, so it can run in a long-running program without growing.
+The count, total, minimum, maximum and mean of the summary cover all added durations,
+while the median and the 95th percentile are of the kept ones.
+
+StopwatchStats 收集多次运行的时长并进行统计，用于快速的微基准测试，例如比较计算校验和的缓冲区大小。多线程安全。
+
+只保留最近的 MaxSamples 个时长，所以可以在长时间运行的程序中使用而不会持续增长。
+统计信息中的次数、总和、最小值、最大值及平均值包括所有添加的时长，中位数及 95 百分位数则只针对保留的时长。
+*/
+type StopwatchStats struct {
+	MaxSamples int // the number of recent durations kept. 0 or negative means DefaultMaxSamples. Set it before the first Add
+
+	samples *collections.RingBuffer[time.Duration] // 最近的时长，第一次添加时创建。
+	count   int
+	total   time.Duration
+	min     time.Duration
+	max     time.Duration
+	buckets []time.Duration // 直方图各桶的上限，由 StopwatchRegistry 设置。
+	counts  []int           // 各桶累计的次数，每个桶包含所有不大于上限的时长。
+	lock    sync.RWMutex
+}
+
+/*
+Add adds a duration.
+
+Add 添加一个时长。
+*/
+func (st *StopwatchStats) Add(d time.Duration) {
+	st.lock.Lock()
+	defer st.lock.Unlock()
+
+	if st.samples == nil {
+		capacity := st.MaxSamples
+		if capacity <= 0 {
+			capacity = DefaultMaxSamples
+		}
+		st.samples = collections.NewRingBuffer[time.Duration](capacity)
+	}
+	st.samples.Push(d)
+
+	if st.count == 0 || d < st.min {
+		st.min = d
+	}
+	if st.count == 0 || d > st.max {
+		st.max = d
+	}
+	st.count++
+	st.total += d
+
+	for i, bound := range st.buckets {
+		if d <= bound {
+			st.counts[i]++
+		}
+	}
+}
+
+/*
+Elapsing runs the given function like [Stopwatch.Elapsing] and adds the elapsed time if it succeeds.
+
+Parameters:
+  - task: The function to execute. Can't be nil.
+
+Returns:
+  - The elapsed time.
+  - Error message. The elapsed time is not added if it is not nil.
+
+Elapsing 与 [Stopwatch.Elapsing] 一样运行给定的函数，成功时添加运行时长。
+
+参数:
+  - task: 要执行的函数。不能为 nil。
+
+返回:
+  - 运行时长。
+  - 错误信息。不为 nil 时不添加运行时长。
+*/
+func (st *StopwatchStats) Elapsing(task func() error) (time.Duration, error) {
+	sw := Stopwatch{}
+	d, err := sw.Elapsing(task)
+	if err == nil {
+		st.Add(d)
+	}
+	return d, err
+}
+
+/*
+Durations returns the kept durations, at most MaxSamples of the most recent ones, in the order of adding.
+
+Durations 返回保留的时长，即最多 MaxSamples 个最近的时长，按添加的顺序排列。
+*/
+func (st *StopwatchStats) Durations() []time.Duration {
+	st.lock.RLock()
+	defer st.lock.RUnlock()
+
+	if st.samples == nil {
+		return []time.Duration{}
+	}
+	return st.samples.Values()
+}
+
+/*
+Summary returns the statistics of the added durations.
+
+Summary 返回已添加时长的统计信息。
+*/
+func (st *StopwatchStats) Summary() DurationSummary {
+	st.lock.RLock()
+	defer st.lock.RUnlock()
+
+	if st.count == 0 {
+		return DurationSummary{}
+	}
+
+	// 中位数及 95 百分位数只能由保留的时长计算，其余的使用所有时长的累计值。
+	summary := SummarizeDurations(st.samples.Values())
+	summary.Count = st.count
+	summary.Total = st.total
+	summary.Min = st.min
+	summary.Max = st.max
+	summary.Mean = st.total / time.Duration(st.count)
+	return summary
+}
+
+/*
+Reset removes all added durations.
+
+Reset 删除已添加的所有时长。
+*/
+func (st *StopwatchStats) Reset() {
+	st.lock.Lock()
+	defer st.lock.Unlock()
+
+	if st.samples != nil {
+		st.samples.Clear()
+	}
+	st.count, st.total, st.min, st.max = 0, 0, 0, 0
+	for i := range st.counts {
+		st.counts[i] = 0
+	}
+}
+
+// histogram 返回直方图各桶的上限、累计次数，以及总次数和总和的快照。
+func (st *StopwatchStats) histogram() ([]time.Duration, []int, int, time.Duration) {
+	st.lock.RLock()
+	defer st.lock.RUnlock()
+
+	return st.buckets, append([]int{}, st.counts...), st.count, st.total
+}
+
+/*
+SummarizeDurations returns the statistics of the durations.
+
+Parameters:
+  - durations: The durations. It is not modified.
+
+Returns:
+  - The statistics.
+
+Example:
+
+	ms := time.Millisecond
+	s := SummarizeDurations([]time.Duration{3 * ms, 1 * ms, 2 * ms, 10 * ms})
+	// s.Min is 1ms, s.Max is 10ms, s.Mean is 4ms, s.Median is 2.5ms, s.P95 is 10ms.
+
+SummarizeDurations 返回一组时长的统计信息。
+
+参数:
+  - durations: 时长数组。不会被修改。
+
+返回:
+  - 统计信息。
+*/
+func SummarizeDurations(durations []time.Duration) DurationSummary {
+	count := len(durations)
+	if count == 0 {
+		return DurationSummary{}
+	}
+
+	sorted := append([]time.Duration{}, durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	summary := DurationSummary{
+		Count: count,
+		Min:   sorted[0],
+		Max:   sorted[count-1],
+	}
+	for _, d := range sorted {
+		summary.Total += d
+	}
+	summary.Mean = summary.Total / time.Duration(count)
+
+	if count%2 == 1 {
+		summary.Median = sorted[count/2]
+	} else {
+		summary.Median = (sorted[count/2-1] + sorted[count/2]) / 2
+	}
+
+	// 最近秩法：第 ceil(0.95 * count) 个值。
+	rank := (95*count + 99) / 100
+	summary.P95 = sorted[rank-1]
+
+	return summary
+}
+
+/*
+ElapsingN runs the given function repeatedly and returns the duration of each run with the statistics.
+The warmup runs are executed first and not measured, so caches and lazy initialization do not skew the result.
+
+Parameters:
+  - n: The number of measured runs.
+  - warmup: The number of warmup runs before measuring.
+  - task: The function to execute. Can't be nil.
+
+Returns:
+  - The durations of the measured runs, in the order of running.
+  - The statistics of the durations. See [SummarizeDurations].
+  - Error message. Running stops at the first error, and the durations so far are returned.
+
+Example:
+
+	durations, summary, err := ElapsingN(100, 10, func() error {
+		_, err := checksum(path, bufferSize)
+		return err
+	})
+	fmt.Println(summary.Median, summary.P95)
+
+ElapsingN 重复运行给定的函数，返回每次运行的时长及统计信息。
+先运行 warmup 次且不计时，避免缓存及延迟初始化影响结果。
+
+参数:
+  - n: 计时运行的次数。
+  - warmup: 计时之前预热运行的次数。
+  - task: 要执行的函数。不能为 nil。
+
+返回:
+  - 计时运行的时长，按运行的顺序排列。
+  - 时长的统计信息。参见 [SummarizeDurations]。
+  - 错误信息。遇到第一个错误时停止运行，并返回已有的时长。
+*/
+func ElapsingN(n int, warmup int, task func() error) ([]time.Duration, DurationSummary, error) {
+	for i := 0; i < warmup; i++ {
+		if err := task(); err != nil {
+			return []time.Duration{}, DurationSummary{}, err
+		}
+	}
+
+	// 保留所有时长，以便全部返回。
+	stats := StopwatchStats{MaxSamples: n}
+	for i := 0; i < n; i++ {
+		if _, err := stats.Elapsing(task); err != nil {
+			return stats.Durations(), stats.Summary(), err
+		}
+	}
+
+	return stats.Durations(), stats.Summary(), nil
+}
@@ -0,0 +1,127 @@
+package timeutils
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummarizeDurations(t *testing.T) {
+	ms := time.Millisecond
+	s := SummarizeDurations([]time.Duration{3 * ms, 1 * ms, 2 * ms, 10 * ms})
+	assert.Equal(t, 4, s.Count)
+	assert.Equal(t, 16*ms, s.Total)
+	assert.Equal(t, 1*ms, s.Min)
+	assert.Equal(t, 10*ms, s.Max)
+	assert.Equal(t, 4*ms, s.Mean)
+	assert.Equal(t, 2500*time.Microsecond, s.Median)
+	assert.Equal(t, 10*ms, s.P95)
+
+	durations := []time.Duration{}
+	for i := 100; i >= 1; i-- {
+		durations = append(durations, time.Duration(i)*ms)
+	}
+	s = SummarizeDurations(durations)
+	assert.Equal(t, 50500*time.Microsecond, s.Median)
+	assert.Equal(t, 95*ms, s.P95)
+	assert.Equal(t, 100*ms, durations[0]) // 不修改参数。
+
+	assert.Equal(t, DurationSummary{}, SummarizeDurations(nil))
+}
+
+func TestStopwatchStats(t *testing.T) {
+	step := time.Millisecond * 10
+	stats := StopwatchStats{}
+
+	for i := 0; i < 3; i++ {
+		d, err := stats.Elapsing(func() error {
+			time.Sleep(step)
+			return nil
+		})
+		assert.Nil(t, err)
+		assert.True(t, d >= step)
+	}
+
+	// 失败的运行不计入。
+	_, err := stats.Elapsing(func() error { return errors.New("failed") })
+	assert.NotNil(t, err)
+
+	stats.Add(step * 10)
+	assert.Equal(t, 4, len(stats.Durations()))
+
+	s := stats.Summary()
+	assert.Equal(t, 4, s.Count)
+	assert.True(t, s.Min >= step)
+	assert.Equal(t, step*10, s.Max)
+
+	stats.Reset()
+	assert.Equal(t, 0, stats.Summary().Count)
+	assert.Equal(t, 0, len(stats.Durations()))
+}
+
+func TestStopwatchStatsMaxSamples(t *testing.T) {
+	ms := time.Millisecond
+	stats := StopwatchStats{MaxSamples: 3}
+	for i := 1; i <= 10; i++ {
+		stats.Add(time.Duration(i) * ms)
+	}
+
+	// 只保留最近的 3 个时长，累计值包括所有时长。
+	assert.Equal(t, []time.Duration{8 * ms, 9 * ms, 10 * ms}, stats.Durations())
+	s := stats.Summary()
+	assert.Equal(t, 10, s.Count)
+	assert.Equal(t, 55*ms, s.Total)
+	assert.Equal(t, 1*ms, s.Min)
+	assert.Equal(t, 10*ms, s.Max)
+	assert.Equal(t, 5500*time.Microsecond, s.Mean)
+	assert.Equal(t, 9*ms, s.Median)
+	assert.Equal(t, 10*ms, s.P95)
+
+	stats.Reset()
+	stats.Add(2 * ms)
+	assert.Equal(t, DurationSummary{Count: 1, Total: 2 * ms, Min: 2 * ms, Max: 2 * ms, Mean: 2 * ms, Median: 2 * ms, P95: 2 * ms}, stats.Summary())
+
+	// 默认的数量。
+	stats = StopwatchStats{}
+	for i := 0; i < DefaultMaxSamples+5; i++ {
+		stats.Add(ms)
+	}
+	assert.Equal(t, DefaultMaxSamples, len(stats.Durations()))
+	assert.Equal(t, DefaultMaxSamples+5, stats.Summary().Count)
+}
+
+func TestElapsingN(t *testing.T) {
+	step := time.Millisecond * 5
+	runs := 0
+	task := func() error {
+		runs++
+		time.Sleep(step)
+		return nil
+	}
+
+	durations, summary, err := ElapsingN(4, 2, task)
+	assert.Nil(t, err)
+	assert.Equal(t, 6, runs)
+	assert.Equal(t, 4, len(durations))
+	assert.Equal(t, 4, summary.Count)
+	assert.True(t, summary.Min >= step)
+
+	// 遇到错误时停止，返回已有的时长。
+	runs = 0
+	durations, summary, err = ElapsingN(5, 1, func() error {
+		runs++
+		if runs == 3 {
+			return errors.New("failed")
+		}
+		return nil
+	})
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, len(durations))
+	assert.Equal(t, 1, summary.Count)
+
+	durations, _, err = ElapsingN(0, 0, task)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(durations))
+}
@@ -1,6 +1,7 @@
 package timeutils
 
 import (
+	"runtime"
 	"testing"
 	"time"
 
@@ -107,6 +108,83 @@ func TestRecord(t *testing.T) {
 	assert.True(t, r[2] >= step*3)
 }
 
+// sink 避免编译器优化掉测试中的内存分配。
+var sink []byte
+
+func TestElapsingWithStats(t *testing.T) {
+	sw := Stopwatch{}
+	size := 1 << 20
+
+	stats, err := sw.ElapsingWithStats(func() error {
+		for i := 0; i < 4; i++ {
+			sink = make([]byte, size)
+		}
+		runtime.GC()
+		return nil
+	})
+
+	assert.Nil(t, err)
+	assert.True(t, stats.Duration > 0)
+	assert.True(t, stats.Allocs >= 4)
+	assert.True(t, stats.AllocBytes >= uint64(size*4))
+	assert.True(t, stats.GCCount >= 1)
+	assert.False(t, sw.IsRunning())
+}
+
+func TestRecordNamed(t *testing.T) {
+	step := time.Millisecond * 50
+	sw := Stopwatch{}
+
+	laps := sw.RecordNamed("idle")
+	assert.Equal(t, 0, len(laps))
+
+	sw.Start()
+
+	time.Sleep(step)
+	sw.RecordNamed("walk")
+
+	time.Sleep(step * 2)
+	sw.Record()
+
+	time.Sleep(step)
+	laps = sw.RecordNamed("report")
+	sw.Stop()
+
+	assert.Equal(t, laps, sw.Laps())
+	assert.Equal(t, 3, len(laps))
+	assert.Equal(t, "walk", laps[0].Label)
+	assert.Equal(t, "", laps[1].Label)
+	assert.Equal(t, "report", laps[2].Label)
+
+	assert.True(t, laps[0].Delta >= step)
+	assert.Equal(t, laps[0].Cumulative, laps[0].Delta)
+	assert.True(t, laps[1].Delta >= step*2)
+	assert.True(t, laps[2].Delta >= step)
+	assert.Equal(t, laps[2].Cumulative, laps[0].Delta+laps[1].Delta+laps[2].Delta)
+	assert.Equal(t, sw.Record(), []time.Duration{laps[0].Cumulative, laps[1].Cumulative, laps[2].Cumulative})
+
+	sw.Reset()
+	assert.Equal(t, 0, len(sw.Laps()))
+}
+
+func TestLapTime(t *testing.T) {
+	step := time.Millisecond * 50
+	sw := Stopwatch{}
+	assert.Equal(t, time.Duration(0), sw.LapTime())
+
+	sw.Start()
+	time.Sleep(step)
+	assert.True(t, sw.LapTime() >= step)
+
+	r := sw.Record()
+	time.Sleep(step)
+	sw.Stop()
+
+	lap := sw.LapTime()
+	assert.True(t, lap >= step)
+	assert.Equal(t, sw.ElapsedTime(), r[0]+lap)
+}
+
 func TestElapsing(t *testing.T) {
 	step := time.Millisecond * 50
 	sw := Stopwatch{}
@@ -119,3 +197,77 @@ func TestElapsing(t *testing.T) {
 	assert.Nil(t, err)
 	assert.True(t, d >= step)
 }
+
+func TestStopwatchHooks(t *testing.T) {
+	clock := NewManualClock(time.Date(2023, 1, 5, 0, 0, 0, 0, time.UTC))
+	events := []string{}
+	sw := Stopwatch{
+		Clock: clock,
+		OnStart: func(s *Stopwatch) {
+			events = append(events, "start")
+		},
+		OnStop: func(s *Stopwatch, elapsed time.Duration) {
+			// 回调中可以调用其它方法。
+			events = append(events, "stop "+elapsed.String()+" "+s.ElapsedTime().String())
+		},
+		OnRecord: func(s *Stopwatch, lap Lap) {
+			events = append(events, "record "+lap.Label+" "+lap.Delta.String())
+		},
+	}
+
+	sw.Stop()
+	sw.Record()
+	sw.Start()
+	sw.Start()
+	clock.Advance(time.Second)
+	sw.RecordNamed("walk")
+	clock.Advance(2 * time.Second)
+	sw.Record()
+	sw.Stop()
+	sw.Stop()
+	sw.Start()
+	clock.Advance(time.Second)
+	sw.Restart()
+
+	assert.Equal(t, []string{
+		"start",
+		"record walk 1s",
+		"record  2s",
+		"stop 3s 3s",
+		"start",
+		"stop 4s 0s",
+		"start",
+	}, events)
+}
+
+func TestPausedTime(t *testing.T) {
+	clock := NewManualClock(time.Date(2023, 1, 5, 0, 0, 0, 0, time.UTC))
+	sw := Stopwatch{Clock: clock}
+	assert.Equal(t, 0, sw.Cycles())
+
+	sw.Start()
+	clock.Advance(2 * time.Second)
+	sw.Stop()
+	clock.Advance(5 * time.Second) // 暂停。
+	sw.Start()
+	sw.Start() // 已经在运行，不计数。
+	clock.Advance(time.Second)
+	assert.Equal(t, 8*time.Second, sw.WallTime())
+	sw.Stop()
+	clock.Advance(time.Hour) // 最后一次停止之后的时间不计入。
+
+	assert.Equal(t, 2, sw.Cycles())
+	assert.Equal(t, 3*time.Second, sw.ElapsedTime())
+	assert.Equal(t, 5*time.Second, sw.PausedTime())
+	assert.Equal(t, 8*time.Second, sw.WallTime())
+
+	sw.Reset()
+	assert.Equal(t, 0, sw.Cycles())
+	assert.Equal(t, time.Duration(0), sw.WallTime())
+
+	sw.Start()
+	clock.Advance(time.Second)
+	sw.Restart()
+	assert.Equal(t, 1, sw.Cycles())
+	assert.Equal(t, time.Duration(0), sw.PausedTime())
+}
@@ -0,0 +1,172 @@
+package timeutils
+
+import "time"
+
+/*
+TimeRange is a half-open time range [Start, End), so adjacent ranges share no instant.
+It is empty if End is not after Start.
+
+TimeRange 是半开的时间范围 [Start, End)，所以相邻的范围没有共同的时刻。End 不在 Start 之后时为空。
+*/
+type TimeRange struct {
+	Start time.Time // The start of the range, inclusive.
+	End   time.Time // The end of the range, exclusive.
+}
+
+/*
+IsEmpty returns whether the range contains no instant, that is, End is not after Start.
+
+IsEmpty 返回范围是否不包含任何时刻，也就是 End 不在 Start 之后。
+*/
+func (r TimeRange) IsEmpty() bool {
+	return !r.End.After(r.Start)
+}
+
+/*
+Duration returns the length of the range. It is 0 if the range is empty.
+
+Duration 返回范围的长度。范围为空时返回 0。
+*/
+func (r TimeRange) Duration() time.Duration {
+	if r.IsEmpty() {
+		return 0
+	}
+	return r.End.Sub(r.Start)
+}
+
+/*
+Contains returns whether t is in the range, that is, Start <= t < End.
+
+Contains 返回 t 是否在范围内，也就是 Start <= t < End。
+*/
+func (r TimeRange) Contains(t time.Time) bool {
+	return !t.Before(r.Start) && t.Before(r.End)
+}
+
+/*
+Overlaps returns whether the two ranges have any instant in common. Empty ranges overlap nothing.
+
+Overlaps 返回两个范围是否有共同的时刻。空的范围与任何范围都不重叠。
+*/
+func (r TimeRange) Overlaps(other TimeRange) bool {
+	return !r.IsEmpty() && !other.IsEmpty() && r.Start.Before(other.End) && other.Start.Before(r.End)
+}
+
+/*
+Intersect returns the common part of the two ranges.
+
+Parameters:
+  - other: The other range.
+
+Returns:
+  - The common part.
+  - false if the two ranges do not overlap, then the returned range is empty.
+
+Intersect 返回两个范围的共同部分。
+
+参数:
+  - other: 另一个范围。
+
+返回:
+  - 共同部分。
+  - 两个范围不重叠时返回 false，此时返回的范围为空。
+*/
+func (r TimeRange) Intersect(other TimeRange) (TimeRange, bool) {
+	if !r.Overlaps(other) {
+		return TimeRange{}, false
+	}
+	return TimeRange{Start: laterOf(r.Start, other.Start), End: earlierOf(r.End, other.End)}, true
+}
+
+/*
+Union returns the range covering both ranges, if they overlap or are adjacent.
+An empty range is ignored, so the union with it is the other range.
+
+Parameters:
+  - other: The other range.
+
+Returns:
+  - The range covering both.
+  - false if there is a gap between the two ranges, then the returned range is empty.
+
+Union 返回覆盖两个范围的范围，要求它们重叠或者相邻。空的范围被忽略，所以与它的并集是另一个范围。
+
+参数:
+  - other: 另一个范围。
+
+返回:
+  - 覆盖两者的范围。
+  - 两个范围之间有间隔时返回 false，此时返回的范围为空。
+*/
+func (r TimeRange) Union(other TimeRange) (TimeRange, bool) {
+	if r.IsEmpty() {
+		return other, true
+	} else if other.IsEmpty() {
+		return r, true
+	}
+
+	if r.End.Before(other.Start) || other.End.Before(r.Start) {
+		return TimeRange{}, false
+	}
+	return TimeRange{Start: earlierOf(r.Start, other.Start), End: laterOf(r.End, other.End)}, true
+}
+
+/*
+SplitByDay splits the range at the midnights in the location of Start.
+The first and the last parts may be shorter than a day. An empty range returns nil.
+
+SplitByDay 在 Start 所在时区的每个零点处拆分范围。第一段及最后一段可能短于一天。空的范围返回 nil。
+*/
+func (r TimeRange) SplitByDay() []TimeRange {
+	return r.split(func(t time.Time) time.Time {
+		return StartOfDay(t).AddDate(0, 0, 1)
+	})
+}
+
+/*
+SplitByWeek splits the range at the midnights of weekStart in the location of Start.
+The first and the last parts may be shorter than a week. An empty range returns nil.
+
+Parameters:
+  - weekStart: The first day of a week, such as time.Monday or time.Sunday.
+
+SplitByWeek 在 Start 所在时区的每个 weekStart 的零点处拆分范围。第一段及最后一段可能短于一周。空的范围返回 nil。
+
+参数:
+  - weekStart: 每周的第一天，例如 time.Monday 或 time.Sunday。
+*/
+func (r TimeRange) SplitByWeek(weekStart time.Weekday) []TimeRange {
+	return r.split(func(t time.Time) time.Time {
+		return StartOfWeek(t, weekStart).AddDate(0, 0, 7)
+	})
+}
+
+// split 在 next 返回的时刻处拆分范围，next 返回 t 之后的下一个拆分点。
+func (r TimeRange) split(next func(t time.Time) time.Time) []TimeRange {
+	if r.IsEmpty() {
+		return nil
+	}
+
+	var result []TimeRange
+	start := r.Start
+	for start.Before(r.End) {
+		end := earlierOf(next(start), r.End)
+		result = append(result, TimeRange{Start: start, End: end})
+		start = end
+	}
+	return result
+}
+
+func earlierOf(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+func laterOf(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
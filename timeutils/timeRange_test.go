@@ -0,0 +1,95 @@
+package timeutils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func rangeTime(day, hour int) time.Time {
+	return time.Date(2023, 1, day, hour, 0, 0, 0, time.UTC)
+}
+
+func TestTimeRange(t *testing.T) {
+	r := TimeRange{Start: rangeTime(5, 0), End: rangeTime(6, 12)}
+	assert.False(t, r.IsEmpty())
+	assert.Equal(t, 36*time.Hour, r.Duration())
+
+	// 包含开始，不包含结束。
+	assert.True(t, r.Contains(rangeTime(5, 0)))
+	assert.True(t, r.Contains(rangeTime(6, 11)))
+	assert.False(t, r.Contains(rangeTime(6, 12)))
+	assert.False(t, r.Contains(rangeTime(4, 23)))
+
+	empty := TimeRange{Start: rangeTime(6, 0), End: rangeTime(6, 0)}
+	assert.True(t, empty.IsEmpty())
+	assert.Equal(t, time.Duration(0), empty.Duration())
+	assert.False(t, empty.Contains(rangeTime(6, 0)))
+	reversed := TimeRange{Start: rangeTime(7, 0), End: rangeTime(6, 0)}
+	assert.True(t, reversed.IsEmpty())
+	assert.Equal(t, time.Duration(0), reversed.Duration())
+
+	// 重叠及交集。
+	other := TimeRange{Start: rangeTime(6, 0), End: rangeTime(8, 0)}
+	assert.True(t, r.Overlaps(other))
+	assert.True(t, other.Overlaps(r))
+	common, ok := r.Intersect(other)
+	assert.True(t, ok)
+	assert.Equal(t, TimeRange{Start: rangeTime(6, 0), End: rangeTime(6, 12)}, common)
+
+	// 相邻的范围不重叠，但可以合并。
+	adjacent := TimeRange{Start: rangeTime(6, 12), End: rangeTime(7, 0)}
+	assert.False(t, r.Overlaps(adjacent))
+	_, ok = r.Intersect(adjacent)
+	assert.False(t, ok)
+	union, ok := r.Union(adjacent)
+	assert.True(t, ok)
+	assert.Equal(t, TimeRange{Start: rangeTime(5, 0), End: rangeTime(7, 0)}, union)
+
+	union, ok = adjacent.Union(other)
+	assert.True(t, ok)
+	assert.Equal(t, TimeRange{Start: rangeTime(6, 0), End: rangeTime(8, 0)}, union)
+
+	// 有间隔的范围不能合并。
+	_, ok = r.Union(TimeRange{Start: rangeTime(7, 0), End: rangeTime(8, 0)})
+	assert.False(t, ok)
+
+	// 空的范围不与任何范围重叠，合并时被忽略。
+	assert.False(t, r.Overlaps(empty))
+	union, ok = r.Union(reversed)
+	assert.True(t, ok)
+	assert.Equal(t, r, union)
+}
+
+func TestTimeRangeSplit(t *testing.T) {
+	r := TimeRange{Start: rangeTime(5, 12), End: rangeTime(7, 6)}
+	days := r.SplitByDay()
+	assert.Equal(t, []TimeRange{
+		{Start: rangeTime(5, 12), End: rangeTime(6, 0)},
+		{Start: rangeTime(6, 0), End: rangeTime(7, 0)},
+		{Start: rangeTime(7, 0), End: rangeTime(7, 6)},
+	}, days)
+
+	// 正好是一天。
+	days = TimeRange{Start: rangeTime(5, 0), End: rangeTime(6, 0)}.SplitByDay()
+	assert.Equal(t, []TimeRange{{Start: rangeTime(5, 0), End: rangeTime(6, 0)}}, days)
+
+	assert.Nil(t, TimeRange{}.SplitByDay())
+
+	// 2023-01-05 是星期四。
+	r = TimeRange{Start: rangeTime(5, 12), End: rangeTime(20, 0)}
+	weeks := r.SplitByWeek(time.Monday)
+	assert.Equal(t, []TimeRange{
+		{Start: rangeTime(5, 12), End: rangeTime(9, 0)},
+		{Start: rangeTime(9, 0), End: rangeTime(16, 0)},
+		{Start: rangeTime(16, 0), End: rangeTime(20, 0)},
+	}, weeks)
+
+	weeks = r.SplitByWeek(time.Thursday)
+	assert.Equal(t, []TimeRange{
+		{Start: rangeTime(5, 12), End: rangeTime(12, 0)},
+		{Start: rangeTime(12, 0), End: rangeTime(19, 0)},
+		{Start: rangeTime(19, 0), End: rangeTime(20, 0)},
+	}, weeks)
+}
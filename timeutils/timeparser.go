@@ -21,6 +21,11 @@ Example:
 
 RequireDateTimeFieldValid 定义是否要求日期时间各字段的值都在范围内。默认为 true。
 该值为全局设置，会影响后续所有操作。
+
+Deprecated: it is not safe for concurrent use and affects unrelated callers.
+Use [Parser] with [ParserOption].Strict instead.
+
+已废弃：该值不能并发使用，且会影响无关的调用者。请使用 [Parser] 及 [ParserOption].Strict。
 */
 var RequireDateTimeFieldValid = true
 
@@ -78,29 +83,6 @@ func ParseUnixTime(s string) *time.Time {
 	return &result
 }
 
-// regexDateTimeNoSep 是用于无分隔符的日期时间正则表达式：
-//  1. 可以有字符前缀及后缀。
-//  2. 日期数字之间无分隔符，需要至少 8 位数字表示 YYYYMMDD。
-//  3. 日期与时间之间可以有为分隔符，也可以无分隔符。分隔符可以是“_”、“-”、“.”、“ ”和“T”。
-//  4. 时间数字之间无分隔符，可以是 4 位数字，6 位数字，或者 9 位数字。
-//     分别表示 HHMM，HHMMSS 及 HHMMSSSS。也就是说，可以精确到分钟、秒或毫秒。
-//  5. 毫秒数为 3 位，与秒数之间可以有“.”作为分隔符，也可以无分隔符。
-//
-// note: 最后的 (\.?(\d{3}))? 不要外圈这括号也行，但加上后解析结果数组与 regexDateTimeHasSep 一致。
-var regexDateTimeNoSep = regexp.MustCompile(
-	`^.*?(\d{4})(\d{2})(\d{2})[-|_|\.| |T]?` +
-		`(\d{2})(\d{2})((\d{2})(\.?(\d{3}))?)?.*`)
-
-// regexDateTimeHasSep 是用于有分隔符的日期时间正则表达式：
-//  1. 可以有字符前缀及后缀。
-//  2. 日期数字之间有分隔符，年 4 位，月 1 或 2 位，日 1 或 2 位。
-//  3. 日期与时间之间必须有为分隔符，可以是“_”、“-”、“.”、“ ”和“T”。
-//  4. 时间数字之间有分隔符，可以是“_”、“-”、“.”、“:”。秒与毫秒之间只能是“.”。
-//     小时 1 或 2 位，分钟和秒都是 2 位，毫秒是 3 位。可以精确到分钟、秒或毫秒。
-var regexDateTimeHasSep = regexp.MustCompile(
-	`^.*?(\d{4})[-|_|\.|](\d{1,2})[-|_|\.|](\d{1,2})[-|_|\.| |T]` +
-		`(\d{1,2})[-|_|\.|\:|](\d{2})([-|_|\.|\:|](\d{2})(\.(\d{3}))?)?.*`)
-
 /*
 ParseDateTime parses date time strings into time variables.
 
@@ -126,6 +108,11 @@ Example:
 	tm = ParseDateTime("2010_2-23.5.34.56.789ddd.jpg") // 2010-02-23 05:34:56.789
 	tm = ParseDateTime("2010.02.23T15-34_56.789")      // 2010-02-23 15:34:56.789
 	tm = ParseDateTime("2010-02-23 15:34:56.7")        // 2010-02-23 15:34:56.000
+	tm = ParseDateTime("2010/02/23 15:34:56")          // 2010-02-23 15:34:56
+
+	// chinese date time.
+	tm = ParseDateTime("照片2023年1月5日 14时30分.jpg")   // 2023-01-05 14:30:00
+	tm = ParseDateTime("2023年01月05日14点30分15秒")      // 2023-01-05 14:30:15
 
 ParseDateTime 将日期时间字符串转换为时间变量。
 
@@ -136,57 +123,77 @@ ParseDateTime 将日期时间字符串转换为时间变量。
   - 解析后的时间。失败均返回 nil。
 */
 func ParseDateTime(s string) *time.Time {
-	parse := func(s string, regex *regexp.Regexp) *time.Time {
-		subs := regex.FindStringSubmatch(s)
-		if len(subs) == 0 {
-			// 没有配置的日期时间字符串，所以数组长度为 0，返回 nil 说明转换不成功。
-			return nil
-		}
+	return ParseDateTimeInLocation(s, time.Local)
+}
 
-		year, _ := strconv.Atoi(subs[1])
-		m, _ := strconv.Atoi(subs[2])
-		month := time.Month(m)
-		day, _ := strconv.Atoi(subs[3])
-		hour, _ := strconv.Atoi(subs[4])
-		minute, _ := strconv.Atoi(subs[5])
-		// subs[6] 包含了秒和毫秒。
-		second, _ := strconv.Atoi(subs[7])
-
-		if IsDateTimeFieldValid(year, m, day, hour, minute, second) != nil {
-			return nil
-		}
+/*
+ParseDateTimeInLocation is like [ParseDateTime], but uses the given location when the string has no time zone.
+A time zone immediately following the time is recognized, either "Z" for UTC, an offset like "+08:00" or "-0530",
+or an abbreviation like "CST" or "PST" resolved by [ResolveZone]. An unknown abbreviation is ignored.
+"Z" and offsets are recognized only when the string is just the date time with the zone,
+or after a time with "T" or ":" when the offset has ":", like "IMG_2023-05-01T12:00:00+08:00.jpg",
+so digits like "-0001" after the time in a file name are not taken as a zone.
+The result is in that zone if it is given in the string.
+A whole ISO 8601 string with a time is parsed by [ParseISO8601] first, so its fraction of seconds can be of any precision.
 
-		// subs[8] 包含了"."和毫秒。
-		millisecond, _ := strconv.Atoi(subs[9])
-		nanosecond := millisecond * 1000_000
+Parameters:
+  - s: The string to parse.
+  - loc: The location used when the string has no time zone. nil means time.Local.
 
-		result := time.Date(year, month, day, hour, minute, second, nanosecond, time.Local)
-		return &result
-	}
+Returns:
+  - The parsed time. nil is returned on failure.
 
-	result := parse(s, regexDateTimeHasSep)
-	if result != nil {
-		return result
-	}
+Example:
+
+	tm = ParseDateTimeInLocation("2023-05-01T12:00:00+08:00", time.UTC) // 2023-05-01 12:00:00 +0800
+	tm = ParseDateTimeInLocation("20230501T120000Z", time.Local)        // 2023-05-01 12:00:00 UTC
+	tm = ParseDateTimeInLocation("2023-05-01 12:00:00", time.UTC)       // 2023-05-01 12:00:00 UTC
+	tm = ParseDateTimeInLocation("2023-05-01 12:00:00 PST", time.UTC)   // 2023-05-01 12:00:00 PST
+
+ParseDateTimeInLocation 与 [ParseDateTime] 相同，但字符串中没有时区时使用给定的时区。
+识别紧跟在时间之后的时区，可以是表示 UTC 的“Z”，“+08:00”、“-0530”这样的偏移量，
+或者由 [ResolveZone] 解析的“CST”、“PST”这样的缩写。忽略未知的缩写。
+“Z”及偏移量只在字符串只是带时区的日期时间时，或者在使用“T”或“:”的时间之后且偏移量带有“:”时识别，
+例如“IMG_2023-05-01T12:00:00+08:00.jpg”，所以文件名中时间之后的“-0001”这样的数字不会被当作时区。
+字符串中给出时区时，结果使用该时区。
+有时间的完整 ISO 8601 字符串先由 [ParseISO8601] 解析，所以秒的小数部分可以是任意精度。
+
+参数:
+  - s: 待解析的字符串。
+  - loc: 字符串中没有时区时使用的时区。nil 表示 time.Local。
+
+返回:
+  - 解析后的时间。失败均返回 nil。
+*/
+func ParseDateTimeInLocation(s string, loc *time.Location) *time.Time {
+	return toPointer(ParseDateTimeInLocationE(s, loc))
+}
+
+/*
+ParseDateTimeE is like [ParseDateTime], but returns an error describing why parsing failed.
 
-	return parse(s, regexDateTimeNoSep)
+ParseDateTimeE 与 [ParseDateTime] 相同，但返回说明解析失败原因的错误。
+*/
+func ParseDateTimeE(s string) (time.Time, error) {
+	return ParseDateTimeInLocationE(s, time.Local)
 }
 
-// regexDateHasSep 是用于有分隔符的日期正则表达式：
-//  1. 可以有字符前缀及后缀。
-//  2. 日期数字之间有分隔符，年 4 位，月 1 或 2 位，日 1 或 2 位。
-var regexDateHasSep = regexp.MustCompile(`^.*?(\d{4})[-|_|\.|](\d{1,2})[-|_|\.|](\d{1,2}).*`)
+/*
+ParseDateTimeInLocationE is like [ParseDateTimeInLocation], but returns an error describing why parsing failed.
 
-// regexDateNoSep 是用于无分隔符的日期正则表达式：
-//  1. 可以有字符前缀及后缀。
-//  2. 日期数字之间无分隔符，需要至少 8 位数字表示 YYYYMMDD。
-var regexDateNoSep = regexp.MustCompile(`^.*?(\d{4})(\d{2})(\d{2}).*`)
+ParseDateTimeInLocationE 与 [ParseDateTimeInLocation] 相同，但返回说明解析失败原因的错误。
+*/
+func ParseDateTimeInLocationE(s string, loc *time.Location) (time.Time, error) {
+	return defaultParser(loc).ParseDateTime(s)
+}
 
 /*
 ParseDate parses date strings into time variables.
 
 Parameters:
   - s: The string to parse. see examples of [ParseDateTime].
+    ISO 8601 week dates and ordinal dates are also supported, see [ParseISO8601].
+    An ordinal date needs the "-", such as "2023-123", since "2023123" may be a date missing a digit.
 
 Returns:
   - The parsed date. nil is returned on failure.
@@ -195,91 +202,66 @@ ParseDate 将日期字符串转换为时间变量。
 
 参数:
   - s: 待解析的字符串。参考 [ParseDateTime] 的示例。
+    也支持 ISO 8601 的周日期及序数日期，参见 [ParseISO8601]。
+    序数日期需要有“-”，例如“2023-123”，因为“2023123”可能是缺少数字的日期。
 
 返回:
   - 解析后的日期。失败均返回 nil。
 */
 func ParseDate(s string) *time.Time {
-	parse := func(s string, regex *regexp.Regexp) *time.Time {
-		subs := regex.FindStringSubmatch(s)
-		if len(subs) == 0 {
-			// 没有配置的日期字符串，所以数组长度为 0，返回 nil 说明转换不成功。
-			return nil
-		}
-
-		year, _ := strconv.Atoi(subs[1])
-		m, _ := strconv.Atoi(subs[2])
-		month := time.Month(m)
-		day, _ := strconv.Atoi(subs[3])
-
-		if IsDateTimeFieldValid(year, m, day, 0, 0, 0) != nil {
-			return nil
-		}
-
-		result := time.Date(year, month, day, 0, 0, 0, 0, time.Local)
-		return &result
-	}
+	return toPointer(ParseDateE(s))
+}
 
-	result := parse(s, regexDateHasSep)
-	if result != nil {
-		return result
-	}
+/*
+ParseDateE is like [ParseDate], but returns an error describing why parsing failed.
 
-	return parse(s, regexDateNoSep)
+ParseDateE 与 [ParseDate] 相同，但返回说明解析失败原因的错误。
+*/
+func ParseDateE(s string) (time.Time, error) {
+	return defaultParser(nil).ParseDate(s)
 }
 
 func IsDateTimeFieldValid(year, month, day, hour, minute, second int) error {
 	if !RequireDateTimeFieldValid {
 		return nil
 	}
+	return validateDateTimeFields(year, month, day, hour, minute, second)
+}
 
+// validateDateTimeFields 检查各字段是否在有效范围内。
+func validateDateTimeFields(year, month, day, hour, minute, second int) error {
 	if month < 1 || month > 12 {
-		return fmt.Errorf("invalid month: %d", month)
+		return fmt.Errorf("month %d out of range", month)
 	}
 	if hour < 0 || hour > 23 {
-		return fmt.Errorf("invalid hour: %d", hour)
+		return fmt.Errorf("hour %d out of range", hour)
 	}
 	if minute < 0 || minute > 59 {
-		return fmt.Errorf("invalid minute: %d", minute)
+		return fmt.Errorf("minute %d out of range", minute)
 	}
 	if second < 0 || second > 59 {
-		return fmt.Errorf("invalid second: %d", second)
+		return fmt.Errorf("second %d out of range", second)
 	}
 	if day < 1 || day > 31 {
-		return fmt.Errorf("invalid day: %d", day)
+		return fmt.Errorf("day %d out of range", day)
 	}
 	if (month == 4 || month == 6 || month == 9 || month == 11) && day > 30 {
-		return fmt.Errorf("invalid day: %d", day)
+		return fmt.Errorf("day %d out of range for month %d", day, month)
 	}
 	if month == 2 {
 		if year%4 == 0 && (year%100 != 0 || year%400 == 0) {
 			// 闰年 2 月份的最大天数为 29
 			if day > 29 {
-				return fmt.Errorf("invalid day for leap year: %d", day)
+				return fmt.Errorf("day %d out of range for February of leap year %d", day, year)
 			}
 		} else if day > 28 {
-			return fmt.Errorf("invalid day: %d", day)
+			return fmt.Errorf("day %d out of range for February of %d", day, year)
 		}
 	}
 
 	return nil
 }
 
-// regexTimeNoSep 是用于无分隔符的时间正则表达式：
-//  1. 可以有字符前缀及后缀。
-//  2. 时间数字之间无分隔符，可以是 4 位数字，6 位数字，或者 9 位数字。
-//     分别表示 HHMM，HHMMSS 及 HHMMSSSS。也就是说，可以精确到分钟、秒或毫秒。
-//  3. 毫秒数为 3 位，与秒数之间可以有“.”作为分隔符，也可以无分隔符。
-//
-// note: 最后的 (\.?(\d{3}))? 不要外圈这括号也行，但加上后解析结果数组与 regexTimeHasSep 一致。
-var regexTimeNoSep = regexp.MustCompile(`^.*?(\d{2})(\d{2})((\d{2})(\.?(\d{3}))?)?.*`)
-
-// regexTimeHasSep 是用于有分隔符的时间正则表达式：
-//  1. 可以有字符前缀及后缀。
-//  2. 时间数字之间有分隔符，可以是“_”、“-”、“.”、“:”。秒与毫秒之间只能是“.”。
-//     小时 1 或 2 位，分钟和秒都是 2 位，毫秒是 3 位。可以精确到分钟、秒或毫秒。
-var regexTimeHasSep = regexp.MustCompile(`^.*?(\d{1,2})[-|_|\.|\:|](\d{2})([-|_|\.|\:|](\d{2})(\.(\d{3}))?)?.*`)
-
 /*
 ParseTime parses time strings into time variables.
 
@@ -299,32 +281,22 @@ ParseTime 将日期字符串转换为时间变量。
   - 解析后的时间。失败均返回 nil。
 */
 func ParseTime(s string) *time.Time {
-	parse := func(s string, regex *regexp.Regexp) *time.Time {
-		subs := regex.FindStringSubmatch(s)
-		if len(subs) == 0 {
-			// 没有配置的日期字符串，所以数组长度为 0，返回 nil 说明转换不成功。
-			return nil
-		}
-
-		hour, _ := strconv.Atoi(subs[1])
-		minute, _ := strconv.Atoi(subs[2])
-		second, _ := strconv.Atoi(subs[4])
-		millisecond, _ := strconv.Atoi(subs[6])
-		nanosecond := millisecond * 1000_000
+	return toPointer(ParseTimeE(s))
+}
 
-		if IsDateTimeFieldValid(0, 1, 1, hour, minute, second) != nil {
-			return nil
-		}
+/*
+ParseTimeE is like [ParseTime], but returns an error describing why parsing failed.
 
-		// time.Parse() 只解析时间时，使用的日期就是 0，1，1。
-		result := time.Date(0, 1, 1, hour, minute, second, nanosecond, time.Local)
-		return &result
-	}
+ParseTimeE 与 [ParseTime] 相同，但返回说明解析失败原因的错误。
+*/
+func ParseTimeE(s string) (time.Time, error) {
+	return defaultParser(nil).ParseTime(s)
+}
 
-	result := parse(s, regexTimeHasSep)
-	if result != nil {
-		return result
+// toPointer 将 E 版本解析函数的结果转换为指针，失败时为 nil。
+func toPointer(t time.Time, err error) *time.Time {
+	if err != nil {
+		return nil
 	}
-
-	return parse(s, regexTimeNoSep)
+	return &t
 }
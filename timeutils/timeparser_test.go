@@ -111,6 +111,62 @@ func TestParseDate(t *testing.T) {
 	tm = ParseDate("20100223153456.789ddd.jpg")
 	assert.NotNil(t, tm)
 	assert.Equal(t, "2010-02-23 00:00:00", tm.Format("2006-01-02 15:04:05"))
+
+	// 默认也可以使用“/”作为日期分隔符。
+	tm = ParseDate("2023/05/01")
+	assert.NotNil(t, tm)
+	assert.Equal(t, "2023-05-01 00:00:00", tm.Format("2006-01-02 15:04:05"))
+	tm = ParseDateTime("2023/5/1 08:30")
+	assert.NotNil(t, tm)
+	assert.Equal(t, "2023-05-01 08:30:00", tm.Format("2006-01-02 15:04:05"))
+}
+
+func TestParseChineseDateTime(t *testing.T) {
+	// 有前、后缀，月和日都只有 1 位，精确到分钟。
+	tm := ParseDateTime("照片2023年1月5日 14时30分.jpg")
+	assert.NotNil(t, tm)
+	assert.Equal(t, "2023-01-05 14:30:00", tm.Format("2006-01-02 15:04:05"))
+
+	// 日期与时间之间无分隔符，“点”表示小时，精确到秒。
+	tm = ParseDateTime("2023年01月05日14点30分15秒")
+	assert.NotNil(t, tm)
+	assert.Equal(t, "2023-01-05 14:30:15", tm.Format("2006-01-02 15:04:05"))
+
+	// 中文日期，“:”分隔的时间，精确到毫秒。
+	tm = ParseDateTime("2023年1月5号_14:30:15.123")
+	assert.NotNil(t, tm)
+	assert.Equal(t, "2023-01-05 14:30:15.123", tm.Format("2006-01-02 15:04:05.000"))
+
+	// 没有时间部分。
+	_, err := ParseDateTimeE("2023年01月05日")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "no time component")
+
+	tm = ParseDate("扫描件_2023年01月05日.pdf")
+	assert.NotNil(t, tm)
+	assert.Equal(t, "2023-01-05 00:00:00", tm.Format("2006-01-02 15:04:05"))
+
+	tm = ParseDate("2023年1月5日 14时30分")
+	assert.NotNil(t, tm)
+	assert.Equal(t, "2023-01-05 00:00:00", tm.Format("2006-01-02 15:04:05"))
+
+	// 超出范围。
+	_, err = ParseDateE("2023年2月29日")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "out of range")
+	_, err = ParseDateTimeE("2023年1月5日 24时30分")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "hour 24 out of range")
+
+	// 不受分隔符选项的影响。
+	option := NewParserOption()
+	option.DateSeparators = ""
+	option.DateTimeSeparators = ""
+	parser, err := NewParser(option)
+	assert.Nil(t, err)
+	tm2, err := parser.ParseDateTime("2023年1月5日 14时30分")
+	assert.Nil(t, err)
+	assert.Equal(t, "2023-01-05 14:30:00", tm2.Format("2006-01-02 15:04:05"))
 }
 
 func TestParseTime(t *testing.T) {
@@ -165,3 +221,98 @@ func TestDateTimeFieldValid(t *testing.T) {
 	assert.NotNil(t, IsDateTimeFieldValid(2010, 2, 23, 5, 60, 59))
 	assert.NotNil(t, IsDateTimeFieldValid(2010, 2, 23, 5, 25, 60))
 }
+
+func TestParseDateTimeInLocation(t *testing.T) {
+	// 带有偏移量时使用该时区，忽略给定的时区。
+	tm := ParseDateTimeInLocation("2023-05-01T12:00:00+08:00", time.UTC)
+	assert.NotNil(t, tm)
+	_, offset := tm.Zone()
+	assert.Equal(t, 8*3600, offset)
+	assert.Equal(t, "2023-05-01T04:00:00Z", tm.UTC().Format(time.RFC3339))
+
+	// 整个字符串是日期时间时，偏移量可以没有“:”，可以为负数。
+	tm = ParseDateTimeInLocation("20230501_1200-0530", time.UTC)
+	assert.NotNil(t, tm)
+	_, offset = tm.Zone()
+	assert.Equal(t, -(5*3600 + 30*60), offset)
+
+	// 文件名中的偏移量必须跟在 ISO 8601 的时间之后，且带有“:”。
+	tm = ParseDateTimeInLocation("IMG_2023-05-01T12:00:00-05:30.jpg", time.UTC)
+	assert.NotNil(t, tm)
+	_, offset = tm.Zone()
+	assert.Equal(t, -(5*3600 + 30*60), offset)
+	for _, s := range []string{"20230501_1200-0530.jpg", "IMG_20230105_143000-1234.jpg", "2023-05-01T12:00:00-0530.jpg"} {
+		tm = ParseDateTimeInLocation(s, time.UTC)
+		assert.NotNil(t, tm, s)
+		assert.Equal(t, time.UTC, tm.Location(), s)
+	}
+
+	// “Z”表示 UTC。
+	tm = ParseDateTimeInLocation("20230501T120000Z", time.Local)
+	assert.NotNil(t, tm)
+	assert.Equal(t, time.UTC, tm.Location())
+	assert.Equal(t, "2023-05-01 12:00:00", tm.Format("2006-01-02 15:04:05"))
+
+	// 没有时区时使用给定的时区。
+	tm = ParseDateTimeInLocation("2023-05-01 12:00:00.123", time.UTC)
+	assert.NotNil(t, tm)
+	assert.Equal(t, time.UTC, tm.Location())
+	assert.Equal(t, "2023-05-01 12:00:00.123", tm.Format("2006-01-02 15:04:05.000"))
+
+	// 跟在时间之后的字母不是时区。
+	tm = ParseDateTimeInLocation("2023-05-01 12:00 Zoo.jpg", time.UTC)
+	assert.NotNil(t, tm)
+	assert.Equal(t, time.UTC, tm.Location())
+	tm = ParseDateTimeInLocation("2023-05-01 12:00Zoo.jpg", nil)
+	assert.NotNil(t, tm)
+	assert.Equal(t, time.Local, tm.Location())
+
+	// 超出范围的偏移量被忽略。
+	tm = ParseDateTimeInLocation("2023-05-01 12:00:00+2500", time.UTC)
+	assert.NotNil(t, tm)
+	assert.Equal(t, time.UTC, tm.Location())
+
+	// ParseDateTime 对带有时区的字符串同样有效。
+	tm = ParseDateTime("2023-05-01T12:00:00Z")
+	assert.NotNil(t, tm)
+	assert.Equal(t, time.UTC, tm.Location())
+}
+
+func TestParseE(t *testing.T) {
+	tm, err := ParseDateTimeE("2010-02-23 15:34:56")
+	assert.Nil(t, err)
+	assert.Equal(t, "2010-02-23 15:34:56", tm.Format("2006-01-02 15:04:05"))
+
+	_, err = ParseDateTimeE("2010-13-23 15:34:56")
+	var parseErr *ParseError
+	assert.ErrorAs(t, err, &parseErr)
+	assert.Equal(t, "month 13 out of range", parseErr.Reason)
+	assert.Equal(t, "2010-13-23 15:34:56", parseErr.Input)
+
+	_, err = ParseDateTimeE("2010-02-23")
+	assert.ErrorAs(t, err, &parseErr)
+	assert.Equal(t, "no time component", parseErr.Reason)
+
+	_, err = ParseDateTimeE("abc")
+	assert.ErrorAs(t, err, &parseErr)
+	assert.Equal(t, "no date time found", parseErr.Reason)
+
+	_, err = ParseDateE("2010-02-30")
+	assert.ErrorAs(t, err, &parseErr)
+	assert.Equal(t, "day 30 out of range for February of 2010", parseErr.Reason)
+
+	_, err = ParseDateE("no date")
+	assert.EqualError(t, err, `cannot parse "no date": no date found`)
+
+	tm, err = ParseTimeE("15:34:56.789")
+	assert.Nil(t, err)
+	assert.Equal(t, "15:34:56.789", tm.Format("15:04:05.000"))
+
+	_, err = ParseTimeE("25:34")
+	assert.ErrorAs(t, err, &parseErr)
+	assert.Equal(t, "hour 25 out of range", parseErr.Reason)
+
+	_, err = ParseTimeE("x")
+	assert.ErrorAs(t, err, &parseErr)
+	assert.Equal(t, "no time found", parseErr.Reason)
+}
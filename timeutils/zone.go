@@ -0,0 +1,162 @@
+package timeutils
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultZoneOffsets 是默认的时区缩写及其偏移量，单位为分钟。
+// 缩写有歧义时按以下选择：CST 为中国标准时间，IST 为印度标准时间，BST 为英国夏令时。
+var defaultZoneOffsets = map[string]int{
+	"UTC": 0, "GMT": 0, "WET": 0, "WEST": 60, "BST": 60,
+	"CET": 60, "CEST": 120, "EET": 120, "EEST": 180, "MSK": 180,
+	"IST": 330, "CST": 480, "HKT": 480, "SGT": 480, "AWST": 480, "JST": 540, "KST": 540,
+	"ACST": 570, "AEST": 600, "AEDT": 660, "NZST": 720, "NZDT": 780,
+	"HST": -600, "AKST": -540, "AKDT": -480, "PST": -480, "PDT": -420,
+	"MST": -420, "MDT": -360, "EST": -300, "EDT": -240,
+}
+
+// regexZoneOffset 是“+0800”、“+08:00”或“-05”这样的偏移量。
+var regexZoneOffset = regexp.MustCompile(`^([-+]\d{2})(?::?(\d{2}))?$`)
+
+/*
+ZoneResolver resolves time zone abbreviations like "CST" to locations, with an explicit table for ambiguous ones.
+It is safe for concurrent use.
+
+ZoneResolver 将“CST”这样的时区缩写解析为时区，有歧义的缩写由明确的对照表决定。可以并发使用。
+*/
+type ZoneResolver struct {
+	mutex sync.RWMutex
+	zones map[string]*time.Location
+}
+
+/*
+NewZoneResolver creates a ZoneResolver with the default table of common abbreviations.
+Each abbreviation is a fixed offset, so "PST" is always -08:00 and "PDT" is always -07:00.
+Ambiguous abbreviations default to CST China Standard Time +08:00, IST India Standard Time +05:30
+and BST British Summer Time +01:00. Use [ZoneResolver.Register] to change them.
+
+NewZoneResolver 使用常见缩写的默认对照表创建 ZoneResolver。
+每个缩写都是固定的偏移量，所以“PST”总是 -08:00，“PDT”总是 -07:00。
+有歧义的缩写默认为：CST 中国标准时间 +08:00，IST 印度标准时间 +05:30，BST 英国夏令时 +01:00。
+使用 [ZoneResolver.Register] 修改。
+*/
+func NewZoneResolver() *ZoneResolver {
+	r := &ZoneResolver{zones: map[string]*time.Location{}}
+	for abbr, minutes := range defaultZoneOffsets {
+		r.zones[abbr] = time.FixedZone(abbr, minutes*60)
+	}
+	return r
+}
+
+/*
+Register maps the abbreviation to the location, replacing the existing one.
+
+Parameters:
+  - abbr: The abbreviation, case insensitive.
+  - loc: The location. nil removes the abbreviation.
+
+Example:
+
+	resolver := NewZoneResolver()
+	chicago, _ := time.LoadLocation("America/Chicago")
+	resolver.Register("CST", chicago) // CST is US Central Time from now on.
+
+Register 将缩写对应到时区，替换已有的。
+
+参数:
+  - abbr: 缩写，不区分大小写。
+  - loc: 时区。nil 表示删除该缩写。
+*/
+func (r *ZoneResolver) Register(abbr string, loc *time.Location) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if loc == nil {
+		delete(r.zones, strings.ToUpper(abbr))
+	} else {
+		r.zones[strings.ToUpper(abbr)] = loc
+	}
+}
+
+/*
+Resolve returns the location of the time zone string.
+
+Parameters:
+  - s: An abbreviation like "CST", "Z" for UTC, an offset like "+0800", "+08:00" or "-05",
+    or an IANA name like "Asia/Shanghai" which needs the time zone database.
+
+Returns:
+  - The location. An offset is returned as a fixed zone.
+  - An error if the string is not recognized.
+
+Resolve 返回时区字符串对应的时区。
+
+参数:
+  - s: “CST”这样的缩写，表示 UTC 的“Z”，“+0800”、“+08:00”或“-05”这样的偏移量，
+    或者“Asia/Shanghai”这样的 IANA 名称，此时需要时区数据库。
+
+返回:
+  - 时区。偏移量返回固定时区。
+  - 错误信息。不能识别字符串时返回错误。
+*/
+func (r *ZoneResolver) Resolve(s string) (*time.Location, error) {
+	text := strings.TrimSpace(s)
+	if text == "Z" {
+		return time.UTC, nil
+	}
+
+	if subs := regexZoneOffset.FindStringSubmatch(text); subs != nil {
+		h, _ := strconv.Atoi(subs[1])
+		m, _ := strconv.Atoi(subs[2])
+		if h < -14 || h > 14 || m > 59 {
+			return nil, fmt.Errorf("zone offset %q out of range", text)
+		}
+		return parseZone("", subs[1], subs[2], nil), nil
+	}
+
+	if strings.Contains(text, "/") {
+		return time.LoadLocation(text)
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	if loc, ok := r.zones[strings.ToUpper(text)]; ok {
+		return loc, nil
+	}
+	return nil, fmt.Errorf("unknown time zone %q", text)
+}
+
+// lookup 返回缩写对应的时区，没有时返回 nil。
+func (r *ZoneResolver) lookup(abbr string) *time.Location {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.zones[abbr]
+}
+
+// defaultZones 由 ResolveZone 及默认的解析器使用。
+var defaultZones = NewZoneResolver()
+
+/*
+ResolveZone returns the location of the time zone string like [ZoneResolver.Resolve], using the default table.
+
+ResolveZone 与 [ZoneResolver.Resolve] 一样返回时区字符串对应的时区，但使用默认的对照表。
+*/
+func ResolveZone(s string) (*time.Location, error) {
+	return defaultZones.Resolve(s)
+}
+
+/*
+RegisterZone maps the abbreviation to the location in the default table like [ZoneResolver.Register].
+It affects [ResolveZone], the package level parsing functions, and parsers created with the default [ParserOption].Zones.
+
+RegisterZone 与 [ZoneResolver.Register] 一样在默认的对照表中将缩写对应到时区。
+影响 [ResolveZone]、包级别的解析函数，以及使用默认 [ParserOption].Zones 创建的解析器。
+*/
+func RegisterZone(abbr string, loc *time.Location) {
+	defaultZones.Register(abbr, loc)
+}
@@ -0,0 +1,112 @@
+package timeutils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveZone(t *testing.T) {
+	for _, v := range []struct {
+		s      string
+		offset int
+	}{
+		{"CST", 8 * 3600},
+		{"pst", -8 * 3600},
+		{"PDT", -7 * 3600},
+		{"IST", 5*3600 + 30*60},
+		{"UTC", 0},
+		{"Z", 0},
+		{"+0800", 8 * 3600},
+		{"+08:00", 8 * 3600},
+		{"-05", -5 * 3600},
+		{"-0330", -(3*3600 + 30*60)},
+	} {
+		loc, err := ResolveZone(v.s)
+		assert.Nil(t, err, v.s)
+		_, offset := time.Date(2023, 1, 5, 0, 0, 0, 0, loc).Zone()
+		assert.Equal(t, v.offset, offset, v.s)
+	}
+
+	for _, s := range []string{"", "XYZ", "+2500", "+08:60", "Nowhere/City"} {
+		_, err := ResolveZone(s)
+		assert.NotNil(t, err, s)
+	}
+}
+
+func TestZoneResolverRegister(t *testing.T) {
+	resolver := NewZoneResolver()
+	resolver.Register("cst", time.FixedZone("CST", -6*3600))
+	resolver.Register("IST", nil)
+
+	loc, err := resolver.Resolve("CST")
+	assert.Nil(t, err)
+	_, offset := time.Date(2023, 1, 5, 0, 0, 0, 0, loc).Zone()
+	assert.Equal(t, -6*3600, offset)
+
+	_, err = resolver.Resolve("IST")
+	assert.NotNil(t, err)
+
+	// 不影响默认的对照表。
+	loc, err = ResolveZone("CST")
+	assert.Nil(t, err)
+	_, offset = time.Date(2023, 1, 5, 0, 0, 0, 0, loc).Zone()
+	assert.Equal(t, 8*3600, offset)
+}
+
+func TestParserZoneAbbreviation(t *testing.T) {
+	const layout = "2006-01-02 15:04:05 -0700"
+
+	for _, v := range []struct {
+		s        string
+		expected string
+	}{
+		{"2023-05-01 12:00:00 PST", "2023-05-01 12:00:00 -0800"},
+		{"IMG_20230501_120000_CST.jpg", "2023-05-01 12:00:00 +0800"},
+		{"2023-05-01 12:00EST", "2023-05-01 12:00:00 -0500"},
+		{"2023年5月1日 12时00分 JST", "2023-05-01 12:00:00 +0900"},
+		{"2023-05-01 12:00:00 XYZ", "2023-05-01 12:00:00 +0000"}, // 未知的缩写被忽略。
+		{"2023-05-01 12:00:00 PSTX", "2023-05-01 12:00:00 +0000"},
+	} {
+		tm := ParseDateTimeInLocation(v.s, time.UTC)
+		assert.NotNil(t, tm, v.s)
+		assert.Equal(t, v.expected, tm.Format(layout), v.s)
+	}
+
+	// 未知的缩写不属于被解析的子串。
+	parser, err := NewParser(nil)
+	assert.Nil(t, err)
+	for _, v := range []struct {
+		s       string
+		matched string
+	}{
+		{"DSC_20230105_1430_HDR.jpg", "20230105_1430"},
+		{"report 2023-01-05 14-30-00 PDT.txt", "2023-01-05 14-30-00 PDT"},
+		{"IMG_20230105_143000-1234.jpg", "20230105_143000"},
+	} {
+		_, matched, err := parser.FindDateTime(v.s)
+		assert.Nil(t, err, v.s)
+		assert.Equal(t, v.matched, matched, v.s)
+	}
+
+	// 使用自己的对照表。
+	resolver := NewZoneResolver()
+	resolver.Register("CST", time.FixedZone("CST", -6*3600))
+	option := NewParserOption()
+	option.Location = time.UTC
+	option.Zones = resolver
+	parser, err = NewParser(option)
+	assert.Nil(t, err)
+	tm, err := parser.ParseDateTime("2023-05-01 12:00:00 CST")
+	assert.Nil(t, err)
+	assert.Equal(t, "2023-05-01 12:00:00 -0600", tm.Format(layout))
+
+	// 不解析缩写。
+	option.Zones = nil
+	parser, err = NewParser(option)
+	assert.Nil(t, err)
+	tm, err = parser.ParseDateTime("2023-05-01 12:00:00 CST")
+	assert.Nil(t, err)
+	assert.Equal(t, "2023-05-01 12:00:00 +0000", tm.Format(layout))
+}